@@ -13,13 +13,52 @@ import (
 
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/config"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/handler"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/scheduler"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/server"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/postgres"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/webhook"
 )
 
 var flagConfig = flag.String("config", "./configs/local.yml", "path to config file")
 
+// holdSweepInterval представляет период, с которым снимаются истёкшие удержания столиков.
+const holdSweepInterval = time.Minute
+
+// notificationsConfig представляет настройки фоновой отправки уведомлений гостям.
+var notificationsConfig = notifier.AsyncConfig{
+	QueueSize:   1000,
+	Workers:     4,
+	SendTimeout: 30 * time.Second,
+}
+
+// jobsConfig представляет настройки выполнения фоновых задач (напоминаний гостям о бронях). Задача выполняется
+// быстрее, чем длится завершение работы сервиса, поэтому начатую задачу при остановке успевают доделать.
+var jobsConfig = scheduler.Config{
+	PollInterval:  10 * time.Second,
+	BatchSize:     20,
+	JobTimeout:    10 * time.Second,
+	MaxAttempts:   6,
+	RetryDelay:    time.Minute,
+	MaxRetryDelay: time.Hour,
+}
+
+// webhooksConfig представляет настройки доставки событий с бронями вебхукам ресторанов. Получатель может быть
+// недоступен часами (например, на время обновления CRM), поэтому попыток больше, чем у фоновых задач.
+var webhooksConfig = webhook.Config{
+	PollInterval:  10 * time.Second,
+	BatchSize:     20,
+	Timeout:       10 * time.Second,
+	MaxAttempts:   8,
+	RetryDelay:    time.Minute,
+	MaxRetryDelay: time.Hour,
+}
+
 // @title           Restaurant Table Booking API
 // @version         1.0
 // @description     API сервиса бронирования столиков в ресторанах
@@ -31,6 +70,11 @@ var flagConfig = flag.String("config", "./configs/local.yml", "path to config fi
 // @host      localhost:8080
 // @BasePath  /api/v1
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Ключ API пользователя в виде "Bearer <ключ>".
+
 func main() {
 	flag.Parse()
 
@@ -48,21 +92,59 @@ func main() {
 	}
 	logger.SetLevel(level)
 
-	db, err := postgres.NewDB(cfg.DSN)
+	st, closeStore, err := newStore(cfg)
 	if err != nil {
 		logger.Fatalf("failed to establish database connection: %s", err)
 	}
 
-	st := postgres.NewStore(db)
-	services := service.NewServices(st)
-	router := handler.NewHandler(services, logger)
+	// уведомления гостям отправляются в фоне, чтобы медленный провайдер не задерживал ответы на запросы,
+	// а напоминания отправляет планировщик фоновых задач, который сам повторяет неудачные попытки
+	baseNotifier := newNotifier(cfg, logger)
+	guestNotifier := notifier.NewAsyncNotifier(baseNotifier, notificationsConfig, logger)
+	reminderBefore := time.Duration(cfg.ReminderHoursBefore) * time.Hour
+
+	// события ресторанов (оформление и отмена броней, изменение столиков), которые хосты получают в реальном времени
+	events, err := newBroker(cfg, logger)
+	if err != nil {
+		logger.Fatalf("failed to start restaurant events broker: %s", err)
+	}
+
+	services := service.NewServices(
+		st, cfg.AdminAPIKey, guestNotifier, baseNotifier, reminderBefore, cfg.BookingLinkKey, events,
+	)
+	router := handler.NewHandler(services, events, logger, cfg.WebsiteDir)
 	srv := server.NewServer(cfg.BindAddr, router.InitRoutes())
 
 	// серверный контекст
 	srvCtx, srvStopCtx := context.WithCancel(context.Background())
 
+	// фоновое снятие истёкших удержаний столиков
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	go runHoldSweeper(sweeperCtx, services.HoldService, logger)
+
+	// фоновое выполнение задач, сохранённых в хранилище
+	jobs := scheduler.NewScheduler(st.Jobs(), jobsConfig, logger)
+	jobs.Handle(model.JobKindBookingReminder, func(ctx context.Context, job model.Job) error {
+		return services.ReminderService.Send(ctx, job.BookingID)
+	})
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Run(jobsCtx)
+		close(jobsDone)
+	}()
+
+	// фоновая доставка событий с бронями вебхукам ресторанов
+	dispatcher := webhook.NewDispatcher(st.Outbox(), st.Webhooks(), webhooksConfig, logger)
+	webhooksCtx, stopWebhooks := context.WithCancel(context.Background())
+	webhooksDone := make(chan struct{})
+	go func() {
+		dispatcher.Run(webhooksCtx)
+		close(webhooksDone)
+	}()
+
 	// прослушивание системных вызовов для прерывания или завершения процесса
-	osSigCh := make(chan os.Signal)
+	osSigCh := make(chan os.Signal, 1)
 	signal.Notify(osSigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
 	go func() {
@@ -80,7 +162,21 @@ func main() {
 			}
 		}()
 
-		if err = db.Close(); err != nil {
+		// хранилище закрывается, поэтому удержания больше не снимаются, а фоновые задачи и доставка событий
+		// вебхукам не выполняются: начатые дожидаемся, а остальные выполнятся после перезапуска
+		stopSweeper()
+		stopJobs()
+		stopWebhooks()
+		<-jobsDone
+		<-webhooksDone
+
+		// потоки событий ресторанов не ждут завершения работы сервера: закрытие подписок завершает их, а клиенты
+		// переподключатся к другому экземпляру сервиса или после перезапуска
+		if err = events.Close(); err != nil {
+			logger.Errorf("failed to close restaurant events broker: %s", err)
+		}
+
+		if err = closeStore(); err != nil {
 			logger.Fatalf("failed to close the database connection: %s", err)
 		}
 
@@ -89,6 +185,11 @@ func main() {
 			logger.Fatalf("server shutdown failed: %s", err)
 		}
 
+		// запросы больше не обрабатываются, поэтому дожидаемся отправки уведомлений, уже поставленных в очередь
+		if err = guestNotifier.Close(shutdownCtx); err != nil {
+			logger.Errorf("failed to send queued notifications: %s", err)
+		}
+
 		shutdownStopCtx()
 		srvStopCtx()
 	}()
@@ -106,3 +207,77 @@ func main() {
 
 	logger.Info("server exited gracefully")
 }
+
+// runHoldSweeper раз в holdSweepInterval снимает удержания столиков, срок которых истёк, пока не будет отменён ctx.
+func runHoldSweeper(ctx context.Context, holds service.HoldService, logger *logrus.Logger) {
+	ticker := time.NewTicker(holdSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := holds.ReleaseExpired()
+			if err != nil {
+				logger.Errorf("failed to release expired holds: %s", err)
+				continue
+			}
+			if released > 0 {
+				logger.Debugf("released expired holds in %d restaurants", released)
+			}
+		}
+	}
+}
+
+// newNotifier создаёт способ доставки уведомлений гостям из выбранных в настройках сервиса. Если выбрано
+// несколько способов, уведомление отправляется каждым из них.
+func newNotifier(cfg *config.Config, logger *logrus.Logger) notifier.Notifier {
+	var notifiers notifier.MultiNotifier
+	for _, name := range cfg.NotifierList() {
+		switch name {
+		case config.NotifierLog:
+			notifiers = append(notifiers, notifier.NewLogNotifier(logger))
+		case config.NotifierFile:
+			notifiers = append(notifiers, notifier.NewFileNotifier(cfg.NotifyFilePath))
+		case config.NotifierSMTP:
+			notifiers = append(notifiers, notifier.NewSMTPNotifier(notifier.SMTPConfig{
+				Addr:     cfg.SMTPAddr,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.SMTPFrom,
+			}))
+		case config.NotifierSMS:
+			notifiers = append(notifiers, notifier.NewSMSNotifier(notifier.SMSConfig{
+				GatewayURL: cfg.SMSGatewayURL,
+				Token:      cfg.SMSGatewayToken,
+				Sender:     cfg.SMSSender,
+			}))
+		}
+	}
+	return notifiers
+}
+
+// newStore инициализирует слой хранения данных, выбранный в настройках сервиса, и возвращает его
+// вместе с функцией освобождения занятых им ресурсов.
+func newStore(cfg *config.Config) (store.Store, func() error, error) {
+	if cfg.StoreDriver == config.StoreDriverMemory {
+		return memory.NewStore(), func() error { return nil }, nil
+	}
+
+	db, err := postgres.NewDB(cfg.DSN)
+	if err != nil {
+		return nil, nil, err
+	}
+	return postgres.NewStore(db), db.Close, nil
+}
+
+// newBroker создаёт способ публикации событий ресторанов и подписки на них. При хранении данных в PostgreSQL события
+// распространяются через LISTEN/NOTIFY между всеми экземплярами сервиса, работающими с одной БД, иначе - только
+// внутри процесса.
+func newBroker(cfg *config.Config, logger *logrus.Logger) (pubsub.Broker, error) {
+	if cfg.StoreDriver == config.StoreDriverMemory {
+		return pubsub.NewHub(), nil
+	}
+	return pubsub.NewPostgresBroker(cfg.DSN, logger)
+}
@@ -21,8 +21,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/restaurants/": {
+        "/guests/": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Телефон можно записать в любом виде (например, 89485722648, +7 948 572-26-48): гости различаются по телефону в формате E.164. Менеджеру и сотруднику гость доступен, только если у него есть брони в их ресторанах, а статистика показывается только по этим ресторанам.",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,14 +36,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "guests"
+                ],
+                "summary": "Найти гостя по телефону",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Телефон гостя",
+                        "name": "phone",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить список всех ресторанов",
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.listRestaurantsResponse"
+                            "$ref": "#/definitions/handler.guestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Не передан телефон",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Гость не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
                         }
                     },
                     "500": {
@@ -47,8 +80,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/guests/{guest_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Вместе с гостем возвращается статистика посещений им ресторанов: количество броней, посещений и отмен, средний размер компании и дата последнего посещения. Менеджеру и сотруднику статистика показывается только по их ресторанам.",
                 "consumes": [
                     "application/json"
                 ],
@@ -56,29 +97,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "guests"
                 ],
-                "summary": "Создать ресторан",
+                "summary": "Получить гостя по его ID",
                 "parameters": [
                     {
-                        "description": "Информация о ресторане",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.createRestaurantRequest"
-                        }
+                        "type": "string",
+                        "description": "ID гостя",
+                        "name": "guest_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.createRestaurantResponse"
+                            "$ref": "#/definitions/handler.guestResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные ресторана",
+                        "description": "Некорректный ID гостя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Гость не найден",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -92,8 +143,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/restaurants/{restaurant_id}/": {
+        "/guests/{guest_id}/bookings": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращаются все брони гостя (в том числе отменённые) в ресторанах, доступных пользователю API.",
                 "consumes": [
                     "application/json"
                 ],
@@ -101,14 +158,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "guests"
                 ],
-                "summary": "Получить ресторан по его ID",
+                "summary": "Получить историю броней гостя",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID ресторана",
-                        "name": "restaurant_id",
+                        "description": "ID гостя",
+                        "name": "guest_id",
                         "in": "path",
                         "required": true
                     }
@@ -117,11 +174,23 @@ const docTemplate = `{
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.getRestaurantResponse"
+                            "$ref": "#/definitions/handler.listGuestBookingsResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный ID ресторана",
+                        "description": "Некорректный ID гостя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Гость не найден",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -133,9 +202,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Если ресторан ожидает гостей (есть брони в будущем/сегодняшнем днях в этом ресторане), то его нельзя удалить.",
+            }
+        },
+        "/restaurants/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -145,27 +220,12 @@ const docTemplate = `{
                 "tags": [
                     "restaurants"
                 ],
-                "summary": "Удаляет ресторан по его ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID ресторана",
-                        "name": "restaurant_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Получить список всех ресторанов (менеджерам и сотрудникам - закреплённых за ними)",
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.deleteRestaurantResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Некорректный данные запроса",
-                        "schema": {
-                            "$ref": "#/definitions/handler.errResponse"
+                            "$ref": "#/definitions/handler.listRestaurantsResponse"
                         }
                     },
                     "500": {
@@ -176,8 +236,12 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Обновление происходит именно путём PATCH-запросов, чтобы была возможность изменять данные частично.",
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -187,34 +251,27 @@ const docTemplate = `{
                 "tags": [
                     "restaurants"
                 ],
-                "summary": "Обновить информацию о ресторане по его ID",
+                "summary": "Создать ресторан",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID ресторана",
-                        "name": "restaurant_id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Информация о ресторане",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateRestaurantData"
+                            "$ref": "#/definitions/handler.createRestaurantRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "201": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.updateRestaurantResponse"
+                            "$ref": "#/definitions/handler.createRestaurantResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный данные запроса",
+                        "description": "Некорректные данные ресторана",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -228,8 +285,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/restaurants/{restaurant_id}/bookings/": {
+        "/restaurants/available": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если на желаемое время не нашлось ни одного ресторана, в ответ добавляются варианты: ближайшие моменты начала брони (раньше или позже в тот же день и в следующие 3 дня) и рестораны, в которых можно рассадить компанию в ближайшее к желаемому время.",
                 "consumes": [
                     "application/json"
                 ],
@@ -237,27 +300,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bookings"
+                    "restaurants"
                 ],
-                "summary": "Получить список всех броней, совершённых в ресторане",
+                "summary": "Найти рестораны, в которых можно забронировать столики",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID ресторана",
-                        "name": "restaurant_id",
-                        "in": "path",
+                        "example": "2022.06.16 17:00",
+                        "description": "Желаемые дата и время посещения ресторана",
+                        "name": "desired_datetime",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 4,
+                        "description": "Количество человек",
+                        "name": "people_number",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочитаемая зона ресторана",
+                        "name": "zone",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.listBookingsResponse"
+                            "$ref": "#/definitions/handler.listAvailableRestaurantsResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный restaurant_id",
+                        "description": "Некорректные дата и время, количество человек или зона",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -269,8 +347,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/restaurants/{restaurant_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -278,9 +363,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bookings"
+                    "restaurants"
                 ],
-                "summary": "Оформить бронь в выбранном ресторане",
+                "summary": "Получить ресторан по его ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -288,26 +373,17 @@ const docTemplate = `{
                         "name": "restaurant_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Информация о брони",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.createBookingRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.createBookingResponse"
+                            "$ref": "#/definitions/handler.getRestaurantResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные брони",
+                        "description": "Некорректный ID ресторана",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -319,10 +395,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/restaurants/{restaurant_id}/tables/": {
-            "get": {
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если ресторан ожидает гостей (есть брони в будущем/сегодняшнем днях в этом ресторане), то его нельзя удалить.",
                 "consumes": [
                     "application/json"
                 ],
@@ -330,9 +410,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tables"
+                    "restaurants"
                 ],
-                "summary": "Получить список столиков в ресторане",
+                "summary": "Удаляет ресторан по его ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -346,11 +426,11 @@ const docTemplate = `{
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.listTablesResponse"
+                            "$ref": "#/definitions/handler.deleteRestaurantResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный restaurant_id",
+                        "description": "Некорректный данные запроса",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -363,7 +443,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновление происходит именно путём PATCH-запросов, чтобы была возможность изменять данные частично.",
                 "consumes": [
                     "application/json"
                 ],
@@ -371,9 +457,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tables"
+                    "restaurants"
                 ],
-                "summary": "Создать столик в ресторане",
+                "summary": "Обновить информацию о ресторане по его ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -383,24 +469,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Информация о столике",
+                        "description": "Информация о ресторане",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.createTableRequest"
+                            "$ref": "#/definitions/model.UpdateRestaurantData"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.createTableResponse"
+                            "$ref": "#/definitions/handler.updateRestaurantResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректные данные столика",
+                        "description": "Некорректный данные запроса",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -414,8 +500,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tables/{table_id}/": {
+        "/restaurants/{restaurant_id}/availability": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все моменты начала брони с шагом 15 минут в пределах графика работы ресторана (кроме уже наступивших) с количеством мест за столиками, свободными на всё время брони, и признаком того, можно ли рассадить компанию. Свободные столики для всех моментов подбираются одним запросом к БД.",
                 "consumes": [
                     "application/json"
                 ],
@@ -423,27 +515,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tables"
+                    "restaurants"
                 ],
-                "summary": "Получить столик по его ID",
+                "summary": "Получить сетку доступности ресторана на выбранный день",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID столика",
-                        "name": "table_id",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2022.06.16",
+                        "description": "Дата посещения ресторана",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 4,
+                        "description": "Количество человек",
+                        "name": "people",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочитаемая зона ресторана",
+                        "name": "zone",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.getTableResponse"
+                            "$ref": "#/definitions/handler.getAvailabilityResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный ID столика",
+                        "description": "Некорректные дата, количество человек или зона",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -455,9 +569,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Если за столиком ещё будут сидеть клиенты (есть брони в будущем/сегодняшнем днях в этом ресторане), то его нельзя удалить.",
+            }
+        },
+        "/restaurants/{restaurant_id}/bookings/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -465,14 +585,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tables"
+                    "bookings"
                 ],
-                "summary": "Удаляет столик по его ID",
+                "summary": "Получить список всех броней, совершённых в ресторане",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID столика",
-                        "name": "table_id",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
                         "in": "path",
                         "required": true
                     }
@@ -481,11 +601,11 @@ const docTemplate = `{
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.deleteTableResponse"
+                            "$ref": "#/definitions/handler.listBookingsResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный данные запроса",
+                        "description": "Некорректный restaurant_id",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -498,8 +618,12 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Обновление происходит именно путём PATCH-запросов, чтобы была возможность изменять данные частично.",
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -507,36 +631,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tables"
+                    "bookings"
                 ],
-                "summary": "Обновить информацию о столике по его ID",
+                "summary": "Оформить бронь в выбранном ресторане",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID столика",
-                        "name": "table_id",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Информация о столике",
+                        "description": "Информация о брони",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateTableData"
+                            "$ref": "#/definitions/handler.createBookingRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
+                    "201": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/handler.updateTableResponse"
+                            "$ref": "#/definitions/handler.createBookingResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный данные запроса",
+                        "description": "Некорректные данные брони",
                         "schema": {
                             "$ref": "#/definitions/handler.errResponse"
                         }
@@ -549,128 +673,3928 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.createBookingRequest": {
-            "type": "object",
-            "properties": {
-                "client_name": {
-                    "description": "ClientName имя клиента, оформляющего бронь.",
-                    "type": "string",
-                    "example": "Павел"
-                },
-                "client_phone": {
-                    "description": "ClientPhone телефон клиента, оформляющего бронь.",
-                    "type": "string",
-                    "example": "89876545654"
-                },
-                "desired_datetime": {
-                    "description": "DesiredDatetime представляет дату и время посещения ресторана в рамках брони",
-                    "type": "string",
-                    "example": "2022.06.16 17:03"
-                },
-                "people_number": {
-                    "type": "integer",
-                    "example": 3
-                }
-            }
         },
-        "handler.createBookingResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 1
+        "/restaurants/{restaurant_id}/bookings/{booking_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Получить бронь в ресторане по её ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID брони",
+                        "name": "booking_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID брони",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
                 }
-            }
-        },
-        "handler.createRestaurantRequest": {
-            "type": "object",
-            "properties": {
-                "average_check": {
-                    "type": "string",
-                    "example": "2500.00"
-                },
-                "average_waiting_time": {
-                    "type": "string",
-                    "example": "60"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "Каравелла"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Столики, забронированные в рамках брони, снова становятся доступными. Бронь, время которой уже наступило, отменить нельзя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Отменить бронь в ресторане",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID брони",
+                        "name": "booking_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.cancelBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Бронь уже отменена или её время наступило",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Гости уже пришли или бронь завершена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
                 }
-            }
-        },
-        "handler.createRestaurantResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 1
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Столики подбираются заново: если прежние столики свободны на новое время и за ними хватает мест, гости остаются за ними. Если рассадить компанию нельзя, бронь остаётся прежней.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Изменить количество человек и (или) дату и время брони",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID брони",
+                        "name": "booking_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные брони",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateBookingData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.updateBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные брони, бронь отменена или её время наступило",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В ресторане не хватает мест или гости уже пришли",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
                 }
             }
         },
-        "handler.createTableRequest": {
+        "/restaurants/{restaurant_id}/bookings/{booking_id}/check-in": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Бронь переводится в статус seated. Приход можно отметить не раньше чем за час до начала брони.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Отметить приход гостей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID брони",
+                        "name": "booking_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.changeBookingStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID брони или до начала брони больше часа",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Бронь нельзя перевести в статус seated",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/bookings/{booking_id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Гости ушли: бронь переводится в статус completed, а её столики освобождаются, даже если время брони ещё не истекло.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Завершить бронь",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID брони",
+                        "name": "booking_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.changeBookingStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID брони",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Бронь нельзя перевести в статус completed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/bookings/{booking_id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подтвердить можно только бронь в статусе pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Подтвердить бронь",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID брони",
+                        "name": "booking_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.changeBookingStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID брони",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Бронь нельзя перевести в статус confirmed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/bookings/{booking_id}/no-show": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Бронь переводится в статус no_show, а её столики сразу освобождаются. Неявку можно отметить только после начала брони.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookings"
+                ],
+                "summary": "Отметить неявку гостей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID брони",
+                        "name": "booking_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.changeBookingStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID брони или время брони ещё не наступило",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бронь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Бронь нельзя перевести в статус no_show",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/duration-policy/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если ресторану не заданы собственные правила, любая бронь длится 2 часа.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Получить правила длительности брони в ресторане",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getDurationPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID ресторана",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Длительность брони указывается в минутах. Для компании применяется правило с наибольшим min_people, не превышающим её размер, а если такого нет - default_duration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Заменить правила длительности брони в ресторане",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Правила длительности брони",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DurationPolicy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.setDurationPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные правила длительности брони",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Поток Server-Sent Events (text/event-stream) с событиями ресторана в реальном времени: booking.created (бронь оформлена), booking.updated (бронь перенесена или изменилось количество гостей), booking.cancelled (бронь отменена) и table.changed (столик добавлен, изменён, удалён или изменилось, с какими столиками его можно сдвинуть; у удалённого столика нет поля table). Каждое событие отправляется как \"event: \u003cсобытие\u003e\" и \"data: \u003cmodel.RestaurantEvent в формате JSON\u003e\". Поток закрывается, если клиент не успевает разбирать события или часть событий могла быть потеряна: тогда стоит заново загрузить брони и столики и переподключиться.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Получить поток событий ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/model.RestaurantEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID ресторана",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Ресторан не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/holds/": {
+            "post": {
+                "description": "Столики подбираются так же, как при оформлении брони, и удерживаются 5 минут, пока гость заполняет форму брони: другие гости не могут их забронировать. Если удержание не подтвердить, оно снимается автоматически. В ответе возвращается токен удержания: его нужно передавать в заголовке X-Hold-Token, чтобы получить, подтвердить или снять удержание. Один клиент может одновременно удерживать столики не более 3 раз.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holds"
+                ],
+                "summary": "Временно удержать столики в выбранном ресторане",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Информация о будущей брони",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.createHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.holdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные брони",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В ресторане не хватает свободных мест",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "У клиента слишком много действующих удержаний",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/holds/{hold_id}/": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holds"
+                ],
+                "summary": "Получить удержание столиков по его ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID удержания",
+                        "name": "hold_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен удержания, выданный при его создании (не нужен с ключом API)",
+                        "name": "X-Hold-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.holdResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID удержания",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ни токен удержания, ни ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к ресторану",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Удержание не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holds"
+                ],
+                "summary": "Снять удержание столиков досрочно",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID удержания",
+                        "name": "hold_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен удержания, выданный при его создании (не нужен с ключом API)",
+                        "name": "X-Hold-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.releaseHoldResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID удержания",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ни токен удержания, ни ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к ресторану",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Удержание не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/holds/{hold_id}/confirm": {
+            "post": {
+                "description": "Удержание снимается, а столики бронируются на время, на которое они удерживались.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holds"
+                ],
+                "summary": "Оформить бронь на удерживаемые столики",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID удержания",
+                        "name": "hold_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен удержания, выданный при его создании (не нужен с ключом API)",
+                        "name": "X-Hold-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Информация о клиенте",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.confirmHoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.createBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные клиента",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ни токен удержания, ни ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к ресторану или онлайн-бронирование заблокировано для гостя из-за неявок",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Удержание не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Срок удержания истёк",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/opening-hours/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если ресторану не задан собственный график, возвращается график по умолчанию (ежедневно с 9:00 до 23:00, последняя бронь - на 21:00).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Получить недельный график работы ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getOpeningHoursResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID ресторана",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "В один день недели можно задать несколько смен (например, обеденную и вечернюю). Если время закрытия не позже времени открытия, смена заканчивается на следующий день (например, с 18:00 до 02:00). Пустой список смен возвращает ресторан к графику по умолчанию.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Заменить недельный график работы ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "График работы ресторана",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetOpeningHoursData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.setOpeningHoursResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный график работы",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/reliability-policy/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Нулевые значения означают, что ограничение не действует: если ресторан не задал правила, онлайн-бронирование доступно всем гостям.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Получить правила надёжности гостей ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getReliabilityPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID ресторана",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Неявки гостя считаются по его телефону в этом ресторане. Начиная с confirmation_after неявок брони гостя, оформленные на сайте, ждут подтверждения рестораном (статус pending), а начиная с block_after неявок гость не может бронировать столики на сайте в течение block_days дней после последней неявки. Брони, которые оформляет ресторан через API, не ограничиваются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Заменить правила надёжности гостей ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Правила надёжности гостей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReliabilityPolicy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.setReliabilityPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные правила надёжности гостей",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/tables/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Получить список столиков в ресторане",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.listTablesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный restaurant_id",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Создать столик в ресторане",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Информация о столике",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.createTableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.createTableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные столика",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/waitlist/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Получить лист ожидания ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.listWaitlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный restaurant_id",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Когда в ресторане освобождаются места (отменяется бронь или появляется столик), они предлагаются первому подходящему гостю из листа ожидания. Предложение действует 15 минут.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Поставить гостя в лист ожидания ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Информация о госте",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.joinWaitlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.joinWaitlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные гостя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/waitlist/{entry_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Получить запись в листе ожидания ресторана по её ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID записи в листе ожидания",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getWaitlistEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID записи",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запись не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если гостю были предложены места, они предлагаются следующему гостю.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Убрать гостя из листа ожидания ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID записи в листе ожидания",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.leaveWaitlistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Гость уже не ждёт мест",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запись не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/waitlist/{entry_id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если предложенные места успели занять, гость снова ждёт своей очереди.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "waitlist"
+                ],
+                "summary": "Принять предложение и оформить бронь по записи в листе ожидания",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID записи в листе ожидания",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.acceptWaitlistOfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Гостю не предлагались места или предложение истекло",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Онлайн-бронирование заблокировано для гостя из-за неявок",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запись не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Предложенные места уже заняты",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/webhooks/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получить список вебхуков ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.listWebhooksResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Ресторан не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "На адрес вебхука POST-запросом отправляются события с бронями ресторана (booking.created, booking.updated, booking.status_changed, booking.cancelled; пустой список events - все события). Тело запроса подписывается ключом вебхука: заголовок X-Webhook-Signature имеет вид t=\u003cunix-время\u003e,v1=\u003cHMAC-SHA256 строки \"\u003cunix-время\u003e.\u003cтело\u003e\" в шестнадцатеричном виде\u003e. Ключ возвращается только в ответе на этот запрос. Недоставленные события (получатель не ответил кодом 2xx) отправляются повторно с нарастающей задержкой.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Создать вебхук ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Адрес и события вебхука",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateWebhookData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.webhookWithSecretResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный адрес или события вебхука",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Ресторан не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/webhooks/{webhook_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получить вебхук ресторана по его ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID вебхука",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Вместе с вебхуком удаляется журнал доставки ему событий: недоставленные события больше не отправляются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удалить вебхук ресторана",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.deleteWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID вебхука",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurant_id}/webhooks/{webhook_id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает последние 50 доставок (первыми идут самые новые): статус (pending - ждёт доставки или повторной попытки, delivered - доставлено, failed - не доставлено за все попытки), количество попыток, код ответа и ошибку последней попытки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получить журнал доставки событий вебхуку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ресторана",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID вебхука",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.listWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID вебхука",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tables/{table_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Получить столик по его ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID столика",
+                        "name": "table_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getTableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID столика",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если за столиком ещё будут сидеть клиенты (есть брони в будущем/сегодняшнем днях в этом ресторане), то его нельзя удалить.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Удаляет столик по его ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID столика",
+                        "name": "table_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.deleteTableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновление происходит именно путём PATCH-запросов, чтобы была возможность изменять данные частично.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Обновить информацию о столике по его ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID столика",
+                        "name": "table_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Информация о столике",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateTableData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.updateTableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный данные запроса",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tables/{table_id}/joinable-tables/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если в ресторане не задано ни одной пары столиков, которые можно сдвинуть, при бронировании сдвигаются любые столики.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Получить список столиков, которые можно сдвинуть со столиком",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID столика",
+                        "name": "table_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.listJoinableTablesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID столика",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tables/{table_id}/joinable-tables/{joinable_table_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сдвигать можно только разные столики одного ресторана. Компания, которой нужно несколько столиков, садится только за столики, которые можно сдвинуть.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Отметить, что столики можно сдвинуть",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID столика",
+                        "name": "table_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID столика, который можно сдвинуть с первым",
+                        "name": "joinable_table_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.joinTablesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные ID столиков",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Столик не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tables"
+                ],
+                "summary": "Отметить, что столики сдвигать нельзя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID столика",
+                        "name": "table_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID столика, который больше нельзя сдвигать с первым",
+                        "name": "joinable_table_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.joinTablesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные ID столиков",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Столик не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить список пользователей API администрирования",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.listUsersResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Администратору платформы доступны все рестораны, менеджер управляет закреплёнными за ним ресторанами, а сотрудник может их только просматривать. Ключ API возвращается только в ответе на этот запрос: в БД хранится лишь его хеш.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Создать пользователя API администрирования",
+                "parameters": [
+                    {
+                        "description": "Информация о пользователе",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.createUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.userWithAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить пользователя, которому принадлежит переданный ключ API",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getUserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить пользователя API администрирования по его ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.getUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удалить пользователя API администрирования по его ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.deleteUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/api-key": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Прежний ключ пользователя перестаёт действовать. Новый ключ возвращается только в ответе на этот запрос.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Выпустить пользователю новый ключ API",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/handler.userWithAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Не передан ключ API",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.errResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.acceptWaitlistOfferResponse": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "description": "BookingID представляет ID оформленной брони.",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handler.cancelBookingResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.changeBookingStatusResponse": {
+            "type": "object",
+            "properties": {
+                "booked_date": {
+                    "description": "BookedDate представляет дату посещения ресторана в рамках брони.",
+                    "type": "string",
+                    "example": "2022.06.16"
+                },
+                "booked_time_from": {
+                    "description": "BookedTimeFrom представляет время начала брони.",
+                    "type": "string",
+                    "example": "14:30"
+                },
+                "booked_time_to": {
+                    "description": "BookedTimeTo представляет время конца брони.",
+                    "type": "string",
+                    "example": "16:30"
+                },
+                "cancelled_at": {
+                    "description": "CancelledAt представляет момент отмены брони (nil, если бронь не отменена).",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "cancelled_by": {
+                    "description": "CancelledBy представляет того, кто отменил бронь: BookingCancelledByClient или BookingCancelledByRestaurant.",
+                    "type": "string",
+                    "example": "client"
+                },
+                "client_email": {
+                    "description": "ClientEmail представляет адрес электронной почты клиента для уведомлений о брони (пустая строка - не указан).",
+                    "type": "string",
+                    "example": "pavel@example.com"
+                },
+                "client_name": {
+                    "description": "ClientName представляет имя клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "client_phone": {
+                    "description": "ClientPhone представляет телефон клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "+79485722648"
+                },
+                "customer_id": {
+                    "description": "CustomerID представляет ID учётной записи гостя, оформившего бронь (nil, если бронь оформлена без учётной записи).",
+                    "type": "integer",
+                    "example": 5
+                },
+                "finished_at": {
+                    "description": "FinishedAt представляет момент, когда гости ушли или бронь была отмечена как неявка: с этого момента столики\nброни свободны (nil, если бронь ещё не завершена).",
+                    "type": "string",
+                    "example": "2022-06-16T16:10:00Z"
+                },
+                "guest_id": {
+                    "description": "GuestID представляет ID гостя, определённого по телефону в брони (nil, если в телефоне нет цифр).",
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "people_number": {
+                    "description": "PeopleNumber представляет количество человек, на которое оформлена бронь.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, в котором оформлена бронь.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "seated_at": {
+                    "description": "SeatedAt представляет момент, когда гости пришли в ресторан (nil, если гости ещё не приходили).",
+                    "type": "string",
+                    "example": "2022-06-16T14:35:00Z"
+                },
+                "status": {
+                    "description": "Status представляет статус брони: pending, confirmed, seated, completed, no_show или cancelled.",
+                    "type": "string",
+                    "example": "confirmed"
+                },
+                "table_ids": {
+                    "description": "TableIDs представляет ID столиков, забронированных в рамках брони (заполняется при получении брони по её ID).",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.confirmHoldRequest": {
+            "type": "object",
+            "properties": {
+                "client_email": {
+                    "description": "ClientEmail адрес электронной почты клиента для уведомлений о брони (необязательно).",
+                    "type": "string",
+                    "example": "pavel@example.com"
+                },
+                "client_name": {
+                    "description": "ClientName имя клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "client_phone": {
+                    "description": "ClientPhone телефон клиента, оформляющего бронь, в международном формате (российский номер можно записать\nи через 8).",
+                    "type": "string",
+                    "example": "+79876545654"
+                }
+            }
+        },
+        "handler.createBookingRequest": {
+            "type": "object",
+            "properties": {
+                "client_email": {
+                    "description": "ClientEmail адрес электронной почты клиента для уведомлений о брони (необязательно).",
+                    "type": "string",
+                    "example": "pavel@example.com"
+                },
+                "client_name": {
+                    "description": "ClientName имя клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "client_phone": {
+                    "description": "ClientPhone телефон клиента, оформляющего бронь, в международном формате (российский номер можно записать\nи через 8). Сохраняется в формате E.164.",
+                    "type": "string",
+                    "example": "+79876545654"
+                },
+                "desired_datetime": {
+                    "description": "DesiredDatetime представляет дату и время посещения ресторана в рамках брони",
+                    "type": "string",
+                    "example": "2022.06.16 17:03"
+                },
+                "pending": {
+                    "description": "Pending означает, что бронь ждёт подтверждения рестораном (необязательно, по умолчанию бронь сразу подтверждена).",
+                    "type": "boolean",
+                    "example": false
+                },
+                "people_number": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "zone": {
+                    "description": "Zone представляет зону ресторана, в которой гость предпочитает сидеть (необязательно, по умолчанию любая).",
+                    "type": "string",
+                    "example": "terrace"
+                }
+            }
+        },
+        "handler.createBookingResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.createHoldRequest": {
+            "type": "object",
+            "properties": {
+                "desired_datetime": {
+                    "description": "DesiredDatetime представляет дату и время посещения ресторана в рамках будущей брони.",
+                    "type": "string",
+                    "example": "2022.06.16 17:03"
+                },
+                "people_number": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "zone": {
+                    "description": "Zone представляет зону ресторана, в которой гость предпочитает сидеть (необязательно, по умолчанию любая).",
+                    "type": "string",
+                    "example": "terrace"
+                }
+            }
+        },
+        "handler.createRestaurantRequest": {
+            "type": "object",
+            "properties": {
+                "average_check": {
+                    "type": "string",
+                    "example": "2500.00"
+                },
+                "average_waiting_time": {
+                    "type": "string",
+                    "example": "60"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Каравелла"
+                }
+            }
+        },
+        "handler.createRestaurantResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.createTableRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "description": "Position представляет расположение столика в зале (необязательно).",
+                    "type": "string",
+                    "example": "у окна, №3"
+                },
+                "seats_number": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "zone": {
+                    "description": "Zone представляет зону ресторана, в которой стоит столик: hall, terrace, vip или non_smoking\n(необязательно, по умолчанию hall).",
+                    "type": "string",
+                    "example": "terrace"
+                }
+            }
+        },
+        "handler.createTableResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handler.createUserRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Анна"
+                },
+                "restaurant_ids": {
+                    "description": "RestaurantIDs представляет ID ресторанов, закреплённых за менеджером или сотрудником.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "role": {
+                    "description": "Role представляет роль пользователя: admin, manager или staff.",
+                    "type": "string",
+                    "example": "manager"
+                }
+            }
+        },
+        "handler.deleteRestaurantResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.deleteTableResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.deleteUserResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.deleteWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.errResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "error": {
+                    "type": "string",
+                    "example": "missing required fields"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "invalid request"
+                }
+            }
+        },
+        "handler.getAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Date представляет дату посещения ресторана.",
+                    "type": "string",
+                    "example": "2022.06.16"
+                },
+                "duration": {
+                    "description": "Duration представляет длительность брони в минутах для компании такого размера.",
+                    "type": "integer",
+                    "example": 120
+                },
+                "people_number": {
+                    "description": "PeopleNumber представляет количество человек в компании.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "slots": {
+                    "description": "Slots представляет моменты начала брони в пределах графика работы ресторана.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AvailabilitySlot"
+                    }
+                }
+            }
+        },
+        "handler.getBookingResponse": {
+            "type": "object",
+            "properties": {
+                "booked_date": {
+                    "description": "BookedDate представляет дату посещения ресторана в рамках брони.",
+                    "type": "string",
+                    "example": "2022.06.16"
+                },
+                "booked_time_from": {
+                    "description": "BookedTimeFrom представляет время начала брони.",
+                    "type": "string",
+                    "example": "14:30"
+                },
+                "booked_time_to": {
+                    "description": "BookedTimeTo представляет время конца брони.",
+                    "type": "string",
+                    "example": "16:30"
+                },
+                "cancelled_at": {
+                    "description": "CancelledAt представляет момент отмены брони (nil, если бронь не отменена).",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "cancelled_by": {
+                    "description": "CancelledBy представляет того, кто отменил бронь: BookingCancelledByClient или BookingCancelledByRestaurant.",
+                    "type": "string",
+                    "example": "client"
+                },
+                "client_email": {
+                    "description": "ClientEmail представляет адрес электронной почты клиента для уведомлений о брони (пустая строка - не указан).",
+                    "type": "string",
+                    "example": "pavel@example.com"
+                },
+                "client_name": {
+                    "description": "ClientName представляет имя клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "client_phone": {
+                    "description": "ClientPhone представляет телефон клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "+79485722648"
+                },
+                "customer_id": {
+                    "description": "CustomerID представляет ID учётной записи гостя, оформившего бронь (nil, если бронь оформлена без учётной записи).",
+                    "type": "integer",
+                    "example": 5
+                },
+                "finished_at": {
+                    "description": "FinishedAt представляет момент, когда гости ушли или бронь была отмечена как неявка: с этого момента столики\nброни свободны (nil, если бронь ещё не завершена).",
+                    "type": "string",
+                    "example": "2022-06-16T16:10:00Z"
+                },
+                "guest_id": {
+                    "description": "GuestID представляет ID гостя, определённого по телефону в брони (nil, если в телефоне нет цифр).",
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "people_number": {
+                    "description": "PeopleNumber представляет количество человек, на которое оформлена бронь.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, в котором оформлена бронь.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "seated_at": {
+                    "description": "SeatedAt представляет момент, когда гости пришли в ресторан (nil, если гости ещё не приходили).",
+                    "type": "string",
+                    "example": "2022-06-16T14:35:00Z"
+                },
+                "status": {
+                    "description": "Status представляет статус брони: pending, confirmed, seated, completed, no_show или cancelled.",
+                    "type": "string",
+                    "example": "confirmed"
+                },
+                "table_ids": {
+                    "description": "TableIDs представляет ID столиков, забронированных в рамках брони (заполняется при получении брони по её ID).",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.getDurationPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.DurationPolicy"
+                }
+            }
+        },
+        "handler.getOpeningHoursResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OpeningHours"
+                    }
+                }
+            }
+        },
+        "handler.getReliabilityPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ReliabilityPolicy"
+                }
+            }
+        },
+        "handler.getRestaurantResponse": {
+            "type": "object",
+            "properties": {
+                "allocation_strategy": {
+                    "description": "AllocationStrategy представляет стратегию, по которой для брони выбираются столики ресторана.",
+                    "type": "string",
+                    "example": "best_fit"
+                },
+                "available_seats_number": {
+                    "description": "AvailableSeatsNumber представляет актуальное количество свободных мест.",
+                    "type": "integer",
+                    "example": 24
+                },
+                "available_zones": {
+                    "description": "AvailableZones представляет актуальное количество свободных мест по зонам ресторана.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ZoneAvailability"
+                    }
+                },
+                "average_check": {
+                    "description": "AverageCheck представляет средний чек на блюдо в ресторане.",
+                    "type": "number",
+                    "example": 2500
+                },
+                "average_waiting_time": {
+                    "description": "AverageWaitingTime представляет среднее время ожидания заказа в минутах.",
+                    "type": "integer",
+                    "example": 60
+                },
+                "duration_policy": {
+                    "description": "DurationPolicy представляет правила, по которым определяется длительность брони в ресторане.",
+                    "$ref": "#/definitions/model.DurationPolicy"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Каравелла"
+                },
+                "opening_hours": {
+                    "description": "OpeningHours представляет недельный график работы ресторана. Если он не задан, ресторан работает\nпо графику DefaultOpeningHours.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OpeningHours"
+                    }
+                }
+            }
+        },
+        "handler.getTableResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "joinable_with": {
+                    "description": "JoinableWith представляет ID столиков, которые можно сдвинуть с этим столиком, чтобы посадить за них\nодну компанию.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "position": {
+                    "description": "Position представляет расположение столика в зале (например, номер на схеме зала).",
+                    "type": "string",
+                    "example": "у окна, №3"
+                },
+                "restaurant_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "seats_number": {
+                    "description": "SeatsNumber представляет вместимость столика.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "zone": {
+                    "description": "Zone представляет зону ресторана, в которой стоит столик: hall, terrace, vip или non_smoking.",
+                    "type": "string",
+                    "example": "terrace"
+                }
+            }
+        },
+        "handler.getUserResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt представляет момент создания пользователя.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Анна"
+                },
+                "restaurant_ids": {
+                    "description": "RestaurantIDs представляет ID ресторанов, закреплённых за менеджером или сотрудником.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "role": {
+                    "description": "Role представляет роль пользователя: admin, manager или staff.",
+                    "type": "string",
+                    "example": "manager"
+                }
+            }
+        },
+        "handler.getWaitlistEntryResponse": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "description": "BookingID представляет ID брони, оформленной по предложению (nil, если бронь не оформлена).",
+                    "type": "integer",
+                    "example": 12
+                },
+                "client_name": {
+                    "description": "ClientName представляет имя гостя.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "client_phone": {
+                    "description": "ClientPhone представляет телефон гостя.",
+                    "type": "string",
+                    "example": "+79485722648"
+                },
+                "created_at": {
+                    "description": "CreatedAt представляет момент, когда гость встал в лист ожидания.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "desired_date": {
+                    "description": "DesiredDate представляет желаемую дату посещения ресторана.",
+                    "type": "string",
+                    "example": "2022.06.16"
+                },
+                "desired_time": {
+                    "description": "DesiredTime представляет желаемое время посещения ресторана.",
+                    "type": "string",
+                    "example": "19:00"
+                },
+                "hold_id": {
+                    "description": "HoldID представляет ID удержания столиков, предложенных гостю (nil, если мест не предлагали).",
+                    "type": "integer",
+                    "example": 7
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "offer_expires_at": {
+                    "description": "OfferExpiresAt представляет момент, до которого гость может принять предложение (nil, если мест не предлагали).",
+                    "type": "string",
+                    "example": "2022-06-15T12:15:00Z"
+                },
+                "people_number": {
+                    "description": "PeopleNumber представляет количество человек в компании.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, в листе ожидания которого стоит гость.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "description": "Status представляет состояние записи: waiting, offered, booked, expired или cancelled.",
+                    "type": "string",
+                    "example": "offered"
+                }
+            }
+        },
+        "handler.getWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt представляет момент создания вебхука.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "events": {
+                    "description": "Events представляет события, на которые подписан вебхук (пустой список - все события).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "booking.created",
+                        "booking.cancelled"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, о бронях которого сообщает вебхук.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "url": {
+                    "description": "URL представляет адрес, на который POST-запросом отправляются события.",
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/bookings"
+                }
+            }
+        },
+        "handler.guestResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt представляет момент первой брони гостя.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "description": "Name представляет имя, указанное гостем в последней брони.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "phone": {
+                    "description": "Phone представляет телефон гостя в формате E.164.",
+                    "type": "string",
+                    "example": "+79485722648"
+                },
+                "restaurants": {
+                    "description": "Restaurants представляет статистику посещений гостем ресторанов, доступных пользователю API.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GuestRestaurantStats"
+                    }
+                }
+            }
+        },
+        "handler.holdResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt представляет момент, после которого столики перестают удерживаться.",
+                    "type": "string",
+                    "example": "2022-06-15T12:05:00Z"
+                },
+                "held_date": {
+                    "description": "HeldDate представляет дату посещения ресторана.",
+                    "type": "string",
+                    "example": "2022.06.16"
+                },
+                "held_time_from": {
+                    "description": "HeldTimeFrom представляет время начала будущей брони.",
+                    "type": "string",
+                    "example": "14:30"
+                },
+                "held_time_to": {
+                    "description": "HeldTimeTo представляет время конца будущей брони.",
+                    "type": "string",
+                    "example": "16:30"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "people_number": {
+                    "description": "PeopleNumber представляет количество человек, для которых удерживаются столики.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, в котором удерживаются столики.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "table_ids": {
+                    "description": "TableIDs представляет ID удерживаемых столиков.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "token": {
+                    "description": "Token представляет секретный токен удержания: без него удержание нельзя получить, подтвердить или снять.\nТокен известен только при создании удержания, а хранится его хеш (TokenHash).",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                }
+            }
+        },
+        "handler.joinTablesResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.joinWaitlistRequest": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "description": "ClientName имя гостя.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "client_phone": {
+                    "description": "ClientPhone телефон гостя в международном формате (российский номер можно записать и через 8).",
+                    "type": "string",
+                    "example": "+79876545654"
+                },
+                "desired_datetime": {
+                    "description": "DesiredDatetime представляет желаемые дату и время посещения ресторана.",
+                    "type": "string",
+                    "example": "2022.06.16 17:03"
+                },
+                "people_number": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handler.joinWaitlistResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "handler.leaveWaitlistResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.listAvailableRestaurantsResponse": {
+            "type": "object",
+            "properties": {
+                "alternatives": {
+                    "description": "Alternatives представляет варианты для гостя, если на желаемое время не нашлось ни одного ресторана.",
+                    "$ref": "#/definitions/model.Alternatives"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Restaurant"
+                    }
+                }
+            }
+        },
+        "handler.listBookingsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Booking"
+                    }
+                }
+            }
+        },
+        "handler.listGuestBookingsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Booking"
+                    }
+                }
+            }
+        },
+        "handler.listJoinableTablesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Table"
+                    }
+                }
+            }
+        },
+        "handler.listRestaurantsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Restaurant"
+                    }
+                }
+            }
+        },
+        "handler.listTablesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Table"
+                    }
+                }
+            }
+        },
+        "handler.listUsersResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                }
+            }
+        },
+        "handler.listWaitlistResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WaitlistEntry"
+                    }
+                }
+            }
+        },
+        "handler.listWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDelivery"
+                    }
+                }
+            }
+        },
+        "handler.listWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Webhook"
+                    }
+                }
+            }
+        },
+        "handler.releaseHoldResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.setDurationPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.setOpeningHoursResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.setReliabilityPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.updateBookingResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.updateRestaurantResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.updateTableResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.userWithAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string",
+                    "example": "rtb_4f1c..."
+                },
+                "created_at": {
+                    "description": "CreatedAt представляет момент создания пользователя.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Анна"
+                },
+                "restaurant_ids": {
+                    "description": "RestaurantIDs представляет ID ресторанов, закреплённых за менеджером или сотрудником.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "role": {
+                    "description": "Role представляет роль пользователя: admin, manager или staff.",
+                    "type": "string",
+                    "example": "manager"
+                }
+            }
+        },
+        "handler.webhookWithSecretResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt представляет момент создания вебхука.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "events": {
+                    "description": "Events представляет события, на которые подписан вебхук (пустой список - все события).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "booking.created",
+                        "booking.cancelled"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, о бронях которого сообщает вебхук.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "9b2e..."
+                },
+                "url": {
+                    "description": "URL представляет адрес, на который POST-запросом отправляются события.",
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/bookings"
+                }
+            }
+        },
+        "model.AlternativeRestaurant": {
+            "type": "object",
+            "properties": {
+                "datetime": {
+                    "description": "DateTime представляет дату и время посещения ресторана.",
+                    "type": "string",
+                    "example": "2022.06.16 18:30"
+                },
+                "restaurant": {
+                    "description": "Restaurant представляет ресторан (с количеством свободных мест на предлагаемое время).",
+                    "$ref": "#/definitions/model.Restaurant"
+                }
+            }
+        },
+        "model.AlternativeTime": {
+            "type": "object",
+            "properties": {
+                "datetime": {
+                    "description": "DateTime представляет дату и время посещения ресторана.",
+                    "type": "string",
+                    "example": "2022.06.16 18:30"
+                },
+                "restaurants_number": {
+                    "description": "RestaurantsNumber представляет количество ресторанов, в которых на это время можно рассадить компанию.",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.Alternatives": {
+            "type": "object",
+            "properties": {
+                "restaurants": {
+                    "description": "Restaurants представляет рестораны, в которых компанию можно рассадить в ближайшее к желаемому время.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AlternativeRestaurant"
+                    }
+                },
+                "times": {
+                    "description": "Times представляет ближайшие к желаемому моменты начала брони (раньше или позже в тот же день и в следующие дни),\nна которые компанию можно рассадить хотя бы в одном ресторане.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AlternativeTime"
+                    }
+                }
+            }
+        },
+        "model.AvailabilitySlot": {
+            "type": "object",
+            "properties": {
+                "available_seats_number": {
+                    "description": "AvailableSeatsNumber представляет количество мест за столиками, свободными на всё время брони.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "bookable": {
+                    "description": "Bookable показывает, можно ли рассадить компанию за свободными столиками (с учётом того, какие столики\nможно сдвинуть).",
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "description": "Time представляет время начала брони.",
+                    "type": "string",
+                    "example": "18:15"
+                }
+            }
+        },
+        "model.Booking": {
+            "type": "object",
+            "properties": {
+                "booked_date": {
+                    "description": "BookedDate представляет дату посещения ресторана в рамках брони.",
+                    "type": "string",
+                    "example": "2022.06.16"
+                },
+                "booked_time_from": {
+                    "description": "BookedTimeFrom представляет время начала брони.",
+                    "type": "string",
+                    "example": "14:30"
+                },
+                "booked_time_to": {
+                    "description": "BookedTimeTo представляет время конца брони.",
+                    "type": "string",
+                    "example": "16:30"
+                },
+                "cancelled_at": {
+                    "description": "CancelledAt представляет момент отмены брони (nil, если бронь не отменена).",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "cancelled_by": {
+                    "description": "CancelledBy представляет того, кто отменил бронь: BookingCancelledByClient или BookingCancelledByRestaurant.",
+                    "type": "string",
+                    "example": "client"
+                },
+                "client_email": {
+                    "description": "ClientEmail представляет адрес электронной почты клиента для уведомлений о брони (пустая строка - не указан).",
+                    "type": "string",
+                    "example": "pavel@example.com"
+                },
+                "client_name": {
+                    "description": "ClientName представляет имя клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "Павел"
+                },
+                "client_phone": {
+                    "description": "ClientPhone представляет телефон клиента, оформляющего бронь.",
+                    "type": "string",
+                    "example": "+79485722648"
+                },
+                "customer_id": {
+                    "description": "CustomerID представляет ID учётной записи гостя, оформившего бронь (nil, если бронь оформлена без учётной записи).",
+                    "type": "integer",
+                    "example": 5
+                },
+                "finished_at": {
+                    "description": "FinishedAt представляет момент, когда гости ушли или бронь была отмечена как неявка: с этого момента столики\nброни свободны (nil, если бронь ещё не завершена).",
+                    "type": "string",
+                    "example": "2022-06-16T16:10:00Z"
+                },
+                "guest_id": {
+                    "description": "GuestID представляет ID гостя, определённого по телефону в брони (nil, если в телефоне нет цифр).",
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "people_number": {
+                    "description": "PeopleNumber представляет количество человек, на которое оформлена бронь.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, в котором оформлена бронь.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "seated_at": {
+                    "description": "SeatedAt представляет момент, когда гости пришли в ресторан (nil, если гости ещё не приходили).",
+                    "type": "string",
+                    "example": "2022-06-16T14:35:00Z"
+                },
+                "status": {
+                    "description": "Status представляет статус брони: pending, confirmed, seated, completed, no_show или cancelled.",
+                    "type": "string",
+                    "example": "confirmed"
+                },
+                "table_ids": {
+                    "description": "TableIDs представляет ID столиков, забронированных в рамках брони (заполняется при получении брони по её ID).",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.CreateWebhookData": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Events представляет события, на которые подписывается вебхук (пустой список - все события).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "booking.created",
+                        "booking.cancelled"
+                    ]
+                },
+                "url": {
+                    "description": "URL представляет адрес, на который отправляются события (http или https).",
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/bookings"
+                }
+            }
+        },
+        "model.DurationPolicy": {
             "type": "object",
             "properties": {
-                "seats_number": {
+                "default_duration": {
+                    "description": "DefaultDuration представляет длительность брони в минутах, если ни одно из правил не подошло.",
                     "type": "integer",
-                    "example": 3
+                    "example": 120
+                },
+                "rules": {
+                    "description": "Rules представляет правила для компаний разного размера: применяется правило с наибольшим MinPeople,\nне превышающим количество человек.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DurationRule"
+                    }
                 }
             }
         },
-        "handler.createTableResponse": {
+        "model.DurationRule": {
             "type": "object",
             "properties": {
-                "id": {
+                "duration": {
+                    "description": "Duration представляет длительность брони в минутах.",
                     "type": "integer",
-                    "example": 2
+                    "example": 180
+                },
+                "min_people": {
+                    "description": "MinPeople представляет минимальное количество человек, с которого действует правило.",
+                    "type": "integer",
+                    "example": 8
                 }
             }
         },
-        "handler.deleteRestaurantResponse": {
+        "model.GuestRestaurantStats": {
             "type": "object",
             "properties": {
-                "status": {
+                "average_party_size": {
+                    "description": "AveragePartySize представляет среднее количество человек в бронях гостя, кроме отменённых и неявок.",
+                    "type": "number",
+                    "example": 3.5
+                },
+                "bookings_number": {
+                    "description": "BookingsNumber представляет количество всех броней гостя в ресторане (в том числе отменённых и будущих).",
+                    "type": "integer",
+                    "example": 5
+                },
+                "cancellations_number": {
+                    "description": "CancellationsNumber представляет количество отменённых броней.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_visit_at": {
+                    "description": "LastVisitAt представляет дату и время последнего посещения ресторана (nil, если гость ещё не приходил).",
                     "type": "string",
-                    "example": "ok"
+                    "example": "2022-06-10T19:00:00Z"
+                },
+                "no_shows_number": {
+                    "description": "NoShowsNumber представляет количество броней, по которым гость не пришёл.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "restaurant_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "visits_number": {
+                    "description": "VisitsNumber представляет количество броней, по которым гость пришёл (или время которых уже наступило, если\nприход гостя не отмечался).",
+                    "type": "integer",
+                    "example": 3
                 }
             }
         },
-        "handler.deleteTableResponse": {
+        "model.OpeningHours": {
             "type": "object",
             "properties": {
-                "status": {
-                    "type": "string"
+                "closes_at": {
+                    "description": "ClosesAt представляет время закрытия ресторана. Если оно не позже времени открытия, ресторан закрывается\nна следующий день.",
+                    "type": "string",
+                    "example": "23:00"
+                },
+                "last_seating": {
+                    "description": "LastSeating представляет время, на которое можно оформить последнюю бронь в рамках смены (в том числе\nпосле полуночи, если ресторан закрывается на следующий день).",
+                    "type": "string",
+                    "example": "21:00"
+                },
+                "opens_at": {
+                    "description": "OpensAt представляет время открытия ресторана.",
+                    "type": "string",
+                    "example": "09:00"
+                },
+                "weekday": {
+                    "description": "Weekday представляет день недели: 0 - воскресенье, 1 - понедельник, ..., 6 - суббота.",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "handler.errResponse": {
+        "model.ReliabilityPolicy": {
             "type": "object",
             "properties": {
-                "code": {
+                "block_after": {
+                    "description": "BlockAfter представляет количество неявок, начиная с которого гостю блокируется онлайн-бронирование\n(0 - бронирование не блокируется).",
                     "type": "integer",
-                    "example": 400
+                    "example": 3
                 },
-                "error": {
-                    "type": "string",
-                    "example": "missing required fields"
+                "block_days": {
+                    "description": "BlockDays представляет количество дней после последней неявки, на которое блокируется онлайн-бронирование.",
+                    "type": "integer",
+                    "example": 30
                 },
-                "status": {
-                    "type": "string",
-                    "example": "invalid request"
+                "confirmation_after": {
+                    "description": "ConfirmationAfter представляет количество неявок, начиная с которого брони гостя ждут подтверждения рестораном\n(0 - подтверждение не требуется).",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "handler.getRestaurantResponse": {
+        "model.Restaurant": {
             "type": "object",
             "properties": {
+                "allocation_strategy": {
+                    "description": "AllocationStrategy представляет стратегию, по которой для брони выбираются столики ресторана.",
+                    "type": "string",
+                    "example": "best_fit"
+                },
                 "available_seats_number": {
                     "description": "AvailableSeatsNumber представляет актуальное количество свободных мест.",
                     "type": "integer",
                     "example": 24
                 },
+                "available_zones": {
+                    "description": "AvailableZones представляет актуальное количество свободных мест по зонам ресторана.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ZoneAvailability"
+                    }
+                },
                 "average_check": {
                     "description": "AverageCheck представляет средний чек на блюдо в ресторане.",
                     "type": "number",
@@ -681,6 +4605,10 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 60
                 },
+                "duration_policy": {
+                    "description": "DurationPolicy представляет правила, по которым определяется длительность брони в ресторане.",
+                    "$ref": "#/definitions/model.DurationPolicy"
+                },
                 "id": {
                     "type": "integer",
                     "example": 3
@@ -688,184 +4616,358 @@ const docTemplate = `{
                 "name": {
                     "type": "string",
                     "example": "Каравелла"
+                },
+                "opening_hours": {
+                    "description": "OpeningHours представляет недельный график работы ресторана. Если он не задан, ресторан работает\nпо графику DefaultOpeningHours.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OpeningHours"
+                    }
                 }
             }
         },
-        "handler.getTableResponse": {
+        "model.RestaurantEvent": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 3
+                "booking": {
+                    "description": "Booking представляет бронь сразу после события (только для событий с бронями).",
+                    "$ref": "#/definitions/model.Booking"
+                },
+                "event": {
+                    "description": "Event представляет событие: RestaurantEventBookingCreated, RestaurantEventBookingUpdated,\nRestaurantEventBookingCancelled или RestaurantEventTableChanged.",
+                    "type": "string",
+                    "example": "booking.created"
+                },
+                "occurred_at": {
+                    "description": "OccurredAt представляет момент события.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
                 },
                 "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, в котором произошло событие.",
                     "type": "integer",
                     "example": 2
                 },
-                "seats_number": {
-                    "description": "SeatsNumber представляет вместимость столика.",
+                "table": {
+                    "description": "Table представляет столик сразу после события (nil, если столик удалён).",
+                    "$ref": "#/definitions/model.Table"
+                },
+                "table_id": {
+                    "description": "TableID представляет ID столика (только для RestaurantEventTableChanged).",
                     "type": "integer",
-                    "example": 4
+                    "example": 3
                 }
             }
         },
-        "handler.listBookingsResponse": {
+        "model.SetOpeningHoursData": {
             "type": "object",
             "properties": {
-                "data": {
+                "opening_hours": {
+                    "description": "OpeningHours представляет смены ресторана. Пустой список означает график работы по умолчанию.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Booking"
+                        "$ref": "#/definitions/model.OpeningHours"
                     }
                 }
             }
         },
-        "handler.listRestaurantsResponse": {
+        "model.Table": {
             "type": "object",
             "properties": {
-                "data": {
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "joinable_with": {
+                    "description": "JoinableWith представляет ID столиков, которые можно сдвинуть с этим столиком, чтобы посадить за них\nодну компанию.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Restaurant"
+                        "type": "integer"
                     }
+                },
+                "position": {
+                    "description": "Position представляет расположение столика в зале (например, номер на схеме зала).",
+                    "type": "string",
+                    "example": "у окна, №3"
+                },
+                "restaurant_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "seats_number": {
+                    "description": "SeatsNumber представляет вместимость столика.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "zone": {
+                    "description": "Zone представляет зону ресторана, в которой стоит столик: hall, terrace, vip или non_smoking.",
+                    "type": "string",
+                    "example": "terrace"
                 }
             }
         },
-        "handler.listTablesResponse": {
+        "model.UpdateBookingData": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Table"
-                    }
+                "desired_datetime": {
+                    "description": "DesiredDatetime представляет новые дату и время посещения ресторана (строка вида \"2022.06.16 17:03\").",
+                    "type": "string",
+                    "example": "2022.06.16 19:00"
+                },
+                "people_number": {
+                    "type": "integer",
+                    "example": 4
                 }
             }
         },
-        "handler.updateRestaurantResponse": {
+        "model.UpdateRestaurantData": {
             "type": "object",
             "properties": {
-                "status": {
+                "allocation_strategy": {
+                    "description": "AllocationStrategy представляет стратегию выбора столиков: best_fit или smallest_first.",
                     "type": "string",
-                    "example": "ok"
+                    "example": "best_fit"
+                },
+                "average_check": {
+                    "type": "string",
+                    "example": "2500.00"
+                },
+                "average_waiting_time": {
+                    "type": "string",
+                    "example": "60"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Каравелла"
                 }
             }
         },
-        "handler.updateTableResponse": {
+        "model.UpdateTableData": {
             "type": "object",
             "properties": {
-                "status": {
+                "position": {
                     "type": "string",
-                    "example": "ok"
+                    "example": "у окна, №3"
+                },
+                "seats_number": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "terrace"
                 }
             }
         },
-        "model.Booking": {
+        "model.User": {
             "type": "object",
             "properties": {
-                "booked_date": {
-                    "description": "BookedDate представляет дату посещения ресторана в рамках брони.",
+                "created_at": {
+                    "description": "CreatedAt представляет момент создания пользователя.",
                     "type": "string",
-                    "example": "2022.06.16"
+                    "example": "2022-06-15T12:00:00Z"
                 },
-                "booked_time_from": {
-                    "description": "BookedTimeFrom представляет время начала брони.",
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
                     "type": "string",
-                    "example": "14:30"
+                    "example": "Анна"
                 },
-                "booked_time_to": {
-                    "description": "BookedTimeTo представляет время конца брони.",
+                "restaurant_ids": {
+                    "description": "RestaurantIDs представляет ID ресторанов, закреплённых за менеджером или сотрудником.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "role": {
+                    "description": "Role представляет роль пользователя: admin, manager или staff.",
                     "type": "string",
-                    "example": "16:30"
+                    "example": "manager"
+                }
+            }
+        },
+        "model.WaitlistEntry": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "description": "BookingID представляет ID брони, оформленной по предложению (nil, если бронь не оформлена).",
+                    "type": "integer",
+                    "example": 12
                 },
                 "client_name": {
-                    "description": "ClientName представляет имя клиента, оформляющего бронь.",
+                    "description": "ClientName представляет имя гостя.",
                     "type": "string",
                     "example": "Павел"
                 },
                 "client_phone": {
-                    "description": "ClientPhone представляет телефон клиента, оформляющего бронь.",
+                    "description": "ClientPhone представляет телефон гостя.",
                     "type": "string",
-                    "example": "89485722648"
+                    "example": "+79485722648"
                 },
-                "id": {
+                "created_at": {
+                    "description": "CreatedAt представляет момент, когда гость встал в лист ожидания.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "desired_date": {
+                    "description": "DesiredDate представляет желаемую дату посещения ресторана.",
+                    "type": "string",
+                    "example": "2022.06.16"
+                },
+                "desired_time": {
+                    "description": "DesiredTime представляет желаемое время посещения ресторана.",
+                    "type": "string",
+                    "example": "19:00"
+                },
+                "hold_id": {
+                    "description": "HoldID представляет ID удержания столиков, предложенных гостю (nil, если мест не предлагали).",
                     "type": "integer",
-                    "example": 3
-                }
-            }
-        },
-        "model.Restaurant": {
-            "type": "object",
-            "properties": {
-                "available_seats_number": {
-                    "description": "AvailableSeatsNumber представляет актуальное количество свободных мест.",
+                    "example": 7
+                },
+                "id": {
                     "type": "integer",
-                    "example": 24
+                    "example": 5
                 },
-                "average_check": {
-                    "description": "AverageCheck представляет средний чек на блюдо в ресторане.",
-                    "type": "number",
-                    "example": 2500
+                "offer_expires_at": {
+                    "description": "OfferExpiresAt представляет момент, до которого гость может принять предложение (nil, если мест не предлагали).",
+                    "type": "string",
+                    "example": "2022-06-15T12:15:00Z"
                 },
-                "average_waiting_time": {
-                    "description": "AverageWaitingTime представляет среднее время ожидания заказа в минутах.",
+                "people_number": {
+                    "description": "PeopleNumber представляет количество человек в компании.",
                     "type": "integer",
-                    "example": 60
+                    "example": 4
                 },
-                "id": {
+                "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, в листе ожидания которого стоит гость.",
                     "type": "integer",
-                    "example": 3
+                    "example": 2
                 },
-                "name": {
+                "status": {
+                    "description": "Status представляет состояние записи: waiting, offered, booked, expired или cancelled.",
                     "type": "string",
-                    "example": "Каравелла"
+                    "example": "offered"
                 }
             }
         },
-        "model.Table": {
+        "model.Webhook": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "description": "CreatedAt представляет момент создания вебхука.",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "events": {
+                    "description": "Events представляет события, на которые подписан вебхук (пустой список - все события).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "booking.created",
+                        "booking.cancelled"
+                    ]
+                },
                 "id": {
                     "type": "integer",
-                    "example": 3
+                    "example": 1
                 },
                 "restaurant_id": {
+                    "description": "RestaurantID представляет ID ресторана, о бронях которого сообщает вебхук.",
                     "type": "integer",
                     "example": 2
                 },
-                "seats_number": {
-                    "description": "SeatsNumber представляет вместимость столика.",
-                    "type": "integer",
-                    "example": 4
+                "url": {
+                    "description": "URL представляет адрес, на который POST-запросом отправляются события.",
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/bookings"
                 }
             }
         },
-        "model.UpdateRestaurantData": {
+        "model.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "average_check": {
+                "attempts": {
+                    "description": "Attempts представляет количество начатых попыток доставки.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "description": "CreatedAt представляет момент, когда доставка была запланирована.",
                     "type": "string",
-                    "example": "2500.00"
+                    "example": "2022-06-15T12:00:00Z"
                 },
-                "average_waiting_time": {
+                "delivered_at": {
+                    "description": "DeliveredAt представляет момент, когда получатель принял событие (nil, если ещё не принял).",
                     "type": "string",
-                    "example": "60"
+                    "example": "2022-06-15T12:00:01Z"
                 },
-                "name": {
+                "event": {
                     "type": "string",
-                    "example": "Каравелла"
+                    "example": "booking.created"
+                },
+                "event_id": {
+                    "description": "EventID и Event представляют ID и вид доставляемого события.",
+                    "type": "integer",
+                    "example": 42
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "last_error": {
+                    "description": "LastError представляет ошибку последней неудачной попытки (пустая строка - ошибок не было).",
+                    "type": "string",
+                    "example": ""
+                },
+                "last_status_code": {
+                    "description": "LastStatusCode представляет код ответа получателя на последнюю попытку (0 - ответа не было).",
+                    "type": "integer",
+                    "example": 200
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt представляет момент, начиная с которого событие можно доставить (или повторить попытку).",
+                    "type": "string",
+                    "example": "2022-06-15T12:00:00Z"
+                },
+                "status": {
+                    "description": "Status представляет статус доставки: WebhookDeliveryPending, WebhookDeliveryDelivered или WebhookDeliveryFailed.",
+                    "type": "string",
+                    "example": "delivered"
+                },
+                "webhook_id": {
+                    "description": "WebhookID представляет ID вебхука, которому доставляется событие.",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "model.UpdateTableData": {
+        "model.ZoneAvailability": {
             "type": "object",
             "properties": {
-                "seats_number": {
+                "available_seats_number": {
+                    "description": "AvailableSeatsNumber представляет актуальное количество свободных мест в зоне.",
                     "type": "integer",
-                    "example": 4
+                    "example": 8
+                },
+                "zone": {
+                    "type": "string",
+                    "example": "terrace"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Ключ API пользователя в виде \"Bearer \u003cключ\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/qiangxue/go-env"
@@ -12,28 +14,140 @@ import (
 
 const envVarsPrefix = "API_"
 
+const (
+	// StoreDriverPostgres означает хранение данных в PostgreSQL.
+	StoreDriverPostgres = "postgres"
+	// StoreDriverMemory означает хранение данных в оперативной памяти (без PostgreSQL).
+	StoreDriverMemory = "memory"
+)
+
+const (
+	// NotifierLog означает запись уведомлений гостям в лог сервиса.
+	NotifierLog = "log"
+	// NotifierFile означает запись уведомлений гостям в файл NotifyFilePath.
+	NotifierFile = "file"
+	// NotifierSMTP означает отправку уведомлений гостям по электронной почте через SMTP-сервер.
+	NotifierSMTP = "smtp"
+	// NotifierSMS означает отправку уведомлений гостям по SMS через HTTP-шлюз SMS-провайдера.
+	NotifierSMS = "sms"
+)
+
 // Config содержит настройки сервиса.
 type Config struct {
 	// BindAddr представляет адрес сервера.
 	BindAddr string `yaml:"bind_addr" env:"BIND_ADDR"`
+	// StoreDriver представляет используемую реализацию слоя хранения данных: StoreDriverPostgres или StoreDriverMemory.
+	StoreDriver string `yaml:"store_driver" env:"STORE_DRIVER"`
 	// DSN (data source name) является строкой подключения к базе данных.
 	DSN string `yaml:"dsn" env:"DSN,secret"`
 	// LogLevel представляет уровень логгирования.
 	LogLevel string `yaml:"log_level" env:"LOG_LEVEL"`
+	// AdminAPIKey представляет ключ API администратора платформы. С ним создаются пользователи API администрирования
+	// (менеджеры и сотрудники ресторанов). Если ключ не задан, войти можно только с ключами созданных пользователей.
+	AdminAPIKey string `yaml:"admin_api_key" env:"ADMIN_API_KEY,secret"`
+	// Notifiers представляет перечисленные через запятую способы доставки уведомлений гостям о их бронях:
+	// NotifierLog, NotifierFile, NotifierSMTP и NotifierSMS (по умолчанию NotifierLog). Если пусто, уведомления
+	// не отправляются.
+	Notifiers string `yaml:"notifiers" env:"NOTIFIERS"`
+	// NotifyFilePath представляет путь к файлу, в который записываются уведомления (для NotifierFile).
+	NotifyFilePath string `yaml:"notify_file_path" env:"NOTIFY_FILE_PATH"`
+	// SMTPAddr представляет адрес SMTP-сервера в виде "host:port" (для NotifierSMTP).
+	SMTPAddr string `yaml:"smtp_addr" env:"SMTP_ADDR"`
+	// SMTPUsername и SMTPPassword представляют учётные данные на SMTP-сервере (если имя пусто, вход не выполняется).
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD,secret"`
+	// SMTPFrom представляет адрес отправителя писем (для NotifierSMTP).
+	SMTPFrom string `yaml:"smtp_from" env:"SMTP_FROM"`
+	// SMSGatewayURL представляет адрес HTTP-шлюза SMS-провайдера (для NotifierSMS).
+	SMSGatewayURL string `yaml:"sms_gateway_url" env:"SMS_GATEWAY_URL"`
+	// SMSGatewayToken представляет токен доступа к HTTP-шлюзу SMS-провайдера.
+	SMSGatewayToken string `yaml:"sms_gateway_token" env:"SMS_GATEWAY_TOKEN,secret"`
+	// SMSSender представляет имя или номер отправителя SMS.
+	SMSSender string `yaml:"sms_sender" env:"SMS_SENDER"`
+	// ReminderHoursBefore представляет, за сколько часов до начала брони гостю отправляется напоминание
+	// (0 - напоминания не отправляются).
+	ReminderHoursBefore int `yaml:"reminder_hours_before" env:"REMINDER_HOURS_BEFORE"`
+	// BookingLinkKey представляет ключ, которым подписываются ссылки гостей на их брони. При смене ключа все выданные
+	// ссылки перестают действовать.
+	BookingLinkKey string `yaml:"booking_link_key" env:"BOOKING_LINK_KEY,secret"`
+	// WebsiteDir представляет папку сайта со статическими файлами и шаблонами страниц (по умолчанию "website"
+	// относительно рабочей папки сервиса).
+	WebsiteDir string `yaml:"website_dir" env:"WEBSITE_DIR"`
+}
+
+// NotifierList возвращает список способов доставки уведомлений гостям из Notifiers.
+func (c Config) NotifierList() []string {
+	var notifiers []string
+	for _, notifier := range strings.Split(c.Notifiers, ",") {
+		if notifier = strings.TrimSpace(notifier); notifier != "" {
+			notifiers = append(notifiers, notifier)
+		}
+	}
+	return notifiers
+}
+
+// HasNotifier проверяет, выбран ли способ доставки уведомлений notifier.
+func (c Config) HasNotifier(notifier string) bool {
+	for _, n := range c.NotifierList() {
+		if n == notifier {
+			return true
+		}
+	}
+	return false
 }
 
 // Validate проверяет, достаточно ли настроек для запуска сервиса.
 func (c Config) Validate() error {
+	// строка подключения к БД нужна только при хранении данных в PostgreSQL
+	dsnRules := make([]validation.Rule, 0, 1)
+	if c.StoreDriver == StoreDriverPostgres {
+		dsnRules = append(dsnRules, validation.Required)
+	}
+
+	// настройки способа доставки уведомлений нужны, только если он выбран
+	requiredFor := func(notifier string) []validation.Rule {
+		if c.HasNotifier(notifier) {
+			return []validation.Rule{validation.Required}
+		}
+		return nil
+	}
+
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.BindAddr, validation.Required),
-		validation.Field(&c.DSN, validation.Required),
+		validation.Field(&c.StoreDriver, validation.Required, validation.In(StoreDriverPostgres, StoreDriverMemory)),
+		validation.Field(&c.DSN, dsnRules...),
 		validation.Field(&c.LogLevel, validation.Required),
+		validation.Field(&c.WebsiteDir, validation.Required),
+		validation.Field(&c.Notifiers, validation.By(validateNotifiers)),
+		validation.Field(&c.NotifyFilePath, requiredFor(NotifierFile)...),
+		validation.Field(&c.SMTPAddr, requiredFor(NotifierSMTP)...),
+		validation.Field(&c.SMTPFrom, requiredFor(NotifierSMTP)...),
+		validation.Field(&c.SMSGatewayURL, requiredFor(NotifierSMS)...),
+		validation.Field(&c.ReminderHoursBefore, validation.Min(0)),
+		// короткий ключ позволил бы подобрать подпись ссылки на чужую бронь
+		validation.Field(&c.BookingLinkKey, validation.Required, validation.Length(16, 0)),
 	)
 }
 
+// validateNotifiers проверяет, что в списке способов доставки уведомлений нет неизвестных.
+func validateNotifiers(value interface{}) error {
+	for _, notifier := range (Config{Notifiers: value.(string)}).NotifierList() {
+		switch notifier {
+		case NotifierLog, NotifierFile, NotifierSMTP, NotifierSMS:
+		default:
+			return fmt.Errorf("unknown notifier %q", notifier)
+		}
+	}
+	return nil
+}
+
 // Load загружает настройки сервиса из переменных среды и, если их не окажется, из yml-файла.
 func Load(ymlConfigPath string) (*Config, error) {
-	cfg := Config{}
+	// по умолчанию данные хранятся в PostgreSQL, уведомления гостям записываются в лог, напоминание о брони
+	// отправляется за сутки, а сайт загружается из папки website
+	cfg := Config{
+		StoreDriver: StoreDriverPostgres, Notifiers: NotifierLog, ReminderHoursBefore: 24, WebsiteDir: "website",
+	}
 
 	// загрузка конфигурационных значений из yml-файла
 	cfgFile, err := os.Open(ymlConfigPath)
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+)
+
+const (
+	currentUserCtxKey = "current_user"
+	// guestsAllowedCtxKey отмечает маршруты, которыми пользуются гости на сайте: они доступны без аутентификации.
+	guestsAllowedCtxKey = "guests_allowed"
+)
+
+// authenticate используется для аутентификации пользователя API по ключу API, переданному в заголовке
+// "Authorization: Bearer <ключ>" (или "X-API-Key: <ключ>"). Пользователь сохраняется в контексте запроса.
+// Запрос без ключа пропускается дальше без пользователя: доступ к маршрутам проверяется отдельно.
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey := apiKeyFromRequest(r)
+		if apiKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := h.service.UserService.Authenticate(apiKey)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidAPIKey) {
+				renderUnauthorized(w, r, err)
+				return
+			}
+			_ = render.Render(w, r, errServiceFailure(err))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), currentUserCtxKey, user)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// apiKeyFromRequest возвращает ключ API из заголовков запроса (пустую строку, если ключ не передан).
+func apiKeyFromRequest(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		const bearerPrefix = "Bearer "
+		if len(header) > len(bearerPrefix) && strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(header[len(bearerPrefix):])
+		}
+		return ""
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// requireRole используется для ограничения доступа к маршрутам: пропускает только аутентифицированных
+// пользователей с одной из ролей roles.
+func (h *Handler) requireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := currentUser(r)
+			if user == nil {
+				renderUnauthorized(w, r, ErrUnauthorized)
+				return
+			}
+
+			for _, role := range roles {
+				if user.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			_ = render.Render(w, r, errForbidden(ErrForbidden))
+		})
+	}
+}
+
+// requireUser пропускает к маршруту любого аутентифицированного пользователя API.
+func (h *Handler) requireUser(next http.Handler) http.Handler {
+	return h.requireRole(model.UserRoleAdmin, model.UserRoleManager, model.UserRoleStaff)(next)
+}
+
+// allowGuests отмечает маршруты, которые доступны гостям без аутентификации (например, страницы сайта и удержание
+// столиков, пока гость оформляет бронь на сайте).
+func (h *Handler) allowGuests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), guestsAllowedCtxKey, true)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// allowHoldOwners пропускает к маршрутам удержания столиков без аутентификации только гостей, которые передали токен
+// удержания в заголовке X-Hold-Token (сам токен проверяется при загрузке удержания). Запросы без токена должны
+// пройти проверку ключа API, как и остальные запросы к ресторану.
+func (h *Handler) allowHoldOwners(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(holdTokenHeader) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.allowGuests(next).ServeHTTP(w, r)
+	})
+}
+
+// authorizeRestaurant проверяет, может ли пользователь из контекста запроса работать с рестораном restaurantID:
+// просматривать его (GET-запросы) или изменять (остальные запросы). Если доступа нет, отправляет ответ с ошибкой
+// и возвращает false.
+func authorizeRestaurant(w http.ResponseWriter, r *http.Request, restaurantID uint64) bool {
+	if guestsAllowed, _ := r.Context().Value(guestsAllowedCtxKey).(bool); guestsAllowed {
+		return true
+	}
+
+	user := currentUser(r)
+	if user == nil {
+		renderUnauthorized(w, r, ErrUnauthorized)
+		return false
+	}
+
+	allowed := user.CanManage(restaurantID)
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		allowed = user.CanRead(restaurantID)
+	}
+	if !allowed {
+		_ = render.Render(w, r, errForbidden(ErrForbidden))
+		return false
+	}
+	return true
+}
+
+// currentUser возвращает аутентифицированного пользователя API из контекста запроса (nil, если ключ не передан).
+func currentUser(r *http.Request) *model.User {
+	user, _ := r.Context().Value(currentUserCtxKey).(*model.User)
+	return user
+}
+
+// renderUnauthorized отправляет ответ с кодом состояния http.StatusUnauthorized и подсказкой, как передать ключ API.
+func renderUnauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	_ = render.Render(w, r, errUnauthorized(err))
+}
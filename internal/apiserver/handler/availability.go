@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+)
+
+// getAvailabilityResponse представляет тело ответа с сеткой доступности ресторана на выбранный день.
+type getAvailabilityResponse struct {
+	*model.Availability
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getAvailabilityResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getAvailability godoc
+// @Summary      Получить сетку доступности ресторана на выбранный день
+// @Description  Возвращает все моменты начала брони с шагом 15 минут в пределах графика работы ресторана (кроме уже наступивших) с количеством мест за столиками, свободными на всё время брони, и признаком того, можно ли рассадить компанию. Свободные столики для всех моментов подбираются одним запросом к БД.
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                   true   "ID ресторана"
+// @Param        date           query     string                   true   "Дата посещения ресторана"  example(2022.06.16)
+// @Param        people         query     int                      true   "Количество человек"        example(4)
+// @Param        zone           query     string                   false  "Предпочитаемая зона ресторана"
+// @Success      200            {object}  getAvailabilityResponse  "ok"
+// @Failure      400            {object}  errResponse              "Некорректные дата, количество человек или зона"
+// @Failure      500            {object}  errResponse              "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/availability [get]
+func (h *Handler) getAvailability(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	date := r.URL.Query().Get("date")
+	peopleNumber := r.URL.Query().Get("people")
+	if date == "" || peopleNumber == "" {
+		_ = render.Render(w, r, errInvalidRequest(ErrAvailabilityMissingFields))
+		return
+	}
+
+	availability, err := h.service.BookingService.GetAvailability(
+		restaurant.ID, date, peopleNumber, r.URL.Query().Get("zone"),
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	if err = render.Render(w, r, &getAvailabilityResponse{availability}); err != nil {
+		_ = render.Render(w, r, errRender(err))
+		return
+	}
+}
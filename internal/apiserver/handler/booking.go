@@ -1,14 +1,21 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
 )
 
+const bookingCtxKey = "booking"
+
 // createBookingRequest представляет тело запроса на создание брони в ресторане.
 type createBookingRequest struct {
 	PeopleNumber int `json:"people_number" example:"3"`
@@ -16,8 +23,15 @@ type createBookingRequest struct {
 	DesiredDatetime string `json:"desired_datetime" example:"2022.06.16 17:03"`
 	// ClientName имя клиента, оформляющего бронь.
 	ClientName string `json:"client_name" example:"Павел"`
-	// ClientPhone телефон клиента, оформляющего бронь.
-	ClientPhone string `json:"client_phone" example:"89876545654"`
+	// ClientPhone телефон клиента, оформляющего бронь, в международном формате (российский номер можно записать
+	// и через 8). Сохраняется в формате E.164.
+	ClientPhone string `json:"client_phone" example:"+79876545654"`
+	// ClientEmail адрес электронной почты клиента для уведомлений о брони (необязательно).
+	ClientEmail string `json:"client_email" example:"pavel@example.com"`
+	// Zone представляет зону ресторана, в которой гость предпочитает сидеть (необязательно, по умолчанию любая).
+	Zone string `json:"zone" example:"terrace"`
+	// Pending означает, что бронь ждёт подтверждения рестораном (необязательно, по умолчанию бронь сразу подтверждена).
+	Pending bool `json:"pending" example:"false"`
 }
 
 // Bind осуществляет пост-обработку запроса.
@@ -25,6 +39,21 @@ func (r *createBookingRequest) Bind(_ *http.Request) error {
 	if r.PeopleNumber == 0 || r.DesiredDatetime == "" || r.ClientName == "" || r.ClientPhone == "" {
 		return ErrBookingMissingFields
 	}
+	if r.Zone != "" && !model.IsZone(r.Zone) {
+		return model.ErrUnknownZone
+	}
+
+	phone, err := model.NormalizePhone(r.ClientPhone)
+	if err != nil {
+		return err
+	}
+	r.ClientPhone = phone
+
+	if r.ClientEmail != "" {
+		if r.ClientEmail, err = model.NormalizeEmail(r.ClientEmail); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -39,16 +68,17 @@ func (r *createBookingResponse) Render(_ http.ResponseWriter, _ *http.Request) e
 }
 
 // createBooking godoc
-// @Summary  Оформить бронь в выбранном ресторане
-// @Tags     bookings
-// @Accept   json
-// @Produce  json
-// @Param    restaurant_id  path      string                 true  "ID ресторана"
-// @Param    input          body      createBookingRequest   true  "Информация о брони"
-// @Success  201            {object}  createBookingResponse  "ok"
-// @Failure  400            {object}  errResponse            "Некорректные данные брони"
-// @Failure  500            {object}  errResponse            "Ошибка на стороне сервера"
-// @Router   /restaurants/{restaurant_id}/bookings/ [post]
+// @Summary   Оформить бронь в выбранном ресторане
+// @Tags      bookings
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string                 true  "ID ресторана"
+// @Param     input          body      createBookingRequest   true  "Информация о брони"
+// @Success   201            {object}  createBookingResponse  "ok"
+// @Failure   400            {object}  errResponse            "Некорректные данные брони"
+// @Failure   500            {object}  errResponse            "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/bookings/ [post]
 func (h *Handler) createBooking(w http.ResponseWriter, r *http.Request) {
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
 
@@ -64,10 +94,19 @@ func (h *Handler) createBooking(w http.ResponseWriter, r *http.Request) {
 		DesiredDatetime: data.DesiredDatetime,
 		ClientName:      data.ClientName,
 		ClientPhone:     data.ClientPhone,
+		ClientEmail:     data.ClientEmail,
+		Zone:            data.Zone,
+		Pending:         data.Pending,
+		// бронь через API оформляет ресторан, и правила надёжности гостей к ней не применяются
+		ByRestaurant: true,
 	}
 
 	bookingID, err := h.service.BookingService.Create(details)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
 		_ = render.Render(w, r, errServiceFailure(err))
 		return
 	}
@@ -89,15 +128,16 @@ func (r *listBookingsResponse) Render(_ http.ResponseWriter, _ *http.Request) er
 }
 
 // listBookings godoc
-// @Summary  Получить список всех броней, совершённых в ресторане
-// @Tags     bookings
-// @Accept   json
-// @Produce  json
-// @Param    restaurant_id  path      string                true  "ID ресторана"
-// @Success  200            {object}  listBookingsResponse  "ok"
-// @Failure  400            {object}  errResponse           "Некорректный restaurant_id"
-// @Failure  500            {object}  errResponse           "Ошибка на стороне сервера"
-// @Router   /restaurants/{restaurant_id}/bookings/ [get]
+// @Summary   Получить список всех броней, совершённых в ресторане
+// @Tags      bookings
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string                true  "ID ресторана"
+// @Success   200            {object}  listBookingsResponse  "ok"
+// @Failure   400            {object}  errResponse           "Некорректный restaurant_id"
+// @Failure   500            {object}  errResponse           "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/bookings/ [get]
 func (h *Handler) listBookings(w http.ResponseWriter, r *http.Request) {
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
 
@@ -111,3 +151,277 @@ func (h *Handler) listBookings(w http.ResponseWriter, r *http.Request) {
 		Data: bookings,
 	})
 }
+
+// bookingCtx используется для загрузки брони (model.Booking) из контекста запроса по booking_id,
+// переданному в параметрах URL запроса. Бронь должна относиться к ресторану из контекста запроса.
+func (h *Handler) bookingCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bookingIDStr := chi.URLParam(r, "booking_id"); bookingIDStr != "" {
+			bookingID, err := strconv.ParseUint(bookingIDStr, 10, 0)
+			if err != nil {
+				_ = render.Render(w, r, errInvalidRequest(err))
+				return
+			}
+
+			booking, err := h.service.BookingService.Get(bookingID)
+			if err != nil {
+				if errors.Is(err, store.ErrBookingNotFound) {
+					_ = render.Render(w, r, errNotFound(err))
+					return
+				}
+				_ = render.Render(w, r, errServiceFailure(err))
+				return
+			}
+
+			restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+			if booking.RestaurantID != restaurant.ID {
+				_ = render.Render(w, r, errNotFound(store.ErrBookingNotFound))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), bookingCtxKey, booking)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		} else {
+			_ = render.Render(w, r, errInvalidRequest(ErrBookingMissingFields))
+			return
+		}
+	})
+}
+
+// getBookingResponse представляет тело ответа на получение брони.
+type getBookingResponse struct {
+	*model.Booking
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getBookingResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getBooking godoc
+// @Summary   Получить бронь в ресторане по её ID
+// @Tags      bookings
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string              true  "ID ресторана"
+// @Param     booking_id     path      string              true  "ID брони"
+// @Success   200            {object}  getBookingResponse  "ok"
+// @Failure   400            {object}  errResponse         "Некорректный ID брони"
+// @Failure   404            {object}  errResponse         "Бронь не найдена"
+// @Failure   500            {object}  errResponse         "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/bookings/{booking_id}/ [get]
+func (h *Handler) getBooking(w http.ResponseWriter, r *http.Request) {
+	booking := r.Context().Value(bookingCtxKey).(*model.Booking)
+
+	if err := render.Render(w, r, &getBookingResponse{booking}); err != nil {
+		_ = render.Render(w, r, errRender(err))
+		return
+	}
+}
+
+// updateBookingResponse представляет тело ответа на изменение брони.
+type updateBookingResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *updateBookingResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// updateBooking godoc
+// @Summary      Изменить количество человек и (или) дату и время брони
+// @Description  Столики подбираются заново: если прежние столики свободны на новое время и за ними хватает мест, гости остаются за ними. Если рассадить компанию нельзя, бронь остаётся прежней.
+// @Tags         bookings
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                   true  "ID ресторана"
+// @Param        booking_id     path      string                   true  "ID брони"
+// @Param        input          body      model.UpdateBookingData  true  "Новые данные брони"
+// @Success      200            {object}  updateBookingResponse    "ok"
+// @Failure      400            {object}  errResponse              "Некорректные данные брони, бронь отменена или её время наступило"
+// @Failure      404            {object}  errResponse              "Бронь не найдена"
+// @Failure      409            {object}  errResponse              "В ресторане не хватает мест или гости уже пришли"
+// @Failure      500            {object}  errResponse              "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/bookings/{booking_id}/ [patch]
+func (h *Handler) updateBooking(w http.ResponseWriter, r *http.Request) {
+	booking := r.Context().Value(bookingCtxKey).(*model.Booking)
+
+	data := model.UpdateBookingData{}
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	if err := h.service.BookingService.Update(booking.ID, data); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidData),
+			errors.Is(err, service.ErrBookingAlreadyCancelled),
+			errors.Is(err, service.ErrBookingInPast):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, service.ErrNotEnoughSeatsInRestaurant), errors.Is(err, service.ErrBookingStatusTransition):
+			_ = render.Render(w, r, errConflict(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	_ = render.Render(w, r, &updateBookingResponse{Status: "ok"})
+}
+
+// cancelBookingResponse представляет тело ответа на отмену брони.
+type cancelBookingResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *cancelBookingResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// cancelBooking godoc
+// @Summary      Отменить бронь в ресторане
+// @Description  Столики, забронированные в рамках брони, снова становятся доступными. Бронь, время которой уже наступило, отменить нельзя.
+// @Tags         bookings
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                 true  "ID ресторана"
+// @Param        booking_id     path      string                 true  "ID брони"
+// @Success      200            {object}  cancelBookingResponse  "ok"
+// @Failure      400            {object}  errResponse            "Бронь уже отменена или её время наступило"
+// @Failure      404            {object}  errResponse            "Бронь не найдена"
+// @Failure      409            {object}  errResponse            "Гости уже пришли или бронь завершена"
+// @Failure      500            {object}  errResponse            "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/bookings/{booking_id}/ [delete]
+func (h *Handler) cancelBooking(w http.ResponseWriter, r *http.Request) {
+	booking := r.Context().Value(bookingCtxKey).(*model.Booking)
+
+	if err := h.service.BookingService.Cancel(booking.ID, model.BookingCancelledByRestaurant); err != nil {
+		switch {
+		case errors.Is(err, service.ErrBookingAlreadyCancelled), errors.Is(err, service.ErrBookingInPast):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, service.ErrBookingStatusTransition):
+			_ = render.Render(w, r, errConflict(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	_ = render.Render(w, r, &cancelBookingResponse{Status: "ok"})
+}
+
+// changeBookingStatusResponse представляет тело ответа на изменение статуса брони.
+type changeBookingStatusResponse struct {
+	*model.Booking
+}
+
+// Render осуществляет предобработку ответа.
+func (r *changeBookingStatusResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// confirmBooking godoc
+// @Summary      Подтвердить бронь
+// @Description  Подтвердить можно только бронь в статусе pending.
+// @Tags         bookings
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                       true  "ID ресторана"
+// @Param        booking_id     path      string                       true  "ID брони"
+// @Success      200            {object}  changeBookingStatusResponse  "ok"
+// @Failure      400            {object}  errResponse                  "Некорректный ID брони"
+// @Failure      404            {object}  errResponse                  "Бронь не найдена"
+// @Failure      409            {object}  errResponse                  "Бронь нельзя перевести в статус confirmed"
+// @Failure      500            {object}  errResponse                  "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/bookings/{booking_id}/confirm [post]
+func (h *Handler) confirmBooking(w http.ResponseWriter, r *http.Request) {
+	h.changeBookingStatus(w, r, h.service.BookingService.Confirm)
+}
+
+// checkInBooking godoc
+// @Summary      Отметить приход гостей
+// @Description  Бронь переводится в статус seated. Приход можно отметить не раньше чем за час до начала брони.
+// @Tags         bookings
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                       true  "ID ресторана"
+// @Param        booking_id     path      string                       true  "ID брони"
+// @Success      200            {object}  changeBookingStatusResponse  "ok"
+// @Failure      400            {object}  errResponse                  "Некорректный ID брони или до начала брони больше часа"
+// @Failure      404            {object}  errResponse                  "Бронь не найдена"
+// @Failure      409            {object}  errResponse                  "Бронь нельзя перевести в статус seated"
+// @Failure      500            {object}  errResponse                  "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/bookings/{booking_id}/check-in [post]
+func (h *Handler) checkInBooking(w http.ResponseWriter, r *http.Request) {
+	h.changeBookingStatus(w, r, h.service.BookingService.CheckIn)
+}
+
+// markNoShow godoc
+// @Summary      Отметить неявку гостей
+// @Description  Бронь переводится в статус no_show, а её столики сразу освобождаются. Неявку можно отметить только после начала брони.
+// @Tags         bookings
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                       true  "ID ресторана"
+// @Param        booking_id     path      string                       true  "ID брони"
+// @Success      200            {object}  changeBookingStatusResponse  "ok"
+// @Failure      400            {object}  errResponse                  "Некорректный ID брони или время брони ещё не наступило"
+// @Failure      404            {object}  errResponse                  "Бронь не найдена"
+// @Failure      409            {object}  errResponse                  "Бронь нельзя перевести в статус no_show"
+// @Failure      500            {object}  errResponse                  "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/bookings/{booking_id}/no-show [post]
+func (h *Handler) markNoShow(w http.ResponseWriter, r *http.Request) {
+	h.changeBookingStatus(w, r, h.service.BookingService.MarkNoShow)
+}
+
+// completeBooking godoc
+// @Summary      Завершить бронь
+// @Description  Гости ушли: бронь переводится в статус completed, а её столики освобождаются, даже если время брони ещё не истекло.
+// @Tags         bookings
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                       true  "ID ресторана"
+// @Param        booking_id     path      string                       true  "ID брони"
+// @Success      200            {object}  changeBookingStatusResponse  "ok"
+// @Failure      400            {object}  errResponse                  "Некорректный ID брони"
+// @Failure      404            {object}  errResponse                  "Бронь не найдена"
+// @Failure      409            {object}  errResponse                  "Бронь нельзя перевести в статус completed"
+// @Failure      500            {object}  errResponse                  "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/bookings/{booking_id}/complete [post]
+func (h *Handler) completeBooking(w http.ResponseWriter, r *http.Request) {
+	h.changeBookingStatus(w, r, h.service.BookingService.Complete)
+}
+
+// changeBookingStatus изменяет статус брони из контекста запроса с помощью change и возвращает изменённую бронь.
+func (h *Handler) changeBookingStatus(w http.ResponseWriter, r *http.Request, change func(id uint64) error) {
+	booking := r.Context().Value(bookingCtxKey).(*model.Booking)
+
+	if err := change(booking.ID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrBookingTooEarly), errors.Is(err, service.ErrBookingNotStarted):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, service.ErrBookingStatusTransition):
+			_ = render.Render(w, r, errConflict(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	booking, err := h.service.BookingService.Get(booking.ID)
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &changeBookingStatusResponse{booking})
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// manageBookingPage отображает содержание страницы брони, открытой гостем по подписанной ссылке: на ней гость без
+// учётной записи может посмотреть бронь, отменить или перенести её.
+func (h *Handler) manageBookingPage(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	booking, err := h.service.BookingLinkService.Get(token)
+	if err != nil {
+		h.renderBookingLinkError(w, r, err)
+		return
+	}
+
+	// название ресторана только дополняет страницу, поэтому без него бронь всё равно показывается
+	var restaurantName string
+	if restaurant, err := h.service.RestaurantService.Get(booking.RestaurantID); err == nil {
+		restaurantName = restaurant.Name
+	}
+
+	h.renderTemplate(w, r, "booking-manage",
+		&TemplatesContext{
+			PageTitle:      "Моя бронь",
+			Booking:        booking,
+			BookingLink:    token,
+			RestaurantName: restaurantName,
+		},
+	)
+}
+
+// cancelBookingByLink обрабатывает запрос гостя на отмену брони по подписанной ссылке.
+func (h *Handler) cancelBookingByLink(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	booking, err := h.service.BookingLinkService.Get(token)
+	if err != nil {
+		h.renderBookingLinkError(w, r, err)
+		return
+	}
+
+	if err = h.service.BookingLinkService.Cancel(token); err != nil {
+		h.renderBookingLinkError(w, r, err)
+		return
+	}
+
+	h.renderTemplate(w, r, "booking-cancelled",
+		&TemplatesContext{
+			PageTitle: "Бронь отменена",
+			BookingID: booking.ID,
+		},
+	)
+}
+
+// rescheduleBookingByLink обрабатывает запрос гостя на изменение количества человек и (или) даты и времени брони
+// по подписанной ссылке. После изменения гость переходит на страницу брони по новой ссылке.
+func (h *Handler) rescheduleBookingByLink(w http.ResponseWriter, r *http.Request) {
+	headerContentType := r.Header.Get("Content-Type")
+	if headerContentType != "application/x-www-form-urlencoded" {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrMakingBookingContentType.Error(),
+				ErrorCode: http.StatusUnsupportedMediaType,
+			},
+		)
+		return
+	}
+
+	var data model.UpdateBookingData
+	if peopleNumber := r.FormValue("people_number"); peopleNumber != "" {
+		peopleNum, err := strconv.Atoi(peopleNumber)
+		if err != nil {
+			h.renderTemplate(w, r, "error",
+				&TemplatesContext{
+					PageTitle: "Произошла ошибка",
+					ErrorText: ErrBookingMissingFields.Error(),
+					ErrorCode: http.StatusBadRequest,
+				},
+			)
+			return
+		}
+		data.PeopleNumber = &peopleNum
+	}
+	if desiredDatetime := r.FormValue("desired_datetime"); desiredDatetime != "" {
+		// поле ввода на сайте передаёт дату и время в формате "2006-01-02T15:04", а сервис ждёт формат API
+		if dateTime, err := time.ParseInLocation("2006-01-02T15:04", desiredDatetime, time.Local); err == nil {
+			desiredDatetime = dateTime.Format("2006.01.02 15:04")
+		}
+		data.DesiredDatetime = &desiredDatetime
+	}
+
+	if err := data.Bind(r); err != nil {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: http.StatusBadRequest,
+			},
+		)
+		return
+	}
+
+	token, err := h.service.BookingLinkService.Reschedule(chi.URLParam(r, "token"), data)
+	if err != nil {
+		h.renderBookingLinkError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/bookings/manage/"+token, http.StatusSeeOther)
+}
+
+// bookingLink возвращает путь к странице брони по подписанной ссылке, которую гость получает после оформления брони.
+// Бронь к этому моменту уже оформлена, поэтому при ошибке возвращается пустая строка: гость всё равно увидит
+// номер брони.
+func (h *Handler) bookingLink(bookingID uint64) string {
+	token, err := h.service.BookingLinkService.Sign(bookingID)
+	if err != nil {
+		return ""
+	}
+	return "/bookings/manage/" + token
+}
+
+// renderBookingLinkError отображает страницу с ошибкой просмотра или изменения брони по подписанной ссылке.
+func (h *Handler) renderBookingLinkError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrInvalidBookingLink), errors.Is(err, store.ErrBookingNotFound):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, service.ErrBookingLinkExpired):
+		statusCode = http.StatusGone
+	case errors.Is(err, service.ErrNotEnoughSeatsInRestaurant):
+		statusCode = http.StatusConflict
+	case errors.Is(err, service.ErrInvalidData), errors.Is(err, service.ErrBookingAlreadyCancelled),
+		errors.Is(err, service.ErrBookingInPast), errors.Is(err, service.ErrBookingStatusTransition):
+		statusCode = http.StatusBadRequest
+	}
+	h.renderTemplate(w, r, "error",
+		&TemplatesContext{
+			PageTitle: "Произошла ошибка",
+			ErrorText: err.Error(),
+			ErrorCode: statusCode,
+		},
+	)
+}
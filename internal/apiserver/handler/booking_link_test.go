@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+)
+
+// TestBookingLink_Tampered проверяет, что по подделанной ссылке нельзя ни посмотреть, ни отменить бронь.
+func TestBookingLink_Tampered(t *testing.T) {
+	s := newTestServer(t)
+
+	target := fmt.Sprintf("/api/v1/restaurants/%d/bookings/", s.restaurantID)
+	w := s.do(http.MethodPost, target, "application/json", strings.NewReader(bookingJSON(t, futureDatetime("2006.01.02 15:04"))))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create booking: status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	booking := s.bookings(t)[0]
+
+	token, err := service.NewBookingLinkService(s.store.Bookings(), nil, "test-booking-link-key").Sign(booking.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := s.do(http.MethodGet, "/bookings/manage/"+token, "", nil).Body.String(); strings.Contains(body, "Ошибка") {
+		t.Fatalf("valid link shows an error page; body: %s", body)
+	}
+
+	// ссылка на соседнюю бронь с подписью настоящей ссылки
+	tampered := fmt.Sprintf("%d%s", booking.ID+1, token[strings.IndexByte(token, '.'):])
+	notFound := fmt.Sprintf("Ошибка %d", http.StatusNotFound)
+	if body := s.do(http.MethodGet, "/bookings/manage/"+tampered, "", nil).Body.String(); !strings.Contains(body, notFound) {
+		t.Errorf("tampered link: page has no %q; body: %s", notFound, body)
+	}
+
+	// подпись, изменённая в последнем символе
+	forged := token[:len(token)-1] + "A"
+	if strings.HasSuffix(token, "A") {
+		forged = token[:len(token)-1] + "B"
+	}
+	if body := s.postForm("/bookings/manage/"+forged+"/cancel", nil).Body.String(); !strings.Contains(body, notFound) {
+		t.Errorf("forged link: page has no %q; body: %s", notFound, body)
+	}
+	if booking = s.bookings(t)[0]; booking.Status == model.BookingStatusCancelled {
+		t.Error("booking is cancelled by a forged link")
+	}
+}
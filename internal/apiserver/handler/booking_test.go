@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/postgres"
+)
+
+// bookingJSON возвращает тело запроса на создание брони через API.
+func bookingJSON(t *testing.T, desiredDatetime string) string {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"people_number":    2,
+		"desired_datetime": desiredDatetime,
+		"client_name":      "Павел",
+		"client_phone":     "+79485722648",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestCreateBooking_MaliciousDatetime(t *testing.T) {
+	s := newTestServer(t)
+	target := fmt.Sprintf("/api/v1/restaurants/%d/bookings/", s.restaurantID)
+
+	for _, tt := range maliciousDatetimes {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, target, "application/json", strings.NewReader(bookingJSON(t, tt.value)))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+		})
+	}
+
+	if bookings := s.bookings(t); len(bookings) != 0 {
+		t.Fatalf("created %d bookings from invalid datetimes, want 0", len(bookings))
+	}
+
+	// хранилище не пострадало: бронь на корректное время оформляется
+	w := s.do(http.MethodPost, target, "application/json", strings.NewReader(bookingJSON(t, futureDatetime("2006.01.02 15:04"))))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("valid booking: status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+}
+
+func TestUpdateBooking_MaliciousDatetime(t *testing.T) {
+	s := newTestServer(t)
+	desiredDatetime := futureDatetime("2006.01.02 15:04")
+
+	w := s.do(http.MethodPost, fmt.Sprintf("/api/v1/restaurants/%d/bookings/", s.restaurantID), "application/json",
+		strings.NewReader(bookingJSON(t, desiredDatetime)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create booking: status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var created createBookingResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	target := fmt.Sprintf("/api/v1/restaurants/%d/bookings/%d/", s.restaurantID, created.ID)
+
+	for _, tt := range maliciousDatetimes {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(map[string]string{"desired_datetime": tt.value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := s.do(http.MethodPatch, target, "application/json", strings.NewReader(string(body)))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+		})
+	}
+
+	// бронь осталась на прежнее время
+	bookings := s.bookings(t)
+	if len(bookings) != 1 {
+		t.Fatalf("got %d bookings, want 1", len(bookings))
+	}
+	if got := bookings[0].StartsAt().Format("2006.01.02 15:04"); got != desiredDatetime {
+		t.Errorf("booking starts at %s, want %s", got, desiredDatetime)
+	}
+}
+
+func TestMakeBooking_MaliciousDatetime(t *testing.T) {
+	s := newTestServer(t)
+	target := fmt.Sprintf("/restaurants/%d/booked", s.restaurantID)
+	form := func(desiredDatetime string) url.Values {
+		return url.Values{
+			"people_number":    {"2"},
+			"desired_datetime": {desiredDatetime},
+			"client_name":      {"Павел"},
+			"client_phone":     {"89485722648"},
+		}
+	}
+
+	for _, tt := range maliciousDatetimes {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.postForm(target, form(tt.value))
+			body := w.Body.String()
+			if !strings.Contains(body, fmt.Sprintf("Ошибка %d", http.StatusBadRequest)) {
+				t.Errorf("want the error page with code %d; body: %s", http.StatusBadRequest, body)
+			}
+			// введённое гостем значение выводится на странице только в экранированном виде
+			if strings.Contains(body, "<script>alert(1)</script>") {
+				t.Error("the error page contains unescaped input")
+			}
+		})
+	}
+
+	if bookings := s.bookings(t); len(bookings) != 0 {
+		t.Fatalf("created %d bookings from invalid datetimes, want 0", len(bookings))
+	}
+
+	w := s.postForm(target, form(futureDatetime("2006.01.02 15:04")))
+	if !strings.Contains(w.Body.String(), "Бронь успешно оформлена") {
+		t.Fatalf("valid booking was not created; body: %s", w.Body)
+	}
+}
+
+// TestCreateBooking_Concurrent одновременно оформляет множество броней на пересекающееся время и проверяет, что ни
+// один столик не забронирован дважды. Тест выполняется на хранилище в оперативной памяти, а если в переменной
+// окружения TEST_POSTGRES_DSN указана строка подключения к БД с применёнными миграциями, - и на PostgreSQL.
+func TestCreateBooking_Concurrent(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testConcurrentBookings(t, memory.NewStore())
+	})
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv("TEST_POSTGRES_DSN")
+		if dsn == "" {
+			t.Skip("TEST_POSTGRES_DSN is not set")
+		}
+		db, err := postgres.NewDB(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		testConcurrentBookings(t, postgres.NewStore(db))
+	})
+}
+
+// testConcurrentBookings оформляет брони в новом ресторане хранилища st параллельными запросами к API.
+func testConcurrentBookings(t *testing.T, st store.Store) {
+	const requests = 64
+
+	s := newTestServerWithStore(t, st, 2, 2, 4, 4, 6)
+	target := fmt.Sprintf("/api/v1/restaurants/%d/bookings/", s.restaurantID)
+
+	// брони начинаются в 19:00, 19:30 и 20:00 и длятся по 2 часа, поэтому все они пересекаются по времени
+	week := time.Now().AddDate(0, 0, 7)
+	evening := time.Date(week.Year(), week.Month(), week.Day(), 19, 0, 0, 0, time.Local)
+
+	start := make(chan struct{})
+	codes := make([]int, requests)
+	bodies := make([]string, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			body, err := json.Marshal(map[string]interface{}{
+				"people_number":    1 + i%6,
+				"desired_datetime": evening.Add(time.Duration(i%3) * 30 * time.Minute).Format("2006.01.02 15:04"),
+				"client_name":      "Павел",
+				"client_phone":     fmt.Sprintf("+7948572%04d", i),
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			<-start
+			w := s.do(http.MethodPost, target, "application/json", strings.NewReader(string(body)))
+			codes[i], bodies[i] = w.Code, w.Body.String()
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i, code := range codes {
+		switch {
+		case code == http.StatusCreated:
+			created++
+		case !strings.Contains(bodies[i], service.ErrNotEnoughSeatsInRestaurant.Error()):
+			t.Errorf("request %d: status = %d; body: %s", i, code, bodies[i])
+		}
+	}
+	if created == 0 {
+		t.Fatal("no bookings were created")
+	}
+
+	bookings := s.bookings(t)
+	if len(bookings) != created {
+		t.Fatalf("got %d bookings, want %d", len(bookings), created)
+	}
+
+	tables, err := st.Tables().GetAll(s.restaurantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seats := make(map[uint64]int, len(tables))
+	for _, table := range tables {
+		seats[table.ID] = table.SeatsNumber
+	}
+
+	// каждая бронь рассажена за своими столиками, и ни один столик не занят двумя бронями на пересекающееся время
+	booked := make(map[uint64][]*model.Booking)
+	for _, b := range bookings {
+		booking, err := st.Bookings().Get(b.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bookedSeats := 0
+		for _, tableID := range booking.TableIDs {
+			bookedSeats += seats[tableID]
+			for _, other := range booked[tableID] {
+				if booking.StartsAt().Before(other.EndsAt()) && other.StartsAt().Before(booking.EndsAt()) {
+					t.Errorf("table %d is booked by overlapping bookings %d and %d", tableID, other.ID, booking.ID)
+				}
+			}
+			booked[tableID] = append(booked[tableID], booking)
+		}
+		if bookedSeats < booking.PeopleNumber {
+			t.Errorf("booking %d for %d people got %d seats", booking.ID, booking.PeopleNumber, bookedSeats)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	customerCtxKey = "customer"
+	// customerSessionCookie представляет название cookie, в которой хранится токен сеанса гостя на сайте.
+	customerSessionCookie = "customer_session"
+)
+
+// customerSession используется для загрузки гостя (model.Customer) из контекста запроса по токену сеанса из cookie.
+// Запрос без действующего сеанса пропускается дальше без гостя: бронировать можно и без учётной записи.
+func (h *Handler) customerSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(customerSessionCookie)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		customer, err := h.service.CustomerService.Authenticate(cookie.Value)
+		if err != nil {
+			if errors.Is(err, store.ErrCustomerNotFound) {
+				// сеанс истёк или завершён: cookie больше не нужна
+				clearSessionCookie(w, r)
+			} else {
+				h.logger.Errorf("failed to authenticate customer: %v", err)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), customerCtxKey, customer)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireCustomer используется для ограничения доступа к страницам личного кабинета: гость без действующего сеанса
+// перенаправляется на страницу входа.
+func (h *Handler) requireCustomer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if currentCustomer(r) == nil {
+			http.Redirect(w, r, "/account/login", http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// currentCustomer возвращает гостя, вошедшего на сайт (nil, если гость не вошёл).
+func currentCustomer(r *http.Request) *model.Customer {
+	customer, _ := r.Context().Value(customerCtxKey).(*model.Customer)
+	return customer
+}
+
+// setSessionCookie сохраняет токен сеанса гостя в cookie. Cookie недоступна скриптам на странице и не отправляется
+// с форм других сайтов (SameSite=Lax), поэтому от имени гостя нельзя отправить форму с чужого сайта.
+func setSessionCookie(w http.ResponseWriter, r *http.Request, session *model.CustomerSession) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     customerSessionCookie,
+		Value:    session.Token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// clearSessionCookie удаляет cookie с токеном сеанса гостя.
+func clearSessionCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     customerSessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// registerPage отображает содержание страницы регистрации гостя.
+func (h *Handler) registerPage(w http.ResponseWriter, r *http.Request) {
+	h.renderTemplate(w, r, "account-register",
+		&TemplatesContext{
+			PageTitle: "Регистрация",
+			Customer:  currentCustomer(r),
+		},
+	)
+}
+
+// registerCustomer обрабатывает запрос на регистрацию гостя. После регистрации гость сразу входит на сайт.
+func (h *Handler) registerCustomer(w http.ResponseWriter, r *http.Request) {
+	if !h.checkFormContentType(w, r) {
+		return
+	}
+
+	session, err := h.service.CustomerService.Register(
+		r.FormValue("client_name"), r.FormValue("client_phone"), r.FormValue("password"),
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidData) || errors.Is(err, store.ErrCustomerAlreadyExists) {
+			h.renderTemplate(w, r, "account-register",
+				&TemplatesContext{
+					PageTitle: "Регистрация",
+					ErrorText: err.Error(),
+				},
+			)
+			return
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: http.StatusInternalServerError,
+			},
+		)
+		return
+	}
+
+	setSessionCookie(w, r, session)
+	http.Redirect(w, r, "/account/", http.StatusSeeOther)
+}
+
+// loginPage отображает содержание страницы входа гостя.
+func (h *Handler) loginPage(w http.ResponseWriter, r *http.Request) {
+	h.renderTemplate(w, r, "account-login",
+		&TemplatesContext{
+			PageTitle: "Вход",
+			Customer:  currentCustomer(r),
+		},
+	)
+}
+
+// loginCustomer обрабатывает запрос на вход гостя по телефону и паролю.
+func (h *Handler) loginCustomer(w http.ResponseWriter, r *http.Request) {
+	if !h.checkFormContentType(w, r) {
+		return
+	}
+
+	session, err := h.service.CustomerService.Login(r.FormValue("client_phone"), r.FormValue("password"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			h.renderTemplate(w, r, "account-login",
+				&TemplatesContext{
+					PageTitle: "Вход",
+					ErrorText: err.Error(),
+				},
+			)
+			return
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: http.StatusInternalServerError,
+			},
+		)
+		return
+	}
+
+	setSessionCookie(w, r, session)
+	http.Redirect(w, r, "/account/", http.StatusSeeOther)
+}
+
+// logoutCustomer обрабатывает запрос на выход гостя: сеанс завершается, а cookie удаляется.
+func (h *Handler) logoutCustomer(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(customerSessionCookie); err == nil {
+		if err = h.service.CustomerService.Logout(cookie.Value); err != nil {
+			h.renderTemplate(w, r, "error",
+				&TemplatesContext{
+					PageTitle: "Произошла ошибка",
+					ErrorText: err.Error(),
+					ErrorCode: http.StatusInternalServerError,
+				},
+			)
+			return
+		}
+	}
+
+	clearSessionCookie(w, r)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// accountPage отображает личный кабинет гостя с его предстоящими и прошедшими бронями.
+func (h *Handler) accountPage(w http.ResponseWriter, r *http.Request) {
+	customer := currentCustomer(r)
+
+	history, err := h.service.CustomerService.GetBookingHistory(customer.ID)
+	if err != nil {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: http.StatusInternalServerError,
+			},
+		)
+		return
+	}
+
+	h.renderTemplate(w, r, "account",
+		&TemplatesContext{
+			PageTitle:      "Мои брони",
+			Customer:       customer,
+			BookingHistory: history,
+		},
+	)
+}
+
+// cancelBookingByCustomer обрабатывает запрос гостя на отмену брони из личного кабинета.
+func (h *Handler) cancelBookingByCustomer(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := strconv.ParseUint(chi.URLParam(r, "booking_id"), 10, 0)
+	if err != nil {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrBookingMissingFields.Error(),
+				ErrorCode: http.StatusBadRequest,
+			},
+		)
+		return
+	}
+
+	if err = h.service.BookingService.CancelByCustomer(bookingID, currentCustomer(r).ID); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrBookingNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, service.ErrBookingAlreadyCancelled), errors.Is(err, service.ErrBookingInPast),
+			errors.Is(err, service.ErrBookingStatusTransition):
+			statusCode = http.StatusBadRequest
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: statusCode,
+			},
+		)
+		return
+	}
+
+	http.Redirect(w, r, "/account/", http.StatusSeeOther)
+}
+
+// checkFormContentType проверяет, что данные формы переданы с типом содержимого application/x-www-form-urlencoded.
+// Если это не так, отображает страницу с ошибкой и возвращает false.
+func (h *Handler) checkFormContentType(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrMakingBookingContentType.Error(),
+				ErrorCode: http.StatusUnsupportedMediaType,
+			},
+		)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// doWithCookies выполняет запрос к сайту с cookie cookies.
+func (s *testServer) doWithCookies(method, target string, form url.Values, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+	if form != nil {
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+// sessionCookie возвращает cookie сеанса гостя из ответа (nil, если ответ её не устанавливает).
+func sessionCookie(w *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == customerSessionCookie {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestCustomer_Session(t *testing.T) {
+	s := newTestServer(t)
+
+	// без сеанса личный кабинет недоступен
+	w := s.doWithCookies(http.MethodGet, "/account/", nil)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/account/login" {
+		t.Fatalf("account without a session: status = %d, location = %q", w.Code, w.Header().Get("Location"))
+	}
+
+	w = s.doWithCookies(http.MethodPost, "/account/register", url.Values{
+		"client_name":  {"Павел"},
+		"client_phone": {"8 927 900-72-65"},
+		"password":     {"correct horse"},
+	})
+	session := sessionCookie(w)
+	if w.Code != http.StatusSeeOther || session == nil || session.Value == "" {
+		t.Fatalf("register: status = %d, cookie = %v; body: %s", w.Code, session, w.Body)
+	}
+	if !session.HttpOnly || session.SameSite != http.SameSiteLaxMode || session.Path != "/" {
+		t.Errorf("session cookie = %+v, want HttpOnly, SameSite=Lax and Path=/", session)
+	}
+
+	w = s.doWithCookies(http.MethodGet, "/account/", nil, session)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Павел") {
+		t.Fatalf("account: status = %d; body: %s", w.Code, w.Body)
+	}
+
+	// повторная регистрация и вход с неверным паролем не начинают сеанс
+	w = s.doWithCookies(http.MethodPost, "/account/register", url.Values{
+		"client_name":  {"Павел"},
+		"client_phone": {"+79279007265"},
+		"password":     {"correct horse"},
+	})
+	if sessionCookie(w) != nil || w.Code != http.StatusOK {
+		t.Errorf("register twice: status = %d, cookie = %v", w.Code, sessionCookie(w))
+	}
+	w = s.doWithCookies(http.MethodPost, "/account/login", url.Values{
+		"client_phone": {"+79279007265"},
+		"password":     {"wrong password"},
+	})
+	if sessionCookie(w) != nil || !strings.Contains(w.Body.String(), "invalid phone number or password") {
+		t.Errorf("login with a wrong password: cookie = %v; body: %s", sessionCookie(w), w.Body)
+	}
+
+	w = s.doWithCookies(http.MethodPost, "/account/login", url.Values{
+		"client_phone": {"+7 927 900 72 65"},
+		"password":     {"correct horse"},
+	})
+	another := sessionCookie(w)
+	if w.Code != http.StatusSeeOther || another == nil || another.Value == session.Value {
+		t.Fatalf("login: status = %d, cookie = %v; body: %s", w.Code, another, w.Body)
+	}
+
+	// после выхода cookie удаляется, а сеанс больше не действует
+	w = s.doWithCookies(http.MethodPost, "/account/logout", url.Values{}, session)
+	if cleared := sessionCookie(w); cleared == nil || cleared.MaxAge >= 0 {
+		t.Errorf("logout: cookie = %v, want it deleted", cleared)
+	}
+	w = s.doWithCookies(http.MethodGet, "/account/", nil, session)
+	if w.Code != http.StatusSeeOther {
+		t.Errorf("account after logout: status = %d, want %d", w.Code, http.StatusSeeOther)
+	}
+	w = s.doWithCookies(http.MethodGet, "/account/", nil, another)
+	if w.Code != http.StatusOK {
+		t.Errorf("account with another session: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+)
+
+// getDurationPolicyResponse представляет тело ответа на получение правил длительности брони в ресторане.
+type getDurationPolicyResponse struct {
+	Data model.DurationPolicy `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getDurationPolicyResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getDurationPolicy godoc
+// @Summary      Получить правила длительности брони в ресторане
+// @Description  Если ресторану не заданы собственные правила, любая бронь длится 2 часа.
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                     true  "ID ресторана"
+// @Success      200            {object}  getDurationPolicyResponse  "ok"
+// @Failure      400            {object}  errResponse                "Некорректный ID ресторана"
+// @Failure      500            {object}  errResponse                "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/duration-policy/ [get]
+func (h *Handler) getDurationPolicy(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	_ = render.Render(w, r, &getDurationPolicyResponse{
+		Data: restaurant.DurationPolicy,
+	})
+}
+
+// setDurationPolicyResponse представляет тело ответа на замену правил длительности брони в ресторане.
+type setDurationPolicyResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *setDurationPolicyResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// setDurationPolicy godoc
+// @Summary      Заменить правила длительности брони в ресторане
+// @Description  Длительность брони указывается в минутах. Для компании применяется правило с наибольшим min_people, не превышающим её размер, а если такого нет - default_duration.
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                     true  "ID ресторана"
+// @Param        input          body      model.DurationPolicy       true  "Правила длительности брони"
+// @Success      200            {object}  setDurationPolicyResponse  "ok"
+// @Failure      400            {object}  errResponse                "Некорректные правила длительности брони"
+// @Failure      500            {object}  errResponse                "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/duration-policy/ [put]
+func (h *Handler) setDurationPolicy(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	policy := model.DurationPolicy{}
+	if err := render.Bind(r, &policy); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	if err := h.service.RestaurantService.SetDurationPolicy(restaurant.ID, policy); err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &setDurationPolicyResponse{Status: "ok"})
+}
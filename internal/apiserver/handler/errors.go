@@ -14,6 +14,27 @@ var (
 	ErrTableMissingFields = errors.New("missing required restaurant table fields")
 	// ErrBookingMissingFields возникает, когда в запросе на создание/получение брони пропущены обязательные поля.
 	ErrBookingMissingFields = errors.New("missing required booking fields")
+	// ErrWaitlistMissingFields возникает, когда в запросе на постановку в лист ожидания/получение записи в нём
+	// пропущены обязательные поля.
+	ErrWaitlistMissingFields = errors.New("missing required waitlist fields")
+	// ErrHoldMissingFields возникает, когда в запросе на удержание столиков/оформление брони по удержанию пропущены
+	// обязательные поля.
+	ErrHoldMissingFields = errors.New("missing required hold fields")
+	// ErrAvailabilityMissingFields возникает, когда в запросе на получение сетки доступности ресторана пропущена
+	// либо дата, либо кол-во человек.
+	ErrAvailabilityMissingFields = errors.New("missing required date or people number")
+	// ErrUserMissingFields возникает, когда в запросе на создание/получение пользователя пропущены обязательные поля.
+	ErrUserMissingFields = errors.New("missing required user fields")
+	// ErrGuestMissingFields возникает, когда в запросе на поиск/получение гостя пропущен телефон или ID гостя.
+	ErrGuestMissingFields = errors.New("missing required guest phone or id")
+	// ErrWebhookMissingFields возникает, когда в запросе на получение/удаление вебхука пропущен ID вебхука.
+	ErrWebhookMissingFields = errors.New("missing required webhook id")
+	// ErrStreamingUnsupported возникает, когда соединение не позволяет открыть поток событий (например, HTTP/2).
+	ErrStreamingUnsupported = errors.New("event streaming is not supported over this connection")
+	// ErrUnauthorized возникает, когда к API администрирования обращаются без ключа API.
+	ErrUnauthorized = errors.New("authentication required: pass an api key in the Authorization header")
+	// ErrForbidden возникает, когда у пользователя API нет прав на запрошенное действие.
+	ErrForbidden = errors.New("access denied")
 	// ErrFindAvailableRestaurants возникает, когда в запросе на поиск доступных ресторанов пропущено либо кол-во человек,
 	// либо дата и время.
 	ErrFindAvailableRestaurants = errors.New("missing required datetime or people number")
@@ -49,6 +70,28 @@ func errInvalidRequest(err error) render.Renderer {
 	}
 }
 
+// errUnauthorized вкладывает ошибку в кастомную структуру errResponse с кодом состояния http.StatusUnauthorized.
+// Создаётся, когда пользователь API не прошёл аутентификацию.
+func errUnauthorized(err error) render.Renderer {
+	return &errResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusUnauthorized,
+		StatusText:     "unauthorized",
+		ErrorText:      err.Error(),
+	}
+}
+
+// errForbidden вкладывает ошибку в кастомную структуру errResponse с кодом состояния http.StatusForbidden.
+// Создаётся, когда у пользователя API нет прав на запрошенное действие.
+func errForbidden(err error) render.Renderer {
+	return &errResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusForbidden,
+		StatusText:     "forbidden",
+		ErrorText:      err.Error(),
+	}
+}
+
 // errNotFound вкладывает ошибку в кастомную структуру errResponse с кодом состояния http.StatusNotFound.
 // Создаётся при отсутствии искомого ресурса по указанному URL.
 func errNotFound(err error) render.Renderer {
@@ -60,6 +103,28 @@ func errNotFound(err error) render.Renderer {
 	}
 }
 
+// errConflict вкладывает ошибку в кастомную структуру errResponse с кодом состояния http.StatusConflict.
+// Создаётся, когда запрос противоречит текущему состоянию ресурса (например, на новое время брони не хватает мест).
+func errConflict(err error) render.Renderer {
+	return &errResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusConflict,
+		StatusText:     "conflict",
+		ErrorText:      err.Error(),
+	}
+}
+
+// errTooManyRequests вкладывает ошибку в кастомную структуру errResponse с кодом состояния http.StatusTooManyRequests.
+// Создаётся, когда клиент превысил ограничение на количество запросов (например, удерживает слишком много столиков).
+func errTooManyRequests(err error) render.Renderer {
+	return &errResponse{
+		Err:            err,
+		HTTPStatusCode: http.StatusTooManyRequests,
+		StatusText:     "too many requests",
+		ErrorText:      err.Error(),
+	}
+}
+
 // errRender вкладывает ошибку в кастомную структуру errResponse с кодом состояния http.StatusUnprocessableEntity.
 // Создаётся при возникновении ошибки обработки ответа.
 func errRender(err error) render.Renderer {
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const guestCtxKey = "guest"
+
+// initGuestsRouter подготавливает отдельный маршрутизатор для просмотра гостей ресторанов.
+func (h *Handler) initGuestsRouter() http.Handler {
+	r := chi.NewRouter()
+	r.Use(h.requireUser)    // гостей просматривают пользователи API, которым доступны рестораны с их бронями
+	r.Get("/", h.findGuest) // GET /guests/?phone=...
+	r.Route("/{guest_id}", func(r chi.Router) {
+		r.Use(h.guestCtx)                       // загрузить информацию о госте из контекста запроса
+		r.Get("/", h.getGuest)                  // GET /guests/123/
+		r.Get("/bookings", h.listGuestBookings) // GET /guests/123/bookings
+	})
+	return r
+}
+
+// guestResponse представляет тело ответа на получение гостя вместе со статистикой посещений ресторанов.
+type guestResponse struct {
+	*model.Guest
+	// Restaurants представляет статистику посещений гостем ресторанов, доступных пользователю API.
+	Restaurants []model.GuestRestaurantStats `json:"restaurants"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *guestResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// findGuest godoc
+// @Summary      Найти гостя по телефону
+// @Description  Телефон можно записать в любом виде (например, 89485722648, +7 948 572-26-48): гости различаются по телефону в формате E.164. Менеджеру и сотруднику гость доступен, только если у него есть брони в их ресторанах, а статистика показывается только по этим ресторанам.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        phone  query     string         true  "Телефон гостя"
+// @Success      200    {object}  guestResponse  "ok"
+// @Failure      400    {object}  errResponse    "Не передан телефон"
+// @Failure      401    {object}  errResponse    "Не передан ключ API"
+// @Failure      404    {object}  errResponse    "Гость не найден"
+// @Failure      500    {object}  errResponse    "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /guests/ [get]
+func (h *Handler) findGuest(w http.ResponseWriter, r *http.Request) {
+	phone := r.URL.Query().Get("phone")
+	if phone == "" {
+		_ = render.Render(w, r, errInvalidRequest(ErrGuestMissingFields))
+		return
+	}
+
+	guest, err := h.service.GuestService.GetByPhone(phone)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidData):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, store.ErrGuestNotFound):
+			_ = render.Render(w, r, errNotFound(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	response, err := h.guestResponse(r, guest)
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+	if response == nil {
+		_ = render.Render(w, r, errNotFound(store.ErrGuestNotFound))
+		return
+	}
+
+	_ = render.Render(w, r, response)
+}
+
+// guestResponse подготавливает ответ с гостем и его статистикой по ресторанам, доступным пользователю API.
+// Если пользователю не доступен ни один ресторан с бронями гостя, возвращает nil: такой гость для него
+// не существует (администратору платформы доступны все гости).
+func (h *Handler) guestResponse(r *http.Request, guest *model.Guest) (*guestResponse, error) {
+	user := currentUser(r)
+
+	stats, err := h.service.GuestService.GetStats(guest.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &guestResponse{Guest: guest, Restaurants: make([]model.GuestRestaurantStats, 0, len(stats))}
+	for _, restaurantStats := range stats {
+		if user.CanRead(restaurantStats.RestaurantID) {
+			response.Restaurants = append(response.Restaurants, restaurantStats)
+		}
+	}
+
+	if len(response.Restaurants) == 0 && !user.IsAdmin() {
+		return nil, nil
+	}
+	return response, nil
+}
+
+// guestCtx используется для загрузки гостя вместе с его статистикой (guestResponse) из контекста запроса
+// по guest_id, переданному в параметрах URL запроса. Гость должен быть доступен пользователю API.
+func (h *Handler) guestCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if guestIDStr := chi.URLParam(r, "guest_id"); guestIDStr != "" {
+			guestID, err := strconv.ParseUint(guestIDStr, 10, 0)
+			if err != nil {
+				_ = render.Render(w, r, errInvalidRequest(err))
+				return
+			}
+
+			guest, err := h.service.GuestService.Get(guestID)
+			if err != nil {
+				if errors.Is(err, store.ErrGuestNotFound) {
+					_ = render.Render(w, r, errNotFound(err))
+					return
+				}
+				_ = render.Render(w, r, errServiceFailure(err))
+				return
+			}
+
+			response, err := h.guestResponse(r, guest)
+			if err != nil {
+				_ = render.Render(w, r, errServiceFailure(err))
+				return
+			}
+			if response == nil {
+				_ = render.Render(w, r, errNotFound(store.ErrGuestNotFound))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), guestCtxKey, response)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		} else {
+			_ = render.Render(w, r, errInvalidRequest(ErrGuestMissingFields))
+			return
+		}
+	})
+}
+
+// getGuest godoc
+// @Summary      Получить гостя по его ID
+// @Description  Вместе с гостем возвращается статистика посещений им ресторанов: количество броней, посещений и отмен, средний размер компании и дата последнего посещения. Менеджеру и сотруднику статистика показывается только по их ресторанам.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        guest_id  path      string         true  "ID гостя"
+// @Success      200       {object}  guestResponse  "ok"
+// @Failure      400       {object}  errResponse    "Некорректный ID гостя"
+// @Failure      401       {object}  errResponse    "Не передан ключ API"
+// @Failure      404       {object}  errResponse    "Гость не найден"
+// @Failure      500       {object}  errResponse    "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /guests/{guest_id}/ [get]
+func (h *Handler) getGuest(w http.ResponseWriter, r *http.Request) {
+	response := r.Context().Value(guestCtxKey).(*guestResponse)
+
+	_ = render.Render(w, r, response)
+}
+
+// listGuestBookingsResponse представляет тело ответа на получение истории броней гостя.
+type listGuestBookingsResponse struct {
+	Data []model.Booking `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *listGuestBookingsResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// listGuestBookings godoc
+// @Summary      Получить историю броней гостя
+// @Description  Возвращаются все брони гостя (в том числе отменённые) в ресторанах, доступных пользователю API.
+// @Tags         guests
+// @Accept       json
+// @Produce      json
+// @Param        guest_id  path      string                     true  "ID гостя"
+// @Success      200       {object}  listGuestBookingsResponse  "ok"
+// @Failure      400       {object}  errResponse                "Некорректный ID гостя"
+// @Failure      401       {object}  errResponse                "Не передан ключ API"
+// @Failure      404       {object}  errResponse                "Гость не найден"
+// @Failure      500       {object}  errResponse                "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /guests/{guest_id}/bookings [get]
+func (h *Handler) listGuestBookings(w http.ResponseWriter, r *http.Request) {
+	guest := r.Context().Value(guestCtxKey).(*guestResponse)
+	user := currentUser(r)
+
+	bookings, err := h.service.GuestService.GetBookings(guest.ID)
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	visibleBookings := make([]model.Booking, 0, len(bookings))
+	for _, booking := range bookings {
+		if user.CanRead(booking.RestaurantID) {
+			visibleBookings = append(visibleBookings, booking)
+		}
+	}
+
+	_ = render.Render(w, r, &listGuestBookingsResponse{
+		Data: visibleBookings,
+	})
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"html/template"
 	"net/http"
+	"path/filepath"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -10,6 +12,7 @@ import (
 	"github.com/swaggo/http-swagger"
 
 	_ "github.com/tmrrwnxtsn/restaurant-table-booking-app/docs"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/pkg/logging"
 )
@@ -17,13 +20,24 @@ import (
 // Handler представляет маршрутизатор.
 type Handler struct {
 	service *service.Services
-	logger  *logrus.Logger
+	// events представляет подписку на события ресторанов для потоков событий
+	events pubsub.Subscriber
+	logger *logrus.Logger
+	// websiteDir представляет папку сайта со статическими файлами и шаблонами страниц
+	websiteDir string
+	// templates содержит шаблоны страниц сайта из папки websiteDir
+	templates *template.Template
 }
 
-func NewHandler(services *service.Services, logger *logrus.Logger) *Handler {
+// NewHandler создаёт маршрутизатор, который отображает страницы сайта по шаблонам из папки websiteDir и раздаёт
+// из неё статические файлы. Если шаблоны не загружаются, вызывается паника.
+func NewHandler(services *service.Services, events pubsub.Subscriber, logger *logrus.Logger, websiteDir string) *Handler {
 	return &Handler{
-		service: services,
-		logger:  logger,
+		service:    services,
+		events:     events,
+		logger:     logger,
+		websiteDir: websiteDir,
+		templates:  template.Must(template.ParseGlob(filepath.Join(websiteDir, templatesPattern))),
 	}
 }
 
@@ -37,32 +51,74 @@ func (h *Handler) InitRoutes() *chi.Mux {
 	r.Use(logging.NewStructuredLogger(h.logger))
 	r.Use(middleware.Recoverer)
 
-	// установка таймаута на обработку запроса
-	r.Use(middleware.Timeout(60 * time.Second))
+	// поток событий ресторана открыт, пока его не закроет клиент, поэтому на него не распространяется таймаут
+	// на обработку запроса
+	r.With(h.authenticate, h.restaurantCtx).Get("/api/v1/restaurants/{restaurant_id}/events", h.streamRestaurantEvents)
 
-	// работа системы в визуальном оформлении
-	r.Get("/", h.home) // GET / (начальная страница)
-	r.Route("/restaurants", func(r chi.Router) {
-		r.Get("/", h.restaurants)                                              // GET /restaurants/?people_num=...&desired_datetime=... (страница со всеми доступными ресторанами)
-		r.With(h.restaurantCtx).Post("/{restaurant_id}/booked", h.makeBooking) // POST /restaurants/123/booked (забронировать места в ресторане)
-	})
+	r.Group(func(r chi.Router) {
+		// установка таймаута на обработку запроса
+		r.Use(middleware.Timeout(60 * time.Second))
 
-	// инициализируем FileServer, который будет обрабатывать HTTP-запросы к статическим файлам из папки "./website".
-	fileServer := http.FileServer(http.Dir("./website/"))
-	r.Handle("/static/*", http.StripPrefix("/static", fileServer))
+		// работа системы в визуальном оформлении
+		r.Group(func(r chi.Router) {
+			// гость, вошедший на сайт, определяется по cookie сеанса
+			r.Use(h.customerSession)
 
-	r.Route("/api/v1", func(r chi.Router) {
-		// маршруты для манипуляции ресторанами
-		r.Mount("/restaurants", h.initRestaurantsRouter())
-		// маршруты для манипуляции столиками ресторанов
-		r.Mount("/tables", h.initTablesRouter())
-	})
+			r.Get("/", h.home) // GET / (начальная страница)
+			r.Route("/restaurants", func(r chi.Router) {
+				r.Use(h.allowGuests)                                                              // страницы сайта открывают гости, поэтому ключ API не нужен
+				r.Get("/", h.restaurants)                                                         // GET /restaurants/?people_num=...&desired_datetime=... (страница со всеми доступными ресторанами)
+				r.With(h.restaurantCtx).Post("/{restaurant_id}/booked", h.makeBooking)            // POST /restaurants/123/booked (забронировать места в ресторане)
+				r.With(h.restaurantCtx).Post("/{restaurant_id}/waitlist", h.joinWaitlistByClient) // POST /restaurants/123/waitlist (встать в лист ожидания ресторана)
+			})
+			r.Route("/bookings", func(r chi.Router) {
+				r.Get("/cancel", h.cancelBookingPage)                           // GET /bookings/cancel (страница отмены брони)
+				r.Post("/cancel", h.cancelBookingByClient)                      // POST /bookings/cancel (отменить бронь)
+				r.Get("/manage/{token}", h.manageBookingPage)                   // GET /bookings/manage/... (страница брони по подписанной ссылке)
+				r.Post("/manage/{token}/cancel", h.cancelBookingByLink)         // POST /bookings/manage/.../cancel (отменить бронь по ссылке)
+				r.Post("/manage/{token}/reschedule", h.rescheduleBookingByLink) // POST /bookings/manage/.../reschedule (перенести бронь по ссылке)
+			})
+			r.Route("/waitlist", func(r chi.Router) {
+				r.Get("/", h.waitlistPage)                       // GET /waitlist (страница листа ожидания)
+				r.Post("/", h.waitlistEntryPage)                 // POST /waitlist (узнать состояние записи в листе ожидания)
+				r.Post("/accept", h.acceptWaitlistOfferByClient) // POST /waitlist/accept (оформить бронь по предложению)
+				r.Post("/leave", h.leaveWaitlistByClient)        // POST /waitlist/leave (покинуть лист ожидания)
+			})
+			r.Route("/account", func(r chi.Router) {
+				r.Get("/register", h.registerPage)                                                         // GET /account/register (страница регистрации)
+				r.Post("/register", h.registerCustomer)                                                    // POST /account/register (зарегистрироваться)
+				r.Get("/login", h.loginPage)                                                               // GET /account/login (страница входа)
+				r.Post("/login", h.loginCustomer)                                                          // POST /account/login (войти)
+				r.Post("/logout", h.logoutCustomer)                                                        // POST /account/logout (выйти)
+				r.With(h.requireCustomer).Get("/", h.accountPage)                                          // GET /account (личный кабинет с историей броней)
+				r.With(h.requireCustomer).Post("/bookings/{booking_id}/cancel", h.cancelBookingByCustomer) // POST /account/bookings/123/cancel (отменить бронь)
+			})
+		})
+
+		// инициализируем FileServer, который будет обрабатывать HTTP-запросы к статическим файлам из папки сайта.
+		fileServer := http.FileServer(http.Dir(h.websiteDir))
+		r.Handle("/static/*", http.StripPrefix("/static", fileServer))
 
-	// swagger-документация
-	r.Get("/swagger/*", httpSwagger.WrapHandler)
+		r.Route("/api/v1", func(r chi.Router) {
+			// аутентификация пользователей API по ключу API
+			r.Use(h.authenticate)
 
-	// профилирование
-	r.Mount("/debug", h.initProfilerRouter())
+			// маршруты для манипуляции ресторанами
+			r.Mount("/restaurants", h.initRestaurantsRouter())
+			// маршруты для манипуляции столиками ресторанов
+			r.Mount("/tables", h.initTablesRouter())
+			// маршруты для управления пользователями API
+			r.Mount("/users", h.initUsersRouter())
+			// маршруты для просмотра гостей ресторанов
+			r.Mount("/guests", h.initGuestsRouter())
+		})
+
+		// swagger-документация
+		r.Get("/swagger/*", httpSwagger.WrapHandler)
+
+		// профилирование
+		r.Mount("/debug", h.initProfilerRouter())
+	})
 
 	return r
 }
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// testAdminAPIKey представляет ключ API администратора платформы в тестах.
+const testAdminAPIKey = "test-admin-key"
+
+// testWebsiteDir представляет папку сайта относительно папки пакета, из которой запускаются тесты.
+const testWebsiteDir = "../../../website"
+
+// testServer представляет маршрутизатор сервиса поверх хранилища с одним рестораном.
+type testServer struct {
+	router *chi.Mux
+	store  store.Store
+	// events представляет шину событий ресторанов, через которую сервис оповещает потоки событий
+	events       *pubsub.Hub
+	restaurantID uint64
+}
+
+// newTestServer создаёт маршрутизатор сервиса поверх хранилища в оперативной памяти с рестораном, работающим
+// по графику по умолчанию, и двумя столиками.
+func newTestServer(t *testing.T) *testServer {
+	return newTestServerWithStore(t, memory.NewStore(), 2, 4)
+}
+
+// newTestServerWithStore создаёт маршрутизатор сервиса поверх хранилища st, добавляя в него новый ресторан,
+// работающий по графику по умолчанию, со столиками на seats мест.
+func newTestServerWithStore(t *testing.T, st store.Store, seats ...int) *testServer {
+	t.Helper()
+
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tableSeats := range seats {
+		if _, err = st.Tables().Create(restaurantID, tableSeats, "", model.ZoneHall); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	events := pubsub.NewHub()
+	services := service.NewServices(st, testAdminAPIKey, nil, nil, 0, "test-booking-link-key", events)
+	return &testServer{
+		router:       NewHandler(services, events, logger, testWebsiteDir).InitRoutes(),
+		store:        st,
+		events:       events,
+		restaurantID: restaurantID,
+	}
+}
+
+// do выполняет запрос к маршрутизатору. Запросы к API выполняются от имени администратора платформы.
+func (s *testServer) do(method, target, contentType string, body io.Reader) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	if strings.HasPrefix(target, "/api/") {
+		r.Header.Set("Authorization", "Bearer "+testAdminAPIKey)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+// postForm отправляет форму сайта.
+func (s *testServer) postForm(target string, form url.Values) *httptest.ResponseRecorder {
+	return s.do(http.MethodPost, target, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+}
+
+// bookings возвращает все брони ресторана.
+func (s *testServer) bookings(t *testing.T) []model.Booking {
+	t.Helper()
+
+	bookings, err := s.store.Bookings().GetAll(s.restaurantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bookings
+}
+
+// futureDatetime возвращает момент через неделю в 19:00 (ресторан принимает брони по графику по умолчанию)
+// в формате layout.
+func futureDatetime(layout string) string {
+	week := time.Now().AddDate(0, 0, 7)
+	return time.Date(week.Year(), week.Month(), week.Day(), 19, 0, 0, 0, time.Local).Format(layout)
+}
+
+// maliciousDatetimes содержит некорректные и вредоносные значения желаемых даты и времени посещения ресторана:
+// ни одно из них не должно приводить к брони или ошибке на стороне сервера.
+var maliciousDatetimes = []struct {
+	name  string
+	value string
+}{
+	{name: "sql injection after time", value: futureDatetime("2006.01.02 15:04") + "'; DROP TABLE bookings; --"},
+	{name: "sql injection in date", value: "2026.10.18' OR '1'='1"},
+	{name: "sql injection only", value: "' OR 1=1 --"},
+	{name: "sql function call", value: "2026.10.18 19:00'::time); SELECT pg_sleep(10); --"},
+	{name: "html", value: "<script>alert(1)</script>"},
+	{name: "null byte", value: futureDatetime("2006.01.02 15:04") + "\x00"},
+	{name: "trailing seconds", value: futureDatetime("2006.01.02 15:04") + ":00"},
+	{name: "iso date", value: futureDatetime("2006-01-02 15:04")},
+	{name: "time before date", value: futureDatetime("15:04 2006.01.02")},
+	{name: "nonexistent month", value: "2026.13.01 19:00"},
+	{name: "nonexistent day", value: "2026.02.30 19:00"},
+	{name: "nonexistent hour", value: futureDatetime("2006.01.02") + " 25:00"},
+	{name: "nonexistent minute", value: futureDatetime("2006.01.02") + " 19:61"},
+	{name: "negative year", value: "-2026.10.18 19:00"},
+	{name: "past", value: "2001.09.11 19:00"},
+	{name: "long", value: strings.Repeat("2026.10.18 19:00", 4096)},
+	{name: "unicode digits", value: "２０２６.１０.１８ １９:００"},
+	{name: "whitespace", value: "   "},
+}
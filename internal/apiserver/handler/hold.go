@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	holdCtxKey = "hold"
+	// holdTokenHeader представляет заголовок, в котором передаётся токен удержания, выданный при его создании.
+	holdTokenHeader = "X-Hold-Token"
+)
+
+// createHoldRequest представляет тело запроса на временное удержание столиков в ресторане.
+type createHoldRequest struct {
+	PeopleNumber int `json:"people_number" example:"3"`
+	// DesiredDatetime представляет дату и время посещения ресторана в рамках будущей брони.
+	DesiredDatetime string `json:"desired_datetime" example:"2022.06.16 17:03"`
+	// Zone представляет зону ресторана, в которой гость предпочитает сидеть (необязательно, по умолчанию любая).
+	Zone string `json:"zone" example:"terrace"`
+}
+
+// Bind осуществляет пост-обработку запроса.
+func (r *createHoldRequest) Bind(_ *http.Request) error {
+	if r.PeopleNumber == 0 || r.DesiredDatetime == "" {
+		return ErrHoldMissingFields
+	}
+	if r.Zone != "" && !model.IsZone(r.Zone) {
+		return model.ErrUnknownZone
+	}
+	return nil
+}
+
+// holdResponse представляет тело ответа с информацией об удержании столиков.
+type holdResponse struct {
+	*model.Hold
+}
+
+// Render осуществляет предобработку ответа.
+func (r *holdResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// createHold godoc
+// @Summary      Временно удержать столики в выбранном ресторане
+// @Description  Столики подбираются так же, как при оформлении брони, и удерживаются 5 минут, пока гость заполняет форму брони: другие гости не могут их забронировать. Если удержание не подтвердить, оно снимается автоматически. В ответе возвращается токен удержания: его нужно передавать в заголовке X-Hold-Token, чтобы получить, подтвердить или снять удержание. Один клиент может одновременно удерживать столики не более 3 раз.
+// @Tags         holds
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string             true  "ID ресторана"
+// @Param        input          body      createHoldRequest  true  "Информация о будущей брони"
+// @Success      201            {object}  holdResponse       "ok"
+// @Failure      400            {object}  errResponse        "Некорректные данные брони"
+// @Failure      409            {object}  errResponse        "В ресторане не хватает свободных мест"
+// @Failure      429            {object}  errResponse        "У клиента слишком много действующих удержаний"
+// @Failure      500            {object}  errResponse        "Ошибка на стороне сервера"
+// @Router       /restaurants/{restaurant_id}/holds/ [post]
+func (h *Handler) createHold(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	data := &createHoldRequest{}
+	if err := render.Bind(r, data); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	details := model.BookingDetails{
+		RestaurantID:    restaurant.ID,
+		PeopleNumber:    strconv.Itoa(data.PeopleNumber),
+		DesiredDatetime: data.DesiredDatetime,
+		Zone:            data.Zone,
+	}
+
+	hold, err := h.service.HoldService.Create(details, holdClientKey(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidData):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, service.ErrNotEnoughSeatsInRestaurant):
+			_ = render.Render(w, r, errConflict(err))
+		case errors.Is(err, service.ErrTooManyHolds):
+			_ = render.Render(w, r, errTooManyRequests(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	_ = render.Render(w, r, &holdResponse{hold})
+}
+
+// holdClientKey возвращает ключ клиента, по которому ограничивается количество его удержаний: пользователя API,
+// если запрос аутентифицирован, иначе IP-адрес гостя (middleware.RealIP уже подставил его из заголовков прокси).
+func holdClientKey(r *http.Request) string {
+	if user := currentUser(r); user != nil {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// staffHoldAccess проверяет, обращается ли к удержанию пользователь API без токена удержания. Его доступ к ресторану
+// уже проверен в restaurantCtx, поэтому удержание загружается только по ID.
+func staffHoldAccess(r *http.Request) bool {
+	return r.Header.Get(holdTokenHeader) == "" && currentUser(r) != nil
+}
+
+// holdCtx используется для загрузки удержания столиков (model.Hold) из контекста запроса по hold_id, переданному
+// в параметрах URL запроса, и токену удержания из заголовка X-Hold-Token (пользователям API с доступом к ресторану
+// токен не нужен). Удержание должно относиться к ресторану из контекста запроса.
+func (h *Handler) holdCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if holdIDStr := chi.URLParam(r, "hold_id"); holdIDStr != "" {
+			holdID, err := strconv.ParseUint(holdIDStr, 10, 0)
+			if err != nil {
+				_ = render.Render(w, r, errInvalidRequest(err))
+				return
+			}
+
+			var hold *model.Hold
+			if staffHoldAccess(r) {
+				hold, err = h.service.HoldService.GetByID(holdID)
+			} else {
+				hold, err = h.service.HoldService.Get(holdID, r.Header.Get(holdTokenHeader))
+			}
+			if err != nil {
+				if errors.Is(err, store.ErrHoldNotFound) {
+					_ = render.Render(w, r, errNotFound(err))
+					return
+				}
+				_ = render.Render(w, r, errServiceFailure(err))
+				return
+			}
+
+			restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+			if hold.RestaurantID != restaurant.ID {
+				_ = render.Render(w, r, errNotFound(store.ErrHoldNotFound))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), holdCtxKey, hold)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		} else {
+			_ = render.Render(w, r, errInvalidRequest(ErrHoldMissingFields))
+			return
+		}
+	})
+}
+
+// getHold godoc
+// @Summary  Получить удержание столиков по его ID
+// @Tags     holds
+// @Accept   json
+// @Produce  json
+// @Param    restaurant_id  path      string        true   "ID ресторана"
+// @Param    hold_id        path      string        true   "ID удержания"
+// @Param    X-Hold-Token   header    string        false  "Токен удержания, выданный при его создании (не нужен с ключом API)"
+// @Success  200            {object}  holdResponse  "ok"
+// @Failure  400            {object}  errResponse   "Некорректный ID удержания"
+// @Failure  401            {object}  errResponse   "Не передан ни токен удержания, ни ключ API"
+// @Failure  403            {object}  errResponse   "Нет доступа к ресторану"
+// @Failure  404            {object}  errResponse   "Удержание не найдено"
+// @Failure  500            {object}  errResponse   "Ошибка на стороне сервера"
+// @Router   /restaurants/{restaurant_id}/holds/{hold_id}/ [get]
+func (h *Handler) getHold(w http.ResponseWriter, r *http.Request) {
+	hold := r.Context().Value(holdCtxKey).(*model.Hold)
+
+	if err := render.Render(w, r, &holdResponse{hold}); err != nil {
+		_ = render.Render(w, r, errRender(err))
+		return
+	}
+}
+
+// confirmHoldRequest представляет тело запроса на оформление брони по удержанию столиков.
+type confirmHoldRequest struct {
+	// ClientName имя клиента, оформляющего бронь.
+	ClientName string `json:"client_name" example:"Павел"`
+	// ClientPhone телефон клиента, оформляющего бронь, в международном формате (российский номер можно записать
+	// и через 8).
+	ClientPhone string `json:"client_phone" example:"+79876545654"`
+	// ClientEmail адрес электронной почты клиента для уведомлений о брони (необязательно).
+	ClientEmail string `json:"client_email" example:"pavel@example.com"`
+}
+
+// Bind осуществляет пост-обработку запроса.
+func (r *confirmHoldRequest) Bind(_ *http.Request) error {
+	if r.ClientName == "" || r.ClientPhone == "" {
+		return ErrHoldMissingFields
+	}
+
+	phone, err := model.NormalizePhone(r.ClientPhone)
+	if err != nil {
+		return err
+	}
+	r.ClientPhone = phone
+
+	if r.ClientEmail != "" {
+		if r.ClientEmail, err = model.NormalizeEmail(r.ClientEmail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmHold godoc
+// @Summary      Оформить бронь на удерживаемые столики
+// @Description  Удержание снимается, а столики бронируются на время, на которое они удерживались.
+// @Tags         holds
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                 true   "ID ресторана"
+// @Param        hold_id        path      string                 true   "ID удержания"
+// @Param        X-Hold-Token   header    string                 false  "Токен удержания, выданный при его создании (не нужен с ключом API)"
+// @Param        input          body      confirmHoldRequest     true   "Информация о клиенте"
+// @Success      201            {object}  createBookingResponse  "ok"
+// @Failure      400            {object}  errResponse            "Некорректные данные клиента"
+// @Failure      401            {object}  errResponse            "Не передан ни токен удержания, ни ключ API"
+// @Failure      403            {object}  errResponse            "Нет доступа к ресторану или онлайн-бронирование заблокировано для гостя из-за неявок"
+// @Failure      404            {object}  errResponse            "Удержание не найдено"
+// @Failure      409            {object}  errResponse            "Срок удержания истёк"
+// @Failure      500            {object}  errResponse            "Ошибка на стороне сервера"
+// @Router       /restaurants/{restaurant_id}/holds/{hold_id}/confirm [post]
+func (h *Handler) confirmHold(w http.ResponseWriter, r *http.Request) {
+	hold := r.Context().Value(holdCtxKey).(*model.Hold)
+
+	data := &confirmHoldRequest{}
+	if err := render.Bind(r, data); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	var bookingID uint64
+	var err error
+	if staffHoldAccess(r) {
+		bookingID, err = h.service.HoldService.ConfirmByID(hold.ID, data.ClientName, data.ClientPhone, data.ClientEmail)
+	} else {
+		bookingID, err = h.service.HoldService.Confirm(
+			hold.ID, r.Header.Get(holdTokenHeader), 0, data.ClientName, data.ClientPhone, data.ClientEmail,
+		)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidData):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, store.ErrHoldNotFound):
+			_ = render.Render(w, r, errNotFound(err))
+		case errors.Is(err, service.ErrHoldExpired), errors.Is(err, store.ErrTableAlreadyBooked):
+			_ = render.Render(w, r, errConflict(err))
+		case errors.Is(err, service.ErrGuestBlocked):
+			_ = render.Render(w, r, errForbidden(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	_ = render.Render(w, r, &createBookingResponse{ID: bookingID})
+}
+
+// releaseHoldResponse представляет тело ответа на снятие удержания столиков.
+type releaseHoldResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *releaseHoldResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// releaseHold godoc
+// @Summary  Снять удержание столиков досрочно
+// @Tags     holds
+// @Accept   json
+// @Produce  json
+// @Param    restaurant_id  path      string               true   "ID ресторана"
+// @Param    hold_id        path      string               true   "ID удержания"
+// @Param    X-Hold-Token   header    string               false  "Токен удержания, выданный при его создании (не нужен с ключом API)"
+// @Success  200            {object}  releaseHoldResponse  "ok"
+// @Failure  400            {object}  errResponse          "Некорректный ID удержания"
+// @Failure  401            {object}  errResponse          "Не передан ни токен удержания, ни ключ API"
+// @Failure  403            {object}  errResponse          "Нет доступа к ресторану"
+// @Failure  404            {object}  errResponse          "Удержание не найдено"
+// @Failure  500            {object}  errResponse          "Ошибка на стороне сервера"
+// @Router   /restaurants/{restaurant_id}/holds/{hold_id}/ [delete]
+func (h *Handler) releaseHold(w http.ResponseWriter, r *http.Request) {
+	hold := r.Context().Value(holdCtxKey).(*model.Hold)
+
+	var err error
+	if staffHoldAccess(r) {
+		err = h.service.HoldService.ReleaseByID(hold.ID)
+	} else {
+		err = h.service.HoldService.Release(hold.ID, r.Header.Get(holdTokenHeader))
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrHoldNotFound) {
+			_ = render.Render(w, r, errNotFound(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &releaseHoldResponse{Status: "ok"})
+}
@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// doAsGuest выполняет запрос к маршрутизатору без ключа API от имени гостя с IP-адресом ip. Если token не пустой,
+// он передаётся в заголовке X-Hold-Token.
+func (s *testServer) doAsGuest(method, target, ip, token string, body io.Reader) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, body)
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Real-IP", ip)
+	if token != "" {
+		r.Header.Set(holdTokenHeader, token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+// createdHold представляет поля ответа на удержание столиков, которые нужны в тестах.
+type createdHold struct {
+	ID    uint64 `json:"id"`
+	Token string `json:"token"`
+}
+
+// createHold удерживает столики для компании из peopleNumber человек от имени гостя с IP-адресом ip.
+func (s *testServer) createHold(t *testing.T, ip string, peopleNumber int) (*httptest.ResponseRecorder, *createdHold) {
+	t.Helper()
+
+	body := fmt.Sprintf(`{"people_number": %d, "desired_datetime": %q}`,
+		peopleNumber, futureDatetime("2006.01.02 15:04"))
+	w := s.doAsGuest(http.MethodPost, fmt.Sprintf("/api/v1/restaurants/%d/holds/", s.restaurantID), ip, "",
+		strings.NewReader(body))
+	if w.Code != http.StatusCreated {
+		return w, nil
+	}
+
+	hold := &createdHold{}
+	if err := json.NewDecoder(w.Body).Decode(hold); err != nil {
+		t.Fatal(err)
+	}
+	return w, hold
+}
+
+func TestHold_Token(t *testing.T) {
+	s := newTestServer(t)
+
+	w, hold := s.createHold(t, "203.0.113.7", 2)
+	if hold == nil {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	if len(hold.Token) != 64 {
+		t.Fatalf("hold token = %q, want 32 random bytes in hex", hold.Token)
+	}
+	target := fmt.Sprintf("/api/v1/restaurants/%d/holds/%d/", s.restaurantID, hold.ID)
+	confirmBody := `{"client_name": "Павел", "client_phone": "89485722648"}`
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{name: "get", method: http.MethodGet, target: target},
+		{name: "confirm", method: http.MethodPost, target: target + "confirm", body: confirmBody},
+		{name: "release", method: http.MethodDelete, target: target},
+	}
+	for _, tt := range tests {
+		for _, token := range []string{strings.Repeat("0", 64), hold.Token[1:]} {
+			t.Run(fmt.Sprintf("%s/%q", tt.name, token), func(t *testing.T) {
+				w := s.doAsGuest(tt.method, tt.target, "203.0.113.7", token, strings.NewReader(tt.body))
+				if w.Code != http.StatusNotFound {
+					t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusNotFound, w.Body)
+				}
+			})
+		}
+	}
+
+	// токен не раскрывается повторно, а хранится только его хеш
+	w = s.doAsGuest(http.MethodGet, target, "203.0.113.7", hold.Token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get: status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body)
+	}
+	if strings.Contains(w.Body.String(), hold.Token) {
+		t.Errorf("get: the response contains the hold token; body: %s", w.Body)
+	}
+	stored, err := s.store.Holds().Get(hold.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.TokenHash == "" || stored.TokenHash == hold.Token {
+		t.Errorf("stored token hash = %q, want the SHA-256 of the token", stored.TokenHash)
+	}
+
+	w = s.doAsGuest(http.MethodPost, target+"confirm", "203.0.113.7", hold.Token, strings.NewReader(confirmBody))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("confirm: status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	if bookings := s.bookings(t); len(bookings) != 1 {
+		t.Fatalf("got %d bookings, want 1", len(bookings))
+	}
+
+	// удержание снимается при оформлении брони
+	_, hold = s.createHold(t, "203.0.113.7", 2)
+	target = fmt.Sprintf("/api/v1/restaurants/%d/holds/%d/", s.restaurantID, hold.ID)
+	w = s.doAsGuest(http.MethodDelete, target, "203.0.113.7", hold.Token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("release: status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body)
+	}
+}
+
+func TestCreateHold_TooMany(t *testing.T) {
+	s := newTestServerWithStore(t, memory.NewStore(), 2, 2, 2, 2, 2, 2)
+
+	// гость может одновременно удерживать столики не более 3 раз
+	for i := 0; i < 3; i++ {
+		if w, hold := s.createHold(t, "203.0.113.7", 2); hold == nil {
+			t.Fatalf("hold %d: status = %d, want %d; body: %s", i+1, w.Code, http.StatusCreated, w.Body)
+		}
+	}
+
+	w, _ := s.createHold(t, "203.0.113.7", 2)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusTooManyRequests, w.Body)
+	}
+
+	// ограничение действует для каждого клиента отдельно
+	if w, hold := s.createHold(t, "198.51.100.1", 2); hold == nil {
+		t.Fatalf("another client: status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+}
+
+func TestMakeBooking_HoldToken(t *testing.T) {
+	s := newTestServer(t)
+
+	_, hold := s.createHold(t, "203.0.113.7", 2)
+	if hold == nil {
+		t.Fatal("hold was not created")
+	}
+	target := fmt.Sprintf("/restaurants/%d/booked", s.restaurantID)
+	form := url.Values{
+		"people_number":    {"2"},
+		"desired_datetime": {futureDatetime("2006.01.02 15:04")},
+		"client_name":      {"Павел"},
+		"client_phone":     {"89485722648"},
+		"hold_id":          {fmt.Sprint(hold.ID)},
+		"hold_token":       {strings.Repeat("0", 64)},
+	}
+
+	// с чужим токеном бронь оформляется на свободные столики, а удержание остаётся за тем, кто его создал
+	w := s.postForm(target, form)
+	if !strings.Contains(w.Body.String(), "Бронь успешно оформлена") {
+		t.Fatalf("booking was not created; body: %s", w.Body)
+	}
+	if _, err := s.store.Holds().Get(hold.ID); err != nil {
+		t.Fatalf("hold was confirmed with a wrong token: %v", err)
+	}
+
+	form.Set("hold_token", hold.Token)
+	w = s.postForm(target, form)
+	if !strings.Contains(w.Body.String(), "Бронь успешно оформлена") {
+		t.Fatalf("booking was not created; body: %s", w.Body)
+	}
+	if _, err := s.store.Holds().Get(hold.ID); err == nil {
+		t.Error("hold was not confirmed with its token")
+	}
+}
+
+// createUser создаёт пользователя API с ролью role, закреплённого за рестораном restaurantID, и возвращает его ключ API.
+func (s *testServer) createUser(t *testing.T, role string, restaurantID uint64) string {
+	t.Helper()
+
+	w := s.do(http.MethodPost, "/api/v1/users/", "application/json",
+		strings.NewReader(fmt.Sprintf(`{"name": "Анна", "role": %q, "restaurant_ids": [%d]}`, role, restaurantID)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("user was not created: status = %d; body: %s", w.Code, w.Body)
+	}
+	var user struct {
+		APIKey string `json:"api_key"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&user); err != nil {
+		t.Fatal(err)
+	}
+	return user.APIKey
+}
+
+func TestHold_Access(t *testing.T) {
+	s := newTestServer(t)
+
+	_, hold := s.createHold(t, "203.0.113.7", 2)
+	if hold == nil {
+		t.Fatal("hold was not created")
+	}
+	target := fmt.Sprintf("/api/v1/restaurants/%d/holds/%d/", s.restaurantID, hold.ID)
+
+	otherRestaurantID, err := s.store.Restaurants().Create("Молодость", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherStaff := s.createUser(t, "staff", otherRestaurantID)
+	staff := s.createUser(t, "staff", s.restaurantID)
+	manager := s.createUser(t, "manager", s.restaurantID)
+
+	// запрос к удержанию с ключом API и (или) токеном удержания
+	do := func(method, target, apiKey, token, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			r.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+		if token != "" {
+			r.Header.Set(holdTokenHeader, token)
+		}
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		apiKey   string
+		token    string
+		wantCode int
+	}{
+		{name: "get without token", method: http.MethodGet, target: target, wantCode: http.StatusUnauthorized},
+		{name: "confirm without token", method: http.MethodPost, target: target + "confirm", wantCode: http.StatusUnauthorized},
+		{name: "release without token", method: http.MethodDelete, target: target, wantCode: http.StatusUnauthorized},
+		{name: "another restaurant's staff", method: http.MethodGet, target: target, apiKey: otherStaff, wantCode: http.StatusForbidden},
+		{name: "staff cannot release", method: http.MethodDelete, target: target, apiKey: staff, wantCode: http.StatusForbidden},
+		{name: "admin with a wrong token", method: http.MethodGet, target: target, apiKey: testAdminAPIKey, token: strings.Repeat("0", 64), wantCode: http.StatusNotFound},
+		{name: "admin without token", method: http.MethodGet, target: target, apiKey: testAdminAPIKey, wantCode: http.StatusOK},
+		{name: "staff without token", method: http.MethodGet, target: target, apiKey: staff, wantCode: http.StatusOK},
+		{name: "guest with token", method: http.MethodGet, target: target, token: hold.Token, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(tt.method, tt.target, tt.apiKey, tt.token, ""); w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.wantCode, w.Body)
+			}
+		})
+	}
+	if _, err = s.store.Holds().Get(hold.ID); err != nil {
+		t.Fatalf("hold was confirmed or released without access to it: %v", err)
+	}
+
+	// менеджер ресторана подтверждает и снимает удержания гостей без их токенов
+	w := do(http.MethodPost, target+"confirm", manager, "", `{"client_name": "Павел", "client_phone": "89485722648"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("confirm: status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	if bookings := s.bookings(t); len(bookings) != 1 {
+		t.Fatalf("got %d bookings, want 1", len(bookings))
+	}
+
+	_, hold = s.createHold(t, "203.0.113.7", 2)
+	target = fmt.Sprintf("/api/v1/restaurants/%d/holds/%d/", s.restaurantID, hold.ID)
+	if w = do(http.MethodDelete, target, manager, "", ""); w.Code != http.StatusOK {
+		t.Fatalf("release: status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body)
+	}
+	if _, err = s.store.Holds().Get(hold.ID); err == nil {
+		t.Error("hold was not released by the restaurant's manager")
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+)
+
+// getOpeningHoursResponse представляет тело ответа на получение графика работы ресторана.
+type getOpeningHoursResponse struct {
+	Data []model.OpeningHours `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getOpeningHoursResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getOpeningHours godoc
+// @Summary      Получить недельный график работы ресторана
+// @Description  Если ресторану не задан собственный график, возвращается график по умолчанию (ежедневно с 9:00 до 23:00, последняя бронь - на 21:00).
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                   true  "ID ресторана"
+// @Success      200            {object}  getOpeningHoursResponse  "ok"
+// @Failure      400            {object}  errResponse              "Некорректный ID ресторана"
+// @Failure      500            {object}  errResponse              "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/opening-hours/ [get]
+func (h *Handler) getOpeningHours(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	_ = render.Render(w, r, &getOpeningHoursResponse{
+		Data: restaurant.OpeningHours,
+	})
+}
+
+// setOpeningHoursResponse представляет тело ответа на замену графика работы ресторана.
+type setOpeningHoursResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *setOpeningHoursResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// setOpeningHours godoc
+// @Summary      Заменить недельный график работы ресторана
+// @Description  В один день недели можно задать несколько смен (например, обеденную и вечернюю). Если время закрытия не позже времени открытия, смена заканчивается на следующий день (например, с 18:00 до 02:00). Пустой список смен возвращает ресторан к графику по умолчанию.
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                     true  "ID ресторана"
+// @Param        input          body      model.SetOpeningHoursData  true  "График работы ресторана"
+// @Success      200            {object}  setOpeningHoursResponse    "ok"
+// @Failure      400            {object}  errResponse                "Некорректный график работы"
+// @Failure      500            {object}  errResponse                "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/opening-hours/ [put]
+func (h *Handler) setOpeningHours(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	data := model.SetOpeningHoursData{}
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	if err := h.service.RestaurantService.SetOpeningHours(restaurant.ID, data.OpeningHours); err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &setOpeningHoursResponse{Status: "ok"})
+}
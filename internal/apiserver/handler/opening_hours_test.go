@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// TestOpeningHours_Overnight проверяет, что ресторан, который закрывается после полуночи, принимает брони
+// и показывает свободное время до последней брони следующего дня.
+func TestOpeningHours_Overnight(t *testing.T) {
+	s := newTestServer(t)
+
+	// ресторан ежедневно работает с 18:00 до 02:00, последнюю бронь можно оформить на 01:00
+	shifts := make([]map[string]interface{}, 0, 7)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		shifts = append(shifts, map[string]interface{}{
+			"weekday": weekday, "opens_at": "18:00", "closes_at": "02:00", "last_seating": "01:00",
+		})
+	}
+	body, err := json.Marshal(map[string]interface{}{"opening_hours": shifts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := fmt.Sprintf("/api/v1/restaurants/%d/opening-hours/", s.restaurantID)
+	if w := s.do(http.MethodPut, target, "application/json", strings.NewReader(string(body))); w.Code != http.StatusOK {
+		t.Fatalf("set opening hours: status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	// в сетке доступности дня есть время после полуночи (смена предыдущего дня) и вечернее время
+	day := time.Now().AddDate(0, 0, 7)
+	target = fmt.Sprintf("/api/v1/restaurants/%d/availability?", s.restaurantID) + url.Values{
+		"date":   {day.Format("2006.01.02")},
+		"people": {"2"},
+	}.Encode()
+	w := s.do(http.MethodGet, target, "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("availability: status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var availability struct {
+		Slots []struct {
+			Time string `json:"time"`
+		} `json:"slots"`
+	}
+	if err = json.Unmarshal(w.Body.Bytes(), &availability); err != nil {
+		t.Fatal(err)
+	}
+	slots := make(map[string]bool, len(availability.Slots))
+	for _, slot := range availability.Slots {
+		slots[slot.Time] = true
+	}
+	for _, clock := range []string{"00:00", "01:00", "18:00", "23:45"} {
+		if !slots[clock] {
+			t.Errorf("availability has no %s slot; slots: %v", clock, availability.Slots)
+		}
+	}
+	for _, clock := range []string{"01:15", "12:00", "17:45"} {
+		if slots[clock] {
+			t.Errorf("availability has %s slot, want the restaurant closed", clock)
+		}
+	}
+
+	target = fmt.Sprintf("/api/v1/restaurants/%d/bookings/", s.restaurantID)
+	at := func(hour, minute int) string {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local).Format("2006.01.02 15:04")
+	}
+	tests := []struct {
+		name     string
+		datetime string
+		wantCode int
+	}{
+		{name: "after midnight", datetime: at(0, 30), wantCode: http.StatusCreated},
+		{name: "evening", datetime: at(21, 0), wantCode: http.StatusCreated},
+		{name: "after last seating", datetime: at(1, 30), wantCode: http.StatusBadRequest},
+		{name: "afternoon", datetime: at(12, 0), wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, target, "application/json", strings.NewReader(bookingJSON(t, tt.datetime)))
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.wantCode, w.Body)
+			}
+		})
+	}
+
+	// смена, в которой последняя бронь приходится на время после закрытия, отклоняется
+	body, err = json.Marshal(model.SetOpeningHoursData{OpeningHours: []model.OpeningHours{{
+		Weekday:     int(time.Monday),
+		OpensAt:     model.NewShortFormattedTime(18, 0),
+		ClosesAt:    model.NewShortFormattedTime(2, 0),
+		LastSeating: model.NewShortFormattedTime(3, 0),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target = fmt.Sprintf("/api/v1/restaurants/%d/opening-hours/", s.restaurantID)
+	if w = s.do(http.MethodPut, target, "application/json", strings.NewReader(string(body))); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid overnight shift: status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body)
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+)
+
+// getReliabilityPolicyResponse представляет тело ответа на получение правил надёжности гостей ресторана.
+type getReliabilityPolicyResponse struct {
+	Data model.ReliabilityPolicy `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getReliabilityPolicyResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getReliabilityPolicy godoc
+// @Summary      Получить правила надёжности гостей ресторана
+// @Description  Нулевые значения означают, что ограничение не действует: если ресторан не задал правила, онлайн-бронирование доступно всем гостям.
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                        true  "ID ресторана"
+// @Success      200            {object}  getReliabilityPolicyResponse  "ok"
+// @Failure      400            {object}  errResponse                   "Некорректный ID ресторана"
+// @Failure      500            {object}  errResponse                   "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/reliability-policy/ [get]
+func (h *Handler) getReliabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	policy, err := h.service.RestaurantService.GetReliabilityPolicy(restaurant.ID)
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &getReliabilityPolicyResponse{
+		Data: policy,
+	})
+}
+
+// setReliabilityPolicyResponse представляет тело ответа на замену правил надёжности гостей ресторана.
+type setReliabilityPolicyResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *setReliabilityPolicyResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// setReliabilityPolicy godoc
+// @Summary      Заменить правила надёжности гостей ресторана
+// @Description  Неявки гостя считаются по его телефону в этом ресторане. Начиная с confirmation_after неявок брони гостя, оформленные на сайте, ждут подтверждения рестораном (статус pending), а начиная с block_after неявок гость не может бронировать столики на сайте в течение block_days дней после последней неявки. Брони, которые оформляет ресторан через API, не ограничиваются.
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                        true  "ID ресторана"
+// @Param        input          body      model.ReliabilityPolicy       true  "Правила надёжности гостей"
+// @Success      200            {object}  setReliabilityPolicyResponse  "ok"
+// @Failure      400            {object}  errResponse                   "Некорректные правила надёжности гостей"
+// @Failure      500            {object}  errResponse                   "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/reliability-policy/ [put]
+func (h *Handler) setReliabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	policy := model.ReliabilityPolicy{}
+	if err := render.Bind(r, &policy); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	if err := h.service.RestaurantService.SetReliabilityPolicy(restaurant.ID, policy); err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &setReliabilityPolicyResponse{Status: "ok"})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/go-chi/render"
 
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
 )
 
@@ -18,13 +19,29 @@ const restaurantCtxKey = "restaurant"
 // initRestaurantsRouter подготавливает отдельный маршрутизатор для манипуляции ресторанами.
 func (h *Handler) initRestaurantsRouter() http.Handler {
 	r := chi.NewRouter()
-	r.Post("/", h.createRestaurant) // POST /restaurants/
-	r.Get("/", h.listRestaurants)   // GET /restaurants/
+	r.With(h.requireRole(model.UserRoleAdmin)).Post("/", h.createRestaurant) // POST /restaurants/
+	r.With(h.requireUser).Get("/", h.listRestaurants)                        // GET /restaurants/
+	r.With(h.requireUser).Get("/available", h.listAvailableRestaurants)      // GET /restaurants/available?desired_datetime=...&people_number=...
 	r.Route("/{restaurant_id}", func(r chi.Router) {
-		r.Use(h.restaurantCtx)            // загрузить информацию о ресторане из контекста запроса
-		r.Get("/", h.getRestaurant)       // GET /restaurants/123/
-		r.Patch("/", h.updateRestaurant)  // PATCH /restaurants/123/
-		r.Delete("/", h.deleteRestaurant) // DELETE /restaurants/123/
+		r.Use(h.restaurantCtx)                                                     // загрузить информацию о ресторане из контекста запроса
+		r.Get("/", h.getRestaurant)                                                // GET /restaurants/123/
+		r.Patch("/", h.updateRestaurant)                                           // PATCH /restaurants/123/
+		r.With(h.requireRole(model.UserRoleAdmin)).Delete("/", h.deleteRestaurant) // DELETE /restaurants/123/
+		r.Get("/availability", h.getAvailability)                                  // GET /restaurants/123/availability?date=2022.06.16&people=4
+		// поток событий ресторана (GET /restaurants/123/events) регистрируется в InitRoutes, чтобы на него
+		// не распространялся таймаут на обработку запроса
+		r.Route("/opening-hours", func(r chi.Router) { // работа с графиком работы ресторана
+			r.Get("/", h.getOpeningHours) // GET /restaurants/123/opening-hours
+			r.Put("/", h.setOpeningHours) // PUT /restaurants/123/opening-hours
+		})
+		r.Route("/duration-policy", func(r chi.Router) { // работа с правилами длительности брони
+			r.Get("/", h.getDurationPolicy) // GET /restaurants/123/duration-policy
+			r.Put("/", h.setDurationPolicy) // PUT /restaurants/123/duration-policy
+		})
+		r.Route("/reliability-policy", func(r chi.Router) { // работа с правилами надёжности гостей
+			r.Get("/", h.getReliabilityPolicy) // GET /restaurants/123/reliability-policy
+			r.Put("/", h.setReliabilityPolicy) // PUT /restaurants/123/reliability-policy
+		})
 		r.Route("/tables", func(r chi.Router) { // работа со столиками ресторанов
 			r.Post("/", h.createTable) // POST /restaurants/123/tables
 			r.Get("/", h.listTables)   // GET /restaurants/123/tables
@@ -32,6 +49,49 @@ func (h *Handler) initRestaurantsRouter() http.Handler {
 		r.Route("/bookings", func(r chi.Router) { // работа со бронями ресторанов
 			r.Post("/", h.createBooking) // POST /restaurants/123/bookings
 			r.Get("/", h.listBookings)   // GET /restaurants/123/bookings
+			r.Route("/{booking_id}", func(r chi.Router) {
+				r.Use(h.bookingCtx)                    // загрузить информацию о брони из контекста запроса
+				r.Get("/", h.getBooking)               // GET /restaurants/123/bookings/456
+				r.Patch("/", h.updateBooking)          // PATCH /restaurants/123/bookings/456
+				r.Delete("/", h.cancelBooking)         // DELETE /restaurants/123/bookings/456
+				r.Post("/confirm", h.confirmBooking)   // POST /restaurants/123/bookings/456/confirm
+				r.Post("/check-in", h.checkInBooking)  // POST /restaurants/123/bookings/456/check-in
+				r.Post("/no-show", h.markNoShow)       // POST /restaurants/123/bookings/456/no-show
+				r.Post("/complete", h.completeBooking) // POST /restaurants/123/bookings/456/complete
+			})
+		})
+		r.Route("/waitlist", func(r chi.Router) { // работа с листом ожидания ресторана
+			r.Post("/", h.joinWaitlist) // POST /restaurants/123/waitlist
+			r.Get("/", h.listWaitlist)  // GET /restaurants/123/waitlist
+			r.Route("/{entry_id}", func(r chi.Router) {
+				r.Use(h.waitlistEntryCtx)                // загрузить информацию о записи в листе ожидания из контекста запроса
+				r.Get("/", h.getWaitlistEntry)           // GET /restaurants/123/waitlist/456
+				r.Post("/accept", h.acceptWaitlistOffer) // POST /restaurants/123/waitlist/456/accept
+				r.Delete("/", h.leaveWaitlist)           // DELETE /restaurants/123/waitlist/456
+			})
+		})
+		// вебхуки раскрывают ключи подписи и брони гостей, поэтому ими управляют только администратор и менеджер
+		r.With(h.requireRole(model.UserRoleAdmin, model.UserRoleManager)).Route("/webhooks", func(r chi.Router) {
+			r.Post("/", h.createWebhook) // POST /restaurants/123/webhooks
+			r.Get("/", h.listWebhooks)   // GET /restaurants/123/webhooks
+			r.Route("/{webhook_id}", func(r chi.Router) {
+				r.Use(h.webhookCtx)                           // загрузить информацию о вебхуке из контекста запроса
+				r.Get("/", h.getWebhook)                      // GET /restaurants/123/webhooks/456
+				r.Delete("/", h.deleteWebhook)                // DELETE /restaurants/123/webhooks/456
+				r.Get("/deliveries", h.listWebhookDeliveries) // GET /restaurants/123/webhooks/456/deliveries
+			})
+		})
+	})
+	r.Route("/{restaurant_id}/holds", func(r chi.Router) { // работа с временными удержаниями столиков
+		// столики удерживают гости, пока оформляют бронь на сайте, поэтому ключ API для этого не нужен
+		r.With(h.allowGuests, h.restaurantCtx).Post("/", h.createHold) // POST /restaurants/123/holds
+		r.Route("/{hold_id}", func(r chi.Router) {
+			r.Use(h.allowHoldOwners)          // без ключа API пропустить только гостей с токеном удержания
+			r.Use(h.restaurantCtx)            // загрузить информацию о ресторане из контекста запроса
+			r.Use(h.holdCtx)                  // загрузить информацию об удержании из контекста запроса
+			r.Get("/", h.getHold)             // GET /restaurants/123/holds/456
+			r.Post("/confirm", h.confirmHold) // POST /restaurants/123/holds/456/confirm
+			r.Delete("/", h.releaseHold)      // DELETE /restaurants/123/holds/456
 		})
 	})
 	return r
@@ -63,15 +123,16 @@ func (r *createRestaurantResponse) Render(_ http.ResponseWriter, _ *http.Request
 }
 
 // createRestaurant godoc
-// @Summary  Создать ресторан
-// @Tags     restaurants
-// @Accept   json
-// @Produce  json
-// @Param    input  body      createRestaurantRequest   true  "Информация о ресторане"
-// @Success  201    {object}  createRestaurantResponse  "ok"
-// @Failure  400    {object}  errResponse               "Некорректные данные ресторана"
-// @Failure  500    {object}  errResponse               "Ошибка на стороне сервера"
-// @Router   /restaurants/ [post]
+// @Summary   Создать ресторан
+// @Tags      restaurants
+// @Accept    json
+// @Produce   json
+// @Param     input  body      createRestaurantRequest   true  "Информация о ресторане"
+// @Success   201    {object}  createRestaurantResponse  "ok"
+// @Failure   400    {object}  errResponse               "Некорректные данные ресторана"
+// @Failure   500    {object}  errResponse               "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/ [post]
 func (h *Handler) createRestaurant(w http.ResponseWriter, r *http.Request) {
 	data := &createRestaurantRequest{}
 	if err := render.Bind(r, data); err != nil {
@@ -102,13 +163,14 @@ func (r *listRestaurantsResponse) Render(_ http.ResponseWriter, _ *http.Request)
 }
 
 // listRestaurants godoc
-// @Summary  Получить список всех ресторанов
-// @Tags     restaurants
-// @Accept   json
-// @Produce  json
-// @Success  200  {object}  listRestaurantsResponse  "ok"
-// @Failure  500  {object}  errResponse              "Ошибка на стороне сервера"
-// @Router   /restaurants/ [get]
+// @Summary   Получить список всех ресторанов (менеджерам и сотрудникам - закреплённых за ними)
+// @Tags      restaurants
+// @Accept    json
+// @Produce   json
+// @Success   200  {object}  listRestaurantsResponse  "ok"
+// @Failure   500  {object}  errResponse              "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/ [get]
 func (h *Handler) listRestaurants(w http.ResponseWriter, r *http.Request) {
 	restaurants, err := h.service.RestaurantService.GetAll()
 	if err != nil {
@@ -116,13 +178,91 @@ func (h *Handler) listRestaurants(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// менеджеры и сотрудники видят только закреплённые за ними рестораны
+	user := currentUser(r)
+	visibleRestaurants := make([]model.Restaurant, 0, len(restaurants))
+	for _, restaurant := range restaurants {
+		if user.CanRead(restaurant.ID) {
+			visibleRestaurants = append(visibleRestaurants, restaurant)
+		}
+	}
+
 	_ = render.Render(w, r, &listRestaurantsResponse{
-		Data: restaurants,
+		Data: visibleRestaurants,
 	})
 }
 
+// listAvailableRestaurantsResponse представляет тело ответа на поиск ресторанов, в которых можно забронировать столики.
+type listAvailableRestaurantsResponse struct {
+	Data []model.Restaurant `json:"data"`
+	// Alternatives представляет варианты для гостя, если на желаемое время не нашлось ни одного ресторана.
+	Alternatives *model.Alternatives `json:"alternatives,omitempty"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *listAvailableRestaurantsResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// listAvailableRestaurants godoc
+// @Summary      Найти рестораны, в которых можно забронировать столики
+// @Description  Если на желаемое время не нашлось ни одного ресторана, в ответ добавляются варианты: ближайшие моменты начала брони (раньше или позже в тот же день и в следующие 3 дня) и рестораны, в которых можно рассадить компанию в ближайшее к желаемому время.
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
+// @Param        desired_datetime  query     string                            true   "Желаемые дата и время посещения ресторана"  example(2022.06.16 17:00)
+// @Param        people_number     query     int                               true   "Количество человек"                         example(4)
+// @Param        zone              query     string                            false  "Предпочитаемая зона ресторана"
+// @Success      200               {object}  listAvailableRestaurantsResponse  "ok"
+// @Failure      400               {object}  errResponse                       "Некорректные дата и время, количество человек или зона"
+// @Failure      500               {object}  errResponse                       "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/available [get]
+func (h *Handler) listAvailableRestaurants(w http.ResponseWriter, r *http.Request) {
+	desiredDateTime := r.URL.Query().Get("desired_datetime")
+	peopleNumber := r.URL.Query().Get("people_number")
+	zone := r.URL.Query().Get("zone")
+
+	if desiredDateTime == "" || peopleNumber == "" {
+		_ = render.Render(w, r, errInvalidRequest(ErrFindAvailableRestaurants))
+		return
+	}
+
+	restaurants, alternatives, err := h.findAvailableRestaurants(desiredDateTime, peopleNumber, zone)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &listAvailableRestaurantsResponse{
+		Data:         restaurants,
+		Alternatives: alternatives,
+	})
+}
+
+// findAvailableRestaurants ищет рестораны, в которых можно забронировать столики, а если таких нет - подбирает
+// варианты для гостя (иначе варианты равны nil).
+func (h *Handler) findAvailableRestaurants(
+	desiredDateTime, peopleNumber, zone string,
+) ([]model.Restaurant, *model.Alternatives, error) {
+	restaurants, err := h.service.RestaurantService.GetAllAvailable(desiredDateTime, peopleNumber, zone)
+	if err != nil || len(restaurants) > 0 {
+		return restaurants, nil, err
+	}
+
+	alternatives, err := h.service.RestaurantService.GetAlternatives(desiredDateTime, peopleNumber, zone)
+	if err != nil {
+		return nil, nil, err
+	}
+	return restaurants, alternatives, nil
+}
+
 // restaurantCtx используется для загрузки ресторана (model.Restaurant) из контекста запроса по restaurant_id,
-// переданному в параметрах URL запроса.
+// переданному в параметрах URL запроса. Пользователь API должен иметь доступ к ресторану (см. authorizeRestaurant).
 func (h *Handler) restaurantCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if restaurantIDStr := chi.URLParam(r, "restaurant_id"); restaurantIDStr != "" {
@@ -142,6 +282,11 @@ func (h *Handler) restaurantCtx(next http.Handler) http.Handler {
 				return
 			}
 
+			// менеджеры и сотрудники работают только с закреплёнными за ними ресторанами
+			if !authorizeRestaurant(w, r, restaurant.ID) {
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), restaurantCtxKey, restaurant)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		} else {
@@ -162,15 +307,16 @@ func (r *getRestaurantResponse) Render(_ http.ResponseWriter, _ *http.Request) e
 }
 
 // getRestaurant godoc
-// @Summary  Получить ресторан по его ID
-// @Tags     restaurants
-// @Accept   json
-// @Produce  json
-// @Param    restaurant_id  path      string                 true  "ID ресторана"
-// @Success  200            {object}  getRestaurantResponse  "ok"
-// @Failure  400            {object}  errResponse            "Некорректный ID ресторана"
-// @Failure  500            {object}  errResponse            "Ошибка на стороне сервера"
-// @Router   /restaurants/{restaurant_id}/ [get]
+// @Summary   Получить ресторан по его ID
+// @Tags      restaurants
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string                 true  "ID ресторана"
+// @Success   200            {object}  getRestaurantResponse  "ok"
+// @Failure   400            {object}  errResponse            "Некорректный ID ресторана"
+// @Failure   500            {object}  errResponse            "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/ [get]
 func (h *Handler) getRestaurant(w http.ResponseWriter, r *http.Request) {
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
 
@@ -193,14 +339,15 @@ func (r *updateRestaurantResponse) Render(_ http.ResponseWriter, _ *http.Request
 // updateRestaurant godoc
 // @Summary      Обновить информацию о ресторане по его ID
 // @Description  Обновление происходит именно путём PATCH-запросов, чтобы была возможность изменять данные частично.
-// @Tags     	 restaurants
-// @Accept   	 json
-// @Produce  	 json
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
 // @Param        restaurant_id  path      string                      true  "ID ресторана"
 // @Param        input          body      model.UpdateRestaurantData  true  "Информация о ресторане"
 // @Success      200            {object}  updateRestaurantResponse    "ok"
 // @Failure      400            {object}  errResponse                 "Некорректный данные запроса"
 // @Failure      500            {object}  errResponse                 "Ошибка на стороне сервера"
+// @Security     BearerAuth
 // @Router       /restaurants/{restaurant_id}/ [patch]
 func (h *Handler) updateRestaurant(w http.ResponseWriter, r *http.Request) {
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
@@ -232,13 +379,14 @@ func (r *deleteRestaurantResponse) Render(_ http.ResponseWriter, _ *http.Request
 // deleteRestaurant godoc
 // @Summary      Удаляет ресторан по его ID
 // @Description  Если ресторан ожидает гостей (есть брони в будущем/сегодняшнем днях в этом ресторане), то его нельзя удалить.
-// @Tags     	 restaurants
-// @Accept   	 json
-// @Produce  	 json
+// @Tags         restaurants
+// @Accept       json
+// @Produce      json
 // @Param        restaurant_id  path      string                    true  "ID ресторана"
 // @Success      200            {object}  deleteRestaurantResponse  "ok"
 // @Failure      400            {object}  errResponse               "Некорректный данные запроса"
 // @Failure      500            {object}  errResponse               "Ошибка на стороне сервера"
+// @Security     BearerAuth
 // @Router       /restaurants/{restaurant_id}/ [delete]
 func (h *Handler) deleteRestaurant(w http.ResponseWriter, r *http.Request) {
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+const (
+	// eventsHeartbeatInterval представляет период, с которым в простаивающий поток событий отправляется комментарий:
+	// так прокси-серверы не закрывают соединение, а закрытое клиентом соединение быстрее обнаруживается.
+	eventsHeartbeatInterval = 15 * time.Second
+	// eventsWriteTimeout ограничивает время отправки клиенту одного события.
+	eventsWriteTimeout = 10 * time.Second
+	// eventsRetry представляет, через сколько клиенту стоит переподключиться, если поток событий закрылся.
+	eventsRetry = 3 * time.Second
+)
+
+// streamRestaurantEvents godoc
+// @Summary      Получить поток событий ресторана
+// @Description  Поток Server-Sent Events (text/event-stream) с событиями ресторана в реальном времени: booking.created (бронь оформлена), booking.updated (бронь перенесена или изменилось количество гостей), booking.cancelled (бронь отменена) и table.changed (столик добавлен, изменён, удалён или изменилось, с какими столиками его можно сдвинуть; у удалённого столика нет поля table). Каждое событие отправляется как "event: <событие>" и "data: <model.RestaurantEvent в формате JSON>". Поток закрывается, если клиент не успевает разбирать события или часть событий могла быть потеряна: тогда стоит заново загрузить брони и столики и переподключиться.
+// @Tags         restaurants
+// @Produce      text/event-stream
+// @Param        restaurant_id  path      string                 true  "ID ресторана"
+// @Success      200            {object}  model.RestaurantEvent  "ok"
+// @Failure      400            {object}  errResponse            "Некорректный ID ресторана"
+// @Failure      401            {object}  errResponse            "Не передан ключ API"
+// @Failure      403            {object}  errResponse            "Недостаточно прав"
+// @Failure      404            {object}  errResponse            "Ресторан не найден"
+// @Failure      500            {object}  errResponse            "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/events [get]
+func (h *Handler) streamRestaurantEvents(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	// HTTP-сервер ограничивает время записи всего ответа, а поток событий открыт, пока его не закроет клиент,
+	// поэтому соединение забирается у сервера, а время записи ограничивается для каждого события отдельно
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		_ = render.Render(w, r, errServiceFailure(ErrStreamingUnsupported))
+		return
+	}
+
+	events, unsubscribe := h.events.Subscribe(restaurant.ID)
+	defer unsubscribe()
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+	defer conn.Close()
+
+	stream := &eventStream{conn: conn, rw: rw}
+	if err = stream.write(
+		"HTTP/1.1 200 OK\r\n" +
+			"Content-Type: text/event-stream\r\n" +
+			"Cache-Control: no-cache\r\n" +
+			"Connection: close\r\n" +
+			// запрещаем nginx буферизовать поток
+			"X-Accel-Buffering: no\r\n" +
+			"\r\n" +
+			fmt.Sprintf("retry: %d\n\n", eventsRetry.Milliseconds()),
+	); err != nil {
+		return
+	}
+
+	// клиент ничего не отправляет в поток, поэтому чтение завершается, только когда соединение закрыто
+	// (срок чтения, установленный HTTP-сервером, снимаем, иначе поток закрылся бы через ReadTimeout)
+	if err = conn.SetReadDeadline(time.Time{}); err != nil {
+		return
+	}
+	disconnected := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(ioutil.Discard, rw)
+		close(disconnected)
+	}()
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// подписка закрыта: клиент переподключится и заново загрузит брони и столики
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger.Errorf("failed to encode restaurant event: %s", err)
+				continue
+			}
+			if err = stream.write(fmt.Sprintf("event: %s\ndata: %s\n\n", event.Event, data)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := stream.write(": ping\n\n"); err != nil {
+				return
+			}
+		case <-disconnected:
+			return
+		}
+	}
+}
+
+// eventStream представляет соединение, забранное у HTTP-сервера для потока событий.
+type eventStream struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// write отправляет клиенту str, ожидая не дольше eventsWriteTimeout.
+func (s *eventStream) write(str string) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout)); err != nil {
+		return err
+	}
+	if _, err := s.rw.WriteString(str); err != nil {
+		return err
+	}
+	return s.rw.Flush()
+}
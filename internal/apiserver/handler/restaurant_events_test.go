@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// openEventStream подключается к потоку событий ресторана restaurantID на сервере server от имени администратора
+// платформы и возвращает поток, из которого уже прочитаны заголовки и интервал переподключения.
+func openEventStream(t *testing.T, server *httptest.Server, restaurantID uint64) *bufio.Reader {
+	t.Helper()
+
+	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/restaurants/%d/events", server.URL, restaurantID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Authorization", "Bearer "+testAdminAPIKey)
+
+	// тест не должен зависнуть, если событие так и не пришло
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", contentType)
+	}
+
+	stream := bufio.NewReader(resp.Body)
+	if line, err := stream.ReadString('\n'); err != nil || line != fmt.Sprintf("retry: %d\n", eventsRetry.Milliseconds()) {
+		t.Fatalf("first line = %q, %v; want the reconnection interval", line, err)
+	}
+	if line, err := stream.ReadString('\n'); err != nil || line != "\n" {
+		t.Fatalf("second line = %q, %v; want an empty line", line, err)
+	}
+	return stream
+}
+
+// streamedEvent представляет поля события ресторана, которые проверяют тесты (даты брони в JSON только выводятся).
+type streamedEvent struct {
+	Event        string `json:"event"`
+	RestaurantID uint64 `json:"restaurant_id"`
+	Booking      *struct {
+		ID         uint64 `json:"id"`
+		ClientName string `json:"client_name"`
+	} `json:"booking"`
+	TableID uint64          `json:"table_id"`
+	Table   json.RawMessage `json:"table"`
+}
+
+// readEvent читает из потока следующее событие, пропуская комментарии.
+func readEvent(t *testing.T, stream *bufio.Reader) streamedEvent {
+	t.Helper()
+
+	var name, data string
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && name != "":
+			var event streamedEvent
+			if err = json.Unmarshal([]byte(data), &event); err != nil {
+				t.Fatalf("decode event %q: %v", data, err)
+			}
+			if event.Event != name {
+				t.Fatalf("event field = %q, want %q as in the event line", event.Event, name)
+			}
+			return event
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestStreamRestaurantEvents(t *testing.T) {
+	s := newTestServer(t)
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+
+	// события другого ресторана не попадают в поток
+	otherRestaurantID, err := s.store.Restaurants().Create("Маяк", 15, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.store.Tables().Create(otherRestaurantID, 4, "", model.ZoneHall); err != nil {
+		t.Fatal(err)
+	}
+
+	stream := openEventStream(t, server, s.restaurantID)
+
+	body := bookingJSON(t, futureDatetime("2006.01.02 15:04"))
+	target := fmt.Sprintf("/api/v1/restaurants/%d/bookings/", otherRestaurantID)
+	if w := s.do(http.MethodPost, target, "application/json", strings.NewReader(body)); w.Code != http.StatusCreated {
+		t.Fatalf("create booking in another restaurant: status = %d; body: %s", w.Code, w.Body)
+	}
+	target = fmt.Sprintf("/api/v1/restaurants/%d/bookings/", s.restaurantID)
+	if w := s.do(http.MethodPost, target, "application/json", strings.NewReader(body)); w.Code != http.StatusCreated {
+		t.Fatalf("create booking: status = %d; body: %s", w.Code, w.Body)
+	}
+	bookingID := s.bookings(t)[0].ID
+
+	event := readEvent(t, stream)
+	if event.Event != model.RestaurantEventBookingCreated || event.RestaurantID != s.restaurantID {
+		t.Fatalf("event = %s in restaurant %d, want %s in restaurant %d",
+			event.Event, event.RestaurantID, model.RestaurantEventBookingCreated, s.restaurantID)
+	}
+	if event.Booking == nil || event.Booking.ID != bookingID || event.Booking.ClientName != "Павел" {
+		t.Fatalf("event booking = %+v, want booking %d", event.Booking, bookingID)
+	}
+
+	// события приходят в том порядке, в котором произошли
+	target = fmt.Sprintf("/api/v1/restaurants/%d/bookings/%d/", s.restaurantID, bookingID)
+	if w := s.do(http.MethodDelete, target, "", nil); w.Code != http.StatusOK {
+		t.Fatalf("cancel booking: status = %d; body: %s", w.Code, w.Body)
+	}
+	tables, err := s.store.Tables().GetAll(s.restaurantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableID := tables[0].ID
+	target = fmt.Sprintf("/api/v1/tables/%d/", tableID)
+	if w := s.do(http.MethodDelete, target, "", nil); w.Code != http.StatusOK {
+		t.Fatalf("delete table: status = %d; body: %s", w.Code, w.Body)
+	}
+
+	if event = readEvent(t, stream); event.Event != model.RestaurantEventBookingCancelled || event.Booking == nil || event.Booking.ID != bookingID {
+		t.Fatalf("event = %s, want %s of booking %d", event.Event, model.RestaurantEventBookingCancelled, bookingID)
+	}
+	// у удалённого столика в событии есть только ID
+	if event = readEvent(t, stream); event.Event != model.RestaurantEventTableChanged || event.TableID != tableID || event.Table != nil {
+		t.Fatalf("event = %+v, want %s of deleted table %d", event, model.RestaurantEventTableChanged, tableID)
+	}
+
+	// когда подписка закрывается (например, часть событий могла быть потеряна), поток завершается
+	if err = s.events.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if line, err := stream.ReadString('\n'); err == nil {
+		t.Fatalf("read %q after the subscription is closed, want the end of the stream", line)
+	}
+}
+
+func TestStreamRestaurantEvents_Unauthorized(t *testing.T) {
+	s := newTestServer(t)
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/restaurants/%d/events", server.URL, s.restaurantID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestListAvailableRestaurants_MaliciousDatetime(t *testing.T) {
+	s := newTestServer(t)
+	target := func(desiredDatetime string) string {
+		return "/api/v1/restaurants/available?" + url.Values{
+			"desired_datetime": {desiredDatetime},
+			"people_number":    {"2"},
+		}.Encode()
+	}
+
+	for _, tt := range maliciousDatetimes {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodGet, target(tt.value), "", nil)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+		})
+	}
+
+	w := s.do(http.MethodGet, target(futureDatetime("2006.01.02 15:04")), "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("valid search: status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var found listAvailableRestaurantsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &found); err != nil {
+		t.Fatal(err)
+	}
+	if len(found.Data) != 1 || found.Data[0].ID != s.restaurantID {
+		t.Errorf("found restaurants %+v, want only restaurant %d", found.Data, s.restaurantID)
+	}
+}
+
+func TestRestaurantsPage_MaliciousDatetime(t *testing.T) {
+	s := newTestServer(t)
+	target := func(desiredDatetime string) string {
+		return "/restaurants/?" + url.Values{
+			"desired_datetime": {desiredDatetime},
+			"people_number":    {"2"},
+		}.Encode()
+	}
+
+	for _, tt := range maliciousDatetimes {
+		t.Run(tt.name, func(t *testing.T) {
+			body := s.do(http.MethodGet, target(tt.value), "", nil).Body.String()
+			if !strings.Contains(body, fmt.Sprintf("Ошибка %d", http.StatusBadRequest)) {
+				t.Errorf("want the error page with code %d; body: %s", http.StatusBadRequest, body)
+			}
+			if strings.Contains(body, "<script>alert(1)</script>") {
+				t.Error("the error page contains unescaped input")
+			}
+		})
+	}
+
+	// поле ввода на сайте передаёт дату и время в формате "2006-01-02T15:04"
+	body := s.do(http.MethodGet, target(futureDatetime("2006-01-02T15:04")), "", nil).Body.String()
+	if !strings.Contains(body, "Каравелла") {
+		t.Fatalf("valid search did not find the restaurant; body: %s", body)
+	}
+}
+
+func TestGetAvailability_MaliciousDate(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, tt := range maliciousDatetimes {
+		t.Run(tt.name, func(t *testing.T) {
+			target := fmt.Sprintf("/api/v1/restaurants/%d/availability?", s.restaurantID) + url.Values{
+				"date":   {tt.value},
+				"people": {"2"},
+			}.Encode()
+			w := s.do(http.MethodGet, target, "", nil)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+		})
+	}
+}
+
+// TestListAvailableRestaurants_PeopleNumber проверяет, что рестораны ищутся только для компаний от 1 до 30 человек:
+// подбор столиков для больших компаний занял бы слишком много времени.
+func TestListAvailableRestaurants_PeopleNumber(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name         string
+		peopleNumber string
+		wantCode     int
+	}{
+		{name: "zero", peopleNumber: "0", wantCode: http.StatusBadRequest},
+		{name: "one", peopleNumber: "1", wantCode: http.StatusOK},
+		{name: "max", peopleNumber: "30", wantCode: http.StatusOK},
+		{name: "more than max", peopleNumber: "31", wantCode: http.StatusBadRequest},
+		{name: "huge", peopleNumber: "1000000", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/api/v1/restaurants/available?" + url.Values{
+				"desired_datetime": {futureDatetime("2006.01.02 15:04")},
+				"people_number":    {tt.peopleNumber},
+			}.Encode()
+			if w := s.do(http.MethodGet, target, "", nil); w.Code != tt.wantCode {
+				t.Errorf("API: status = %d, want %d; body: %s", w.Code, tt.wantCode, w.Body)
+			}
+
+			target = "/restaurants/?" + url.Values{
+				"desired_datetime": {futureDatetime("2006-01-02T15:04")},
+				"people_number":    {tt.peopleNumber},
+			}.Encode()
+			body := s.do(http.MethodGet, target, "", nil).Body.String()
+			if failed := strings.Contains(body, fmt.Sprintf("Ошибка %d", http.StatusBadRequest)); failed != (tt.wantCode != http.StatusOK) {
+				t.Errorf("website: error page shown = %v, want %v", failed, tt.wantCode != http.StatusOK)
+			}
+		})
+	}
+}
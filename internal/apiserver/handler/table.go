@@ -19,10 +19,15 @@ const tableCtxKey = "table"
 func (h *Handler) initTablesRouter() http.Handler {
 	r := chi.NewRouter()
 	r.Route("/{table_id}", func(r chi.Router) {
-		r.Use(h.tableCtx)            // загрузить информацию о столике из контекста запроса
-		r.Get("/", h.getTable)       // GET /tables/123/
-		r.Patch("/", h.updateTable)  // PATCH /tables/123/
-		r.Delete("/", h.deleteTable) // DELETE /tables/123/
+		r.Use(h.tableCtx)                                // загрузить информацию о столике из контекста запроса
+		r.Get("/", h.getTable)                           // GET /tables/123/
+		r.Patch("/", h.updateTable)                      // PATCH /tables/123/
+		r.Delete("/", h.deleteTable)                     // DELETE /tables/123/
+		r.Route("/joinable-tables", func(r chi.Router) { // работа со столиками, которые можно сдвинуть с этим
+			r.Get("/", h.listJoinableTables)                 // GET /tables/123/joinable-tables
+			r.Put("/{joinable_table_id}", h.joinTables)      // PUT /tables/123/joinable-tables/456
+			r.Delete("/{joinable_table_id}", h.unjoinTables) // DELETE /tables/123/joinable-tables/456
+		})
 	})
 	return r
 }
@@ -30,6 +35,11 @@ func (h *Handler) initTablesRouter() http.Handler {
 // createTableRequest представляет тело запроса на создание столика в ресторане.
 type createTableRequest struct {
 	SeatsNumber int `json:"seats_number" example:"3"`
+	// Position представляет расположение столика в зале (необязательно).
+	Position string `json:"position" example:"у окна, №3"`
+	// Zone представляет зону ресторана, в которой стоит столик: hall, terrace, vip или non_smoking
+	// (необязательно, по умолчанию hall).
+	Zone string `json:"zone" example:"terrace"`
 }
 
 // Bind осуществляет пост-обработку запроса.
@@ -37,6 +47,12 @@ func (r *createTableRequest) Bind(_ *http.Request) error {
 	if r.SeatsNumber == 0 {
 		return ErrTableMissingFields
 	}
+	if r.SeatsNumber < 0 {
+		return model.ErrInvalidSeatsNumber
+	}
+	if r.Zone != "" && !model.IsZone(r.Zone) {
+		return model.ErrUnknownZone
+	}
 	return nil
 }
 
@@ -51,16 +67,17 @@ func (r *createTableResponse) Render(_ http.ResponseWriter, _ *http.Request) err
 }
 
 // createTable godoc
-// @Summary  Создать столик в ресторане
-// @Tags     tables
-// @Accept   json
-// @Produce  json
-// @Param    restaurant_id  path      string               true  "ID ресторана"
-// @Param    input          body      createTableRequest   true  "Информация о столике"
-// @Success  201            {object}  createTableResponse  "ok"
-// @Failure  400            {object}  errResponse          "Некорректные данные столика"
-// @Failure  500            {object}  errResponse          "Ошибка на стороне сервера"
-// @Router   /restaurants/{restaurant_id}/tables/ [post]
+// @Summary   Создать столик в ресторане
+// @Tags      tables
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string               true  "ID ресторана"
+// @Param     input          body      createTableRequest   true  "Информация о столике"
+// @Success   201            {object}  createTableResponse  "ok"
+// @Failure   400            {object}  errResponse          "Некорректные данные столика"
+// @Failure   500            {object}  errResponse          "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/tables/ [post]
 func (h *Handler) createTable(w http.ResponseWriter, r *http.Request) {
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
 
@@ -70,7 +87,7 @@ func (h *Handler) createTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tableID, err := h.service.TableService.Create(restaurant.ID, data.SeatsNumber)
+	tableID, err := h.service.TableService.Create(restaurant.ID, data.SeatsNumber, data.Position, data.Zone)
 	if err != nil {
 		_ = render.Render(w, r, errServiceFailure(err))
 		return
@@ -93,15 +110,16 @@ func (r *listTablesResponse) Render(_ http.ResponseWriter, _ *http.Request) erro
 }
 
 // listTables godoc
-// @Summary  Получить список столиков в ресторане
-// @Tags     tables
-// @Accept   json
-// @Produce  json
-// @Param    restaurant_id  path      string              true  "ID ресторана"
-// @Success  200            {object}  listTablesResponse  "ok"
-// @Failure  400            {object}  errResponse         "Некорректный restaurant_id"
-// @Failure  500            {object}  errResponse         "Ошибка на стороне сервера"
-// @Router   /restaurants/{restaurant_id}/tables/ [get]
+// @Summary   Получить список столиков в ресторане
+// @Tags      tables
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string              true  "ID ресторана"
+// @Success   200            {object}  listTablesResponse  "ok"
+// @Failure   400            {object}  errResponse         "Некорректный restaurant_id"
+// @Failure   500            {object}  errResponse         "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/tables/ [get]
 func (h *Handler) listTables(w http.ResponseWriter, r *http.Request) {
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
 
@@ -117,7 +135,7 @@ func (h *Handler) listTables(w http.ResponseWriter, r *http.Request) {
 }
 
 // tableCtx используется для загрузки столика (model.Table) из контекста запроса по table_id,
-// переданному в параметрах URL запроса.
+// переданному в параметрах URL запроса. Пользователь API должен иметь доступ к ресторану, в котором стоит столик.
 func (h *Handler) tableCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if tableIDStr := chi.URLParam(r, "table_id"); tableIDStr != "" {
@@ -137,6 +155,11 @@ func (h *Handler) tableCtx(next http.Handler) http.Handler {
 				return
 			}
 
+			// столики доступны тем же пользователям, что и ресторан, в котором они стоят
+			if !authorizeRestaurant(w, r, table.RestaurantID) {
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), tableCtxKey, table)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		} else {
@@ -157,15 +180,16 @@ func (r *getTableResponse) Render(_ http.ResponseWriter, _ *http.Request) error
 }
 
 // getTable godoc
-// @Summary  Получить столик по его ID
-// @Tags     tables
-// @Accept   json
-// @Produce  json
-// @Param    table_id  path      string            true  "ID столика"
-// @Success  200       {object}  getTableResponse  "ok"
-// @Failure  400       {object}  errResponse       "Некорректный ID столика"
-// @Failure  500       {object}  errResponse       "Ошибка на стороне сервера"
-// @Router   /tables/{table_id}/ [get]
+// @Summary   Получить столик по его ID
+// @Tags      tables
+// @Accept    json
+// @Produce   json
+// @Param     table_id  path      string            true  "ID столика"
+// @Success   200       {object}  getTableResponse  "ok"
+// @Failure   400       {object}  errResponse       "Некорректный ID столика"
+// @Failure   500       {object}  errResponse       "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /tables/{table_id}/ [get]
 func (h *Handler) getTable(w http.ResponseWriter, r *http.Request) {
 	table := r.Context().Value(tableCtxKey).(*model.Table)
 
@@ -189,13 +213,14 @@ func (r *updateTableResponse) Render(_ http.ResponseWriter, _ *http.Request) err
 // @Summary      Обновить информацию о столике по его ID
 // @Description  Обновление происходит именно путём PATCH-запросов, чтобы была возможность изменять данные частично.
 // @Tags         tables
-// @Accept   	 json
-// @Produce  	 json
+// @Accept       json
+// @Produce      json
 // @Param        table_id  path      string                 true  "ID столика"
 // @Param        input     body      model.UpdateTableData  true  "Информация о столике"
 // @Success      200       {object}  updateTableResponse    "ok"
 // @Failure      400       {object}  errResponse            "Некорректный данные запроса"
 // @Failure      500       {object}  errResponse            "Ошибка на стороне сервера"
+// @Security     BearerAuth
 // @Router       /tables/{table_id}/ [patch]
 func (h *Handler) updateTable(w http.ResponseWriter, r *http.Request) {
 	table := r.Context().Value(tableCtxKey).(*model.Table)
@@ -227,13 +252,14 @@ func (r *deleteTableResponse) Render(_ http.ResponseWriter, _ *http.Request) err
 // deleteRestaurant godoc
 // @Summary      Удаляет столик по его ID
 // @Description  Если за столиком ещё будут сидеть клиенты (есть брони в будущем/сегодняшнем днях в этом ресторане), то его нельзя удалить.
-// @Tags     	 tables
-// @Accept   	 json
-// @Produce  	 json
+// @Tags         tables
+// @Accept       json
+// @Produce      json
 // @Param        table_id  path      string               true  "ID столика"
 // @Success      200       {object}  deleteTableResponse  "ok"
 // @Failure      400       {object}  errResponse          "Некорректный данные запроса"
 // @Failure      500       {object}  errResponse          "Ошибка на стороне сервера"
+// @Security     BearerAuth
 // @Router       /tables/{table_id}/ [delete]
 func (h *Handler) deleteTable(w http.ResponseWriter, r *http.Request) {
 	table := r.Context().Value(tableCtxKey).(*model.Table)
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// listJoinableTablesResponse представляет тело ответа на получение списка столиков, которые можно сдвинуть со столиком.
+type listJoinableTablesResponse struct {
+	Data []model.Table `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *listJoinableTablesResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// listJoinableTables godoc
+// @Summary      Получить список столиков, которые можно сдвинуть со столиком
+// @Description  Если в ресторане не задано ни одной пары столиков, которые можно сдвинуть, при бронировании сдвигаются любые столики.
+// @Tags         tables
+// @Accept       json
+// @Produce      json
+// @Param        table_id  path      string                      true  "ID столика"
+// @Success      200       {object}  listJoinableTablesResponse  "ok"
+// @Failure      400       {object}  errResponse                 "Некорректный ID столика"
+// @Failure      500       {object}  errResponse                 "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /tables/{table_id}/joinable-tables/ [get]
+func (h *Handler) listJoinableTables(w http.ResponseWriter, r *http.Request) {
+	table := r.Context().Value(tableCtxKey).(*model.Table)
+
+	tables, err := h.service.TableService.GetJoinable(table.ID)
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &listJoinableTablesResponse{
+		Data: tables,
+	})
+}
+
+// joinTablesResponse представляет тело ответа на изменение возможности сдвинуть столики.
+type joinTablesResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *joinTablesResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// joinTables godoc
+// @Summary      Отметить, что столики можно сдвинуть
+// @Description  Сдвигать можно только разные столики одного ресторана. Компания, которой нужно несколько столиков, садится только за столики, которые можно сдвинуть.
+// @Tags         tables
+// @Accept       json
+// @Produce      json
+// @Param        table_id           path      string              true  "ID столика"
+// @Param        joinable_table_id  path      string              true  "ID столика, который можно сдвинуть с первым"
+// @Success      200                {object}  joinTablesResponse  "ok"
+// @Failure      400                {object}  errResponse         "Некорректные ID столиков"
+// @Failure      404                {object}  errResponse         "Столик не найден"
+// @Failure      500                {object}  errResponse         "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /tables/{table_id}/joinable-tables/{joinable_table_id} [put]
+func (h *Handler) joinTables(w http.ResponseWriter, r *http.Request) {
+	h.changeTableJoin(w, r, h.service.TableService.Join)
+}
+
+// unjoinTables godoc
+// @Summary   Отметить, что столики сдвигать нельзя
+// @Tags      tables
+// @Accept    json
+// @Produce   json
+// @Param     table_id           path      string              true  "ID столика"
+// @Param     joinable_table_id  path      string              true  "ID столика, который больше нельзя сдвигать с первым"
+// @Success   200                {object}  joinTablesResponse  "ok"
+// @Failure   400                {object}  errResponse         "Некорректные ID столиков"
+// @Failure   404                {object}  errResponse         "Столик не найден"
+// @Failure   500                {object}  errResponse         "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /tables/{table_id}/joinable-tables/{joinable_table_id} [delete]
+func (h *Handler) unjoinTables(w http.ResponseWriter, r *http.Request) {
+	h.changeTableJoin(w, r, h.service.TableService.Unjoin)
+}
+
+// changeTableJoin разбирает ID второго столика из параметров URL запроса и применяет к паре столиков change.
+func (h *Handler) changeTableJoin(w http.ResponseWriter, r *http.Request, change func(id, joinableID uint64) error) {
+	table := r.Context().Value(tableCtxKey).(*model.Table)
+
+	joinableID, err := strconv.ParseUint(chi.URLParam(r, "joinable_table_id"), 10, 0)
+	if err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	if err = change(table.ID, joinableID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidData):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, store.ErrTableNotFound):
+			_ = render.Render(w, r, errNotFound(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	_ = render.Render(w, r, &joinTablesResponse{Status: "ok"})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateTable_SeatsNumber(t *testing.T) {
+	s := newTestServer(t)
+	target := fmt.Sprintf("/api/v1/restaurants/%d/tables/", s.restaurantID)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "missing", body: `{}`, wantCode: http.StatusBadRequest},
+		{name: "zero", body: `{"seats_number": 0}`, wantCode: http.StatusBadRequest},
+		{name: "negative", body: `{"seats_number": -4}`, wantCode: http.StatusBadRequest},
+		{name: "positive", body: `{"seats_number": 4}`, wantCode: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, target, "application/json", strings.NewReader(tt.body))
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.wantCode, w.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateTable_SeatsNumber(t *testing.T) {
+	s := newTestServer(t)
+	tables, err := s.store.Tables().GetAll(s.restaurantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := tables[0]
+	target := fmt.Sprintf("/api/v1/tables/%d/", table.ID)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "zero", body: `{"seats_number": 0}`, wantCode: http.StatusBadRequest},
+		{name: "negative", body: `{"seats_number": -4}`, wantCode: http.StatusBadRequest},
+		{name: "positive", body: fmt.Sprintf(`{"seats_number": %d}`, table.SeatsNumber+1), wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPatch, target, "application/json", strings.NewReader(tt.body))
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.wantCode, w.Body)
+			}
+		})
+	}
+
+	updated, err := s.store.Tables().Get(table.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.SeatsNumber != table.SeatsNumber+1 {
+		t.Errorf("table has %d seats, want %d", updated.SeatsNumber, table.SeatsNumber+1)
+	}
+}
@@ -2,38 +2,72 @@ package handler
 
 import (
 	"errors"
-	"html/template"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/render"
 
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
 )
 
-const templatesPattern = "website/templates/*.gohtml"
-
-var tmpls *template.Template
-
-func init() {
-	tmpls = template.Must(template.ParseGlob(templatesPattern))
-}
+// templatesPattern представляет шаблон путей к gohtml-шаблонам страниц относительно папки сайта.
+const templatesPattern = "templates/*.gohtml"
 
 // TemplatesContext представляет данные, которые передаются в gohtml-шаблоны.
 type TemplatesContext struct {
 	PageTitle   string
 	Restaurants []model.Restaurant
 	BookingID   uint64
+	// BookingPending означает, что оформленная бронь ждёт подтверждения рестораном.
+	BookingPending bool
+	// Booking представляет бронь, которую гость открыл по подписанной ссылке.
+	Booking *model.Booking
+	// BookingLink представляет подписанную ссылку на бронь: после оформления брони - путь к её странице, а на странице
+	// брони - токен ссылки, по которому бронь отменяется или переносится.
+	BookingLink string
+
+	// Zones представляет зоны ресторанов, из которых гость может выбрать предпочитаемую.
+	Zones []model.Zone
+	// Zone представляет выбранную гостем зону (пустая строка - любая зона).
+	Zone string
+
+	// RestaurantName и BookingDetails представляют ресторан и данные брони, которую не удалось оформить
+	// из-за нехватки мест: с ними гость может встать в лист ожидания.
+	RestaurantName string
+	BookingDetails model.BookingDetails
+	// WaitlistEntry представляет запись гостя в листе ожидания.
+	WaitlistEntry *model.WaitlistEntry
+
+	// PeopleNumber и Alternatives представляют количество человек и варианты, которые предлагаются гостю,
+	// если на желаемое время не нашлось ни одного ресторана.
+	PeopleNumber string
+	Alternatives *model.Alternatives
+
+	// Customer представляет гостя, вошедшего на сайт (nil, если гость не вошёл): его имя и телефон подставляются
+	// в форму брони.
+	Customer *model.Customer
+	// BookingHistory представляет предстоящие и прошедшие брони гостя в его личном кабинете.
+	BookingHistory *model.BookingHistory
 
 	ErrorCode int
 	ErrorText string
 }
 
+// ZoneTitle возвращает название выбранной гостем зоны для отображения на сайте.
+func (c *TemplatesContext) ZoneTitle() string {
+	return model.ZoneTitle(c.Zone)
+}
+
 // home отображает содержание стартовой страницы, где необходимо указать количество человек, дату и время посещения.
 func (h *Handler) home(w http.ResponseWriter, r *http.Request) {
-	renderTemplate(w, r, "home",
+	h.renderTemplate(w, r, "home",
 		&TemplatesContext{
 			PageTitle: "Бронирование столиков в ресторанах",
+			Zones:     model.Zones(),
+			Customer:  currentCustomer(r),
 		},
 	)
 }
@@ -42,9 +76,10 @@ func (h *Handler) home(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) restaurants(w http.ResponseWriter, r *http.Request) {
 	desiredDateTime := r.URL.Query().Get("desired_datetime")
 	peopleNumber := r.URL.Query().Get("people_number")
+	zone := r.URL.Query().Get("zone")
 
 	if desiredDateTime == "" || peopleNumber == "" {
-		renderTemplate(w, r, "error",
+		h.renderTemplate(w, r, "error",
 			&TemplatesContext{
 				PageTitle: "Произошла ошибка",
 				ErrorText: ErrFindAvailableRestaurants.Error(),
@@ -54,13 +89,13 @@ func (h *Handler) restaurants(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	restaurants, err := h.service.RestaurantService.GetAllAvailable(desiredDateTime, peopleNumber)
+	restaurants, alternatives, err := h.findAvailableRestaurants(desiredDateTime, peopleNumber, zone)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		if errors.Is(err, service.ErrInvalidData) {
 			statusCode = http.StatusBadRequest
 		}
-		renderTemplate(w, r, "error",
+		h.renderTemplate(w, r, "error",
 			&TemplatesContext{
 				PageTitle: "Произошла ошибка",
 				ErrorText: err.Error(),
@@ -70,10 +105,14 @@ func (h *Handler) restaurants(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderTemplate(w, r, "restaurants",
+	h.renderTemplate(w, r, "restaurants",
 		&TemplatesContext{
-			PageTitle:   "Выбор ресторана",
-			Restaurants: restaurants,
+			PageTitle:    "Выбор ресторана",
+			Restaurants:  restaurants,
+			Zone:         zone,
+			PeopleNumber: peopleNumber,
+			Alternatives: alternatives,
+			Customer:     currentCustomer(r),
 		},
 	)
 }
@@ -82,7 +121,7 @@ func (h *Handler) restaurants(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) makeBooking(w http.ResponseWriter, r *http.Request) {
 	headerContentType := r.Header.Get("Content-Type")
 	if headerContentType != "application/x-www-form-urlencoded" {
-		renderTemplate(w, r, "error",
+		h.renderTemplate(w, r, "error",
 			&TemplatesContext{
 				PageTitle: "Произошла ошибка",
 				ErrorText: ErrMakingBookingContentType.Error(),
@@ -94,21 +133,51 @@ func (h *Handler) makeBooking(w http.ResponseWriter, r *http.Request) {
 
 	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
 
+	clientPhone, ok := h.formPhone(w, r)
+	if !ok {
+		return
+	}
+	clientEmail, ok := h.formEmail(w, r)
+	if !ok {
+		return
+	}
+
 	details := model.BookingDetails{
 		RestaurantID:    restaurant.ID,
 		PeopleNumber:    r.FormValue("people_number"),
 		DesiredDatetime: r.FormValue("desired_datetime"),
 		ClientName:      r.FormValue("client_name"),
-		ClientPhone:     r.FormValue("client_phone"),
+		ClientPhone:     clientPhone,
+		ClientEmail:     clientEmail,
+		Zone:            r.FormValue("zone"),
+	}
+	// бронь гостя, вошедшего на сайт, попадает в историю его броней
+	if customer := currentCustomer(r); customer != nil {
+		details.CustomerID = customer.ID
 	}
 
-	bookingID, err := h.service.BookingService.Create(details)
+	bookingID, err := h.bookHeldTables(restaurant.ID, r.FormValue("hold_id"), r.FormValue("hold_token"), details)
 	if err != nil {
+		// если мест не хватило, предлагаем гостю встать в лист ожидания ресторана
+		if errors.Is(err, service.ErrNotEnoughSeatsInRestaurant) {
+			h.renderTemplate(w, r, "waitlist-join",
+				&TemplatesContext{
+					PageTitle:      "Свободных мест нет",
+					RestaurantName: restaurant.Name,
+					BookingDetails: details,
+				},
+			)
+			return
+		}
+		if h.renderGuestBlocked(w, r, err) {
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if errors.Is(err, service.ErrInvalidData) {
 			statusCode = http.StatusBadRequest
 		}
-		renderTemplate(w, r, "error",
+		h.renderTemplate(w, r, "error",
 			&TemplatesContext{
 				PageTitle: "Произошла ошибка",
 				ErrorText: err.Error(),
@@ -118,17 +187,371 @@ func (h *Handler) makeBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderTemplate(w, r, "booking-created",
+	h.renderTemplate(w, r, "booking-created",
+		&TemplatesContext{
+			PageTitle:      "Бронь успешно оформлена",
+			BookingID:      bookingID,
+			BookingPending: h.isBookingPending(bookingID),
+			BookingLink:    h.bookingLink(bookingID),
+			Customer:       currentCustomer(r),
+		},
+	)
+}
+
+// isBookingPending проверяет, ждёт ли бронь подтверждения рестораном (например, по правилам надёжности гостей).
+// Бронь к этому моменту уже оформлена, поэтому ошибка получения брони не мешает показать гостю её номер.
+func (h *Handler) isBookingPending(bookingID uint64) bool {
+	booking, err := h.service.BookingService.Get(bookingID)
+	return err == nil && booking.IsPending()
+}
+
+// renderGuestBlocked отображает гостю понятное объяснение, почему онлайн-бронирование в ресторане для него
+// заблокировано, если err - service.GuestBlockedError. Возвращает false, если это другая ошибка.
+func (h *Handler) renderGuestBlocked(w http.ResponseWriter, r *http.Request, err error) bool {
+	var blockedErr *service.GuestBlockedError
+	if !errors.As(err, &blockedErr) {
+		return false
+	}
+
+	h.renderTemplate(w, r, "error",
 		&TemplatesContext{
-			PageTitle: "Бронь успешно оформлена",
+			PageTitle: "Онлайн-бронирование недоступно",
+			ErrorText: fmt.Sprintf(
+				"Вы несколько раз не пришли по своим броням, поэтому до %s забронировать столик в этом ресторане "+
+					"на сайте нельзя. Чтобы забронировать столик, позвоните в ресторан.",
+				blockedErr.Until.Format("2006.01.02 15:04"),
+			),
+			ErrorCode: http.StatusForbidden,
+		},
+	)
+	return true
+}
+
+// bookHeldTables оформляет бронь на столики, удержанные для гостя, пока он заполнял форму. Если столики не удержаны,
+// токен удержания не подходит или срок удержания истёк, бронь оформляется на свободные в этот момент столики.
+func (h *Handler) bookHeldTables(
+	restaurantID uint64, holdIDStr, holdToken string, details model.BookingDetails,
+) (uint64, error) {
+	holdID, err := strconv.ParseUint(holdIDStr, 10, 0)
+	if err != nil {
+		return h.service.BookingService.Create(details)
+	}
+
+	hold, err := h.service.HoldService.Get(holdID, holdToken)
+	if err != nil && !errors.Is(err, store.ErrHoldNotFound) {
+		return 0, err
+	}
+	if err == nil && hold.RestaurantID == restaurantID {
+		bookingID, err := h.service.HoldService.Confirm(
+			holdID, holdToken, details.CustomerID, details.ClientName, details.ClientPhone, details.ClientEmail,
+		)
+		if !errors.Is(err, service.ErrHoldExpired) && !errors.Is(err, store.ErrHoldNotFound) {
+			return bookingID, err
+		}
+	}
+
+	return h.service.BookingService.Create(details)
+}
+
+// cancelBookingPage отображает содержание страницы, на которой клиент может отменить свою бронь.
+func (h *Handler) cancelBookingPage(w http.ResponseWriter, r *http.Request) {
+	h.renderTemplate(w, r, "cancel-booking",
+		&TemplatesContext{
+			PageTitle: "Отмена брони",
+		},
+	)
+}
+
+// cancelBookingByClient обрабатывает запрос клиента на отмену брони. Клиент подтверждает, что бронь принадлежит ему,
+// указывая номер телефона, на который она была оформлена.
+func (h *Handler) cancelBookingByClient(w http.ResponseWriter, r *http.Request) {
+	headerContentType := r.Header.Get("Content-Type")
+	if headerContentType != "application/x-www-form-urlencoded" {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrMakingBookingContentType.Error(),
+				ErrorCode: http.StatusUnsupportedMediaType,
+			},
+		)
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(r.FormValue("booking_id"), 10, 0)
+	if err != nil {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrBookingMissingFields.Error(),
+				ErrorCode: http.StatusBadRequest,
+			},
+		)
+		return
+	}
+
+	if err = h.service.BookingService.CancelByClient(bookingID, r.FormValue("client_phone")); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrBookingNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, service.ErrBookingAlreadyCancelled), errors.Is(err, service.ErrBookingInPast),
+			errors.Is(err, service.ErrBookingStatusTransition):
+			statusCode = http.StatusBadRequest
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: statusCode,
+			},
+		)
+		return
+	}
+
+	h.renderTemplate(w, r, "booking-cancelled",
+		&TemplatesContext{
+			PageTitle: "Бронь отменена",
 			BookingID: bookingID,
 		},
 	)
 }
 
+// joinWaitlistByClient обрабатывает запрос гостя на постановку в лист ожидания ресторана, в котором не хватило мест.
+func (h *Handler) joinWaitlistByClient(w http.ResponseWriter, r *http.Request) {
+	headerContentType := r.Header.Get("Content-Type")
+	if headerContentType != "application/x-www-form-urlencoded" {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrMakingBookingContentType.Error(),
+				ErrorCode: http.StatusUnsupportedMediaType,
+			},
+		)
+		return
+	}
+
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	clientPhone, ok := h.formPhone(w, r)
+	if !ok {
+		return
+	}
+
+	details := model.BookingDetails{
+		RestaurantID:    restaurant.ID,
+		PeopleNumber:    r.FormValue("people_number"),
+		DesiredDatetime: r.FormValue("desired_datetime"),
+		ClientName:      r.FormValue("client_name"),
+		ClientPhone:     clientPhone,
+	}
+
+	entryID, err := h.service.WaitlistService.Join(details)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidData) {
+			statusCode = http.StatusBadRequest
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: statusCode,
+			},
+		)
+		return
+	}
+
+	h.renderWaitlistEntry(w, r, entryID, details.ClientPhone)
+}
+
+// waitlistPage отображает содержание страницы, на которой гость может узнать состояние своей записи в листе ожидания.
+func (h *Handler) waitlistPage(w http.ResponseWriter, r *http.Request) {
+	h.renderTemplate(w, r, "waitlist",
+		&TemplatesContext{
+			PageTitle: "Лист ожидания",
+		},
+	)
+}
+
+// waitlistEntryPage отображает состояние записи гостя в листе ожидания. Гость подтверждает, что запись принадлежит
+// ему, указывая номер телефона, с которым он встал в лист ожидания.
+func (h *Handler) waitlistEntryPage(w http.ResponseWriter, r *http.Request) {
+	entryID, ok := h.parseWaitlistEntryForm(w, r)
+	if !ok {
+		return
+	}
+
+	h.renderWaitlistEntry(w, r, entryID, r.FormValue("client_phone"))
+}
+
+// acceptWaitlistOfferByClient обрабатывает запрос гостя на оформление брони по предложению из листа ожидания.
+func (h *Handler) acceptWaitlistOfferByClient(w http.ResponseWriter, r *http.Request) {
+	entryID, ok := h.parseWaitlistEntryForm(w, r)
+	if !ok {
+		return
+	}
+
+	bookingID, err := h.service.WaitlistService.AcceptByClient(entryID, r.FormValue("client_phone"))
+	if err != nil {
+		if h.renderGuestBlocked(w, r, err) {
+			return
+		}
+
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrWaitlistEntryNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, service.ErrWaitlistNoOffer), errors.Is(err, service.ErrInvalidData):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, service.ErrNotEnoughSeatsInRestaurant):
+			statusCode = http.StatusConflict
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: statusCode,
+			},
+		)
+		return
+	}
+
+	h.renderTemplate(w, r, "booking-created",
+		&TemplatesContext{
+			PageTitle:      "Бронь успешно оформлена",
+			BookingID:      bookingID,
+			BookingPending: h.isBookingPending(bookingID),
+			BookingLink:    h.bookingLink(bookingID),
+		},
+	)
+}
+
+// leaveWaitlistByClient обрабатывает запрос гостя на выход из листа ожидания.
+func (h *Handler) leaveWaitlistByClient(w http.ResponseWriter, r *http.Request) {
+	entryID, ok := h.parseWaitlistEntryForm(w, r)
+	if !ok {
+		return
+	}
+
+	clientPhone := r.FormValue("client_phone")
+	if err := h.service.WaitlistService.LeaveByClient(entryID, clientPhone); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, store.ErrWaitlistEntryNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, service.ErrWaitlistEntryClosed):
+			statusCode = http.StatusBadRequest
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: statusCode,
+			},
+		)
+		return
+	}
+
+	h.renderWaitlistEntry(w, r, entryID, clientPhone)
+}
+
+// formPhone возвращает телефон гостя из формы в формате E.164. Если телефон нельзя привести к этому формату,
+// отображает страницу с ошибкой и возвращает false.
+func (h *Handler) formPhone(w http.ResponseWriter, r *http.Request) (string, bool) {
+	phone, err := model.NormalizePhone(r.FormValue("client_phone"))
+	if err != nil {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: http.StatusBadRequest,
+			},
+		)
+		return "", false
+	}
+	return phone, true
+}
+
+// formEmail возвращает необязательный адрес электронной почты гостя из формы. Если адрес указан некорректно,
+// отображает страницу с ошибкой и возвращает false.
+func (h *Handler) formEmail(w http.ResponseWriter, r *http.Request) (string, bool) {
+	email := r.FormValue("client_email")
+	if email == "" {
+		return "", true
+	}
+
+	email, err := model.NormalizeEmail(email)
+	if err != nil {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: http.StatusBadRequest,
+			},
+		)
+		return "", false
+	}
+	return email, true
+}
+
+// parseWaitlistEntryForm проверяет тип содержимого формы и получает из неё ID записи в листе ожидания.
+// Если данные некорректны, отображает страницу с ошибкой и возвращает false.
+func (h *Handler) parseWaitlistEntryForm(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	headerContentType := r.Header.Get("Content-Type")
+	if headerContentType != "application/x-www-form-urlencoded" {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrMakingBookingContentType.Error(),
+				ErrorCode: http.StatusUnsupportedMediaType,
+			},
+		)
+		return 0, false
+	}
+
+	entryID, err := strconv.ParseUint(r.FormValue("entry_id"), 10, 0)
+	if err != nil || r.FormValue("client_phone") == "" {
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: ErrWaitlistMissingFields.Error(),
+				ErrorCode: http.StatusBadRequest,
+			},
+		)
+		return 0, false
+	}
+	return entryID, true
+}
+
+// renderWaitlistEntry отображает страницу с состоянием записи гостя в листе ожидания.
+func (h *Handler) renderWaitlistEntry(w http.ResponseWriter, r *http.Request, entryID uint64, clientPhone string) {
+	entry, err := h.service.WaitlistService.GetByClient(entryID, clientPhone)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, store.ErrWaitlistEntryNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		h.renderTemplate(w, r, "error",
+			&TemplatesContext{
+				PageTitle: "Произошла ошибка",
+				ErrorText: err.Error(),
+				ErrorCode: statusCode,
+			},
+		)
+		return
+	}
+
+	h.renderTemplate(w, r, "waitlist-entry",
+		&TemplatesContext{
+			PageTitle:     "Лист ожидания",
+			WaitlistEntry: entry,
+		},
+	)
+}
+
 // renderTemplate обрабатывает шаблон страницы с переданными в него данными.
-func renderTemplate(w http.ResponseWriter, r *http.Request, name string, data interface{}) {
-	if err := tmpls.ExecuteTemplate(w, name, data); err != nil {
+func (h *Handler) renderTemplate(w http.ResponseWriter, r *http.Request, name string, data interface{}) {
+	if err := h.templates.ExecuteTemplate(w, name, data); err != nil {
 		_ = render.Render(w, r, errServiceFailure(err))
 		return
 	}
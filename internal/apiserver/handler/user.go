@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const userCtxKey = "user"
+
+// initUsersRouter подготавливает отдельный маршрутизатор для управления пользователями API администрирования.
+func (h *Handler) initUsersRouter() http.Handler {
+	r := chi.NewRouter()
+	r.With(h.requireUser).Get("/me", h.getCurrentUser) // GET /users/me
+	r.Group(func(r chi.Router) {
+		r.Use(h.requireRole(model.UserRoleAdmin)) // пользователями управляет только администратор платформы
+		r.Post("/", h.createUser)                 // POST /users/
+		r.Get("/", h.listUsers)                   // GET /users/
+		r.Route("/{user_id}", func(r chi.Router) {
+			r.Use(h.userCtx)                           // загрузить информацию о пользователе из контекста запроса
+			r.Get("/", h.getUser)                      // GET /users/123/
+			r.Delete("/", h.deleteUser)                // DELETE /users/123/
+			r.Post("/api-key", h.regenerateUserAPIKey) // POST /users/123/api-key
+		})
+	})
+	return r
+}
+
+// createUserRequest представляет тело запроса на создание пользователя API администрирования.
+type createUserRequest struct {
+	Name string `json:"name" example:"Анна"`
+	// Role представляет роль пользователя: admin, manager или staff.
+	Role string `json:"role" example:"manager"`
+	// RestaurantIDs представляет ID ресторанов, закреплённых за менеджером или сотрудником.
+	RestaurantIDs []uint64 `json:"restaurant_ids"`
+}
+
+// Bind осуществляет пост-обработку запроса.
+func (r *createUserRequest) Bind(_ *http.Request) error {
+	if r.Name == "" || r.Role == "" {
+		return ErrUserMissingFields
+	}
+	return nil
+}
+
+// userWithAPIKeyResponse представляет тело ответа с пользователем и его ключом API, который показывается только один раз.
+type userWithAPIKeyResponse struct {
+	*model.User
+	APIKey string `json:"api_key" example:"rtb_4f1c..."`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *userWithAPIKeyResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// createUser godoc
+// @Summary      Создать пользователя API администрирования
+// @Description  Администратору платформы доступны все рестораны, менеджер управляет закреплёнными за ним ресторанами, а сотрудник может их только просматривать. Ключ API возвращается только в ответе на этот запрос: в БД хранится лишь его хеш.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        input  body      createUserRequest       true  "Информация о пользователе"
+// @Success      201    {object}  userWithAPIKeyResponse  "ok"
+// @Failure      400    {object}  errResponse             "Некорректные данные пользователя"
+// @Failure      401    {object}  errResponse             "Не передан ключ API"
+// @Failure      403    {object}  errResponse             "Недостаточно прав"
+// @Failure      500    {object}  errResponse             "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /users/ [post]
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	data := &createUserRequest{}
+	if err := render.Bind(r, data); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	user, apiKey, err := h.service.UserService.Create(data.Name, data.Role, data.RestaurantIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	_ = render.Render(w, r, &userWithAPIKeyResponse{User: user, APIKey: apiKey})
+}
+
+// listUsersResponse представляет тело ответа на получение списка пользователей.
+type listUsersResponse struct {
+	Data []model.User `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *listUsersResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// listUsers godoc
+// @Summary   Получить список пользователей API администрирования
+// @Tags      users
+// @Accept    json
+// @Produce   json
+// @Success   200  {object}  listUsersResponse  "ok"
+// @Failure   401  {object}  errResponse        "Не передан ключ API"
+// @Failure   403  {object}  errResponse        "Недостаточно прав"
+// @Failure   500  {object}  errResponse        "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /users/ [get]
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.UserService.GetAll()
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &listUsersResponse{
+		Data: users,
+	})
+}
+
+// userCtx используется для загрузки пользователя API (model.User) из контекста запроса по user_id,
+// переданному в параметрах URL запроса.
+func (h *Handler) userCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userIDStr := chi.URLParam(r, "user_id"); userIDStr != "" {
+			userID, err := strconv.ParseUint(userIDStr, 10, 0)
+			if err != nil {
+				_ = render.Render(w, r, errInvalidRequest(err))
+				return
+			}
+
+			user, err := h.service.UserService.Get(userID)
+			if err != nil {
+				if errors.Is(err, store.ErrUserNotFound) {
+					_ = render.Render(w, r, errNotFound(err))
+					return
+				}
+				_ = render.Render(w, r, errServiceFailure(err))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userCtxKey, user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		} else {
+			_ = render.Render(w, r, errInvalidRequest(ErrUserMissingFields))
+			return
+		}
+	})
+}
+
+// getUserResponse представляет тело ответа на получение пользователя API администрирования.
+type getUserResponse struct {
+	*model.User
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getUserResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getUser godoc
+// @Summary   Получить пользователя API администрирования по его ID
+// @Tags      users
+// @Accept    json
+// @Produce   json
+// @Param     user_id  path      string           true  "ID пользователя"
+// @Success   200      {object}  getUserResponse  "ok"
+// @Failure   400      {object}  errResponse      "Некорректный ID пользователя"
+// @Failure   401      {object}  errResponse      "Не передан ключ API"
+// @Failure   403      {object}  errResponse      "Недостаточно прав"
+// @Failure   404      {object}  errResponse      "Пользователь не найден"
+// @Failure   500      {object}  errResponse      "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /users/{user_id}/ [get]
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(userCtxKey).(*model.User)
+
+	if err := render.Render(w, r, &getUserResponse{user}); err != nil {
+		_ = render.Render(w, r, errRender(err))
+		return
+	}
+}
+
+// getCurrentUser godoc
+// @Summary   Получить пользователя, которому принадлежит переданный ключ API
+// @Tags      users
+// @Accept    json
+// @Produce   json
+// @Success   200  {object}  getUserResponse  "ok"
+// @Failure   401  {object}  errResponse      "Не передан ключ API"
+// @Security  BearerAuth
+// @Router    /users/me [get]
+func (h *Handler) getCurrentUser(w http.ResponseWriter, r *http.Request) {
+	if err := render.Render(w, r, &getUserResponse{currentUser(r)}); err != nil {
+		_ = render.Render(w, r, errRender(err))
+		return
+	}
+}
+
+// regenerateUserAPIKey godoc
+// @Summary      Выпустить пользователю новый ключ API
+// @Description  Прежний ключ пользователя перестаёт действовать. Новый ключ возвращается только в ответе на этот запрос.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        user_id  path      string                  true  "ID пользователя"
+// @Success      200      {object}  userWithAPIKeyResponse  "ok"
+// @Failure      400      {object}  errResponse             "Некорректный ID пользователя"
+// @Failure      401      {object}  errResponse             "Не передан ключ API"
+// @Failure      403      {object}  errResponse             "Недостаточно прав"
+// @Failure      404      {object}  errResponse             "Пользователь не найден"
+// @Failure      500      {object}  errResponse             "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /users/{user_id}/api-key [post]
+func (h *Handler) regenerateUserAPIKey(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(userCtxKey).(*model.User)
+
+	apiKey, err := h.service.UserService.RegenerateAPIKey(user.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrUserNotFound) {
+			_ = render.Render(w, r, errNotFound(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &userWithAPIKeyResponse{User: user, APIKey: apiKey})
+}
+
+// deleteUserResponse представляет тело ответа на удаление пользователя.
+type deleteUserResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *deleteUserResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// deleteUser godoc
+// @Summary   Удалить пользователя API администрирования по его ID
+// @Tags      users
+// @Accept    json
+// @Produce   json
+// @Param     user_id  path      string              true  "ID пользователя"
+// @Success   200      {object}  deleteUserResponse  "ok"
+// @Failure   400      {object}  errResponse         "Некорректный ID пользователя"
+// @Failure   401      {object}  errResponse         "Не передан ключ API"
+// @Failure   403      {object}  errResponse         "Недостаточно прав"
+// @Failure   404      {object}  errResponse         "Пользователь не найден"
+// @Failure   500      {object}  errResponse         "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /users/{user_id}/ [delete]
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(userCtxKey).(*model.User)
+
+	if err := h.service.UserService.Delete(user.ID); err != nil {
+		if errors.Is(err, store.ErrUserNotFound) {
+			_ = render.Render(w, r, errNotFound(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &deleteUserResponse{Status: "ok"})
+}
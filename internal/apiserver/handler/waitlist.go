@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const waitlistEntryCtxKey = "waitlistEntry"
+
+// joinWaitlistRequest представляет тело запроса на постановку гостя в лист ожидания ресторана.
+type joinWaitlistRequest struct {
+	PeopleNumber int `json:"people_number" example:"3"`
+	// DesiredDatetime представляет желаемые дату и время посещения ресторана.
+	DesiredDatetime string `json:"desired_datetime" example:"2022.06.16 17:03"`
+	// ClientName имя гостя.
+	ClientName string `json:"client_name" example:"Павел"`
+	// ClientPhone телефон гостя в международном формате (российский номер можно записать и через 8).
+	ClientPhone string `json:"client_phone" example:"+79876545654"`
+}
+
+// Bind осуществляет пост-обработку запроса.
+func (r *joinWaitlistRequest) Bind(_ *http.Request) error {
+	if r.PeopleNumber == 0 || r.DesiredDatetime == "" || r.ClientName == "" || r.ClientPhone == "" {
+		return ErrWaitlistMissingFields
+	}
+
+	phone, err := model.NormalizePhone(r.ClientPhone)
+	if err != nil {
+		return err
+	}
+	r.ClientPhone = phone
+	return nil
+}
+
+// joinWaitlistResponse представляет тело ответа на постановку гостя в лист ожидания.
+type joinWaitlistResponse struct {
+	ID uint64 `json:"id" example:"5"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *joinWaitlistResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// joinWaitlist godoc
+// @Summary      Поставить гостя в лист ожидания ресторана
+// @Description  Когда в ресторане освобождаются места (отменяется бронь или появляется столик), они предлагаются первому подходящему гостю из листа ожидания. Предложение действует 15 минут.
+// @Tags         waitlist
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                true  "ID ресторана"
+// @Param        input          body      joinWaitlistRequest   true  "Информация о госте"
+// @Success      201            {object}  joinWaitlistResponse  "ok"
+// @Failure      400            {object}  errResponse           "Некорректные данные гостя"
+// @Failure      500            {object}  errResponse           "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/waitlist/ [post]
+func (h *Handler) joinWaitlist(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	data := &joinWaitlistRequest{}
+	if err := render.Bind(r, data); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	details := model.BookingDetails{
+		RestaurantID:    restaurant.ID,
+		PeopleNumber:    strconv.Itoa(data.PeopleNumber),
+		DesiredDatetime: data.DesiredDatetime,
+		ClientName:      data.ClientName,
+		ClientPhone:     data.ClientPhone,
+	}
+
+	entryID, err := h.service.WaitlistService.Join(details)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	_ = render.Render(w, r, &joinWaitlistResponse{
+		ID: entryID,
+	})
+}
+
+// listWaitlistResponse представляет тело ответа на получение листа ожидания ресторана.
+type listWaitlistResponse struct {
+	Data []model.WaitlistEntry `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *listWaitlistResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// listWaitlist godoc
+// @Summary   Получить лист ожидания ресторана
+// @Tags      waitlist
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string                true  "ID ресторана"
+// @Success   200            {object}  listWaitlistResponse  "ok"
+// @Failure   400            {object}  errResponse           "Некорректный restaurant_id"
+// @Failure   500            {object}  errResponse           "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/waitlist/ [get]
+func (h *Handler) listWaitlist(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	entries, err := h.service.WaitlistService.GetAll(restaurant.ID)
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &listWaitlistResponse{
+		Data: entries,
+	})
+}
+
+// waitlistEntryCtx используется для загрузки записи в листе ожидания (model.WaitlistEntry) из контекста запроса
+// по entry_id, переданному в параметрах URL запроса. Запись должна относиться к ресторану из контекста запроса.
+func (h *Handler) waitlistEntryCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if entryIDStr := chi.URLParam(r, "entry_id"); entryIDStr != "" {
+			entryID, err := strconv.ParseUint(entryIDStr, 10, 0)
+			if err != nil {
+				_ = render.Render(w, r, errInvalidRequest(err))
+				return
+			}
+
+			entry, err := h.service.WaitlistService.Get(entryID)
+			if err != nil {
+				if errors.Is(err, store.ErrWaitlistEntryNotFound) {
+					_ = render.Render(w, r, errNotFound(err))
+					return
+				}
+				_ = render.Render(w, r, errServiceFailure(err))
+				return
+			}
+
+			restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+			if entry.RestaurantID != restaurant.ID {
+				_ = render.Render(w, r, errNotFound(store.ErrWaitlistEntryNotFound))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), waitlistEntryCtxKey, entry)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		} else {
+			_ = render.Render(w, r, errInvalidRequest(ErrWaitlistMissingFields))
+			return
+		}
+	})
+}
+
+// getWaitlistEntryResponse представляет тело ответа на получение записи в листе ожидания.
+type getWaitlistEntryResponse struct {
+	*model.WaitlistEntry
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getWaitlistEntryResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getWaitlistEntry godoc
+// @Summary   Получить запись в листе ожидания ресторана по её ID
+// @Tags      waitlist
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string                    true  "ID ресторана"
+// @Param     entry_id       path      string                    true  "ID записи в листе ожидания"
+// @Success   200            {object}  getWaitlistEntryResponse  "ok"
+// @Failure   400            {object}  errResponse               "Некорректный ID записи"
+// @Failure   404            {object}  errResponse               "Запись не найдена"
+// @Failure   500            {object}  errResponse               "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/waitlist/{entry_id}/ [get]
+func (h *Handler) getWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	entry := r.Context().Value(waitlistEntryCtxKey).(*model.WaitlistEntry)
+
+	if err := render.Render(w, r, &getWaitlistEntryResponse{entry}); err != nil {
+		_ = render.Render(w, r, errRender(err))
+		return
+	}
+}
+
+// acceptWaitlistOfferResponse представляет тело ответа на принятие предложения из листа ожидания.
+type acceptWaitlistOfferResponse struct {
+	// BookingID представляет ID оформленной брони.
+	BookingID uint64 `json:"booking_id" example:"12"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *acceptWaitlistOfferResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// acceptWaitlistOffer godoc
+// @Summary      Принять предложение и оформить бронь по записи в листе ожидания
+// @Description  Если предложенные места успели занять, гость снова ждёт своей очереди.
+// @Tags         waitlist
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                       true  "ID ресторана"
+// @Param        entry_id       path      string                       true  "ID записи в листе ожидания"
+// @Success      201            {object}  acceptWaitlistOfferResponse  "ok"
+// @Failure      400            {object}  errResponse                  "Гостю не предлагались места или предложение истекло"
+// @Failure      403            {object}  errResponse                  "Онлайн-бронирование заблокировано для гостя из-за неявок"
+// @Failure      404            {object}  errResponse                  "Запись не найдена"
+// @Failure      409            {object}  errResponse                  "Предложенные места уже заняты"
+// @Failure      500            {object}  errResponse                  "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/waitlist/{entry_id}/accept [post]
+func (h *Handler) acceptWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	entry := r.Context().Value(waitlistEntryCtxKey).(*model.WaitlistEntry)
+
+	bookingID, err := h.service.WaitlistService.Accept(entry.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWaitlistNoOffer), errors.Is(err, service.ErrInvalidData):
+			_ = render.Render(w, r, errInvalidRequest(err))
+		case errors.Is(err, service.ErrNotEnoughSeatsInRestaurant):
+			_ = render.Render(w, r, errConflict(err))
+		case errors.Is(err, service.ErrGuestBlocked):
+			_ = render.Render(w, r, errForbidden(err))
+		default:
+			_ = render.Render(w, r, errServiceFailure(err))
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	_ = render.Render(w, r, &acceptWaitlistOfferResponse{BookingID: bookingID})
+}
+
+// leaveWaitlistResponse представляет тело ответа на удаление гостя из листа ожидания.
+type leaveWaitlistResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *leaveWaitlistResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// leaveWaitlist godoc
+// @Summary      Убрать гостя из листа ожидания ресторана
+// @Description  Если гостю были предложены места, они предлагаются следующему гостю.
+// @Tags         waitlist
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                 true  "ID ресторана"
+// @Param        entry_id       path      string                 true  "ID записи в листе ожидания"
+// @Success      200            {object}  leaveWaitlistResponse  "ok"
+// @Failure      400            {object}  errResponse            "Гость уже не ждёт мест"
+// @Failure      404            {object}  errResponse            "Запись не найдена"
+// @Failure      500            {object}  errResponse            "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/waitlist/{entry_id}/ [delete]
+func (h *Handler) leaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	entry := r.Context().Value(waitlistEntryCtxKey).(*model.WaitlistEntry)
+
+	if err := h.service.WaitlistService.Leave(entry.ID); err != nil {
+		if errors.Is(err, service.ErrWaitlistEntryClosed) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &leaveWaitlistResponse{Status: "ok"})
+}
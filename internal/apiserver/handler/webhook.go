@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/service"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const webhookCtxKey = "webhook"
+
+// webhookWithSecretResponse представляет тело ответа с вебхуком и ключом, которым подписываются его события.
+// Ключ показывается только один раз.
+type webhookWithSecretResponse struct {
+	*model.Webhook
+	Secret string `json:"secret" example:"9b2e..."`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *webhookWithSecretResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// createWebhook godoc
+// @Summary      Создать вебхук ресторана
+// @Description  На адрес вебхука POST-запросом отправляются события с бронями ресторана (booking.created, booking.updated, booking.status_changed, booking.cancelled; пустой список events - все события). Тело запроса подписывается ключом вебхука: заголовок X-Webhook-Signature имеет вид t=<unix-время>,v1=<HMAC-SHA256 строки "<unix-время>.<тело>" в шестнадцатеричном виде>. Ключ возвращается только в ответе на этот запрос. Недоставленные события (получатель не ответил кодом 2xx) отправляются повторно с нарастающей задержкой.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                     true  "ID ресторана"
+// @Param        input          body      model.CreateWebhookData    true  "Адрес и события вебхука"
+// @Success      201            {object}  webhookWithSecretResponse  "ok"
+// @Failure      400            {object}  errResponse                "Некорректный адрес или события вебхука"
+// @Failure      403            {object}  errResponse                "Недостаточно прав"
+// @Failure      404            {object}  errResponse                "Ресторан не найден"
+// @Failure      500            {object}  errResponse                "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/webhooks/ [post]
+func (h *Handler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	data := &model.CreateWebhookData{}
+	if err := render.Bind(r, data); err != nil {
+		_ = render.Render(w, r, errInvalidRequest(err))
+		return
+	}
+
+	webhook, secret, err := h.service.WebhookService.Create(restaurant.ID, *data)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidData) {
+			_ = render.Render(w, r, errInvalidRequest(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	_ = render.Render(w, r, &webhookWithSecretResponse{Webhook: webhook, Secret: secret})
+}
+
+// listWebhooksResponse представляет тело ответа на получение списка вебхуков ресторана.
+type listWebhooksResponse struct {
+	Data []model.Webhook `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *listWebhooksResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// listWebhooks godoc
+// @Summary   Получить список вебхуков ресторана
+// @Tags      webhooks
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string                true  "ID ресторана"
+// @Success   200            {object}  listWebhooksResponse  "ok"
+// @Failure   403            {object}  errResponse           "Недостаточно прав"
+// @Failure   404            {object}  errResponse           "Ресторан не найден"
+// @Failure   500            {object}  errResponse           "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/webhooks/ [get]
+func (h *Handler) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+
+	webhooks, err := h.service.WebhookService.GetAll(restaurant.ID)
+	if err != nil {
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &listWebhooksResponse{
+		Data: webhooks,
+	})
+}
+
+// webhookCtx используется для загрузки вебхука (model.Webhook) из контекста запроса по webhook_id, переданному
+// в параметрах URL запроса. Вебхук должен относиться к ресторану из контекста запроса.
+func (h *Handler) webhookCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if webhookIDStr := chi.URLParam(r, "webhook_id"); webhookIDStr != "" {
+			webhookID, err := strconv.ParseUint(webhookIDStr, 10, 0)
+			if err != nil {
+				_ = render.Render(w, r, errInvalidRequest(err))
+				return
+			}
+
+			webhook, err := h.service.WebhookService.Get(webhookID)
+			if err != nil {
+				if errors.Is(err, store.ErrWebhookNotFound) {
+					_ = render.Render(w, r, errNotFound(err))
+					return
+				}
+				_ = render.Render(w, r, errServiceFailure(err))
+				return
+			}
+
+			restaurant := r.Context().Value(restaurantCtxKey).(*model.Restaurant)
+			if webhook.RestaurantID != restaurant.ID {
+				_ = render.Render(w, r, errNotFound(store.ErrWebhookNotFound))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), webhookCtxKey, webhook)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		} else {
+			_ = render.Render(w, r, errInvalidRequest(ErrWebhookMissingFields))
+			return
+		}
+	})
+}
+
+// getWebhookResponse представляет тело ответа на получение вебхука.
+type getWebhookResponse struct {
+	*model.Webhook
+}
+
+// Render осуществляет предобработку ответа.
+func (r *getWebhookResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// getWebhook godoc
+// @Summary   Получить вебхук ресторана по его ID
+// @Tags      webhooks
+// @Accept    json
+// @Produce   json
+// @Param     restaurant_id  path      string              true  "ID ресторана"
+// @Param     webhook_id     path      string              true  "ID вебхука"
+// @Success   200            {object}  getWebhookResponse  "ok"
+// @Failure   400            {object}  errResponse         "Некорректный ID вебхука"
+// @Failure   403            {object}  errResponse         "Недостаточно прав"
+// @Failure   404            {object}  errResponse         "Вебхук не найден"
+// @Failure   500            {object}  errResponse         "Ошибка на стороне сервера"
+// @Security  BearerAuth
+// @Router    /restaurants/{restaurant_id}/webhooks/{webhook_id}/ [get]
+func (h *Handler) getWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook := r.Context().Value(webhookCtxKey).(*model.Webhook)
+
+	_ = render.Render(w, r, &getWebhookResponse{Webhook: webhook})
+}
+
+// deleteWebhookResponse представляет тело ответа на удаление вебхука.
+type deleteWebhookResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *deleteWebhookResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// deleteWebhook godoc
+// @Summary      Удалить вебхук ресторана
+// @Description  Вместе с вебхуком удаляется журнал доставки ему событий: недоставленные события больше не отправляются.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                 true  "ID ресторана"
+// @Param        webhook_id     path      string                 true  "ID вебхука"
+// @Success      200            {object}  deleteWebhookResponse  "ok"
+// @Failure      400            {object}  errResponse            "Некорректный ID вебхука"
+// @Failure      403            {object}  errResponse            "Недостаточно прав"
+// @Failure      404            {object}  errResponse            "Вебхук не найден"
+// @Failure      500            {object}  errResponse            "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/webhooks/{webhook_id}/ [delete]
+func (h *Handler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook := r.Context().Value(webhookCtxKey).(*model.Webhook)
+
+	if err := h.service.WebhookService.Delete(webhook.ID); err != nil {
+		if errors.Is(err, store.ErrWebhookNotFound) {
+			_ = render.Render(w, r, errNotFound(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &deleteWebhookResponse{Status: "ok"})
+}
+
+// listWebhookDeliveriesResponse представляет тело ответа на получение журнала доставки событий вебхуку.
+type listWebhookDeliveriesResponse struct {
+	Data []model.WebhookDelivery `json:"data"`
+}
+
+// Render осуществляет предобработку ответа.
+func (r *listWebhookDeliveriesResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+// listWebhookDeliveries godoc
+// @Summary      Получить журнал доставки событий вебхуку
+// @Description  Возвращает последние 50 доставок (первыми идут самые новые): статус (pending - ждёт доставки или повторной попытки, delivered - доставлено, failed - не доставлено за все попытки), количество попыток, код ответа и ошибку последней попытки.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        restaurant_id  path      string                         true  "ID ресторана"
+// @Param        webhook_id     path      string                         true  "ID вебхука"
+// @Success      200            {object}  listWebhookDeliveriesResponse  "ok"
+// @Failure      400            {object}  errResponse                    "Некорректный ID вебхука"
+// @Failure      403            {object}  errResponse                    "Недостаточно прав"
+// @Failure      404            {object}  errResponse                    "Вебхук не найден"
+// @Failure      500            {object}  errResponse                    "Ошибка на стороне сервера"
+// @Security     BearerAuth
+// @Router       /restaurants/{restaurant_id}/webhooks/{webhook_id}/deliveries [get]
+func (h *Handler) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhook := r.Context().Value(webhookCtxKey).(*model.Webhook)
+
+	deliveries, err := h.service.WebhookService.GetDeliveries(webhook.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrWebhookNotFound) {
+			_ = render.Render(w, r, errNotFound(err))
+			return
+		}
+		_ = render.Render(w, r, errServiceFailure(err))
+		return
+	}
+
+	_ = render.Render(w, r, &listWebhookDeliveriesResponse{
+		Data: deliveries,
+	})
+}
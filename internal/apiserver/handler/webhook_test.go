@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateWebhook_URL(t *testing.T) {
+	s := newTestServer(t)
+	target := fmt.Sprintf("/api/v1/restaurants/%d/webhooks/", s.restaurantID)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+	}{
+		{name: "public", url: "https://crm.example.com/hooks/bookings", wantCode: http.StatusCreated},
+		{name: "localhost", url: "http://localhost:8080/hooks", wantCode: http.StatusBadRequest},
+		{name: "loopback", url: "http://127.0.0.1/hooks", wantCode: http.StatusBadRequest},
+		{name: "private network", url: "http://192.168.1.10/hooks", wantCode: http.StatusBadRequest},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data/", wantCode: http.StatusBadRequest},
+		{name: "unique local ipv6", url: "http://[fd12:3456::1]/hooks", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"url": %q}`, tt.url)
+			w := s.do(http.MethodPost, target, "application/json", strings.NewReader(body))
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.wantCode, w.Body)
+			}
+		})
+	}
+}
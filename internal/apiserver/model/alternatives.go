@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Alternatives представляет варианты, которые предлагаются гостю, если на желаемое время не нашлось ни одного
+// ресторана, где можно рассадить его компанию.
+type Alternatives struct {
+	// Times представляет ближайшие к желаемому моменты начала брони (раньше или позже в тот же день и в следующие дни),
+	// на которые компанию можно рассадить хотя бы в одном ресторане.
+	Times []AlternativeTime `json:"times"`
+	// Restaurants представляет рестораны, в которых компанию можно рассадить в ближайшее к желаемому время.
+	Restaurants []AlternativeRestaurant `json:"restaurants"`
+}
+
+// IsEmpty проверяет, нашлись ли варианты для гостя.
+func (a Alternatives) IsEmpty() bool {
+	return len(a.Times) == 0 && len(a.Restaurants) == 0
+}
+
+// AlternativeTime представляет момент начала брони, предлагаемый гостю вместо желаемого.
+type AlternativeTime struct {
+	// DateTime представляет дату и время посещения ресторана.
+	DateTime FormattedDateTime `json:"datetime" example:"2022.06.16 18:30"`
+	// RestaurantsNumber представляет количество ресторанов, в которых на это время можно рассадить компанию.
+	RestaurantsNumber int `json:"restaurants_number" example:"2"`
+}
+
+// AlternativeRestaurant представляет ресторан, предлагаемый гостю, с ближайшим к желаемому временем, на которое
+// в нём можно рассадить компанию.
+type AlternativeRestaurant struct {
+	// Restaurant представляет ресторан (с количеством свободных мест на предлагаемое время).
+	Restaurant Restaurant `json:"restaurant"`
+	// DateTime представляет дату и время посещения ресторана.
+	DateTime FormattedDateTime `json:"datetime" example:"2022.06.16 18:30"`
+}
+
+// FormattedDateTime представляет дату и время в формате "2006.01.02 15:04".
+type FormattedDateTime time.Time
+
+func (t FormattedDateTime) MarshalJSON() ([]byte, error) {
+	stamp := fmt.Sprintf("\"%s\"", t.String())
+	return []byte(stamp), nil
+}
+
+// String возвращает дату и время в формате "2006.01.02 15:04".
+func (t FormattedDateTime) String() string {
+	return time.Time(t).Format("2006.01.02 15:04")
+}
+
+// InputValue возвращает дату и время в формате поля ввода datetime-local на сайте ("2006-01-02T15:04").
+func (t FormattedDateTime) InputValue() string {
+	return time.Time(t).Format("2006-01-02T15:04")
+}
@@ -0,0 +1,24 @@
+package model
+
+// Availability представляет сетку доступности ресторана на выбранный день для компании из PeopleNumber человек.
+type Availability struct {
+	// Date представляет дату посещения ресторана.
+	Date ShortFormattedDate `json:"date" example:"2022.06.16"`
+	// PeopleNumber представляет количество человек в компании.
+	PeopleNumber int `json:"people_number" example:"4"`
+	// Duration представляет длительность брони в минутах для компании такого размера.
+	Duration int `json:"duration" example:"120"`
+	// Slots представляет моменты начала брони в пределах графика работы ресторана.
+	Slots []AvailabilitySlot `json:"slots"`
+}
+
+// AvailabilitySlot представляет время начала брони в сетке доступности ресторана на выбранный день.
+type AvailabilitySlot struct {
+	// Time представляет время начала брони.
+	Time ShortFormattedTime `json:"time" example:"18:15"`
+	// AvailableSeatsNumber представляет количество мест за столиками, свободными на всё время брони.
+	AvailableSeatsNumber int `json:"available_seats_number" example:"12"`
+	// Bookable показывает, можно ли рассадить компанию за свободными столиками (с учётом того, какие столики
+	// можно сдвинуть).
+	Bookable bool `json:"bookable" example:"true"`
+}
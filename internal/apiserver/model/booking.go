@@ -1,41 +1,181 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 )
 
+const (
+	// BookingCancelledByClient означает, что бронь отменил сам клиент (через веб-сайт).
+	BookingCancelledByClient = "client"
+	// BookingCancelledByRestaurant означает, что бронь отменил ресторан (через API).
+	BookingCancelledByRestaurant = "restaurant"
+)
+
+const (
+	// BookingStatusPending означает, что бронь ожидает подтверждения рестораном.
+	BookingStatusPending = "pending"
+	// BookingStatusConfirmed означает, что бронь подтверждена и гости ещё не пришли.
+	BookingStatusConfirmed = "confirmed"
+	// BookingStatusSeated означает, что гости пришли и сидят за столиками.
+	BookingStatusSeated = "seated"
+	// BookingStatusCompleted означает, что гости ушли, а столики освободились.
+	BookingStatusCompleted = "completed"
+	// BookingStatusNoShow означает, что гости не пришли.
+	BookingStatusNoShow = "no_show"
+	// BookingStatusCancelled означает, что бронь отменена.
+	BookingStatusCancelled = "cancelled"
+)
+
+// bookingStatusTransitions содержит статусы, в которые можно перевести бронь из каждого статуса. Из статусов
+// BookingStatusCompleted, BookingStatusNoShow и BookingStatusCancelled бронь перевести нельзя.
+var bookingStatusTransitions = map[string][]string{
+	BookingStatusPending:   {BookingStatusConfirmed, BookingStatusSeated, BookingStatusNoShow, BookingStatusCancelled},
+	BookingStatusConfirmed: {BookingStatusSeated, BookingStatusNoShow, BookingStatusCancelled},
+	BookingStatusSeated:    {BookingStatusCompleted},
+}
+
+// CanChangeBookingStatus проверяет, можно ли перевести бронь из статуса from в статус to.
+func CanChangeBookingStatus(from, to string) bool {
+	for _, status := range bookingStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
 // Booking представляет бронь.
 type Booking struct {
 	ID uint64 `json:"id" example:"3"`
+	// RestaurantID представляет ID ресторана, в котором оформлена бронь.
+	RestaurantID uint64 `json:"restaurant_id" example:"2"`
+	// CustomerID представляет ID учётной записи гостя, оформившего бронь (nil, если бронь оформлена без учётной записи).
+	CustomerID *uint64 `json:"customer_id,omitempty" example:"5"`
+	// GuestID представляет ID гостя, определённого по телефону в брони (nil, если в телефоне нет цифр).
+	GuestID *uint64 `json:"guest_id,omitempty" example:"12"`
 	// ClientName представляет имя клиента, оформляющего бронь.
 	ClientName string `json:"client_name" example:"Павел"`
 	// ClientPhone представляет телефон клиента, оформляющего бронь.
-	ClientPhone string `json:"client_phone" example:"89485722648"`
+	ClientPhone string `json:"client_phone" example:"+79485722648"`
+	// ClientEmail представляет адрес электронной почты клиента для уведомлений о брони (пустая строка - не указан).
+	ClientEmail string `json:"client_email,omitempty" example:"pavel@example.com"`
+	// PeopleNumber представляет количество человек, на которое оформлена бронь.
+	PeopleNumber int `json:"people_number" example:"4"`
 	// BookedDate представляет дату посещения ресторана в рамках брони.
 	BookedDate ShortFormattedDate `json:"booked_date" example:"2022.06.16"`
 	// BookedTimeFrom представляет время начала брони.
 	BookedTimeFrom ShortFormattedTime `json:"booked_time_from" example:"14:30"`
 	// BookedTimeTo представляет время конца брони.
 	BookedTimeTo ShortFormattedTime `json:"booked_time_to" example:"16:30"`
+	// Status представляет статус брони: pending, confirmed, seated, completed, no_show или cancelled.
+	Status string `json:"status" example:"confirmed"`
+	// SeatedAt представляет момент, когда гости пришли в ресторан (nil, если гости ещё не приходили).
+	SeatedAt *time.Time `json:"seated_at,omitempty" example:"2022-06-16T14:35:00Z"`
+	// FinishedAt представляет момент, когда гости ушли или бронь была отмечена как неявка: с этого момента столики
+	// брони свободны (nil, если бронь ещё не завершена).
+	FinishedAt *time.Time `json:"finished_at,omitempty" example:"2022-06-16T16:10:00Z"`
+	// CancelledAt представляет момент отмены брони (nil, если бронь не отменена).
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" example:"2022-06-15T12:00:00Z"`
+	// CancelledBy представляет того, кто отменил бронь: BookingCancelledByClient или BookingCancelledByRestaurant.
+	CancelledBy string `json:"cancelled_by,omitempty" example:"client"`
+	// TableIDs представляет ID столиков, забронированных в рамках брони (заполняется при получении брони по её ID).
+	TableIDs []uint64 `json:"table_ids,omitempty"`
+}
+
+// IsCancelled проверяет, отменена ли бронь.
+func (b Booking) IsCancelled() bool {
+	return b.CancelledAt != nil
+}
+
+// IsActive проверяет, ждёт ли бронь прихода гостей (ожидает подтверждения или подтверждена).
+func (b Booking) IsActive() bool {
+	return b.Status == BookingStatusPending || b.Status == BookingStatusConfirmed
+}
+
+// IsPending проверяет, ожидает ли бронь подтверждения рестораном.
+func (b Booking) IsPending() bool {
+	return b.Status == BookingStatusPending
+}
+
+// IsNoShow проверяет, отмечена ли бронь как неявка.
+func (b Booking) IsNoShow() bool {
+	return b.Status == BookingStatusNoShow
+}
+
+// StartsAt возвращает дату и время начала брони.
+func (b Booking) StartsAt() time.Time {
+	date, clock := time.Time(b.BookedDate), time.Time(b.BookedTimeFrom)
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+}
+
+// EndsAt возвращает дату и время конца брони.
+func (b Booking) EndsAt() time.Time {
+	date, clock := time.Time(b.BookedDate), time.Time(b.BookedTimeTo)
+	endsAt := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	// бронь, которая заканчивается после полуночи, заканчивается на следующий день
+	if !endsAt.After(b.StartsAt()) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt
 }
 
 // ShortFormattedTime представляет время в формате "15:04".
 type ShortFormattedTime time.Time
 
+// NewShortFormattedTime возвращает время суток с указанными часами и минутами.
+func NewShortFormattedTime(hour, minute int) ShortFormattedTime {
+	return ShortFormattedTime(time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC))
+}
+
 func (t ShortFormattedTime) MarshalJSON() ([]byte, error) {
-	stamp := fmt.Sprintf("\"%s\"", time.Time(t).Format("15:04"))
+	stamp := fmt.Sprintf("\"%s\"", t.String())
 	return []byte(stamp), nil
 }
 
+func (t *ShortFormattedTime) UnmarshalJSON(data []byte) error {
+	var stamp string
+	if err := json.Unmarshal(data, &stamp); err != nil {
+		return err
+	}
+	parsed, err := time.Parse("15:04", stamp)
+	if err != nil {
+		return err
+	}
+	*t = ShortFormattedTime(parsed)
+	return nil
+}
+
+// String возвращает время в формате "15:04".
+func (t ShortFormattedTime) String() string {
+	return time.Time(t).Format("15:04")
+}
+
+// TimeOfDay возвращает время, прошедшее с начала суток.
+func (t ShortFormattedTime) TimeOfDay() time.Duration {
+	return TimeOfDay(time.Time(t))
+}
+
 // ShortFormattedDate представляет дату в формате "2006.01.02".
 type ShortFormattedDate time.Time
 
 func (t ShortFormattedDate) MarshalJSON() ([]byte, error) {
-	stamp := fmt.Sprintf("\"%s\"", time.Time(t).Format("2006.01.02"))
+	stamp := fmt.Sprintf("\"%s\"", t.String())
 	return []byte(stamp), nil
 }
 
+// String возвращает дату в формате "2006.01.02".
+func (t ShortFormattedDate) String() string {
+	return time.Time(t).Format("2006.01.02")
+}
+
+// TimeOfDay возвращает время, прошедшее с начала суток до момента t.
+func TimeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
 // BookingsTables представляет таблицу в БД, в которой хранятся столики и брони, к которым они относятся.
 type BookingsTables struct {
 	ID        uint64
@@ -55,4 +195,31 @@ type BookingDetails struct {
 	ClientName string
 	// ClientPhone телефон клиента, оформляющего бронь.
 	ClientPhone string
+	// ClientEmail представляет адрес электронной почты клиента для уведомлений о брони (необязательно).
+	ClientEmail string
+	// CustomerID представляет ID учётной записи гостя, оформляющего бронь (0 - бронь оформляется без учётной записи).
+	CustomerID uint64
+	// Zone представляет зону ресторана, в которой клиент хочет сидеть (пустая строка - любая зона).
+	Zone string
+	// Pending означает, что бронь оформляется в статусе BookingStatusPending и ждёт подтверждения рестораном
+	// (иначе бронь сразу подтверждена).
+	Pending bool
+	// ByRestaurant означает, что бронь оформляет ресторан (например, принимая заказ по телефону), поэтому правила
+	// надёжности гостей ресторана к ней не применяются.
+	ByRestaurant bool
+}
+
+// UpdateBookingData содержит новые количество человек и (или) дату и время брони и используется для её изменения.
+type UpdateBookingData struct {
+	PeopleNumber *int `json:"people_number" example:"4"`
+	// DesiredDatetime представляет новые дату и время посещения ресторана (строка вида "2022.06.16 17:03").
+	DesiredDatetime *string `json:"desired_datetime" example:"2022.06.16 19:00"`
+}
+
+// Bind осуществляет пост-обработку запроса UpdateBookingData.
+func (d *UpdateBookingData) Bind(_ *http.Request) error {
+	if d.PeopleNumber == nil && d.DesiredDatetime == nil {
+		return ErrUpdateBookingData
+	}
+	return nil
 }
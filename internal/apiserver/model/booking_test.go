@@ -0,0 +1,30 @@
+package model
+
+import "testing"
+
+func TestCanChangeBookingStatus(t *testing.T) {
+	statuses := []string{
+		BookingStatusPending, BookingStatusConfirmed, BookingStatusSeated,
+		BookingStatusCompleted, BookingStatusNoShow, BookingStatusCancelled,
+	}
+	// allowed содержит все допустимые переходы, остальные переходы запрещены
+	allowed := map[[2]string]bool{
+		{BookingStatusPending, BookingStatusConfirmed}:   true,
+		{BookingStatusPending, BookingStatusSeated}:      true,
+		{BookingStatusPending, BookingStatusNoShow}:      true,
+		{BookingStatusPending, BookingStatusCancelled}:   true,
+		{BookingStatusConfirmed, BookingStatusSeated}:    true,
+		{BookingStatusConfirmed, BookingStatusNoShow}:    true,
+		{BookingStatusConfirmed, BookingStatusCancelled}: true,
+		{BookingStatusSeated, BookingStatusCompleted}:    true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]string{from, to}]
+			if got := CanChangeBookingStatus(from, to); got != want {
+				t.Errorf("CanChangeBookingStatus(%s, %s) = %t, want %t", from, to, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Customer представляет учётную запись гостя на сайте. Гость с учётной записью не вводит имя и телефон при каждой
+// брони и видит историю своих броней; оформить бронь можно и без учётной записи.
+type Customer struct {
+	ID   uint64 `json:"id" example:"5"`
+	Name string `json:"name" example:"Павел"`
+	// Phone представляет телефон гостя, по которому он входит на сайт и который указывается в его бронях.
+	Phone string `json:"phone" example:"+79485722648"`
+	// CreatedAt представляет момент регистрации гостя.
+	CreatedAt time.Time `json:"created_at" example:"2022-06-15T12:00:00Z"`
+}
+
+// CustomerSession представляет начатый сеанс гостя на сайте.
+type CustomerSession struct {
+	// Customer представляет гостя, для которого начат сеанс.
+	Customer *Customer
+	// Token представляет токен сеанса, который сохраняется в cookie гостя. В БД хранится только его хеш.
+	Token string
+	// ExpiresAt представляет момент, после которого сеанс перестаёт действовать.
+	ExpiresAt time.Time
+}
+
+// CustomerBooking представляет бронь из истории броней гостя вместе с названием ресторана.
+type CustomerBooking struct {
+	Booking
+	// RestaurantName представляет название ресторана, в котором оформлена бронь.
+	RestaurantName string
+}
+
+// BookingHistory представляет историю броней гостя с учётной записью.
+type BookingHistory struct {
+	// Upcoming представляет действующие брони, время которых ещё не наступило (от ближайшей к самой дальней).
+	Upcoming []CustomerBooking
+	// Past представляет прошедшие, завершённые и отменённые брони (от самой поздней к самой ранней).
+	Past []CustomerBooking
+}
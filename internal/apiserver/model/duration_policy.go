@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultBookingDuration представляет длительность брони в минутах, если ресторан не задал собственную.
+	DefaultBookingDuration = 120
+	// MaxBookingDuration представляет максимально допустимую длительность брони в минутах.
+	MaxBookingDuration = 12 * 60
+)
+
+// DurationRule представляет правило, по которому компании от MinPeople человек бронируют столики на Duration минут.
+type DurationRule struct {
+	// MinPeople представляет минимальное количество человек, с которого действует правило.
+	MinPeople int `json:"min_people" example:"8"`
+	// Duration представляет длительность брони в минутах.
+	Duration int `json:"duration" example:"180"`
+}
+
+// DurationPolicy представляет правила, по которым определяется длительность брони в ресторане.
+type DurationPolicy struct {
+	// DefaultDuration представляет длительность брони в минутах, если ни одно из правил не подошло.
+	DefaultDuration int `json:"default_duration" example:"120"`
+	// Rules представляет правила для компаний разного размера: применяется правило с наибольшим MinPeople,
+	// не превышающим количество человек.
+	Rules []DurationRule `json:"rules"`
+}
+
+// DefaultDurationPolicy возвращает правила ресторана, который не задал собственных: любая бронь длится 2 часа.
+func DefaultDurationPolicy() DurationPolicy {
+	return DurationPolicy{DefaultDuration: DefaultBookingDuration}
+}
+
+// DurationFor возвращает длительность брони для компании из peopleNumber человек.
+func (p DurationPolicy) DurationFor(peopleNumber int) time.Duration {
+	minutes, bestMinPeople := p.DefaultDuration, 0
+	for _, rule := range p.Rules {
+		if rule.MinPeople <= peopleNumber && rule.MinPeople > bestMinPeople {
+			minutes, bestMinPeople = rule.Duration, rule.MinPeople
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Validate проверяет, что все длительности брони положительны и не превышают MaxBookingDuration,
+// а правила не повторяются.
+func (p DurationPolicy) Validate() error {
+	if p.DefaultDuration < 1 || p.DefaultDuration > MaxBookingDuration {
+		return fmt.Errorf("%w: the default duration must be between 1 and %d minutes", ErrInvalidDurationPolicy, MaxBookingDuration)
+	}
+
+	minPeople := make(map[int]struct{}, len(p.Rules))
+	for _, rule := range p.Rules {
+		if rule.MinPeople < 1 {
+			return fmt.Errorf("%w: the minimum number of people cannot be less than 1", ErrInvalidDurationPolicy)
+		}
+		if rule.Duration < 1 || rule.Duration > MaxBookingDuration {
+			return fmt.Errorf("%w: the duration must be between 1 and %d minutes", ErrInvalidDurationPolicy, MaxBookingDuration)
+		}
+		if _, ok := minPeople[rule.MinPeople]; ok {
+			return fmt.Errorf("%w: duplicate rule for %d people", ErrInvalidDurationPolicy, rule.MinPeople)
+		}
+		minPeople[rule.MinPeople] = struct{}{}
+	}
+	return nil
+}
+
+// Bind осуществляет пост-обработку запроса DurationPolicy.
+func (p *DurationPolicy) Bind(_ *http.Request) error {
+	return p.Validate()
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// maxEmailLength представляет максимальную длину адреса электронной почты (RFC 5321).
+const maxEmailLength = 254
+
+// NormalizeEmail проверяет адрес электронной почты гостя и возвращает его без пробелов по краям. Адрес указывается
+// без имени получателя (pavel@example.com, а не "Павел <pavel@example.com>"). Если адрес некорректен,
+// возвращается ErrInvalidEmail.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if len(email) > maxEmailLength {
+		return "", ErrInvalidEmail
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Name != "" || address.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
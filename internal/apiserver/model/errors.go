@@ -7,4 +7,29 @@ var (
 	ErrUpdateRestaurantData = errors.New("update restaurant data has no values")
 	// ErrUpdateTableData возникает при попытке обновить данные о столике в ресторане без передачи самих данных.
 	ErrUpdateTableData = errors.New("update table data has no values")
+	// ErrUpdateBookingData возникает при попытке изменить бронь без передачи новых данных.
+	ErrUpdateBookingData = errors.New("update booking data has no values")
+	// ErrInvalidOpeningHours возникает при попытке задать ресторану некорректный график работы.
+	ErrInvalidOpeningHours = errors.New("invalid opening hours")
+	// ErrInvalidDurationPolicy возникает при попытке задать ресторану некорректные правила длительности брони.
+	ErrInvalidDurationPolicy = errors.New("invalid booking duration policy")
+	// ErrInvalidReliabilityPolicy возникает при попытке задать ресторану некорректные правила надёжности гостей.
+	ErrInvalidReliabilityPolicy = errors.New("invalid guest reliability policy")
+	// ErrUnknownAllocationStrategy возникает при попытке задать ресторану неподдерживаемую стратегию выбора столиков.
+	ErrUnknownAllocationStrategy = errors.New("unknown table allocation strategy")
+	// ErrInvalidSeatsNumber возникает при попытке создать столик без мест или с отрицательным количеством мест.
+	ErrInvalidSeatsNumber = errors.New("invalid seats number: a table must have at least one seat")
+	// ErrUnknownZone возникает при попытке указать несуществующую зону ресторана.
+	ErrUnknownZone = errors.New("unknown restaurant zone")
+	// ErrInvalidPhone возникает, когда телефон нельзя привести к формату E.164.
+	ErrInvalidPhone = errors.New("invalid phone number: use the international format, e.g. +79279007265")
+	// ErrInvalidEmail возникает, когда адрес электронной почты гостя некорректен.
+	ErrInvalidEmail = errors.New("invalid email address, e.g. pavel@example.com")
+	// ErrInvalidWebhookURL возникает при попытке создать вебхук с некорректным адресом.
+	ErrInvalidWebhookURL = errors.New("invalid webhook url: use an absolute http or https url")
+	// ErrNonPublicWebhookURL возникает при попытке создать вебхук, адрес которого ведёт не в интернет, а в локальную
+	// или внутреннюю сеть (например, на localhost или 10.0.0.1).
+	ErrNonPublicWebhookURL = errors.New("invalid webhook url: the destination must be a public internet address")
+	// ErrUnknownWebhookEvent возникает при попытке подписать вебхук на несуществующее событие.
+	ErrUnknownWebhookEvent = errors.New("unknown webhook event")
 )
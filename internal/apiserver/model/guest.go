@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Guest представляет гостя ресторанов. Гости различаются по телефону в формате E.164 (см. NormalizePhone),
+// поэтому брони с одним и тем же телефоном, записанным по-разному, относятся к одному гостю.
+type Guest struct {
+	ID uint64 `json:"id" example:"12"`
+	// Phone представляет телефон гостя в формате E.164.
+	Phone string `json:"phone" example:"+79485722648"`
+	// Name представляет имя, указанное гостем в последней брони.
+	Name string `json:"name" example:"Павел"`
+	// CreatedAt представляет момент первой брони гостя.
+	CreatedAt time.Time `json:"created_at" example:"2022-06-15T12:00:00Z"`
+}
+
+// GuestRestaurantStats представляет статистику посещений гостем одного ресторана.
+type GuestRestaurantStats struct {
+	RestaurantID uint64 `json:"restaurant_id" example:"2"`
+	// BookingsNumber представляет количество всех броней гостя в ресторане (в том числе отменённых и будущих).
+	BookingsNumber int `json:"bookings_number" example:"5"`
+	// VisitsNumber представляет количество броней, по которым гость пришёл (или время которых уже наступило, если
+	// приход гостя не отмечался).
+	VisitsNumber int `json:"visits_number" example:"3"`
+	// CancellationsNumber представляет количество отменённых броней.
+	CancellationsNumber int `json:"cancellations_number" example:"1"`
+	// NoShowsNumber представляет количество броней, по которым гость не пришёл.
+	NoShowsNumber int `json:"no_shows_number" example:"1"`
+	// AveragePartySize представляет среднее количество человек в бронях гостя, кроме отменённых и неявок.
+	AveragePartySize float64 `json:"average_party_size" example:"3.5"`
+	// LastVisitAt представляет дату и время последнего посещения ресторана (nil, если гость ещё не приходил).
+	LastVisitAt *time.Time `json:"last_visit_at,omitempty" example:"2022-06-10T19:00:00Z"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// Hold представляет временное удержание столиков: пока гость заполняет форму брони, подобранные для него столики
+// считаются занятыми и не достаются другим гостям. Удержание либо превращается в бронь, либо снимается по истечении срока.
+type Hold struct {
+	ID uint64 `json:"id" example:"7"`
+	// RestaurantID представляет ID ресторана, в котором удерживаются столики.
+	RestaurantID uint64 `json:"restaurant_id" example:"2"`
+	// PeopleNumber представляет количество человек, для которых удерживаются столики.
+	PeopleNumber int `json:"people_number" example:"4"`
+	// HeldDate представляет дату посещения ресторана.
+	HeldDate ShortFormattedDate `json:"held_date" example:"2022.06.16"`
+	// HeldTimeFrom представляет время начала будущей брони.
+	HeldTimeFrom ShortFormattedTime `json:"held_time_from" example:"14:30"`
+	// HeldTimeTo представляет время конца будущей брони.
+	HeldTimeTo ShortFormattedTime `json:"held_time_to" example:"16:30"`
+	// ExpiresAt представляет момент, после которого столики перестают удерживаться.
+	ExpiresAt time.Time `json:"expires_at" example:"2022-06-15T12:05:00Z"`
+	// TableIDs представляет ID удерживаемых столиков.
+	TableIDs []uint64 `json:"table_ids"`
+	// Token представляет секретный токен удержания: без него удержание нельзя получить, подтвердить или снять.
+	// Токен известен только при создании удержания, а хранится его хеш (TokenHash).
+	Token string `json:"token,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	// TokenHash представляет SHA-256 хеш токена удержания.
+	TokenHash string `json:"-"`
+	// ClientKey представляет ключ клиента, удерживающего столики (например, IP-адрес гостя).
+	ClientKey string `json:"-"`
+}
+
+// IsExpired проверяет, истёк ли срок удержания столиков.
+func (h Hold) IsExpired() bool {
+	return !time.Now().Before(h.ExpiresAt)
+}
+
+// StartsAt возвращает дату и время начала будущей брони.
+func (h Hold) StartsAt() time.Time {
+	date, clock := time.Time(h.HeldDate), time.Time(h.HeldTimeFrom)
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+}
+
+// EndsAt возвращает дату и время конца будущей брони.
+func (h Hold) EndsAt() time.Time {
+	date, clock := time.Time(h.HeldDate), time.Time(h.HeldTimeTo)
+	endsAt := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	// бронь, которая заканчивается после полуночи, заканчивается на следующий день
+	if !endsAt.After(h.StartsAt()) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt
+}
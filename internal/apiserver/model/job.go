@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	// JobKindBookingReminder означает напоминание гостю о предстоящей брони.
+	JobKindBookingReminder = "booking_reminder"
+)
+
+const (
+	// JobStatusPending означает, что задача ждёт выполнения (в том числе повторной попытки после ошибки).
+	JobStatusPending = "pending"
+	// JobStatusDone означает, что задача выполнена.
+	JobStatusDone = "done"
+	// JobStatusFailed означает, что задача так и не выполнилась за все попытки.
+	JobStatusFailed = "failed"
+)
+
+// Job представляет фоновую задачу, связанную с бронью (например, напоминание гостю). Задачи хранятся в БД, поэтому
+// не теряются при перезапуске сервиса.
+type Job struct {
+	ID uint64
+	// Kind представляет вид задачи, по которому выбирается её обработчик (например, JobKindBookingReminder).
+	Kind string
+	// BookingID представляет ID брони, к которой относится задача.
+	BookingID uint64
+	// RunAt представляет момент, начиная с которого задачу можно выполнить (или повторить после ошибки).
+	RunAt time.Time
+	// Status представляет статус задачи: JobStatusPending, JobStatusDone или JobStatusFailed.
+	Status string
+	// Attempts представляет количество начатых попыток выполнить задачу.
+	Attempts int
+	// LastError представляет ошибку последней неудачной попытки (пустая строка - ошибок не было).
+	LastError string
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// OpeningHours представляет смену (интервал работы) ресторана в один из дней недели. В один день у ресторана может
+// быть несколько смен, например, обеденная и вечерняя.
+type OpeningHours struct {
+	// Weekday представляет день недели: 0 - воскресенье, 1 - понедельник, ..., 6 - суббота.
+	Weekday int `json:"weekday" example:"1"`
+	// OpensAt представляет время открытия ресторана.
+	OpensAt ShortFormattedTime `json:"opens_at" example:"09:00"`
+	// ClosesAt представляет время закрытия ресторана. Если оно не позже времени открытия, ресторан закрывается
+	// на следующий день.
+	ClosesAt ShortFormattedTime `json:"closes_at" example:"23:00"`
+	// LastSeating представляет время, на которое можно оформить последнюю бронь в рамках смены (в том числе
+	// после полуночи, если ресторан закрывается на следующий день).
+	LastSeating ShortFormattedTime `json:"last_seating" example:"21:00"`
+}
+
+// Validate проверяет, что смена приходится на существующий день недели, а время последней брони
+// находится между открытием и закрытием ресторана.
+func (h OpeningHours) Validate() error {
+	if h.Weekday < int(time.Sunday) || h.Weekday > int(time.Saturday) {
+		return fmt.Errorf("%w: weekday must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidOpeningHours)
+	}
+	opensAt, closesAt, lastSeating := h.OpensAt.TimeOfDay(), h.ClosesAt.TimeOfDay(), h.LastSeating.TimeOfDay()
+	if h.IsOvernight() {
+		// последняя бронь оформляется либо до полуночи, либо на следующий день до закрытия
+		if lastSeating < opensAt && lastSeating > closesAt {
+			return fmt.Errorf("%w: the last seating must be within opening hours", ErrInvalidOpeningHours)
+		}
+		return nil
+	}
+	if lastSeating < opensAt || lastSeating > closesAt {
+		return fmt.Errorf("%w: the last seating must be within opening hours", ErrInvalidOpeningHours)
+	}
+	return nil
+}
+
+// IsOvernight проверяет, закрывается ли ресторан на следующий день после открытия (например, с 18:00 до 02:00).
+// Смена, время закрытия которой совпадает со временем открытия, длится сутки.
+func (h OpeningHours) IsOvernight() bool {
+	return h.ClosesAt.TimeOfDay() <= h.OpensAt.TimeOfDay()
+}
+
+// AcceptsBookingAt проверяет, можно ли в рамках смены оформить бронь, начинающуюся в момент t. Если ресторан
+// закрывается на следующий день, бронь после полуночи относится к смене предыдущего дня недели.
+func (h OpeningHours) AcceptsBookingAt(t time.Time) bool {
+	clock := TimeOfDay(t)
+	opensAt, lastSeating := h.OpensAt.TimeOfDay(), h.LastSeating.TimeOfDay()
+
+	// время последней брони раньше времени открытия только у смены, которая заканчивается на следующий день
+	lastSeatingNextDay := lastSeating < opensAt
+
+	if int(t.Weekday()) == h.Weekday {
+		return opensAt <= clock && (clock <= lastSeating || lastSeatingNextDay)
+	}
+	nextWeekday := (h.Weekday + 1) % 7
+	return int(t.Weekday()) == nextWeekday && lastSeatingNextDay && clock <= lastSeating
+}
+
+// DefaultOpeningHours возвращает график работы ресторана, у которого не задан собственный график:
+// ежедневно с 9:00 до 23:00, последнюю бронь можно оформить на 21:00.
+func DefaultOpeningHours() []OpeningHours {
+	hours := make([]OpeningHours, 0, 7)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		hours = append(hours, OpeningHours{
+			Weekday:     int(weekday),
+			OpensAt:     NewShortFormattedTime(9, 0),
+			ClosesAt:    NewShortFormattedTime(23, 0),
+			LastSeating: NewShortFormattedTime(21, 0),
+		})
+	}
+	return hours
+}
+
+// SetOpeningHoursData содержит недельный график работы ресторана и используется для его замены.
+type SetOpeningHoursData struct {
+	// OpeningHours представляет смены ресторана. Пустой список означает график работы по умолчанию.
+	OpeningHours []OpeningHours `json:"opening_hours"`
+}
+
+// Bind осуществляет пост-обработку запроса SetOpeningHoursData.
+func (d *SetOpeningHoursData) Bind(_ *http.Request) error {
+	for _, hours := range d.OpeningHours {
+		if err := hours.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// shift возвращает смену в понедельник с opensAt до closesAt и последней бронью на lastSeating (время в формате "15:04").
+func shift(opensAt, closesAt, lastSeating string) OpeningHours {
+	parse := func(value string) ShortFormattedTime {
+		t, err := time.Parse("15:04", value)
+		if err != nil {
+			panic(err)
+		}
+		return ShortFormattedTime(t)
+	}
+	return OpeningHours{
+		Weekday:     int(time.Monday),
+		OpensAt:     parse(opensAt),
+		ClosesAt:    parse(closesAt),
+		LastSeating: parse(lastSeating),
+	}
+}
+
+func TestOpeningHours_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   OpeningHours
+		wantErr bool
+	}{
+		{name: "day", hours: shift("09:00", "23:00", "21:00")},
+		{name: "last seating at opening", hours: shift("09:00", "23:00", "09:00")},
+		{name: "last seating at closing", hours: shift("09:00", "23:00", "23:00")},
+		{name: "last seating before opening", hours: shift("09:00", "23:00", "08:00"), wantErr: true},
+		{name: "last seating after closing", hours: shift("09:00", "23:00", "23:30"), wantErr: true},
+
+		{name: "overnight with last seating before midnight", hours: shift("18:00", "02:00", "23:00")},
+		{name: "overnight with last seating after midnight", hours: shift("18:00", "02:00", "01:00")},
+		{name: "overnight with last seating at midnight", hours: shift("18:00", "02:00", "00:00")},
+		{name: "closes at midnight", hours: shift("18:00", "00:00", "22:00")},
+		{name: "around the clock", hours: shift("09:00", "09:00", "08:00")},
+		{name: "overnight with last seating after closing", hours: shift("18:00", "02:00", "03:00"), wantErr: true},
+		{name: "overnight with last seating before opening", hours: shift("18:00", "02:00", "17:00"), wantErr: true},
+
+		{name: "weekday out of range", hours: OpeningHours{Weekday: 7}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.hours.Validate()
+			if tt.wantErr != errors.Is(err, ErrInvalidOpeningHours) || !tt.wantErr && err != nil {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpeningHours_AcceptsBookingAt(t *testing.T) {
+	// 2026.10.19 - понедельник
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		hours OpeningHours
+		t     time.Time
+		want  bool
+	}{
+		{name: "day shift", hours: shift("09:00", "23:00", "21:00"), t: at(19, 19, 0), want: true},
+		{name: "day shift at opening", hours: shift("09:00", "23:00", "21:00"), t: at(19, 9, 0), want: true},
+		{name: "day shift at last seating", hours: shift("09:00", "23:00", "21:00"), t: at(19, 21, 0), want: true},
+		{name: "day shift before opening", hours: shift("09:00", "23:00", "21:00"), t: at(19, 8, 59)},
+		{name: "day shift after last seating", hours: shift("09:00", "23:00", "21:00"), t: at(19, 21, 1)},
+		{name: "day shift on another day", hours: shift("09:00", "23:00", "21:00"), t: at(20, 19, 0)},
+
+		{name: "overnight before midnight", hours: shift("18:00", "02:00", "01:00"), t: at(19, 23, 30), want: true},
+		{name: "overnight at midnight", hours: shift("18:00", "02:00", "01:00"), t: at(20, 0, 0), want: true},
+		{name: "overnight after midnight", hours: shift("18:00", "02:00", "01:00"), t: at(20, 1, 0), want: true},
+		{name: "overnight after last seating", hours: shift("18:00", "02:00", "01:00"), t: at(20, 1, 30)},
+		{name: "overnight before opening", hours: shift("18:00", "02:00", "01:00"), t: at(19, 17, 0)},
+		{name: "overnight on the morning of the opening day", hours: shift("18:00", "02:00", "01:00"), t: at(19, 0, 30)},
+		{name: "overnight on the next evening", hours: shift("18:00", "02:00", "01:00"), t: at(20, 18, 0)},
+		{name: "overnight with last seating before midnight", hours: shift("18:00", "02:00", "23:00"), t: at(19, 23, 30)},
+		{name: "overnight with last seating before midnight after it", hours: shift("18:00", "02:00", "23:00"), t: at(20, 0, 30)},
+
+		// смена с субботы на воскресенье
+		{
+			name:  "overnight from saturday to sunday",
+			hours: OpeningHours{Weekday: int(time.Saturday), OpensAt: NewShortFormattedTime(18, 0), ClosesAt: NewShortFormattedTime(2, 0), LastSeating: NewShortFormattedTime(1, 0)},
+			t:     at(25, 0, 30),
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.AcceptsBookingAt(tt.t); got != tt.want {
+				t.Errorf("AcceptsBookingAt(%s) = %v, want %v", tt.t.Format("Mon 15:04"), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package model
+
+import "strings"
+
+const (
+	// russiaCountryCode представляет код страны России (и Казахстана) в формате E.164.
+	russiaCountryCode = "7"
+	// russiaPhoneLength представляет количество цифр в российском телефоне вместе с кодом страны.
+	russiaPhoneLength = 11
+	// minPhoneLength и maxPhoneLength представляют минимальное и максимальное количество цифр в телефоне
+	// вместе с кодом страны (E.164 допускает не больше 15 цифр).
+	minPhoneLength = 7
+	maxPhoneLength = 15
+)
+
+// phoneSeparators содержит символы, которыми гости разделяют цифры телефона (в том числе неразрывный пробел):
+// они отбрасываются при нормализации.
+const phoneSeparators = " \t-().\u00a0"
+
+// NormalizePhone приводит телефон к формату E.164 (+79279007265), в котором телефоны хранятся в БД.
+//
+// Международный номер записывается с кодом страны через "+" или "00" (+44 20 7946 0958, 0044 20 7946 0958).
+// Российский номер можно записать и без кода страны: через 8 (8 927 900-72-65), через 7 (79279007265) или только
+// десятью цифрами мобильного номера (927 900 72 65). Цифры можно разделять пробелами, дефисами, точками и скобками.
+// Если телефон нельзя привести к формату E.164, возвращается ErrInvalidPhone.
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+
+	international := false
+	if strings.HasPrefix(phone, "+") {
+		international = true
+		phone = phone[1:]
+	}
+
+	var digits strings.Builder
+	for _, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(phoneSeparators, r):
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	if !international {
+		switch {
+		case strings.HasPrefix(number, "00"):
+			// международный номер, набранный через префикс выхода на международную линию
+			number = number[2:]
+		case len(number) == russiaPhoneLength && (number[0] == '8' || number[0] == '7'):
+			number = russiaCountryCode + number[1:]
+		case len(number) == russiaPhoneLength-1 && number[0] == '9':
+			// российский мобильный номер без кода страны
+			number = russiaCountryCode + number
+		default:
+			// без кода страны нельзя понять, в какой стране зарегистрирован номер
+			return "", ErrInvalidPhone
+		}
+	}
+
+	// код страны не начинается с 0, а российские номера всегда состоят из 11 цифр
+	if len(number) < minPhoneLength || len(number) > maxPhoneLength || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	if strings.HasPrefix(number, russiaCountryCode) && len(number) != russiaPhoneLength {
+		return "", ErrInvalidPhone
+	}
+	return "+" + number, nil
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		// want - телефон в формате E.164 (пустая строка - телефон должен быть отклонён)
+		want string
+	}{
+		// российские номера: через 8, через 7, через +7 и без кода страны
+		{name: "8 prefix", phone: "89279007265", want: "+79279007265"},
+		{name: "7 prefix", phone: "79279007265", want: "+79279007265"},
+		{name: "+7 prefix", phone: "+79279007265", want: "+79279007265"},
+		{name: "mobile without country code", phone: "9279007265", want: "+79279007265"},
+		{name: "landline with 8 prefix", phone: "84951234567", want: "+74951234567"},
+		{name: "kazakhstan", phone: "+7 701 234 56 78", want: "+77012345678"},
+
+		// разделители цифр
+		{name: "spaces", phone: "8 927 900 72 65", want: "+79279007265"},
+		{name: "dashes", phone: "8-927-900-72-65", want: "+79279007265"},
+		{name: "brackets and dashes", phone: "8 (927) 900-72-65", want: "+79279007265"},
+		{name: "dots", phone: "+7.927.900.72.65", want: "+79279007265"},
+		{name: "tab and non-breaking space", phone: "+7\t927 900 7265", want: "+79279007265"},
+		{name: "surrounding spaces", phone: "  +7 927 900-72-65\n", want: "+79279007265"},
+
+		// иностранные номера в формате E.164 и через префикс 00
+		{name: "uk", phone: "+44 20 7946 0958", want: "+442079460958"},
+		{name: "uk via 00", phone: "0044 20 7946 0958", want: "+442079460958"},
+		{name: "usa", phone: "+1 (202) 555-0143", want: "+12025550143"},
+		{name: "germany", phone: "+49 30 901820", want: "+4930901820"},
+		{name: "shortest", phone: "+2901234", want: "+2901234"},
+		{name: "longest", phone: "+123456789012345", want: "+123456789012345"},
+
+		// некорректные телефоны
+		{name: "empty", phone: ""},
+		{name: "only spaces", phone: "   "},
+		{name: "only plus", phone: "+"},
+		{name: "letters", phone: "8 927 900 72 6five"},
+		{name: "extension", phone: "+7 927 900-72-65 ext. 1"},
+		{name: "plus in the middle", phone: "8+9279007265"},
+		{name: "double plus", phone: "++79279007265"},
+		{name: "slash", phone: "8/927/9007265"},
+		{name: "unicode digits", phone: "８９２７９００７２６５"},
+		{name: "sql injection", phone: "89279007265' OR '1'='1"},
+		{name: "8 prefix too short", phone: "8927900726"},
+		{name: "8 prefix too long", phone: "892790072650"},
+		{name: "+7 too short", phone: "+7927900726"},
+		{name: "+7 too long", phone: "+792790072650"},
+		{name: "10 digits not mobile", phone: "4951234567"},
+		{name: "foreign without plus", phone: "442079460958"},
+		{name: "too short", phone: "+290123"},
+		{name: "too long", phone: "+1234567890123456"},
+		{name: "country code starts with 0", phone: "+0123456789"},
+		{name: "000 prefix", phone: "000442079460958"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.phone)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidPhone) {
+					t.Errorf("NormalizePhone(%q) = %q, %v; want ErrInvalidPhone", tt.phone, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizePhone(%q) = %q, %v; want %q", tt.phone, got, err, tt.want)
+			}
+
+			// нормализованный телефон не меняется при повторной нормализации
+			if again, err := NormalizePhone(got); err != nil || again != got {
+				t.Errorf("NormalizePhone(%q) = %q, %v; want it unchanged", got, again, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MaxBlockDays представляет максимально допустимый срок блокировки онлайн-бронирования в днях.
+const MaxBlockDays = 365
+
+// ReliabilityPolicy представляет правила, по которым ресторан ограничивает онлайн-бронирование гостям, не приходящим
+// по своим броням. Неявки гостя считаются по его телефону в формате E.164 только в этом ресторане. Нулевые значения
+// отключают соответствующие ограничения, поэтому ресторан без собственных правил принимает брони от всех гостей.
+type ReliabilityPolicy struct {
+	// ConfirmationAfter представляет количество неявок, начиная с которого брони гостя ждут подтверждения рестораном
+	// (0 - подтверждение не требуется).
+	ConfirmationAfter int `json:"confirmation_after" example:"1"`
+	// BlockAfter представляет количество неявок, начиная с которого гостю блокируется онлайн-бронирование
+	// (0 - бронирование не блокируется).
+	BlockAfter int `json:"block_after" example:"3"`
+	// BlockDays представляет количество дней после последней неявки, на которое блокируется онлайн-бронирование.
+	BlockDays int `json:"block_days" example:"30"`
+}
+
+// RequiresConfirmation проверяет, должны ли брони гостя с noShows неявками ждать подтверждения рестораном.
+func (p ReliabilityPolicy) RequiresConfirmation(noShows int) bool {
+	return p.ConfirmationAfter > 0 && noShows >= p.ConfirmationAfter
+}
+
+// BlockedUntil возвращает момент, до которого гостю с noShows неявками, последняя из которых была в lastNoShowAt,
+// заблокировано онлайн-бронирование. Если бронирование не блокируется, возвращает false.
+func (p ReliabilityPolicy) BlockedUntil(noShows int, lastNoShowAt time.Time) (time.Time, bool) {
+	if p.BlockAfter == 0 || noShows < p.BlockAfter {
+		return time.Time{}, false
+	}
+	return lastNoShowAt.AddDate(0, 0, p.BlockDays), true
+}
+
+// Validate проверяет, что количества неявок не отрицательны, а срок блокировки задан, если бронирование блокируется.
+func (p ReliabilityPolicy) Validate() error {
+	if p.ConfirmationAfter < 0 || p.BlockAfter < 0 {
+		return fmt.Errorf("%w: the number of no-shows cannot be negative", ErrInvalidReliabilityPolicy)
+	}
+	if p.BlockAfter == 0 {
+		if p.BlockDays != 0 {
+			return fmt.Errorf("%w: the block period requires the number of no-shows to block after", ErrInvalidReliabilityPolicy)
+		}
+		return nil
+	}
+	if p.BlockDays < 1 || p.BlockDays > MaxBlockDays {
+		return fmt.Errorf("%w: the block period must be between 1 and %d days", ErrInvalidReliabilityPolicy, MaxBlockDays)
+	}
+	return nil
+}
+
+// Bind осуществляет пост-обработку запроса ReliabilityPolicy.
+func (p *ReliabilityPolicy) Bind(_ *http.Request) error {
+	return p.Validate()
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReliabilityPolicy(t *testing.T) {
+	policy := ReliabilityPolicy{ConfirmationAfter: 1, BlockAfter: 3, BlockDays: 30}
+	lastNoShowAt := time.Date(2022, time.June, 15, 21, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		noShows          int
+		wantConfirmation bool
+		wantBlocked      bool
+	}{
+		{noShows: 0},
+		{noShows: 1, wantConfirmation: true},
+		{noShows: 2, wantConfirmation: true},
+		{noShows: 3, wantConfirmation: true, wantBlocked: true},
+	}
+	for _, tt := range tests {
+		if got := policy.RequiresConfirmation(tt.noShows); got != tt.wantConfirmation {
+			t.Errorf("RequiresConfirmation(%d) = %t, want %t", tt.noShows, got, tt.wantConfirmation)
+		}
+		until, blocked := policy.BlockedUntil(tt.noShows, lastNoShowAt)
+		if blocked != tt.wantBlocked {
+			t.Errorf("BlockedUntil(%d) blocked = %t, want %t", tt.noShows, blocked, tt.wantBlocked)
+		}
+		if want := lastNoShowAt.AddDate(0, 0, 30); blocked && !until.Equal(want) {
+			t.Errorf("BlockedUntil(%d) = %s, want %s", tt.noShows, until, want)
+		}
+	}
+
+	// нулевые правила ничего не ограничивают
+	if (ReliabilityPolicy{}).RequiresConfirmation(10) {
+		t.Error("zero policy requires confirmation")
+	}
+	if _, blocked := (ReliabilityPolicy{}).BlockedUntil(10, lastNoShowAt); blocked {
+		t.Error("zero policy blocks online booking")
+	}
+}
+
+func TestReliabilityPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ReliabilityPolicy
+		wantErr bool
+	}{
+		{name: "zero", policy: ReliabilityPolicy{}},
+		{name: "confirmation only", policy: ReliabilityPolicy{ConfirmationAfter: 1}},
+		{name: "block", policy: ReliabilityPolicy{BlockAfter: 3, BlockDays: MaxBlockDays}},
+		{name: "negative confirmation", policy: ReliabilityPolicy{ConfirmationAfter: -1}, wantErr: true},
+		{name: "negative block", policy: ReliabilityPolicy{BlockAfter: -1}, wantErr: true},
+		{name: "block days without block", policy: ReliabilityPolicy{BlockDays: 30}, wantErr: true},
+		{name: "block without days", policy: ReliabilityPolicy{BlockAfter: 3}, wantErr: true},
+		{name: "too long block", policy: ReliabilityPolicy{BlockAfter: 3, BlockDays: MaxBlockDays + 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidReliabilityPolicy) {
+				t.Errorf("Validate() = %v, want ErrInvalidReliabilityPolicy", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() = %v, want no error", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,23 @@
 package model
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// AllocationStrategyBestFit представляет стратегию выбора столиков, при которой компания садится за как можно
+	// меньшее количество столиков, а свободными за ними остаётся как можно меньше мест.
+	AllocationStrategyBestFit = "best_fit"
+	// AllocationStrategySmallestFirst представляет стратегию выбора столиков, при которой столики занимаются
+	// по возрастанию количества мест, пока за ними не поместится вся компания.
+	AllocationStrategySmallestFirst = "smallest_first"
+)
+
+// IsAllocationStrategy проверяет, поддерживается ли стратегия выбора столиков с названием strategy.
+func IsAllocationStrategy(strategy string) bool {
+	return strategy == AllocationStrategyBestFit || strategy == AllocationStrategySmallestFirst
+}
 
 // Restaurant представляет ресторан.
 type Restaurant struct {
@@ -12,6 +29,29 @@ type Restaurant struct {
 	AverageCheck float64 `json:"average_check" example:"2500.00"`
 	// AvailableSeatsNumber представляет актуальное количество свободных мест.
 	AvailableSeatsNumber int `json:"available_seats_number,omitempty" example:"24"`
+	// AvailableZones представляет актуальное количество свободных мест по зонам ресторана.
+	AvailableZones []ZoneAvailability `json:"available_zones,omitempty"`
+	// OpeningHours представляет недельный график работы ресторана. Если он не задан, ресторан работает
+	// по графику DefaultOpeningHours.
+	OpeningHours []OpeningHours `json:"opening_hours,omitempty"`
+	// DurationPolicy представляет правила, по которым определяется длительность брони в ресторане.
+	DurationPolicy DurationPolicy `json:"duration_policy"`
+	// AllocationStrategy представляет стратегию, по которой для брони выбираются столики ресторана.
+	AllocationStrategy string `json:"allocation_strategy" example:"best_fit"`
+}
+
+// AcceptsBookingAt проверяет, можно ли оформить в ресторане бронь, начинающуюся в момент t.
+func (r Restaurant) AcceptsBookingAt(t time.Time) bool {
+	hours := r.OpeningHours
+	if len(hours) == 0 {
+		hours = DefaultOpeningHours()
+	}
+	for _, shift := range hours {
+		if shift.AcceptsBookingAt(t) {
+			return true
+		}
+	}
+	return false
 }
 
 // UpdateRestaurantData содержит информацию о ресторане и используется для обновления записи о нём в БД.
@@ -19,12 +59,17 @@ type UpdateRestaurantData struct {
 	Name               *string  `json:"name" example:"Каравелла"`
 	AverageWaitingTime *int     `json:"average_waiting_time,string" example:"60"`
 	AverageCheck       *float64 `json:"average_check,string" example:"2500.00"`
+	// AllocationStrategy представляет стратегию выбора столиков: best_fit или smallest_first.
+	AllocationStrategy *string `json:"allocation_strategy" example:"best_fit"`
 }
 
 // Bind осуществляет пост-обработку запроса UpdateRestaurantData.
 func (d *UpdateRestaurantData) Bind(_ *http.Request) error {
-	if d.Name == nil && d.AverageWaitingTime == nil && d.AverageCheck == nil {
+	if d.Name == nil && d.AverageWaitingTime == nil && d.AverageCheck == nil && d.AllocationStrategy == nil {
 		return ErrUpdateRestaurantData
 	}
+	if d.AllocationStrategy != nil && !IsAllocationStrategy(*d.AllocationStrategy) {
+		return ErrUnknownAllocationStrategy
+	}
 	return nil
 }
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	// RestaurantEventBookingCreated означает, что в ресторане оформлена бронь (в том числе по удержанию и из листа
+	// ожидания).
+	RestaurantEventBookingCreated = "booking.created"
+	// RestaurantEventBookingUpdated означает, что бронь в ресторане изменена: перенесена на другое время или
+	// изменилось количество гостей (а вместе с ним могли измениться и столики брони).
+	RestaurantEventBookingUpdated = "booking.updated"
+	// RestaurantEventBookingCancelled означает, что бронь в ресторане отменена.
+	RestaurantEventBookingCancelled = "booking.cancelled"
+	// RestaurantEventTableChanged означает, что столик ресторана добавлен, изменён, удалён или изменилось, с какими
+	// столиками его можно сдвинуть.
+	RestaurantEventTableChanged = "table.changed"
+)
+
+// RestaurantEvent представляет событие ресторана, которое в реальном времени получают хосты ресторана, чтобы
+// не обновлять список броней и столиков вручную.
+type RestaurantEvent struct {
+	// Event представляет событие: RestaurantEventBookingCreated, RestaurantEventBookingUpdated,
+	// RestaurantEventBookingCancelled или RestaurantEventTableChanged.
+	Event string `json:"event" example:"booking.created"`
+	// RestaurantID представляет ID ресторана, в котором произошло событие.
+	RestaurantID uint64 `json:"restaurant_id" example:"2"`
+	// OccurredAt представляет момент события.
+	OccurredAt time.Time `json:"occurred_at" example:"2022-06-15T12:00:00Z"`
+	// Booking представляет бронь сразу после события (только для событий с бронями).
+	Booking *Booking `json:"booking,omitempty"`
+	// TableID представляет ID столика (только для RestaurantEventTableChanged).
+	TableID uint64 `json:"table_id,omitempty" example:"3"`
+	// Table представляет столик сразу после события (nil, если столик удалён).
+	Table *Table `json:"table,omitempty"`
+}
@@ -8,17 +8,42 @@ type Table struct {
 	RestaurantID uint64 `json:"restaurant_id" example:"2"`
 	// SeatsNumber представляет вместимость столика.
 	SeatsNumber int `json:"seats_number" example:"4"`
+	// Position представляет расположение столика в зале (например, номер на схеме зала).
+	Position string `json:"position" example:"у окна, №3"`
+	// Zone представляет зону ресторана, в которой стоит столик: hall, terrace, vip или non_smoking.
+	Zone string `json:"zone" example:"terrace"`
+	// JoinableWith представляет ID столиков, которые можно сдвинуть с этим столиком, чтобы посадить за них
+	// одну компанию.
+	JoinableWith []uint64 `json:"joinable_with"`
+}
+
+// IsJoinableWith проверяет, можно ли сдвинуть столик со столиком с ID tableID.
+func (t Table) IsJoinableWith(tableID uint64) bool {
+	for _, id := range t.JoinableWith {
+		if id == tableID {
+			return true
+		}
+	}
+	return false
 }
 
 // UpdateTableData содержит информацию о столике в ресторане и используется для обновления записи о нём в БД.
 type UpdateTableData struct {
-	SeatsNumber *int `json:"seats_number" example:"4"`
+	SeatsNumber *int    `json:"seats_number" example:"4"`
+	Position    *string `json:"position" example:"у окна, №3"`
+	Zone        *string `json:"zone" example:"terrace"`
 }
 
 // Bind осуществляет пост-обработку запроса UpdateTableData.
 func (d *UpdateTableData) Bind(_ *http.Request) error {
-	if d.SeatsNumber == nil {
+	if d.SeatsNumber == nil && d.Position == nil && d.Zone == nil {
 		return ErrUpdateTableData
 	}
+	if d.SeatsNumber != nil && *d.SeatsNumber <= 0 {
+		return ErrInvalidSeatsNumber
+	}
+	if d.Zone != nil && !IsZone(*d.Zone) {
+		return ErrUnknownZone
+	}
 	return nil
 }
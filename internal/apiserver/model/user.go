@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+const (
+	// UserRoleAdmin представляет администратора платформы: ему доступны все рестораны и управление пользователями.
+	UserRoleAdmin = "admin"
+	// UserRoleManager представляет менеджера, который управляет только закреплёнными за ним ресторанами.
+	UserRoleManager = "manager"
+	// UserRoleStaff представляет сотрудника, который может только просматривать закреплённые за ним рестораны.
+	UserRoleStaff = "staff"
+)
+
+// User представляет пользователя API администрирования ресторанов.
+type User struct {
+	ID   uint64 `json:"id" example:"3"`
+	Name string `json:"name" example:"Анна"`
+	// Role представляет роль пользователя: admin, manager или staff.
+	Role string `json:"role" example:"manager"`
+	// RestaurantIDs представляет ID ресторанов, закреплённых за менеджером или сотрудником.
+	RestaurantIDs []uint64 `json:"restaurant_ids"`
+	// CreatedAt представляет момент создания пользователя.
+	CreatedAt time.Time `json:"created_at" example:"2022-06-15T12:00:00Z"`
+}
+
+// IsUserRole проверяет, существует ли роль пользователя role.
+func IsUserRole(role string) bool {
+	return role == UserRoleAdmin || role == UserRoleManager || role == UserRoleStaff
+}
+
+// IsAdmin проверяет, является ли пользователь администратором платформы.
+func (u User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// CanRead проверяет, может ли пользователь просматривать ресторан restaurantID (его столики, брони и т.д.).
+func (u User) CanRead(restaurantID uint64) bool {
+	return u.IsAdmin() || u.hasRestaurant(restaurantID)
+}
+
+// CanManage проверяет, может ли пользователь изменять ресторан restaurantID (его столики, брони и т.д.).
+func (u User) CanManage(restaurantID uint64) bool {
+	return u.IsAdmin() || (u.Role == UserRoleManager && u.hasRestaurant(restaurantID))
+}
+
+// hasRestaurant проверяет, закреплён ли ресторан restaurantID за пользователем.
+func (u User) hasRestaurant(restaurantID uint64) bool {
+	for _, id := range u.RestaurantIDs {
+		if id == restaurantID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// WaitlistStatusWaiting означает, что гость ждёт, когда в ресторане освободятся места.
+	WaitlistStatusWaiting = "waiting"
+	// WaitlistStatusOffered означает, что гостю предложены освободившиеся места и он может оформить бронь,
+	// пока не истёк срок предложения.
+	WaitlistStatusOffered = "offered"
+	// WaitlistStatusBooked означает, что гость принял предложение и по нему оформлена бронь.
+	WaitlistStatusBooked = "booked"
+	// WaitlistStatusExpired означает, что гость не принял предложение вовремя или желаемое время посещения уже прошло.
+	WaitlistStatusExpired = "expired"
+	// WaitlistStatusCancelled означает, что гость покинул лист ожидания.
+	WaitlistStatusCancelled = "cancelled"
+)
+
+// WaitlistEntry представляет запись в листе ожидания ресторана.
+type WaitlistEntry struct {
+	ID uint64 `json:"id" example:"5"`
+	// RestaurantID представляет ID ресторана, в листе ожидания которого стоит гость.
+	RestaurantID uint64 `json:"restaurant_id" example:"2"`
+	// ClientName представляет имя гостя.
+	ClientName string `json:"client_name" example:"Павел"`
+	// ClientPhone представляет телефон гостя.
+	ClientPhone string `json:"client_phone" example:"+79485722648"`
+	// PeopleNumber представляет количество человек в компании.
+	PeopleNumber int `json:"people_number" example:"4"`
+	// DesiredDate представляет желаемую дату посещения ресторана.
+	DesiredDate ShortFormattedDate `json:"desired_date" example:"2022.06.16"`
+	// DesiredTime представляет желаемое время посещения ресторана.
+	DesiredTime ShortFormattedTime `json:"desired_time" example:"19:00"`
+	// Status представляет состояние записи: waiting, offered, booked, expired или cancelled.
+	Status string `json:"status" example:"offered"`
+	// CreatedAt представляет момент, когда гость встал в лист ожидания.
+	CreatedAt time.Time `json:"created_at" example:"2022-06-15T12:00:00Z"`
+	// OfferExpiresAt представляет момент, до которого гость может принять предложение (nil, если мест не предлагали).
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty" example:"2022-06-15T12:15:00Z"`
+	// HoldID представляет ID удержания столиков, предложенных гостю (nil, если мест не предлагали).
+	HoldID *uint64 `json:"hold_id,omitempty" example:"7"`
+	// BookingID представляет ID брони, оформленной по предложению (nil, если бронь не оформлена).
+	BookingID *uint64 `json:"booking_id,omitempty" example:"12"`
+}
+
+// DesiredAt возвращает желаемые дату и время посещения ресторана.
+func (e WaitlistEntry) DesiredAt() time.Time {
+	date, clock := time.Time(e.DesiredDate), time.Time(e.DesiredTime)
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+}
+
+// IsActive проверяет, ждёт ли гость мест или предложения по-прежнему.
+func (e WaitlistEntry) IsActive() bool {
+	return e.Status == WaitlistStatusWaiting || e.Status == WaitlistStatusOffered
+}
+
+// BookingDetails возвращает данные для оформления брони по записи в листе ожидания.
+func (e WaitlistEntry) BookingDetails() BookingDetails {
+	return BookingDetails{
+		RestaurantID:    e.RestaurantID,
+		PeopleNumber:    strconv.Itoa(e.PeopleNumber),
+		DesiredDatetime: e.DesiredAt().Format("2006.01.02 15:04"),
+		ClientName:      e.ClientName,
+		ClientPhone:     e.ClientPhone,
+	}
+}
+
+// StatusTitle возвращает описание состояния записи для отображения на сайте.
+func (e WaitlistEntry) StatusTitle() string {
+	switch e.Status {
+	case WaitlistStatusWaiting:
+		return "Ожидаем, когда освободятся места"
+	case WaitlistStatusOffered:
+		return "Места освободились – успейте оформить бронь"
+	case WaitlistStatusBooked:
+		return "Бронь оформлена"
+	case WaitlistStatusExpired:
+		return "Предложение больше не действует"
+	case WaitlistStatusCancelled:
+		return "Вы покинули лист ожидания"
+	}
+	return e.Status
+}
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookEventBookingCreated означает, что бронь оформлена (в том числе по удержанию и из листа ожидания).
+	WebhookEventBookingCreated = "booking.created"
+	// WebhookEventBookingUpdated означает, что изменились количество человек, дата, время или столики брони.
+	WebhookEventBookingUpdated = "booking.updated"
+	// WebhookEventBookingStatusChanged означает, что бронь подтверждена, гости пришли, ушли или не пришли.
+	WebhookEventBookingStatusChanged = "booking.status_changed"
+	// WebhookEventBookingCancelled означает, что бронь отменена.
+	WebhookEventBookingCancelled = "booking.cancelled"
+)
+
+// WebhookEvents возвращает все события с бронями, на которые можно подписать вебхук.
+func WebhookEvents() []string {
+	return []string{
+		WebhookEventBookingCreated,
+		WebhookEventBookingUpdated,
+		WebhookEventBookingStatusChanged,
+		WebhookEventBookingCancelled,
+	}
+}
+
+// IsWebhookEvent проверяет, существует ли событие event.
+func IsWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// WebhookDeliveryPending означает, что событие ждёт доставки (в том числе повторной попытки после ошибки).
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliveryDelivered означает, что получатель принял событие (ответил кодом 2xx).
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryFailed означает, что событие так и не доставлено за все попытки.
+	WebhookDeliveryFailed = "failed"
+)
+
+// Webhook представляет адрес, на который сервис отправляет события с бронями ресторана (например, в кассовую
+// систему или CRM ресторана).
+type Webhook struct {
+	ID uint64 `json:"id" example:"1"`
+	// RestaurantID представляет ID ресторана, о бронях которого сообщает вебхук.
+	RestaurantID uint64 `json:"restaurant_id" example:"2"`
+	// URL представляет адрес, на который POST-запросом отправляются события.
+	URL string `json:"url" example:"https://crm.example.com/hooks/bookings"`
+	// Events представляет события, на которые подписан вебхук (пустой список - все события).
+	Events []string `json:"events" example:"booking.created,booking.cancelled"`
+	// Secret представляет ключ, которым подписываются события вебхука. Показывается только при создании вебхука.
+	Secret string `json:"-"`
+	// CreatedAt представляет момент создания вебхука.
+	CreatedAt time.Time `json:"created_at" example:"2022-06-15T12:00:00Z"`
+}
+
+// Subscribed проверяет, подписан ли вебхук на событие event.
+func (w Webhook) Subscribed(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookData содержит адрес и события нового вебхука и используется для его создания.
+type CreateWebhookData struct {
+	// URL представляет адрес, на который отправляются события (http или https).
+	URL string `json:"url" example:"https://crm.example.com/hooks/bookings"`
+	// Events представляет события, на которые подписывается вебхук (пустой список - все события).
+	Events []string `json:"events" example:"booking.created,booking.cancelled"`
+}
+
+// Bind осуществляет пост-обработку запроса CreateWebhookData.
+func (d *CreateWebhookData) Bind(_ *http.Request) error {
+	return d.Validate()
+}
+
+// Validate проверяет адрес и события вебхука. Адрес должен вести в интернет: IP-адреса локальной и внутренних сетей
+// (см. IsPublicIP) и имена вроде localhost отклоняются. Имя хоста может указывать и на внутренний адрес, поэтому
+// адрес получателя проверяется ещё раз при отправке событий.
+func (d CreateWebhookData) Validate() error {
+	u, err := url.Parse(d.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if !isPublicHost(u.Hostname()) {
+		return ErrNonPublicWebhookURL
+	}
+	for _, event := range d.Events {
+		if !IsWebhookEvent(event) {
+			return ErrUnknownWebhookEvent
+		}
+	}
+	return nil
+}
+
+// nonPublicHostSuffixes содержит окончания имён хостов, которые разрешаются только в локальной или внутренней сети.
+var nonPublicHostSuffixes = []string{".localhost", ".local", ".internal", ".home.arpa"}
+
+// isPublicHost проверяет, может ли хост host из адреса вебхука вести в интернет.
+func isPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicIP(ip)
+	}
+
+	if host == "" || host == "localhost" || strings.ContainsAny(host, ":%") {
+		return false
+	}
+	for _, suffix := range nonPublicHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return false
+		}
+	}
+
+	// домены верхнего уровня не начинаются с цифры, а такие хосты, как 127.1, 2130706433 или 0x7f.1, система
+	// разрешает в IP-адреса в обход net.ParseIP
+	tld := host[strings.LastIndex(host, ".")+1:]
+	return tld != "" && (tld[0] < '0' || tld[0] > '9')
+}
+
+// nonPublicNetworks содержит сети, которые не ведут в интернет, помимо проверяемых методами net.IP (локальная сеть
+// по RFC 1918, loopback, link-local и т.д.).
+var nonPublicNetworks = parseNetworks(
+	"0.0.0.0/8",       // текущая сеть
+	"100.64.0.0/10",   // сеть провайдера с NAT (RFC 6598)
+	"192.0.0.0/24",    // служебные адреса IETF
+	"192.0.2.0/24",    // документация (TEST-NET-1)
+	"198.18.0.0/15",   // тестирование производительности сетей
+	"198.51.100.0/24", // документация (TEST-NET-2)
+	"203.0.113.0/24",  // документация (TEST-NET-3)
+	"240.0.0.0/4",     // зарезервированные адреса и широковещательный адрес
+	"64:ff9b::/96",    // NAT64: за адресом может скрываться IPv4-адрес внутренней сети
+	"64:ff9b:1::/48",  // локальный NAT64
+	"100::/64",        // адреса для отбрасывания трафика
+	"2001:db8::/32",   // документация
+	"2002::/16",       // 6to4: за адресом может скрываться IPv4-адрес внутренней сети
+)
+
+// parseNetworks разбирает сети в формате CIDR.
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// IsPublicIP проверяет, ведёт ли IP-адрес ip в интернет: адреса loopback, локальных сетей (RFC 1918 и уникальные
+// локальные IPv6-адреса), link-local, multicast и другие служебные адреса считаются непубличными. На такие адреса
+// события вебхуков не отправляются, чтобы через вебхук нельзя было обратиться к сервисам внутренней сети.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// BookingEvent представляет событие с бронью, которое отправляется вебхукам ресторана. Событие сохраняется в той же
+// транзакции, что и изменение брони, поэтому не теряется, даже если сервис остановится сразу после изменения.
+type BookingEvent struct {
+	// ID представляет ID события: при повторных попытках доставки он не меняется, поэтому по нему получатель может
+	// отбросить повторы.
+	ID uint64 `json:"id" example:"42"`
+	// Event представляет событие: WebhookEventBookingCreated, WebhookEventBookingUpdated,
+	// WebhookEventBookingStatusChanged или WebhookEventBookingCancelled.
+	Event string `json:"event" example:"booking.created"`
+	// RestaurantID представляет ID ресторана, в котором оформлена бронь.
+	RestaurantID uint64 `json:"restaurant_id" example:"2"`
+	// OccurredAt представляет момент события.
+	OccurredAt time.Time `json:"occurred_at" example:"2022-06-15T12:00:00Z"`
+	// Booking представляет бронь сразу после события.
+	Booking Booking `json:"booking"`
+}
+
+// WebhookDelivery представляет доставку события вебхуку: её журнал показывает, доставлено ли событие, сколько было
+// попыток и чем закончилась последняя из них.
+type WebhookDelivery struct {
+	ID uint64 `json:"id" example:"7"`
+	// WebhookID представляет ID вебхука, которому доставляется событие.
+	WebhookID uint64 `json:"webhook_id" example:"1"`
+	// EventID и Event представляют ID и вид доставляемого события.
+	EventID uint64 `json:"event_id" example:"42"`
+	Event   string `json:"event" example:"booking.created"`
+	// Payload представляет тело запроса с событием (model.BookingEvent в формате JSON).
+	Payload json.RawMessage `json:"-"`
+	// Status представляет статус доставки: WebhookDeliveryPending, WebhookDeliveryDelivered или WebhookDeliveryFailed.
+	Status string `json:"status" example:"delivered"`
+	// Attempts представляет количество начатых попыток доставки.
+	Attempts int `json:"attempts" example:"1"`
+	// LastStatusCode представляет код ответа получателя на последнюю попытку (0 - ответа не было).
+	LastStatusCode int `json:"last_status_code,omitempty" example:"200"`
+	// LastError представляет ошибку последней неудачной попытки (пустая строка - ошибок не было).
+	LastError string `json:"last_error,omitempty" example:""`
+	// NextAttemptAt представляет момент, начиная с которого событие можно доставить (или повторить попытку).
+	NextAttemptAt time.Time `json:"next_attempt_at" example:"2022-06-15T12:00:00Z"`
+	// CreatedAt представляет момент, когда доставка была запланирована.
+	CreatedAt time.Time `json:"created_at" example:"2022-06-15T12:00:00Z"`
+	// DeliveredAt представляет момент, когда получатель принял событие (nil, если ещё не принял).
+	DeliveredAt *time.Time `json:"delivered_at,omitempty" example:"2022-06-15T12:00:01Z"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestCreateWebhookData_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "https", url: "https://crm.example.com/hooks/bookings"},
+		{name: "http with port", url: "http://crm.example.com:8080/hooks"},
+		{name: "public ipv4", url: "https://93.184.216.34/hooks"},
+		{name: "public ipv6", url: "https://[2606:2800:220:1:248:1893:25c8:1946]/hooks"},
+		{name: "trailing dot", url: "https://crm.example.com./hooks"},
+
+		{name: "no scheme", url: "crm.example.com/hooks", wantErr: ErrInvalidWebhookURL},
+		{name: "ftp", url: "ftp://crm.example.com/hooks", wantErr: ErrInvalidWebhookURL},
+		{name: "no host", url: "https:///hooks", wantErr: ErrInvalidWebhookURL},
+
+		{name: "localhost", url: "http://localhost:8080/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "localhost in upper case", url: "http://LOCALHOST./hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "localhost subdomain", url: "http://api.localhost/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "mdns", url: "http://printer.local/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "internal domain", url: "http://metadata.google.internal/computeMetadata/v1/", wantErr: ErrNonPublicWebhookURL},
+		{name: "loopback", url: "http://127.0.0.1/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "loopback ipv6", url: "http://[::1]/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "unspecified", url: "http://0.0.0.0/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "rfc1918 10/8", url: "http://10.0.0.1/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "rfc1918 172.16/12", url: "http://172.16.5.4/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "rfc1918 192.168/16", url: "http://192.168.1.1/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "link-local metadata", url: "http://169.254.169.254/latest/meta-data/", wantErr: ErrNonPublicWebhookURL},
+		{name: "link-local ipv6", url: "http://[fe80::1]/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "link-local ipv6 with zone", url: "http://[fe80::1%25eth0]/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "unique local ipv6", url: "http://[fd00::1]/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "ipv4-mapped loopback", url: "http://[::ffff:127.0.0.1]/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "carrier-grade nat", url: "http://100.64.0.1/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "decimal ip", url: "http://2130706433/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "short ip", url: "http://127.1/hooks", wantErr: ErrNonPublicWebhookURL},
+		{name: "hex ip", url: "http://0x7f.0.0.1/hooks", wantErr: ErrNonPublicWebhookURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CreateWebhookData{URL: tt.url}.Validate()
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: true},
+		{ip: "8.8.8.8", want: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{ip: "127.0.0.1"},
+		{ip: "127.255.255.254"},
+		{ip: "::1"},
+		{ip: "0.0.0.0"},
+		{ip: "::"},
+		{ip: "10.1.2.3"},
+		{ip: "172.31.255.255"},
+		{ip: "192.168.0.10"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "fc00::1"},
+		{ip: "100.100.100.200"},
+		{ip: "198.18.0.1"},
+		{ip: "224.0.0.1"},
+		{ip: "ff02::1"},
+		{ip: "255.255.255.255"},
+		{ip: "::ffff:10.0.0.1"},
+		{ip: "64:ff9b::a00:1"},
+		{ip: "2002:a00:1::1"},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %t, want %t", tt.ip, got, tt.want)
+		}
+	}
+}
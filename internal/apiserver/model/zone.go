@@ -0,0 +1,84 @@
+package model
+
+import "sort"
+
+const (
+	// ZoneHall представляет основной зал ресторана (зона столиков по умолчанию).
+	ZoneHall = "hall"
+	// ZoneTerrace представляет террасу.
+	ZoneTerrace = "terrace"
+	// ZoneVIP представляет VIP-зал.
+	ZoneVIP = "vip"
+	// ZoneNonSmoking представляет зал для некурящих.
+	ZoneNonSmoking = "non_smoking"
+)
+
+// Zone представляет зону ресторана, в которой стоят столики.
+type Zone struct {
+	// Name представляет название зоны, которое используется в API и хранится в БД.
+	Name string
+	// Title представляет название зоны для отображения на сайте.
+	Title string
+}
+
+// zones содержит все зоны ресторанов в порядке их отображения на сайте.
+var zones = []Zone{
+	{Name: ZoneHall, Title: "Основной зал"},
+	{Name: ZoneTerrace, Title: "Терраса"},
+	{Name: ZoneVIP, Title: "VIP-зал"},
+	{Name: ZoneNonSmoking, Title: "Зал для некурящих"},
+}
+
+// Zones возвращает все зоны ресторанов.
+func Zones() []Zone {
+	return append([]Zone(nil), zones...)
+}
+
+// IsZone проверяет, существует ли зона с названием name.
+func IsZone(name string) bool {
+	for _, zone := range zones {
+		if zone.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ZoneTitle возвращает название зоны для отображения на сайте.
+func ZoneTitle(name string) string {
+	for _, zone := range zones {
+		if zone.Name == name {
+			return zone.Title
+		}
+	}
+	return name
+}
+
+// ZoneAvailability представляет количество свободных мест в зоне ресторана.
+type ZoneAvailability struct {
+	Zone string `json:"zone" example:"terrace"`
+	// AvailableSeatsNumber представляет актуальное количество свободных мест в зоне.
+	AvailableSeatsNumber int `json:"available_seats_number" example:"8"`
+}
+
+// Title возвращает название зоны для отображения на сайте.
+func (a ZoneAvailability) Title() string {
+	return ZoneTitle(a.Zone)
+}
+
+// SortZoneAvailability сортирует свободные места по зонам в порядке отображения зон на сайте.
+func SortZoneAvailability(availability []ZoneAvailability) {
+	sort.Slice(availability, func(i, j int) bool {
+		return zoneIndex(availability[i].Zone) < zoneIndex(availability[j].Zone)
+	})
+}
+
+// zoneIndex возвращает порядковый номер зоны при отображении на сайте.
+func zoneIndex(name string) int {
+	for i, zone := range zones {
+		if zone.Name == name {
+			return i
+		}
+	}
+	return len(zones)
+}
@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrQueueFull возвращается, если очередь уведомлений переполнена и уведомление не поставлено в очередь.
+	ErrQueueFull = errors.New("notification queue is full")
+	// ErrNotifierClosed возвращается при попытке отправить уведомление после остановки AsyncNotifier.
+	ErrNotifierClosed = errors.New("notifier is closed")
+)
+
+// AsyncConfig представляет настройки асинхронной отправки уведомлений.
+type AsyncConfig struct {
+	// QueueSize представляет количество уведомлений, которые могут ждать отправки.
+	QueueSize int
+	// Workers представляет количество уведомлений, которые отправляются одновременно.
+	Workers int
+	// SendTimeout ограничивает время отправки одного уведомления.
+	SendTimeout time.Duration
+}
+
+// AsyncNotifier ставит уведомления в очередь и отправляет их в фоне, поэтому медленный провайдер не задерживает
+// ответ на HTTP-запрос. Ошибки отправки записываются в лог.
+type AsyncNotifier struct {
+	next   Notifier
+	cfg    AsyncConfig
+	logger *logrus.Logger
+
+	queue chan Notification
+	wg    sync.WaitGroup
+
+	// mu защищает queue от закрытия во время постановки уведомления в очередь
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncNotifier запускает отправку уведомлений способом next в фоне.
+func NewAsyncNotifier(next Notifier, cfg AsyncConfig, logger *logrus.Logger) *AsyncNotifier {
+	a := &AsyncNotifier{
+		next:   next,
+		cfg:    cfg,
+		logger: logger,
+		queue:  make(chan Notification, cfg.QueueSize),
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		a.wg.Add(1)
+		go a.work()
+	}
+	return a
+}
+
+// Notify ставит уведомление в очередь и сразу возвращает управление. Контекст запроса не передаётся в отправку:
+// уведомление должно уйти, даже если запрос уже завершён.
+func (a *AsyncNotifier) Notify(_ context.Context, n Notification) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrNotifierClosed
+	}
+
+	select {
+	case a.queue <- n:
+		return nil
+	default:
+		a.logger.Errorf("failed to queue %s notification for booking %d: %s", n.Event, n.Booking.ID, ErrQueueFull)
+		return ErrQueueFull
+	}
+}
+
+// Close перестаёт принимать уведомления и ждёт, пока отправятся уже поставленные в очередь. Если ctx завершится
+// раньше, возвращает его ошибку, а оставшиеся уведомления отправляются в фоне, пока процесс не завершится.
+func (a *AsyncNotifier) Close(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work отправляет уведомления из очереди, пока очередь не будет закрыта.
+func (a *AsyncNotifier) work() {
+	defer a.wg.Done()
+
+	for n := range a.queue {
+		a.send(n)
+	}
+}
+
+// send отправляет одно уведомление не дольше SendTimeout.
+func (a *AsyncNotifier) send(n Notification) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.SendTimeout)
+	defer cancel()
+
+	if err := a.next.Notify(ctx, n); err != nil {
+		a.logger.Errorf("failed to send %s notification for booking %d: %s", n.Event, n.Booking.ID, err)
+	}
+}
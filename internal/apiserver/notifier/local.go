@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier записывает уведомления в лог сервиса вместо отправки гостям. Подходит для локального запуска.
+type LogNotifier struct {
+	logger *logrus.Logger
+}
+
+func NewLogNotifier(logger *logrus.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (l *LogNotifier) Notify(_ context.Context, n Notification) error {
+	message, err := Render(n)
+	if err != nil {
+		return err
+	}
+
+	l.logger.WithFields(logrus.Fields{
+		"event":        n.Event,
+		"booking_id":   n.Booking.ID,
+		"client_phone": n.Booking.ClientPhone,
+		"client_email": n.Booking.ClientEmail,
+	}).Info(message.Text)
+	return nil
+}
+
+// FileNotifier дописывает уведомления в текстовый файл вместо отправки гостям. Подходит для локального запуска,
+// когда нужно посмотреть, что именно получили бы гости.
+type FileNotifier struct {
+	path string
+	// mu не даёт перемешаться строкам уведомлений, которые записываются одновременно
+	mu sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (f *FileNotifier) Notify(_ context.Context, n Notification) error {
+	message, err := Render(n)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open notifications file: %w", err)
+	}
+
+	// гость мог не указать адрес электронной почты
+	recipients := n.Booking.ClientPhone
+	if n.Booking.ClientEmail != "" {
+		recipients += ", " + n.Booking.ClientEmail
+	}
+
+	_, err = fmt.Fprintf(file, "%s\nTo: %s\nEvent: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), recipients, n.Event, message.Subject, message.Text,
+	)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write notifications file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// Message представляет текст уведомления.
+type Message struct {
+	// Subject представляет тему письма.
+	Subject string
+	// Text представляет текст письма или SMS.
+	Text string
+}
+
+// messageSubject представляет шаблон темы письма, общий для событий с бронью.
+const messageSubject = `Бронь №{{.Booking.ID}} в ресторане «{{.RestaurantName}}»`
+
+// messageSubjects содержит шаблоны темы письма для событий, у которых она отличается от messageSubject.
+var messageSubjects = map[string]string{
+	EventWaitlistOffer: `Освободились места в ресторане «{{.RestaurantName}}»`,
+}
+
+// messageTexts содержит шаблоны текста уведомления для каждого события.
+var messageTexts = map[string]string{
+	EventBookingCreated: `{{.Booking.ClientName}}, ваша бронь №{{.Booking.ID}} в ресторане «{{.RestaurantName}}» ` +
+		`{{if .Booking.IsPending}}принята и ждёт подтверждения рестораном{{else}}оформлена{{end}}: ` +
+		`{{template "details" .}}.`,
+	EventBookingConfirmed: `{{.Booking.ClientName}}, ресторан «{{.RestaurantName}}» подтвердил вашу бронь ` +
+		`№{{.Booking.ID}}: {{template "details" .}}. Ждём вас!`,
+	EventBookingUpdated: `{{.Booking.ClientName}}, ваша бронь №{{.Booking.ID}} в ресторане «{{.RestaurantName}}» ` +
+		`изменена. Теперь: {{template "details" .}}.`,
+	EventBookingCancelled: `{{.Booking.ClientName}}, ваша бронь №{{.Booking.ID}} в ресторане «{{.RestaurantName}}» ` +
+		`на {{.Booking.BookedDate}} в {{.Booking.BookedTimeFrom}} отменена` +
+		`{{if cancelledByRestaurant .Booking}} рестораном. Если это ошибка, позвоните в ресторан{{end}}.`,
+	EventBookingReminder: `{{.Booking.ClientName}}, напоминаем о вашей брони №{{.Booking.ID}} в ресторане ` +
+		`«{{.RestaurantName}}»: {{template "details" .}}.` +
+		`{{if .Booking.IsPending}} Бронь всё ещё ждёт подтверждения рестораном.{{end}} ` +
+		`Если планы изменились, пожалуйста, отмените бронь.`,
+	EventWaitlistOffer: `{{.Booking.ClientName}}, в ресторане «{{.RestaurantName}}» освободились места, которых вы ждали ` +
+		`в листе ожидания: {{template "details" .}}. Столики закреплены за вами до ` +
+		`{{.WaitlistEntry.OfferExpiresAt.Format "15:04"}}: чтобы оформить бронь, примите предложение по записи ` +
+		`№{{.WaitlistEntry.ID}} в листе ожидания на сайте.`,
+}
+
+// messageDetails представляет шаблон описания брони, общий для текстов уведомлений.
+const messageDetails = `{{define "details"}}{{.Booking.BookedDate}} с {{.Booking.BookedTimeFrom}} ` +
+	`до {{.Booking.BookedTimeTo}}, гостей: {{.Booking.PeopleNumber}}{{end}}`
+
+// messageFuncs содержит функции, доступные в шаблонах уведомлений.
+var messageFuncs = template.FuncMap{
+	"cancelledByRestaurant": func(booking model.Booking) bool {
+		return booking.CancelledBy == model.BookingCancelledByRestaurant
+	},
+}
+
+var (
+	subjectTemplate  = template.Must(template.New("subject").Parse(messageSubject))
+	subjectTemplates = parseSubjectTemplates()
+	textTemplates    = parseTextTemplates()
+)
+
+// parseSubjectTemplates разбирает шаблоны темы письма для событий из messageSubjects.
+func parseSubjectTemplates() map[string]*template.Template {
+	templates := make(map[string]*template.Template, len(messageSubjects))
+	for event, subject := range messageSubjects {
+		templates[event] = template.Must(template.New(event).Parse(subject))
+	}
+	return templates
+}
+
+// parseTextTemplates разбирает шаблоны текста уведомлений.
+func parseTextTemplates() map[string]*template.Template {
+	templates := make(map[string]*template.Template, len(messageTexts))
+	for event, text := range messageTexts {
+		tmpl := template.Must(template.New(event).Funcs(messageFuncs).Parse(messageDetails))
+		templates[event] = template.Must(tmpl.Parse(text))
+	}
+	return templates
+}
+
+// Render формирует текст уведомления n по шаблону его события.
+func Render(n Notification) (Message, error) {
+	textTemplate, ok := textTemplates[n.Event]
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %s", ErrUnknownEvent, n.Event)
+	}
+
+	eventSubjectTemplate, ok := subjectTemplates[n.Event]
+	if !ok {
+		eventSubjectTemplate = subjectTemplate
+	}
+
+	var subject, text strings.Builder
+	if err := eventSubjectTemplate.Execute(&subject, n); err != nil {
+		return Message{}, err
+	}
+	if err := textTemplate.Execute(&text, n); err != nil {
+		return Message{}, err
+	}
+
+	return Message{Subject: subject.String(), Text: text.String()}, nil
+}
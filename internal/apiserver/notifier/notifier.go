@@ -0,0 +1,64 @@
+// Package notifier представляет отправку гостям уведомлений о их бронях: по электронной почте (SMTP), по SMS
+// (через HTTP-шлюз SMS-провайдера), а для локального запуска - в лог сервиса или в файл.
+package notifier
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+const (
+	// EventBookingCreated означает, что гость оформил бронь.
+	EventBookingCreated = "booking_created"
+	// EventBookingConfirmed означает, что ресторан подтвердил бронь, ожидавшую подтверждения.
+	EventBookingConfirmed = "booking_confirmed"
+	// EventBookingUpdated означает, что изменились количество человек или дата и время брони.
+	EventBookingUpdated = "booking_updated"
+	// EventBookingCancelled означает, что бронь отменена.
+	EventBookingCancelled = "booking_cancelled"
+	// EventBookingReminder означает напоминание о предстоящей брони.
+	EventBookingReminder = "booking_reminder"
+	// EventWaitlistOffer означает, что гостю из листа ожидания предложены освободившиеся места.
+	EventWaitlistOffer = "waitlist_offer"
+)
+
+// ErrUnknownEvent возвращается при попытке отправить уведомление о неизвестном событии.
+var ErrUnknownEvent = errors.New("unknown notification event")
+
+// Notification представляет уведомление гостя о событии с его бронью.
+type Notification struct {
+	// Event представляет событие с бронью: EventBookingCreated, EventBookingConfirmed, EventBookingUpdated,
+	// EventBookingCancelled, EventBookingReminder или EventWaitlistOffer.
+	Event string
+	// Booking представляет бронь после события. Для EventWaitlistOffer это бронь, которую гость может оформить
+	// по предложению: её ID ещё нет.
+	Booking model.Booking
+	// WaitlistEntry представляет запись в листе ожидания, гостю из которой предложены места (только для
+	// EventWaitlistOffer).
+	WaitlistEntry model.WaitlistEntry
+	// RestaurantName представляет название ресторана, в котором оформлена бронь.
+	RestaurantName string
+}
+
+// Notifier представляет способ доставки уведомлений гостям.
+type Notifier interface {
+	// Notify отправляет гостю уведомление n. Если у гостя нет контакта, по которому работает способ доставки
+	// (например, не указан адрес электронной почты), уведомление пропускается без ошибки.
+	Notify(ctx context.Context, n Notification) error
+}
+
+// MultiNotifier отправляет каждое уведомление всеми способами доставки по очереди.
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, n Notification) error {
+	// ошибка одного способа доставки не мешает отправить уведомление остальными
+	var firstErr error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,243 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// testNotification возвращает уведомление о событии event с бронью на 4 человек 16 июня 2022 года с 19:00 до 21:00.
+func testNotification(event string) Notification {
+	return Notification{
+		Event: event,
+		Booking: model.Booking{
+			ID:             3,
+			ClientName:     "Павел",
+			ClientPhone:    "+79485722648",
+			ClientEmail:    "pavel@example.com",
+			PeopleNumber:   4,
+			BookedDate:     model.ShortFormattedDate(time.Date(2022, time.June, 16, 0, 0, 0, 0, time.Local)),
+			BookedTimeFrom: model.NewShortFormattedTime(19, 0),
+			BookedTimeTo:   model.NewShortFormattedTime(21, 0),
+			Status:         model.BookingStatusConfirmed,
+		},
+		RestaurantName: "Каравелла",
+	}
+}
+
+// recordingNotifier запоминает уведомления вместо отправки: каждое уведомление "отправляется" за delay
+// и завершается ошибкой err.
+type recordingNotifier struct {
+	mu    sync.Mutex
+	sent  []Notification
+	err   error
+	delay time.Duration
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, n Notification) error {
+	time.Sleep(r.delay)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, n)
+	return r.err
+}
+
+func (r *recordingNotifier) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]string, 0, len(r.sent))
+	for _, n := range r.sent {
+		events = append(events, n.Event)
+	}
+	return events
+}
+
+func TestRender(t *testing.T) {
+	pending := testNotification(EventBookingCreated)
+	pending.Booking.Status = model.BookingStatusPending
+
+	cancelledByRestaurant := testNotification(EventBookingCancelled)
+	cancelledByRestaurant.Booking.Status = model.BookingStatusCancelled
+	cancelledByRestaurant.Booking.CancelledBy = model.BookingCancelledByRestaurant
+
+	offerExpiresAt := time.Date(2022, time.June, 15, 18, 30, 0, 0, time.Local)
+	offer := testNotification(EventWaitlistOffer)
+	offer.WaitlistEntry = model.WaitlistEntry{ID: 7, OfferExpiresAt: &offerExpiresAt}
+
+	tests := []struct {
+		name         string
+		notification Notification
+		wantSubject  string
+		wantText     string
+	}{
+		{
+			name:         "created",
+			notification: testNotification(EventBookingCreated),
+			wantSubject:  "Бронь №3 в ресторане «Каравелла»",
+			wantText: "Павел, ваша бронь №3 в ресторане «Каравелла» оформлена: " +
+				"2022.06.16 с 19:00 до 21:00, гостей: 4.",
+		},
+		{
+			name:         "created pending",
+			notification: pending,
+			wantSubject:  "Бронь №3 в ресторане «Каравелла»",
+			wantText: "Павел, ваша бронь №3 в ресторане «Каравелла» принята и ждёт подтверждения рестораном: " +
+				"2022.06.16 с 19:00 до 21:00, гостей: 4.",
+		},
+		{
+			name:         "cancelled by restaurant",
+			notification: cancelledByRestaurant,
+			wantSubject:  "Бронь №3 в ресторане «Каравелла»",
+			wantText: "Павел, ваша бронь №3 в ресторане «Каравелла» на 2022.06.16 в 19:00 отменена рестораном. " +
+				"Если это ошибка, позвоните в ресторан.",
+		},
+		{
+			name:         "waitlist offer",
+			notification: offer,
+			wantSubject:  "Освободились места в ресторане «Каравелла»",
+			wantText: "Павел, в ресторане «Каравелла» освободились места, которых вы ждали в листе ожидания: " +
+				"2022.06.16 с 19:00 до 21:00, гостей: 4. Столики закреплены за вами до 18:30: чтобы оформить бронь, " +
+				"примите предложение по записи №7 в листе ожидания на сайте.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Render(tt.notification)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message.Subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", message.Subject, tt.wantSubject)
+			}
+			if message.Text != tt.wantText {
+				t.Errorf("text = %q, want %q", message.Text, tt.wantText)
+			}
+		})
+	}
+
+	// у каждого события есть текст уведомления
+	for _, event := range []string{
+		EventBookingCreated, EventBookingConfirmed, EventBookingUpdated,
+		EventBookingCancelled, EventBookingReminder, EventWaitlistOffer,
+	} {
+		n := testNotification(event)
+		n.WaitlistEntry = offer.WaitlistEntry
+		if message, err := Render(n); err != nil || message.Text == "" {
+			t.Errorf("Render(%s) = %q, %v; want a text", event, message.Text, err)
+		}
+	}
+
+	if _, err := Render(testNotification("booking_lost")); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("Render() of an unknown event = %v, want ErrUnknownEvent", err)
+	}
+}
+
+func TestSMSNotifier(t *testing.T) {
+	var requests []smsRequest
+	status := http.StatusOK
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer sms-token" {
+			t.Errorf("Authorization = %q, want the gateway token", got)
+		}
+		var req smsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode sms request: %v", err)
+		}
+		requests = append(requests, req)
+		w.WriteHeader(status)
+	}))
+	defer gateway.Close()
+
+	sms := NewSMSNotifier(SMSConfig{GatewayURL: gateway.URL, Token: "sms-token", Sender: "Каравелла"})
+	n := testNotification(EventBookingConfirmed)
+	if err := sms.Notify(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	message, err := Render(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || requests[0] != (smsRequest{To: "+79485722648", From: "Каравелла", Text: message.Text}) {
+		t.Fatalf("gateway requests = %+v, want one sms to the guest", requests)
+	}
+
+	// без телефона SMS не отправляется
+	n.Booking.ClientPhone = ""
+	if err = sms.Notify(context.Background(), n); err != nil || len(requests) != 1 {
+		t.Errorf("Notify() without a phone = %v with %d requests, want no sms", err, len(requests))
+	}
+
+	status = http.StatusServiceUnavailable
+	if err = sms.Notify(context.Background(), testNotification(EventBookingConfirmed)); err == nil {
+		t.Error("Notify() with an unavailable gateway succeeded, want error")
+	}
+}
+
+func TestMultiNotifier(t *testing.T) {
+	failing := &recordingNotifier{err: errors.New("smtp is unavailable")}
+	working := &recordingNotifier{}
+
+	// ошибка почты не мешает отправить SMS
+	err := MultiNotifier{failing, working}.Notify(context.Background(), testNotification(EventBookingCreated))
+	if err == nil || err.Error() != "smtp is unavailable" {
+		t.Errorf("Notify() = %v, want the email error", err)
+	}
+	if events := working.events(); len(events) != 1 {
+		t.Errorf("sent by the second notifier = %v, want one notification", events)
+	}
+}
+
+func TestAsyncNotifier(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	next := &recordingNotifier{delay: 10 * time.Millisecond}
+	async := NewAsyncNotifier(next, AsyncConfig{QueueSize: 10, Workers: 1, SendTimeout: time.Second}, logger)
+
+	events := []string{EventBookingCreated, EventBookingUpdated, EventBookingCancelled}
+	for _, event := range events {
+		if err := async.Notify(context.Background(), testNotification(event)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// остановка дожидается отправки уведомлений из очереди
+	if err := async.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := next.events(); strings.Join(got, ",") != strings.Join(events, ",") {
+		t.Errorf("sent = %v, want %v", got, events)
+	}
+
+	if err := async.Notify(context.Background(), testNotification(EventBookingCreated)); !errors.Is(err, ErrNotifierClosed) {
+		t.Errorf("Notify() after Close() = %v, want ErrNotifierClosed", err)
+	}
+}
+
+func TestAsyncNotifier_QueueFull(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	// без обработчиков очередь не разбирается
+	async := NewAsyncNotifier(&recordingNotifier{}, AsyncConfig{QueueSize: 1, SendTimeout: time.Second}, logger)
+
+	if err := async.Notify(context.Background(), testNotification(EventBookingCreated)); err != nil {
+		t.Fatal(err)
+	}
+	if err := async.Notify(context.Background(), testNotification(EventBookingUpdated)); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Notify() to a full queue = %v, want ErrQueueFull", err)
+	}
+}
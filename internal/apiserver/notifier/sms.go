@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// SMSConfig представляет настройки HTTP-шлюза SMS-провайдера.
+type SMSConfig struct {
+	// GatewayURL представляет адрес, на который отправляются запросы на отправку SMS.
+	GatewayURL string
+	// Token представляет токен доступа к шлюзу (передаётся в заголовке "Authorization: Bearer <токен>").
+	Token string
+	// Sender представляет имя или номер отправителя SMS.
+	Sender string
+}
+
+// smsRequest представляет тело запроса к шлюзу на отправку SMS.
+type smsRequest struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+// SMSNotifier отправляет уведомления гостям по SMS через HTTP-шлюз SMS-провайдера: на адрес шлюза отправляется
+// POST-запрос с JSON-телом {"to": "+79279007265", "from": "<отправитель>", "text": "<текст>"}.
+type SMSNotifier struct {
+	cfg    SMSConfig
+	client *http.Client
+}
+
+func NewSMSNotifier(cfg SMSConfig) *SMSNotifier {
+	return &SMSNotifier{cfg: cfg, client: &http.Client{}}
+}
+
+func (s *SMSNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.Booking.ClientPhone == "" {
+		return nil
+	}
+
+	message, err := Render(n)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(smsRequest{To: n.Booking.ClientPhone, From: s.cfg.Sender, Text: message.Text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.GatewayURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("send sms: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send sms: %w", err)
+	}
+	defer resp.Body.Close()
+	// дочитываем ответ, чтобы соединение можно было переиспользовать
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("send sms: gateway responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
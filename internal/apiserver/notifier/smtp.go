@@ -0,0 +1,125 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig представляет настройки подключения к SMTP-серверу.
+type SMTPConfig struct {
+	// Addr представляет адрес SMTP-сервера в виде "host:port".
+	Addr string
+	// Username и Password представляют учётные данные на SMTP-сервере (если Username пуст, вход не выполняется).
+	Username string
+	Password string
+	// From представляет адрес отправителя писем.
+	From string
+}
+
+// SMTPNotifier отправляет уведомления гостям по электронной почте через SMTP-сервер. Если сервер поддерживает
+// STARTTLS, соединение шифруется.
+type SMTPNotifier struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+func (s *SMTPNotifier) Notify(ctx context.Context, n Notification) error {
+	// гость мог не указать адрес электронной почты
+	if n.Booking.ClientEmail == "" {
+		return nil
+	}
+
+	message, err := Render(n)
+	if err != nil {
+		return err
+	}
+
+	if err = s.send(ctx, n.Booking.ClientEmail, message); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
+
+// send отправляет письмо message на адрес to. Отправка прерывается, когда истекает срок ctx.
+func (s *SMTPNotifier) send(ctx context.Context, to string, message Message) error {
+	host, _, err := net.SplitHostPort(s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp не принимает контекст, поэтому срок отправки ограничивается дедлайном соединения
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	if err = client.Rcpt(to); err != nil {
+		return err
+	}
+
+	body, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = body.Write(composeEmail(s.cfg.From, to, message)); err != nil {
+		return err
+	}
+	if err = body.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// composeEmail формирует письмо в формате RFC 5322 с текстом в UTF-8.
+func composeEmail(from, to string, message Message) []byte {
+	var email strings.Builder
+	email.WriteString("From: " + from + "\r\n")
+	email.WriteString("To: " + to + "\r\n")
+	email.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	email.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	email.WriteString("MIME-Version: 1.0\r\n")
+	email.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	email.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	email.WriteString("\r\n")
+	email.WriteString(strings.ReplaceAll(message.Text, "\n", "\r\n"))
+	email.WriteString("\r\n")
+	return []byte(email.String())
+}
@@ -0,0 +1,141 @@
+package pubsub
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// notifyChannel представляет канал PostgreSQL, через который экземпляры сервиса обмениваются событиями ресторанов.
+const notifyChannel = "restaurant_events"
+
+const (
+	// minReconnectInterval и maxReconnectInterval ограничивают паузу перед повторным подключением к БД после потери
+	// соединения, на котором слушается канал.
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+)
+
+var _ Broker = (*PostgresBroker)(nil)
+
+// PostgresBroker распространяет события ресторанов между всеми экземплярами сервиса, работающими с одной БД:
+// событие отправляется в канал PostgreSQL (NOTIFY), а каждый экземпляр, в том числе отправивший его, слушает канал
+// (LISTEN) и доставляет событие своим подписчикам.
+type PostgresBroker struct {
+	hub      *Hub
+	db       *sql.DB
+	listener *pq.Listener
+	logger   *logrus.Logger
+	// done закрывается, когда прослушивание канала завершено
+	done chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPostgresBroker подключается к БД по строке подключения dsn и начинает слушать канал с событиями ресторанов.
+func NewPostgresBroker(dsn string, logger *logrus.Logger) (*PostgresBroker, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// события отправляются короткими запросами, поэтому много соединений не нужно
+	db.SetMaxOpenConns(2)
+
+	// Listen ждёт подключения к БД сколько угодно долго, поэтому недоступность БД проверяем заранее
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	b := &PostgresBroker{
+		hub:    NewHub(),
+		db:     db,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+	b.listener = pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, b.logListenerEvent)
+	if err = b.listener.Listen(notifyChannel); err != nil {
+		_ = b.listener.Close()
+		_ = db.Close()
+		return nil, err
+	}
+
+	go b.listen()
+	return b, nil
+}
+
+func (b *PostgresBroker) Publish(event model.RestaurantEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	// событие с бронью или столиком занимает около килобайта, а NOTIFY принимает до 8000 байт
+	payload, err := json.Marshal(event)
+	if err != nil {
+		b.logger.Errorf("failed to encode restaurant event: %s", err)
+		return
+	}
+	if _, err = b.db.Exec("SELECT pg_notify($1, $2)", notifyChannel, string(payload)); err != nil {
+		b.logger.Errorf("failed to publish restaurant event: %s", err)
+	}
+}
+
+func (b *PostgresBroker) Subscribe(restaurantID uint64) (<-chan model.RestaurantEvent, func()) {
+	return b.hub.Subscribe(restaurantID)
+}
+
+func (b *PostgresBroker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	// после закрытия соединения канал уведомлений закрывается, и прослушивание завершается
+	_ = b.listener.Close()
+	<-b.done
+
+	_ = b.hub.Close()
+	return b.db.Close()
+}
+
+// listen доставляет подписчикам события из канала PostgreSQL, пока не будет закрыто соединение.
+func (b *PostgresBroker) listen() {
+	defer close(b.done)
+
+	for notification := range b.listener.Notify {
+		if notification == nil {
+			// соединение восстановлено после разрыва: события за время разрыва потеряны, поэтому закрываем подписки,
+			// чтобы подписчики заново загрузили брони и столики
+			b.hub.reset()
+			continue
+		}
+
+		var event model.RestaurantEvent
+		if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+			b.logger.Errorf("failed to decode restaurant event: %s", err)
+			continue
+		}
+		b.hub.Publish(event)
+	}
+}
+
+// logListenerEvent записывает в лог потерю и восстановление соединения, на котором слушается канал.
+func (b *PostgresBroker) logListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		b.logger.Warnf("restaurant events listener disconnected: %s", err)
+	case pq.ListenerEventConnectionAttemptFailed:
+		b.logger.Warnf("restaurant events listener failed to reconnect: %s", err)
+	case pq.ListenerEventReconnected:
+		b.logger.Info("restaurant events listener reconnected")
+	}
+}
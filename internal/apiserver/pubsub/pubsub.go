@@ -0,0 +1,129 @@
+// Package pubsub представляет публикацию событий ресторанов (оформление и отмена броней, изменение столиков) и
+// подписку на них: хосты ресторана получают события в реальном времени и не обновляют список броней вручную.
+// События распространяются внутри процесса (Hub), а при нескольких экземплярах сервиса с общей БД - через
+// LISTEN/NOTIFY в PostgreSQL (PostgresBroker).
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// subscriptionBufferSize ограничивает количество событий, которые ждут, пока подписчик их разберёт.
+const subscriptionBufferSize = 64
+
+// Publisher представляет способ публикации событий ресторанов.
+type Publisher interface {
+	// Publish публикует событие event. Событие публикуется уже после изменения брони или столика, поэтому ошибки
+	// публикации не возвращаются: они не должны отменять изменение.
+	Publish(event model.RestaurantEvent)
+}
+
+// Subscriber представляет способ подписки на события ресторанов.
+type Subscriber interface {
+	// Subscribe подписывается на события ресторана restaurantID и возвращает канал с событиями и функцию отмены
+	// подписки. Канал закрывается, когда подписка отменена, подписчик не успевает разбирать события или часть
+	// событий могла быть потеряна: тогда подписчику стоит заново загрузить брони и столики и подписаться снова.
+	Subscribe(restaurantID uint64) (<-chan model.RestaurantEvent, func())
+}
+
+// Broker публикует события ресторанов и доставляет их подписчикам.
+type Broker interface {
+	Publisher
+	Subscriber
+	// Close закрывает все подписки и освобождает ресурсы. После закрытия события не публикуются, а новые подписки
+	// сразу закрываются.
+	Close() error
+}
+
+var _ Broker = (*Hub)(nil)
+
+// Hub доставляет события ресторанов подписчикам внутри процесса.
+type Hub struct {
+	mu sync.Mutex
+	// subscriptions представляет каналы подписчиков по ID ресторанов.
+	subscriptions map[uint64]map[chan model.RestaurantEvent]struct{}
+	closed        bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subscriptions: make(map[uint64]map[chan model.RestaurantEvent]struct{})}
+}
+
+func (h *Hub) Publish(event model.RestaurantEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscriptions[event.RestaurantID] {
+		select {
+		case ch <- event:
+		default:
+			// подписчик не успевает разбирать события: закрываем подписку, а не ждём его, чтобы не задерживать
+			// остальных подписчиков и изменение брони, после которого публикуется событие
+			h.unsubscribe(event.RestaurantID, ch)
+		}
+	}
+}
+
+func (h *Hub) Subscribe(restaurantID uint64) (<-chan model.RestaurantEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan model.RestaurantEvent, subscriptionBufferSize)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if h.subscriptions[restaurantID] == nil {
+		h.subscriptions[restaurantID] = make(map[chan model.RestaurantEvent]struct{})
+	}
+	h.subscriptions[restaurantID][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.unsubscribe(restaurantID, ch)
+	}
+}
+
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	h.unsubscribeAll()
+	return nil
+}
+
+// reset закрывает все подписки, когда часть событий могла быть потеряна. Новые подписки при этом принимаются.
+func (h *Hub) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unsubscribeAll()
+}
+
+// unsubscribeAll закрывает все подписки. Вызывающий код должен удерживать блокировку h.mu.
+func (h *Hub) unsubscribeAll() {
+	for restaurantID, channels := range h.subscriptions {
+		for ch := range channels {
+			h.unsubscribe(restaurantID, ch)
+		}
+	}
+}
+
+// unsubscribe закрывает подписку на события ресторана restaurantID с каналом ch, если она ещё не закрыта.
+// Вызывающий код должен удерживать блокировку h.mu.
+func (h *Hub) unsubscribe(restaurantID uint64, ch chan model.RestaurantEvent) {
+	channels := h.subscriptions[restaurantID]
+	if _, ok := channels[ch]; !ok {
+		return
+	}
+
+	delete(channels, ch)
+	if len(channels) == 0 {
+		delete(h.subscriptions, restaurantID)
+	}
+	close(ch)
+}
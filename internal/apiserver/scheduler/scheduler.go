@@ -0,0 +1,161 @@
+// Package scheduler представляет выполнение фоновых задач, связанных с бронями (model.Job), внутри процесса сервиса.
+// Задачи хранятся в слое хранения данных, поэтому не теряются при перезапуске, а неудачные попытки повторяются
+// с нарастающей задержкой.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// errNoHandler возникает, если для вида задачи не зарегистрирован обработчик.
+var errNoHandler = errors.New("no handler for the job kind")
+
+// Handler выполняет задачу. Если задачу не удалось выполнить, возвращает ошибку, и попытка повторяется позже.
+type Handler func(ctx context.Context, job model.Job) error
+
+// Config представляет настройки выполнения фоновых задач.
+type Config struct {
+	// PollInterval представляет период, с которым проверяется, не наступило ли время задач.
+	PollInterval time.Duration
+	// BatchSize представляет, сколько задач выбирается за одну проверку.
+	BatchSize int
+	// JobTimeout ограничивает время выполнения одной задачи.
+	JobTimeout time.Duration
+	// MaxAttempts представляет количество попыток выполнить задачу, после которых она считается невыполненной.
+	MaxAttempts int
+	// RetryDelay представляет задержку перед первой повторной попыткой: каждая следующая задержка вдвое больше
+	// предыдущей, но не больше MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Scheduler периодически выбирает задачи, время которых наступило, и выполняет их обработчиками, зарегистрированными
+// для их видов.
+type Scheduler struct {
+	jobRepo  store.JobRepository
+	cfg      Config
+	logger   *logrus.Logger
+	handlers map[string]Handler
+}
+
+func NewScheduler(jobRepo store.JobRepository, cfg Config, logger *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		jobRepo:  jobRepo,
+		cfg:      cfg,
+		logger:   logger,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Handle регистрирует обработчик задач вида kind. Обработчики регистрируются до запуска Run.
+func (s *Scheduler) Handle(kind string, handler Handler) {
+	s.handlers[kind] = handler
+}
+
+// Run выполняет задачи, пока не будет отменён ctx. Начатая задача при отмене дорабатывает (но не дольше JobTimeout),
+// а выбранные, но ещё не начатые задачи возвращаются в очередь.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue выполняет задачи, время которых наступило, пока они не закончатся или не будет отменён ctx.
+func (s *Scheduler) runDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// задача закрепляется с запасом: за это время её точно успеют выполнить или вернуть в очередь
+		jobs, err := s.jobRepo.Claim(s.cfg.BatchSize, time.Now().Add(2*s.cfg.JobTimeout))
+		if err != nil {
+			s.logger.Errorf("failed to claim jobs: %s", err)
+			return
+		}
+
+		for i, job := range jobs {
+			if ctx.Err() != nil {
+				s.release(jobs[i:])
+				return
+			}
+			s.run(job)
+		}
+
+		if len(jobs) < s.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// run выполняет задачу и сохраняет результат: отмечает задачу выполненной, планирует повторную попытку
+// или, если попытки закончились, отмечает задачу невыполненной.
+func (s *Scheduler) run(job model.Job) {
+	err := s.handle(job)
+	if err == nil {
+		if err = s.jobRepo.Complete(job.ID); err != nil {
+			s.logger.Errorf("failed to complete job %d: %s", job.ID, err)
+		}
+		return
+	}
+
+	if job.Attempts >= s.cfg.MaxAttempts {
+		s.logger.Errorf("job %d (%s) failed after %d attempts: %s", job.ID, job.Kind, job.Attempts, err)
+		if err = s.jobRepo.Fail(job.ID, err.Error()); err != nil {
+			s.logger.Errorf("failed to mark job %d as failed: %s", job.ID, err)
+		}
+		return
+	}
+
+	retryAt := time.Now().Add(s.retryDelay(job.Attempts))
+	s.logger.Warnf("job %d (%s) failed, retrying at %s: %s", job.ID, job.Kind, retryAt.Format(time.RFC3339), err)
+	if err = s.jobRepo.Retry(job.ID, retryAt, err.Error()); err != nil {
+		s.logger.Errorf("failed to reschedule job %d: %s", job.ID, err)
+	}
+}
+
+// handle выполняет задачу обработчиком её вида не дольше JobTimeout.
+func (s *Scheduler) handle(job model.Job) error {
+	handler, ok := s.handlers[job.Kind]
+	if !ok {
+		return errNoHandler
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.JobTimeout)
+	defer cancel()
+
+	return handler(ctx, job)
+}
+
+// retryDelay возвращает задержку перед повторной попыткой после attempts неудачных попыток.
+func (s *Scheduler) retryDelay(attempts int) time.Duration {
+	delay := s.cfg.RetryDelay
+	for i := 1; i < attempts && delay < s.cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.cfg.MaxRetryDelay {
+		delay = s.cfg.MaxRetryDelay
+	}
+	return delay
+}
+
+// release возвращает в очередь задачи, к выполнению которых так и не приступили.
+func (s *Scheduler) release(jobs []model.Job) {
+	for _, job := range jobs {
+		if err := s.jobRepo.Release(job.ID); err != nil {
+			s.logger.Errorf("failed to release job %d: %s", job.ID, err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// testJobKind возвращает вид i-й задачи в тестах: у брони остаётся одна задача каждого вида, поэтому задачи
+// различаются видами.
+func testJobKind(i int) string {
+	return fmt.Sprintf("test_%d", i)
+}
+
+// testConfig представляет настройки, с которыми повторные попытки выполняются почти сразу.
+var testConfig = Config{
+	PollInterval:  time.Millisecond,
+	BatchSize:     10,
+	JobTimeout:    time.Second,
+	MaxAttempts:   3,
+	RetryDelay:    time.Millisecond,
+	MaxRetryDelay: 2 * time.Millisecond,
+}
+
+// newTestJobs создаёт хранилище с бронью и планирует по ней count задач, время которых уже наступило.
+func newTestJobs(t *testing.T, count int) (store.JobRepository, []uint64) {
+	t.Helper()
+
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	week := time.Now().AddDate(0, 0, 7)
+	bookingID, err := st.Bookings().Create(
+		restaurantID, 0, "Павел", "+79485722648", "", 2, model.BookingStatusConfirmed, week, week, time.Hour,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobIDs := make([]uint64, 0, count)
+	for i := 0; i < count; i++ {
+		jobID, err := st.Jobs().Schedule(testJobKind(i), bookingID, time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+	return st.Jobs(), jobIDs
+}
+
+// newTestScheduler создаёт планировщик задач поверх jobRepo, который выполняет задачи всех видов обработчиком handler.
+func newTestScheduler(jobRepo store.JobRepository, count int, handler Handler) *Scheduler {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	s := NewScheduler(jobRepo, testConfig, logger)
+	for i := 0; i < count; i++ {
+		s.Handle(testJobKind(i), handler)
+	}
+	return s
+}
+
+// runUntilIdle несколько раз выполняет задачи, время которых наступило, с паузами, за которые наступает время
+// повторных попыток.
+func runUntilIdle(s *Scheduler) {
+	for i := 0; i < 10; i++ {
+		s.runDue(context.Background())
+		time.Sleep(5 * testConfig.MaxRetryDelay)
+	}
+}
+
+func TestScheduler_Retry(t *testing.T) {
+	jobRepo, _ := newTestJobs(t, 1)
+
+	// первые две попытки завершаются ошибкой, третья - успешно
+	var attempts []int
+	s := newTestScheduler(jobRepo, 1, func(_ context.Context, job model.Job) error {
+		attempts = append(attempts, job.Attempts)
+		if job.Attempts < 3 {
+			return errors.New("smtp is unavailable")
+		}
+		return nil
+	})
+	runUntilIdle(s)
+
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[1] != 2 || attempts[2] != 3 {
+		t.Fatalf("attempts = %v, want [1 2 3]", attempts)
+	}
+	// выполненная задача больше не выбирается
+	if jobs, err := jobRepo.Claim(10, time.Now().Add(time.Minute)); err != nil || len(jobs) != 0 {
+		t.Errorf("Claim() after completion = %v, %v; want no jobs", jobs, err)
+	}
+}
+
+func TestScheduler_Fail(t *testing.T) {
+	jobRepo, _ := newTestJobs(t, 1)
+
+	calls := 0
+	s := newTestScheduler(jobRepo, 1, func(_ context.Context, _ model.Job) error {
+		calls++
+		return errors.New("smtp is unavailable")
+	})
+	runUntilIdle(s)
+
+	// после MaxAttempts попыток задача считается невыполненной и больше не повторяется
+	if calls != testConfig.MaxAttempts {
+		t.Fatalf("handler calls = %d, want %d", calls, testConfig.MaxAttempts)
+	}
+	if jobs, err := jobRepo.Claim(10, time.Now().Add(time.Minute)); err != nil || len(jobs) != 0 {
+		t.Errorf("Claim() after the last attempt = %v, %v; want no jobs", jobs, err)
+	}
+}
+
+func TestScheduler_NoHandler(t *testing.T) {
+	jobRepo, _ := newTestJobs(t, 1)
+
+	// задачу без обработчика повторяют, пока не закончатся попытки: обработчик может появиться в новой версии сервиса
+	runUntilIdle(newTestScheduler(jobRepo, 0, nil))
+
+	if jobs, err := jobRepo.Claim(10, time.Now().Add(time.Minute)); err != nil || len(jobs) != 0 {
+		t.Errorf("Claim() after the last attempt = %v, %v; want no jobs", jobs, err)
+	}
+}
+
+// TestScheduler_Restart проверяет, что задачи, которые не успели выполнить до остановки сервиса, хранятся
+// в хранилище и выполняются после перезапуска, а попытка, к которой так и не приступили, не засчитывается.
+func TestScheduler_Restart(t *testing.T) {
+	jobRepo, jobIDs := newTestJobs(t, 2)
+
+	// сервис останавливается во время выполнения первой задачи, и вторая задача возвращается в очередь
+	ctx, stop := context.WithCancel(context.Background())
+	var done []uint64
+	before := newTestScheduler(jobRepo, 2, func(_ context.Context, job model.Job) error {
+		done = append(done, job.ID)
+		stop()
+		return nil
+	})
+	before.runDue(ctx)
+
+	if len(done) != 1 || done[0] != jobIDs[0] {
+		t.Fatalf("jobs done before the restart = %v, want [%d]", done, jobIDs[0])
+	}
+
+	// новый экземпляр планировщика выполняет оставшуюся задачу с первой попытки
+	var after []model.Job
+	newTestScheduler(jobRepo, 2, func(_ context.Context, job model.Job) error {
+		after = append(after, job)
+		return nil
+	}).runDue(context.Background())
+
+	if len(after) != 1 || after[0].ID != jobIDs[1] || after[0].Attempts != 1 {
+		t.Fatalf("jobs done after the restart = %+v, want job %d at attempt 1", after, jobIDs[1])
+	}
+}
+
+func TestScheduler_RetryDelay(t *testing.T) {
+	s := NewScheduler(nil, Config{RetryDelay: time.Minute, MaxRetryDelay: 5 * time.Minute}, logrus.New())
+
+	// задержка удваивается после каждой неудачной попытки, но не превышает MaxRetryDelay
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, delay := range want {
+		if got := s.retryDelay(i + 1); got != delay {
+			t.Errorf("retryDelay(%d) = %s, want %s", i+1, got, delay)
+		}
+	}
+}
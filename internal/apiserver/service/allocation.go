@@ -0,0 +1,336 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// JoinFunc проверяет, можно ли сдвинуть два столика, чтобы посадить за них одну компанию.
+// Значение nil означает, что сдвигать можно любые столики.
+type JoinFunc func(a, b model.Table) bool
+
+const (
+	// maxPeopleNumber представляет наибольшее количество человек в компании, для которой можно найти рестораны,
+	// удержать столики, оформить бронь или встать в лист ожидания.
+	maxPeopleNumber = 30
+	// maxTablesPerParty представляет наибольшее количество столиков, которые можно сдвинуть для одной компании.
+	maxTablesPerParty = 8
+)
+
+// TableAllocator представляет стратегию выбора столиков для брони.
+type TableAllocator interface {
+	// Allocate выбирает из свободных столиков те, за которые сядет компания из peopleNumber человек. Если компании
+	// нужно несколько столиков, то каждый из них должен сдвигаться (canJoin) хотя бы с одним другим выбранным
+	// столиком, чтобы компания сидела вместе. Компании выделяется не больше maxTablesPerParty столиков. Если рассадить
+	// компанию нельзя, возвращает ErrNotEnoughSeatsInRestaurant.
+	Allocate(tables []model.Table, peopleNumber int, canJoin JoinFunc) ([]model.Table, error)
+}
+
+// allocators содержит поддерживаемые стратегии выбора столиков по их названиям.
+var allocators = map[string]TableAllocator{
+	model.AllocationStrategyBestFit:       BestFitAllocator{},
+	model.AllocationStrategySmallestFirst: SmallestFirstAllocator{},
+}
+
+// allocatorFor возвращает стратегию выбора столиков по её названию. Если ресторан не задал стратегию
+// (или задал неизвестную), используется BestFitAllocator.
+func allocatorFor(strategy string) TableAllocator {
+	if allocator, ok := allocators[strategy]; ok {
+		return allocator
+	}
+	return BestFitAllocator{}
+}
+
+// joinFuncFor возвращает JoinFunc по схеме зала ресторана. Пока для ресторана не задано ни одной пары столиков,
+// которые можно сдвинуть, сдвигать можно любые столики (как до появления схемы зала).
+func joinFuncFor(restaurantTables []model.Table) JoinFunc {
+	for _, table := range restaurantTables {
+		if len(table.JoinableWith) > 0 {
+			return func(a, b model.Table) bool {
+				return a.IsJoinableWith(b.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// SmallestFirstAllocator представляет реализацию TableAllocator, которая занимает столики по возрастанию количества
+// мест, пока за ними не поместится вся компания.
+type SmallestFirstAllocator struct{}
+
+func (SmallestFirstAllocator) Allocate(tables []model.Table, peopleNumber int, canJoin JoinFunc) ([]model.Table, error) {
+	tables = withSeats(tables)
+	if totalSeats(tables) < peopleNumber {
+		return nil, ErrNotEnoughSeatsInRestaurant
+	}
+
+	// алгоритм бронирования столиков:
+	// 	1) доступные столики сортируются по возрастанию количества мест
+	// 	2) пока суммарное количество мест у забронированных столиков не превысит (или будет равно) количество человек,
+	//		которые желают прийти в ресторан, на каждой итерации бронируется наименьший столик, который можно сдвинуть
+	//		с уже забронированными (но не больше maxTablesPerParty столиков)
+	// 	3) если компания не поместилась, шаг 2 повторяется, начиная со следующего по размеру столика
+
+	// 1
+	sorted := append([]model.Table(nil), tables...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].SeatsNumber < sorted[j].SeatsNumber
+	})
+
+	// 3
+	for start := range sorted {
+		// 2
+		// столики, которые будут забронированы после создания брони
+		bookedTables := []model.Table{sorted[start]}
+		booked := map[uint64]bool{sorted[start].ID: true}
+		// отслеживаем количество занятых мест
+		bookedSeatsCurr := sorted[start].SeatsNumber
+		for bookedSeatsCurr < peopleNumber && len(bookedTables) < maxTablesPerParty {
+			next := -1
+			for i, table := range sorted {
+				if !booked[table.ID] && joinsAny(table, bookedTables, canJoin) {
+					next = i
+					break
+				}
+			}
+			if next == -1 {
+				break
+			}
+			bookedTables = append(bookedTables, sorted[next])
+			booked[sorted[next].ID] = true
+			bookedSeatsCurr += sorted[next].SeatsNumber
+		}
+
+		if bookedSeatsCurr >= peopleNumber {
+			return bookedTables, nil
+		}
+	}
+	return nil, ErrNotEnoughSeatsInRestaurant
+}
+
+// BestFitAllocator представляет реализацию TableAllocator, которая рассаживает компанию за как можно меньшее количество
+// столиков, а из равноценных наборов столиков выбирает тот, за которым останется меньше всего свободных мест.
+// Например, компания из 5 человек сядет за свободный столик на 6 мест, а не за столики на 2 и 3 места.
+type BestFitAllocator struct{}
+
+func (a BestFitAllocator) Allocate(tables []model.Table, peopleNumber int, canJoin JoinFunc) ([]model.Table, error) {
+	tables = withSeats(tables)
+	if totalSeats(tables) < peopleNumber {
+		return nil, ErrNotEnoughSeatsInRestaurant
+	}
+
+	var bookedTables []model.Table
+	if canJoin == nil {
+		bookedTables = a.allocateAny(tables, peopleNumber)
+	} else {
+		bookedTables = a.allocateJoinable(tables, peopleNumber, canJoin)
+	}
+	if bookedTables == nil {
+		return nil, ErrNotEnoughSeatsInRestaurant
+	}
+	return bookedTables, nil
+}
+
+// allocateAny выбирает столики, когда сдвигать можно любые из них. Если рассадить компанию нельзя, возвращает nil.
+func (BestFitAllocator) allocateAny(tables []model.Table, peopleNumber int) []model.Table {
+	maxSeats := totalSeats(tables)
+
+	// задача сводится к задаче о рюкзаке: minTables[seats] - наименьшее количество столиков, у которых в сумме
+	// ровно seats мест, а used[i][seats] показывает, что в этом наборе столиков участвует i-й столик
+	const unreachable = -1
+	minTables := make([]int, maxSeats+1)
+	for seats := range minTables {
+		minTables[seats] = unreachable
+	}
+	minTables[0] = 0
+
+	used := make([][]bool, len(tables))
+	for i, table := range tables {
+		used[i] = make([]bool, maxSeats+1)
+		for seats := maxSeats; seats >= table.SeatsNumber; seats-- {
+			prev := minTables[seats-table.SeatsNumber]
+			if prev == unreachable {
+				continue
+			}
+			if minTables[seats] == unreachable || prev+1 < minTables[seats] {
+				minTables[seats] = prev + 1
+				used[i][seats] = true
+			}
+		}
+	}
+
+	// из наборов, в которых хватает мест на всю компанию, выбираем набор из наименьшего количества столиков,
+	// а среди них - с наименьшим количеством мест
+	bestSeats := unreachable
+	for seats := peopleNumber; seats <= maxSeats; seats++ {
+		if minTables[seats] == unreachable {
+			continue
+		}
+		if bestSeats == unreachable || minTables[seats] < minTables[bestSeats] {
+			bestSeats = seats
+		}
+	}
+	// даже наименьший набор столиков, за которым поместится компания, слишком велик
+	if bestSeats == unreachable || minTables[bestSeats] > maxTablesPerParty {
+		return nil
+	}
+
+	// восстанавливаем набор столиков, начиная с последнего рассмотренного
+	bookedTables := make([]model.Table, 0, minTables[bestSeats])
+	for i, seats := len(tables)-1, bestSeats; i >= 0 && seats > 0; i-- {
+		if used[i][seats] {
+			bookedTables = append(bookedTables, tables[i])
+			seats -= tables[i].SeatsNumber
+		}
+	}
+	return bookedTables
+}
+
+// allocateJoinable выбирает столики, когда сдвигать можно только некоторые из них. Столики и возможность их сдвинуть
+// образуют граф, а подходящие наборы столиков - связные подграфы в его компонентах связности. Перебор всех связных
+// подграфов растёт экспоненциально с размером зала, поэтому набор жадно наращивается от каждого столика: к нему
+// добавляется наименьший из соседних столиков, с которым компании хватит мест, а если такого нет - наибольший.
+// Из полученных наборов выбирается лучший. Если рассадить компанию нельзя, возвращает nil.
+func (BestFitAllocator) allocateJoinable(tables []model.Table, peopleNumber int, canJoin JoinFunc) []model.Table {
+	// neighbours[i] - индексы столиков, которые можно сдвинуть с i-м столиком
+	neighbours := make([][]int, len(tables))
+	for i := range tables {
+		for j := i + 1; j < len(tables); j++ {
+			if canJoin(tables[i], tables[j]) {
+				neighbours[i] = append(neighbours[i], j)
+				neighbours[j] = append(neighbours[j], i)
+			}
+		}
+	}
+
+	// componentSeats[component[i]] - количество мест в компоненте связности, в которую входит i-й столик
+	component := make([]int, len(tables))
+	for i := range component {
+		component[i] = -1
+	}
+	var componentSeats []int
+	for root := range tables {
+		if component[root] != -1 {
+			continue
+		}
+		component[root] = len(componentSeats)
+		seats := 0
+		for queue := []int{root}; len(queue) > 0; queue = queue[1:] {
+			v := queue[0]
+			seats += tables[v].SeatsNumber
+			for _, u := range neighbours[v] {
+				if component[u] == -1 {
+					component[u] = component[root]
+					queue = append(queue, u)
+				}
+			}
+		}
+		componentSeats = append(componentSeats, seats)
+	}
+
+	var best []int
+	bestSeats := 0
+	for root := range tables {
+		// в компоненте связности не хватит мест на всю компанию, как столики в ней ни сдвигай
+		if componentSeats[component[root]] < peopleNumber {
+			continue
+		}
+
+		set, seats := growJoinable(tables, neighbours, root, peopleNumber)
+		if seats < peopleNumber {
+			continue
+		}
+		if best == nil || len(set) < len(best) || len(set) == len(best) && seats < bestSeats {
+			best, bestSeats = set, seats
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	bookedTables := make([]model.Table, 0, len(best))
+	for _, i := range best {
+		bookedTables = append(bookedTables, tables[i])
+	}
+	return bookedTables
+}
+
+// growJoinable наращивает набор столиков от столика root, пока за ним не поместится компания из peopleNumber человек
+// или в наборе не окажется maxTablesPerParty столиков. Возвращает индексы столиков набора и количество мест за ними.
+func growJoinable(tables []model.Table, neighbours [][]int, root, peopleNumber int) ([]int, int) {
+	set, seats := []int{root}, tables[root].SeatsNumber
+	inSet := map[int]bool{root: true}
+	for seats < peopleNumber && len(set) < maxTablesPerParty {
+		next := -1
+		for _, v := range set {
+			for _, u := range neighbours[v] {
+				if inSet[u] || next != -1 && !betterJoin(tables[u], tables[next], peopleNumber-seats) {
+					continue
+				}
+				next = u
+			}
+		}
+		if next == -1 {
+			break
+		}
+		set = append(set, next)
+		inSet[next] = true
+		seats += tables[next].SeatsNumber
+	}
+	return set, seats
+}
+
+// betterJoin проверяет, лучше ли добавить к набору столик a, чем столик b, когда компании не хватает missing мест:
+// столик, с которым мест хватит, лучше столика, с которым не хватит, из первых лучше меньший, а из вторых - больший.
+func betterJoin(a, b model.Table, missing int) bool {
+	aFits, bFits := a.SeatsNumber >= missing, b.SeatsNumber >= missing
+	if aFits != bFits {
+		return aFits
+	}
+	if aFits {
+		return a.SeatsNumber < b.SeatsNumber
+	}
+	return a.SeatsNumber > b.SeatsNumber
+}
+
+// joinsAny проверяет, можно ли сдвинуть столик хотя бы с одним из столиков набора.
+func joinsAny(table model.Table, set []model.Table, canJoin JoinFunc) bool {
+	if canJoin == nil {
+		return true
+	}
+	for _, t := range set {
+		if canJoin(table, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// withSeats возвращает столики, за которыми есть хотя бы одно место. Столики без мест (например, добавленные в БД
+// в обход API) никого не вместят, а в алгоритмах выбора столиков сломали бы подсчёт мест.
+func withSeats(tables []model.Table) []model.Table {
+	for i, table := range tables {
+		if table.SeatsNumber > 0 {
+			continue
+		}
+		seated := append(make([]model.Table, 0, len(tables)-1), tables[:i]...)
+		for _, table = range tables[i+1:] {
+			if table.SeatsNumber > 0 {
+				seated = append(seated, table)
+			}
+		}
+		return seated
+	}
+	return tables
+}
+
+// totalSeats возвращает суммарное количество мест за столиками.
+func totalSeats(tables []model.Table) int {
+	seats := 0
+	for _, table := range tables {
+		seats += table.SeatsNumber
+	}
+	return seats
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// layout возвращает столики ресторана с указанным количеством мест. ID столиков идут подряд, начиная с firstID,
+// как в testdata/testdata.sql.
+func layout(firstID uint64, seats ...int) []model.Table {
+	tables := make([]model.Table, 0, len(seats))
+	for i, tableSeats := range seats {
+		tables = append(tables, model.Table{ID: firstID + uint64(i), SeatsNumber: tableSeats})
+	}
+	return tables
+}
+
+// inRow делает столики сдвигаемыми с соседями по порядку: первый со вторым, второй с третьим и т.д.
+func inRow(tables []model.Table) []model.Table {
+	row := append([]model.Table(nil), tables...)
+	for i := range row {
+		row[i].JoinableWith = nil
+		if i > 0 {
+			row[i].JoinableWith = append(row[i].JoinableWith, row[i-1].ID)
+		}
+		if i < len(row)-1 {
+			row[i].JoinableWith = append(row[i].JoinableWith, row[i+1].ID)
+		}
+	}
+	return row
+}
+
+// столики ресторанов из testdata/testdata.sql
+var (
+	caravelLayout    = layout(1, 4, 4, 4, 4, 4, 4, 3, 3, 2, 2)
+	youthLayout      = layout(11, 3, 3, 3)
+	meatSaladLayout  = layout(14, 8, 8, 3, 3, 3, 3)
+	brokenSeatLayout = layout(20, -4, 2, 0, 4)
+)
+
+func TestAllocators(t *testing.T) {
+	tests := []struct {
+		name         string
+		tables       []model.Table
+		peopleNumber int
+		// wantTables и wantSeats - количество столиков и мест, которые выбирает BestFitAllocator
+		// (0 - если рассадить компанию нельзя)
+		wantTables int
+		wantSeats  int
+		// bestFitOnly означает, что SmallestFirstAllocator не может рассадить компанию: он занимает сначала маленькие
+		// столики, и их количество достигает maxTablesPerParty раньше, чем компании хватит мест
+		bestFitOnly bool
+	}{
+		{name: "caravel, 1 person", tables: caravelLayout, peopleNumber: 1, wantTables: 1, wantSeats: 2},
+		{name: "caravel, 3 people", tables: caravelLayout, peopleNumber: 3, wantTables: 1, wantSeats: 3},
+		{name: "caravel, 4 people", tables: caravelLayout, peopleNumber: 4, wantTables: 1, wantSeats: 4},
+		{name: "caravel, 5 people", tables: caravelLayout, peopleNumber: 5, wantTables: 2, wantSeats: 5},
+		{name: "caravel, 9 people", tables: caravelLayout, peopleNumber: 9, wantTables: 3, wantSeats: 9},
+		{name: "caravel, max tables", tables: caravelLayout, peopleNumber: 30, wantTables: 8, wantSeats: 30, bestFitOnly: true},
+		{name: "caravel, more than max tables", tables: caravelLayout, peopleNumber: 31},
+		{name: "caravel, too many people", tables: caravelLayout, peopleNumber: 35},
+		{name: "caravel in a row, 5 people", tables: inRow(caravelLayout), peopleNumber: 5, wantTables: 2, wantSeats: 5},
+		{name: "caravel in a row, 9 people", tables: inRow(caravelLayout), peopleNumber: 9, wantTables: 3, wantSeats: 10},
+		{name: "caravel in a row, max tables", tables: inRow(caravelLayout), peopleNumber: 30, wantTables: 8, wantSeats: 30},
+		{name: "caravel in a row, more than max tables", tables: inRow(caravelLayout), peopleNumber: 31},
+		{name: "youth, 3 people", tables: youthLayout, peopleNumber: 3, wantTables: 1, wantSeats: 3},
+		{name: "youth, 4 people", tables: youthLayout, peopleNumber: 4, wantTables: 2, wantSeats: 6},
+		{name: "youth, all seats", tables: youthLayout, peopleNumber: 9, wantTables: 3, wantSeats: 9},
+		{name: "youth, too many people", tables: youthLayout, peopleNumber: 10},
+		{name: "meat and salad, 8 people", tables: meatSaladLayout, peopleNumber: 8, wantTables: 1, wantSeats: 8},
+		{name: "meat and salad, 9 people", tables: meatSaladLayout, peopleNumber: 9, wantTables: 2, wantSeats: 11},
+		{name: "meat and salad, 16 people", tables: meatSaladLayout, peopleNumber: 16, wantTables: 2, wantSeats: 16},
+		{name: "meat and salad, too many people", tables: meatSaladLayout, peopleNumber: 29},
+		{name: "meat and salad in a row, 16 people", tables: inRow(meatSaladLayout), peopleNumber: 16, wantTables: 2, wantSeats: 16},
+		{name: "meat and salad in a row, 12 people", tables: inRow(meatSaladLayout), peopleNumber: 12, wantTables: 2, wantSeats: 16},
+		{name: "tables without seats, 3 people", tables: brokenSeatLayout, peopleNumber: 3, wantTables: 1, wantSeats: 4},
+		{name: "tables without seats, 6 people", tables: brokenSeatLayout, peopleNumber: 6, wantTables: 2, wantSeats: 6},
+		{name: "tables without seats, too many people", tables: brokenSeatLayout, peopleNumber: 7},
+		{name: "tables without seats in a row, 6 people", tables: inRow(brokenSeatLayout), peopleNumber: 6},
+	}
+
+	for _, allocator := range []TableAllocator{BestFitAllocator{}, SmallestFirstAllocator{}} {
+		_, bestFit := allocator.(BestFitAllocator)
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%T/%s", allocator, tt.name), func(t *testing.T) {
+				canJoin := joinFuncFor(tt.tables)
+				allocated, err := allocator.Allocate(tt.tables, tt.peopleNumber, canJoin)
+				if tt.wantTables == 0 || tt.bestFitOnly && !bestFit {
+					if !errors.Is(err, ErrNotEnoughSeatsInRestaurant) {
+						t.Fatalf("Allocate() = %v, %v; want ErrNotEnoughSeatsInRestaurant", allocated, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Allocate() error = %v", err)
+				}
+				checkAllocation(t, tt.tables, allocated, tt.peopleNumber, canJoin)
+
+				if seats := totalSeats(allocated); bestFit && (len(allocated) != tt.wantTables || seats != tt.wantSeats) {
+					t.Errorf("allocated %d tables with %d seats, want %d tables with %d seats",
+						len(allocated), seats, tt.wantTables, tt.wantSeats)
+				}
+			})
+		}
+	}
+}
+
+// TestBestFitAllocator_LargeHall проверяет, что столики в большом зале, где каждый столик можно сдвинуть с соседями
+// по схеме зала, выбираются быстро: количество наборов сдвинутых столиков в таком зале растёт экспоненциально.
+func TestBestFitAllocator_LargeHall(t *testing.T) {
+	const side = 20
+
+	// зал из side x side столиков на 2 места, каждый из которых можно сдвинуть с соседями по горизонтали и вертикали
+	tables := make([]model.Table, 0, side*side)
+	for i := 0; i < side*side; i++ {
+		table := model.Table{ID: uint64(i + 1), SeatsNumber: 2}
+		if i%side > 0 {
+			table.JoinableWith = append(table.JoinableWith, uint64(i))
+		}
+		if i%side < side-1 {
+			table.JoinableWith = append(table.JoinableWith, uint64(i+2))
+		}
+		if i >= side {
+			table.JoinableWith = append(table.JoinableWith, uint64(i+1-side))
+		}
+		if i < side*(side-1) {
+			table.JoinableWith = append(table.JoinableWith, uint64(i+1+side))
+		}
+		tables = append(tables, table)
+	}
+	canJoin := joinFuncFor(tables)
+
+	for _, peopleNumber := range []int{1, 7, 2 * maxTablesPerParty} {
+		allocated, err := BestFitAllocator{}.Allocate(tables, peopleNumber, canJoin)
+		if err != nil {
+			t.Fatalf("Allocate(%d people) error = %v", peopleNumber, err)
+		}
+		checkAllocation(t, tables, allocated, peopleNumber, canJoin)
+	}
+
+	if _, err := (BestFitAllocator{}).Allocate(tables, 2*maxTablesPerParty+1, canJoin); !errors.Is(err, ErrNotEnoughSeatsInRestaurant) {
+		t.Errorf("Allocate() for more than %d tables: error = %v, want ErrNotEnoughSeatsInRestaurant", maxTablesPerParty, err)
+	}
+}
+
+// checkAllocation проверяет, что компания из peopleNumber человек поместилась за выбранными столиками, каждый из них
+// свободен, выбран один раз и есть хотя бы одно место, а все столики можно сдвинуть вместе.
+func checkAllocation(t *testing.T, tables, allocated []model.Table, peopleNumber int, canJoin JoinFunc) {
+	t.Helper()
+
+	free := make(map[uint64]bool, len(tables))
+	for _, table := range tables {
+		free[table.ID] = true
+	}
+	for _, table := range allocated {
+		if !free[table.ID] {
+			t.Errorf("table %d is not free or allocated twice", table.ID)
+		}
+		free[table.ID] = false
+		if table.SeatsNumber <= 0 {
+			t.Errorf("table %d with %d seats is allocated", table.ID, table.SeatsNumber)
+		}
+	}
+	if seats := totalSeats(allocated); seats < peopleNumber {
+		t.Errorf("allocated %d seats for %d people", seats, peopleNumber)
+	}
+
+	// столики, до которых можно дойти, сдвигая их по одному начиная с первого, должны охватить весь набор
+	if canJoin == nil || len(allocated) == 0 {
+		return
+	}
+	joined := []model.Table{allocated[0]}
+	for grown := true; grown; {
+		grown = false
+		for _, table := range allocated {
+			if !containsTable(joined, table.ID) && joinsAny(table, joined, canJoin) {
+				joined = append(joined, table)
+				grown = true
+			}
+		}
+	}
+	if len(joined) != len(allocated) {
+		t.Errorf("allocated tables %v cannot be joined together", allocated)
+	}
+}
+
+// containsTable проверяет, входит ли столик с ID tableID в набор столиков.
+func containsTable(tables []model.Table, tableID uint64) bool {
+	for _, table := range tables {
+		if table.ID == tableID {
+			return true
+		}
+	}
+	return false
+}
@@ -1,87 +1,601 @@
 package service
 
 import (
+	"errors"
 	"fmt"
-	"sort"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
 )
 
 // BookingService представляет бизнес-логику работы с бронями.
 type BookingService interface {
-	// Create создаёт бронь в ресторане на выбранные дату, время и количество человек.
+	// Create создаёт бронь в ресторане на выбранные дату, время и количество человек. Если бронь оформляет сам гость
+	// (не ресторан), учитываются правила надёжности гостей ресторана: при блокировке онлайн-бронирования
+	// возвращается *GuestBlockedError, а после нескольких неявок бронь оформляется в статусе model.BookingStatusPending.
 	Create(details model.BookingDetails) (uint64, error)
 	// GetAll возвращает список всех броней ресторана.
 	GetAll(restaurantID uint64) ([]model.Booking, error)
+	// Get возвращает бронь по её ID.
+	Get(id uint64) (*model.Booking, error)
+	// Update изменяет количество человек и (или) дату и время брони. Столики подбираются заново (если прежние столики
+	// свободны на новое время и за ними хватает мест, гости остаются за ними), а бронь изменяется атомарно: если
+	// рассадить компанию нельзя, возвращается ErrNotEnoughSeatsInRestaurant, и бронь остаётся прежней.
+	Update(id uint64, data model.UpdateBookingData) error
+	// Cancel отменяет бронь по её ID, освобождая забронированные столики. Нельзя отменить бронь,
+	// время которой уже наступило. Принимает cancelledBy - того, кто отменяет бронь.
+	Cancel(id uint64, cancelledBy string) error
+	// CancelByClient отменяет бронь по просьбе клиента, если указанный им телефон совпадает с телефоном в брони.
+	CancelByClient(id uint64, clientPhone string) error
+	// CancelByCustomer отменяет бронь по просьбе гостя с учётной записью customerID, если бронь оформлена им.
+	CancelByCustomer(id, customerID uint64) error
+	// Confirm подтверждает бронь, ожидающую подтверждения рестораном.
+	Confirm(id uint64) error
+	// CheckIn отмечает приход гостей по брони. Отметить приход можно не раньше чем за checkInAdvance до начала брони.
+	CheckIn(id uint64) error
+	// MarkNoShow отмечает, что гости по брони не пришли. Отметить неявку можно только после начала брони;
+	// столики брони сразу освобождаются.
+	MarkNoShow(id uint64) error
+	// Complete отмечает, что гости ушли, и освобождает столики брони, даже если время брони ещё не истекло.
+	Complete(id uint64) error
+	// GetAvailability возвращает сетку доступности ресторана на дату date (в формате "2006.01.02") для компании
+	// из peopleNumber человек: все моменты начала брони с шагом availabilitySlotStep в пределах графика работы
+	// ресторана с количеством свободных мест. Если указана зона (zone), учитываются только столики в этой зоне.
+	GetAvailability(restaurantID uint64, date, peopleNumber, zone string) (*model.Availability, error)
 }
 
+// maxBookingAttempts представляет количество попыток оформить бронь, если подобранные столики одновременно
+// с нами бронирует другой клиент.
+const maxBookingAttempts = 3
+
+// checkInAdvance представляет, насколько раньше начала брони можно отметить приход гостей.
+const checkInAdvance = time.Hour
+
+// availabilitySlotStep представляет шаг, с которым в сетке доступности ресторана идут моменты начала брони.
+const availabilitySlotStep = 15 * time.Minute
+
 // BookingServiceImpl представляет реализацию BookingService.
 type BookingServiceImpl struct {
-	bookingRepo store.BookingRepository
-	tableRepo   store.TableRepository
+	bookingRepo    store.BookingRepository
+	tableRepo      store.TableRepository
+	restaurantRepo store.RestaurantRepository
+	hoursRepo      store.OpeningHoursRepository
+	policyRepo     store.DurationPolicyRepository
+	// guestRepo и reliabilityRepo нужны для проверки гостя по правилам надёжности ресторана
+	guestRepo       store.GuestRepository
+	reliabilityRepo store.ReliabilityPolicyRepository
+	// waitlist получает освободившиеся при отмене и изменении броней места
+	waitlist WaitlistService
+	// notifier уведомляет гостей об оформлении, подтверждении, изменении и отмене их броней
+	notifier notifier.Notifier
+	// reminders планирует напоминания гостям о предстоящих бронях
+	reminders ReminderService
+	// events сообщает хостам ресторана об оформлении и отмене броней
+	events pubsub.Publisher
 }
 
-func NewBookingService(bookingRepo store.BookingRepository, tableRepo store.TableRepository) *BookingServiceImpl {
-	return &BookingServiceImpl{bookingRepo: bookingRepo, tableRepo: tableRepo}
+// BookingServiceDeps представляет зависимости BookingServiceImpl.
+type BookingServiceDeps struct {
+	BookingRepo     store.BookingRepository
+	TableRepo       store.TableRepository
+	RestaurantRepo  store.RestaurantRepository
+	HoursRepo       store.OpeningHoursRepository
+	PolicyRepo      store.DurationPolicyRepository
+	GuestRepo       store.GuestRepository
+	ReliabilityRepo store.ReliabilityPolicyRepository
+	// Waitlist, Notifier, Reminders и Events необязательны: без них освободившиеся места не предлагаются листу
+	// ожидания, гости не получают уведомлений и напоминаний, а хосты - событий
+	Waitlist  WaitlistService
+	Notifier  notifier.Notifier
+	Reminders ReminderService
+	Events    pubsub.Publisher
+}
+
+func NewBookingService(deps BookingServiceDeps) *BookingServiceImpl {
+	return &BookingServiceImpl{
+		bookingRepo:     deps.BookingRepo,
+		tableRepo:       deps.TableRepo,
+		restaurantRepo:  deps.RestaurantRepo,
+		hoursRepo:       deps.HoursRepo,
+		policyRepo:      deps.PolicyRepo,
+		guestRepo:       deps.GuestRepo,
+		reliabilityRepo: deps.ReliabilityRepo,
+		waitlist:        deps.Waitlist,
+		notifier:        deps.Notifier,
+		reminders:       deps.Reminders,
+		events:          deps.Events,
+	}
 }
 
 func (s *BookingServiceImpl) Create(details model.BookingDetails) (uint64, error) {
-	desiredDateTime := strings.Split(details.DesiredDatetime, " ")
+	dateTime, err := parseDesiredDatetime(details.DesiredDatetime)
+	if err != nil {
+		return 0, err
+	}
 
-	// получаем доступные для брони столики в выбранном ресторане
-	tables, err := s.tableRepo.GetAllAvailable(details.RestaurantID, desiredDateTime[0], desiredDateTime[1])
+	// бронь можно оформить только на время, когда ресторан принимает гостей по своему графику работы
+	restaurant, err := getRestaurant(s.restaurantRepo, s.hoursRepo, s.policyRepo, details.RestaurantID)
 	if err != nil {
 		return 0, err
 	}
+	if !restaurant.AcceptsBookingAt(dateTime) {
+		return 0, fmt.Errorf("%w: the restaurant is closed at the desired time", ErrInvalidData)
+	}
 
-	peopleNum, err := strconv.Atoi(details.PeopleNumber)
+	peopleNum, err := parsePeopleNumber(details.PeopleNumber)
 	if err != nil {
-		return 0, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+		return 0, err
 	}
 
-	// подсчёт общего количество доступных мест в ресторане
-	availableSeatsNum := 0
-	for _, table := range tables {
-		availableSeatsNum += table.SeatsNumber
+	// пустая зона означает, что гостю подойдёт любая зона
+	if details.Zone != "" && !model.IsZone(details.Zone) {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidData, model.ErrUnknownZone.Error())
 	}
 
-	// если суммарное количество доступных мест меньше, чем хочет прийти людей
-	if availableSeatsNum < peopleNum {
-		return 0, ErrNotEnoughSeatsInRestaurant
+	// ресторан сам решает, принимать ли бронь от гостя, поэтому правила надёжности применяются только к броням,
+	// которые гости оформляют сами
+	if !details.ByRestaurant {
+		requireConfirmation, err := checkGuestReliability(
+			s.guestRepo, s.bookingRepo, s.reliabilityRepo, details.RestaurantID, details.ClientPhone,
+		)
+		if err != nil {
+			return 0, err
+		}
+		details.Pending = details.Pending || requireConfirmation
 	}
 
-	// алгоритм бронирования столиков:
-	// 	1) доступные столики сортируются по возрастанию количества мест
-	// 	2) пока суммарное количество мест у забронированных столиков не превысит (или будет равно) количество человек,
-	//		которые желают прийти в ресторан, на каждой итерации бронируется столик
+	// длительность брони зависит от правил ресторана и количества человек
+	duration := restaurant.DurationPolicy.DurationFor(peopleNum)
 
-	// 1
-	sort.SliceStable(tables, func(i, j int) bool {
-		return tables[i].SeatsNumber < tables[j].SeatsNumber
+	var bookingID uint64
+	err = retryTableConflicts(func() error {
+		bookingID, err = s.bookTables(restaurant, details, peopleNum, dateTime, duration)
+		return err
 	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.notify(notifier.EventBookingCreated, bookingID)
+	publishBookingEvent(s.events, s.bookingRepo, model.RestaurantEventBookingCreated, bookingID)
+	return bookingID, nil
+}
 
-	// 2
-	// столики, которые будут забронированы после создания брони (изначально пусто)
-	bookedTables := make([]uint64, 0)
-	// отслеживаем количество занятых мест
-	bookedSeatsCurr := 0
-	for i := 0; i < len(tables) && bookedSeatsCurr < peopleNum; i++ {
-		bookedTables = append(bookedTables, tables[i].ID)
-		bookedSeatsCurr += tables[i].SeatsNumber
+// retryTableConflicts выполняет попытку attempt занять подобранные столики. Столики выбираются и занимаются в разных
+// запросах к хранилищу: если между ними столик займёт другой клиент, хранилище вернёт store.ErrTableAlreadyBooked,
+// и попытка повторяется со свежим списком свободных столиков, но не больше maxBookingAttempts раз.
+func retryTableConflicts(attempt func() error) error {
+	for i := 0; i < maxBookingAttempts; i++ {
+		if err := attempt(); !errors.Is(err, store.ErrTableAlreadyBooked) {
+			return err
+		}
 	}
 
-	dateTime, err := time.Parse("2006.01.02 15:04", details.DesiredDatetime)
+	// столики раз за разом занимают одновременно с нами, поэтому считаем, что свободных мест нет
+	return ErrNotEnoughSeatsInRestaurant
+}
+
+// bookTables выбирает свободные столики для компании из peopleNum человек и оформляет на них бронь.
+func (s *BookingServiceImpl) bookTables(
+	restaurant *model.Restaurant, details model.BookingDetails, peopleNum int, dateTime time.Time, duration time.Duration,
+) (uint64, error) {
+	// получаем доступные для брони столики в выбранном ресторане (в предпочитаемой гостем зоне)
+	tables, err := s.tableRepo.GetAllAvailable(details.RestaurantID, dateTime, duration, details.Zone)
 	if err != nil {
-		return 0, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+		return 0, err
+	}
+
+	bookedTables, err := allocateTables(s.tableRepo, restaurant, tables, peopleNum)
+	if err != nil {
+		return 0, err
 	}
 
-	return s.bookingRepo.Create(details.ClientName, details.ClientPhone, dateTime, dateTime, bookedTables...)
+	status := model.BookingStatusConfirmed
+	if details.Pending {
+		status = model.BookingStatusPending
+	}
+
+	return s.bookingRepo.Create(
+		details.RestaurantID, details.CustomerID, details.ClientName, details.ClientPhone, details.ClientEmail,
+		peopleNum, status, dateTime, dateTime, duration, bookedTables...,
+	)
 }
 
 func (s *BookingServiceImpl) GetAll(restaurantID uint64) ([]model.Booking, error) {
 	return s.bookingRepo.GetAll(restaurantID)
 }
+
+func (s *BookingServiceImpl) Get(id uint64) (*model.Booking, error) {
+	return s.bookingRepo.Get(id)
+}
+
+func (s *BookingServiceImpl) Update(id uint64, data model.UpdateBookingData) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if booking.IsCancelled() {
+		return ErrBookingAlreadyCancelled
+	}
+
+	// изменить можно только бронь, которая ждёт гостей
+	if !booking.IsActive() {
+		return fmt.Errorf("%w: the booking is %s", ErrBookingStatusTransition, booking.Status)
+	}
+
+	// бронь, время которой уже наступило, изменить нельзя: клиенты уже пришли (или не пришли) в ресторан
+	if !time.Now().Before(booking.StartsAt()) {
+		return ErrBookingInPast
+	}
+
+	dateTime := booking.StartsAt()
+	if data.DesiredDatetime != nil {
+		// бронь нельзя перенести в прошлое
+		if dateTime, err = parseDesiredDatetime(*data.DesiredDatetime); err != nil {
+			return err
+		}
+	}
+
+	peopleNum := booking.PeopleNumber
+	if data.PeopleNumber != nil {
+		peopleNum = *data.PeopleNumber
+		if err = checkPeopleNumber(peopleNum); err != nil {
+			return err
+		}
+	}
+
+	// новое время брони тоже должно приходиться на время, когда ресторан принимает гостей
+	restaurant, err := getRestaurant(s.restaurantRepo, s.hoursRepo, s.policyRepo, booking.RestaurantID)
+	if err != nil {
+		return err
+	}
+	if !restaurant.AcceptsBookingAt(dateTime) {
+		return fmt.Errorf("%w: the restaurant is closed at the desired time", ErrInvalidData)
+	}
+
+	// длительность брони пересчитывается по новому количеству человек
+	duration := restaurant.DurationPolicy.DurationFor(peopleNum)
+
+	if err = retryTableConflicts(func() error {
+		return s.rebookTables(restaurant, booking, peopleNum, dateTime, duration)
+	}); err != nil {
+		return err
+	}
+
+	s.notify(notifier.EventBookingUpdated, booking.ID)
+	publishBookingEvent(s.events, s.bookingRepo, model.RestaurantEventBookingUpdated, booking.ID)
+	// прежние столики брони могли освободиться
+	s.offerFreedCapacity(booking.RestaurantID)
+	return nil
+}
+
+// rebookTables подбирает столики для изменённой брони и переносит на них бронь.
+func (s *BookingServiceImpl) rebookTables(
+	restaurant *model.Restaurant, booking *model.Booking, peopleNum int, dateTime time.Time, duration time.Duration,
+) error {
+	// столики самой брони считаются свободными: гости могут остаться за ними
+	tables, err := s.tableRepo.GetAllAvailableForBooking(booking.ID, dateTime, duration)
+	if err != nil {
+		return err
+	}
+
+	bookedTables := keptTables(booking.TableIDs, tables, peopleNum)
+	if bookedTables == nil {
+		bookedTables, err = allocateTables(s.tableRepo, restaurant, tables, peopleNum)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.bookingRepo.Update(booking.ID, peopleNum, dateTime, dateTime, duration, bookedTables...)
+}
+
+// allocateTables выбирает среди свободных столиков ресторана столики для компании из peopleNum человек по стратегии,
+// заданной рестораном, и возвращает их ID.
+func allocateTables(
+	tableRepo store.TableRepository, restaurant *model.Restaurant, tables []model.Table, peopleNum int,
+) ([]uint64, error) {
+	// сдвигать можно только те столики, которые рядом стоят по схеме зала ресторана
+	restaurantTables, err := tableRepo.GetAll(restaurant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocatedTables, err := allocatorFor(restaurant.AllocationStrategy).Allocate(tables, peopleNum, joinFuncFor(restaurantTables))
+	if err != nil {
+		return nil, err
+	}
+
+	tableIDs := make([]uint64, 0, len(allocatedTables))
+	for _, table := range allocatedTables {
+		tableIDs = append(tableIDs, table.ID)
+	}
+	return tableIDs, nil
+}
+
+// keptTables возвращает ID прежних столиков брони, если все они есть среди свободных столиков и за ними хватает мест
+// на компанию из peopleNum человек. Иначе возвращает nil.
+func keptTables(tableIDs []uint64, available []model.Table, peopleNum int) []uint64 {
+	if len(tableIDs) == 0 {
+		return nil
+	}
+
+	seats := 0
+	for _, tableID := range tableIDs {
+		found := false
+		for _, table := range available {
+			if table.ID == tableID {
+				seats += table.SeatsNumber
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+
+	if seats < peopleNum {
+		return nil
+	}
+	return tableIDs
+}
+
+func (s *BookingServiceImpl) Cancel(id uint64, cancelledBy string) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if booking.IsCancelled() {
+		return ErrBookingAlreadyCancelled
+	}
+
+	if !model.CanChangeBookingStatus(booking.Status, model.BookingStatusCancelled) {
+		return fmt.Errorf(
+			"%w: %s -> %s", ErrBookingStatusTransition, booking.Status, model.BookingStatusCancelled,
+		)
+	}
+
+	// бронь, время которой уже наступило, отменить нельзя: клиенты уже пришли (или не пришли) в ресторан
+	if !time.Now().Before(booking.StartsAt()) {
+		return ErrBookingInPast
+	}
+
+	if err = s.bookingRepo.Cancel(id, cancelledBy); err != nil {
+		return err
+	}
+
+	s.notify(notifier.EventBookingCancelled, id)
+	publishBookingEvent(s.events, s.bookingRepo, model.RestaurantEventBookingCancelled, id)
+	s.offerFreedCapacity(booking.RestaurantID)
+	return nil
+}
+
+func (s *BookingServiceImpl) CancelByClient(id uint64, clientPhone string) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// не раскрываем существование чужой брони: при несовпадении телефона бронь считается ненайденной
+	if !samePhone(booking.ClientPhone, clientPhone) {
+		return store.ErrBookingNotFound
+	}
+
+	return s.Cancel(id, model.BookingCancelledByClient)
+}
+
+// samePhone проверяет, совпадает ли телефон clientPhone, введённый гостем в любом виде, с сохранённым телефоном
+// storedPhone.
+func samePhone(storedPhone, clientPhone string) bool {
+	phone, err := model.NormalizePhone(clientPhone)
+	return err == nil && phone == storedPhone
+}
+
+func (s *BookingServiceImpl) CancelByCustomer(id, customerID uint64) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// как и при отмене по телефону, чужая бронь считается ненайденной
+	if booking.CustomerID == nil || *booking.CustomerID != customerID {
+		return store.ErrBookingNotFound
+	}
+
+	return s.Cancel(id, model.BookingCancelledByClient)
+}
+
+func (s *BookingServiceImpl) Confirm(id uint64) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.changeStatus(booking, model.BookingStatusConfirmed); err != nil {
+		return err
+	}
+
+	s.notify(notifier.EventBookingConfirmed, id)
+	return nil
+}
+
+func (s *BookingServiceImpl) CheckIn(id uint64) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// гости могут прийти немного раньше, но не за несколько часов и не в другой день
+	if time.Now().Add(checkInAdvance).Before(booking.StartsAt()) {
+		return ErrBookingTooEarly
+	}
+
+	return s.changeStatus(booking, model.BookingStatusSeated)
+}
+
+func (s *BookingServiceImpl) MarkNoShow(id uint64) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// пока время брони не наступило, гости ещё могут прийти
+	if time.Now().Before(booking.StartsAt()) {
+		return ErrBookingNotStarted
+	}
+
+	if err = s.changeStatus(booking, model.BookingStatusNoShow); err != nil {
+		return err
+	}
+
+	s.offerFreedCapacity(booking.RestaurantID)
+	return nil
+}
+
+func (s *BookingServiceImpl) Complete(id uint64) error {
+	booking, err := s.bookingRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.changeStatus(booking, model.BookingStatusCompleted); err != nil {
+		return err
+	}
+
+	// гости могли уйти раньше конца брони, и её столики освободились
+	s.offerFreedCapacity(booking.RestaurantID)
+	return nil
+}
+
+// notify уведомляет гостя о событии event с бронью bookingID и заново планирует напоминание о брони.
+func (s *BookingServiceImpl) notify(event string, bookingID uint64) {
+	notifyGuest(s.notifier, s.bookingRepo, s.restaurantRepo, event, bookingID)
+	scheduleReminder(s.reminders, bookingID)
+}
+
+// changeStatus переводит бронь в статус to, если это допускает жизненный цикл брони (model.CanChangeBookingStatus).
+func (s *BookingServiceImpl) changeStatus(booking *model.Booking, to string) error {
+	if !model.CanChangeBookingStatus(booking.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrBookingStatusTransition, booking.Status, to)
+	}
+
+	changed, err := s.bookingRepo.UpdateStatus(booking.ID, booking.Status, to)
+	if err != nil {
+		return err
+	}
+	// статус брони изменился одновременно с нами, и переход, который мы проверили, уже не актуален
+	if !changed {
+		return fmt.Errorf("%w: the booking status has been changed concurrently", ErrBookingStatusTransition)
+	}
+	return nil
+}
+
+func (s *BookingServiceImpl) GetAvailability(
+	restaurantID uint64, date, peopleNumber, zone string,
+) (*model.Availability, error) {
+	day, err := time.ParseInLocation("2006.01.02", date, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+
+	peopleNum, err := parsePeopleNumber(peopleNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// пустая зона означает, что гостю подойдёт любая зона
+	if zone != "" && !model.IsZone(zone) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidData, model.ErrUnknownZone.Error())
+	}
+
+	restaurant, err := getRestaurant(s.restaurantRepo, s.hoursRepo, s.policyRepo, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := restaurant.DurationPolicy.DurationFor(peopleNum)
+
+	// в сетку попадают моменты, на которые ресторан принимает брони и которые ещё не наступили
+	slots := bookingSlots(restaurant, day, day.AddDate(0, 0, 1))
+
+	availableSlots, err := slotsAvailability(s.tableRepo, restaurant, slots, peopleNum, duration, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Availability{
+		Date:         model.ShortFormattedDate(day),
+		PeopleNumber: peopleNum,
+		Duration:     int(duration / time.Minute),
+		Slots:        availableSlots,
+	}, nil
+}
+
+// bookingSlots возвращает моменты начала брони с шагом availabilitySlotStep в промежутке [from, to), на которые
+// ресторан принимает брони и которые ещё не наступили.
+func bookingSlots(restaurant *model.Restaurant, from, to time.Time) []time.Time {
+	now := time.Now()
+
+	var slots []time.Time
+	for slot := from; slot.Before(to); slot = slot.Add(availabilitySlotStep) {
+		// время брони указывается по часам ресторана, поэтому с текущим моментом сравнивается местное время
+		startsAt := time.Date(slot.Year(), slot.Month(), slot.Day(), slot.Hour(), slot.Minute(), 0, 0, time.Local)
+		if restaurant.AcceptsBookingAt(slot) && startsAt.After(now) {
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// slotsAvailability возвращает для каждого из моментов начала брони slots количество мест за столиками ресторана,
+// свободными на всё время брони duration, и признак того, можно ли рассадить за ними компанию из peopleNum человек.
+// Свободные столики для всех моментов получаются одним запросом к хранилищу.
+func slotsAvailability(
+	tableRepo store.TableRepository, restaurant *model.Restaurant, slots []time.Time, peopleNum int,
+	duration time.Duration, zone string,
+) ([]model.AvailabilitySlot, error) {
+	availableSlots := make([]model.AvailabilitySlot, 0, len(slots))
+	if len(slots) == 0 {
+		return availableSlots, nil
+	}
+
+	tablesBySlots, err := tableRepo.GetAllAvailableBySlots(restaurant.ID, slots, duration, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	// сдвигать можно только те столики, которые рядом стоят по схеме зала ресторана
+	restaurantTables, err := tableRepo.GetAll(restaurant.ID)
+	if err != nil {
+		return nil, err
+	}
+	allocator, join := allocatorFor(restaurant.AllocationStrategy), joinFuncFor(restaurantTables)
+
+	for i, slot := range slots {
+		_, err = allocator.Allocate(tablesBySlots[i], peopleNum, join)
+		if err != nil && !errors.Is(err, ErrNotEnoughSeatsInRestaurant) {
+			return nil, err
+		}
+
+		availableSlots = append(availableSlots, model.AvailabilitySlot{
+			Time:                 model.NewShortFormattedTime(slot.Hour(), slot.Minute()),
+			AvailableSeatsNumber: totalSeats(tablesBySlots[i]),
+			Bookable:             err == nil,
+		})
+	}
+	return availableSlots, nil
+}
+
+// offerFreedCapacity предлагает освободившиеся места гостям из листа ожидания ресторана. Бронь к этому моменту уже
+// изменена, поэтому ошибка не возвращается: места будут предложены, когда лист ожидания обработается в следующий раз.
+func (s *BookingServiceImpl) offerFreedCapacity(restaurantID uint64) {
+	if s.waitlist != nil {
+		_ = s.waitlist.OfferFreedCapacity(restaurantID)
+	}
+}
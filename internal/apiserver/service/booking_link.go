@@ -0,0 +1,125 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// BookingLinkService представляет бизнес-логику ссылок, по которым гость без учётной записи может посмотреть,
+// отменить или перенести свою бронь. Ссылка содержит токен вида "<ID брони>.<срок действия>.<подпись>",
+// подписанный HMAC-SHA256 ключом из настроек сервиса, поэтому подделать ссылку на чужую бронь нельзя.
+type BookingLinkService interface {
+	// Sign возвращает токен ссылки на бронь, действующий до конца брони.
+	Sign(bookingID uint64) (string, error)
+	// Get возвращает бронь по токену ссылки. Если токен подделан, возвращается ErrInvalidBookingLink,
+	// а если срок его действия истёк, - ErrBookingLinkExpired.
+	Get(token string) (*model.Booking, error)
+	// Cancel отменяет бронь по токену ссылки по просьбе гостя.
+	Cancel(token string) error
+	// Reschedule изменяет количество человек и (или) дату и время брони по токену ссылки так же, как
+	// BookingService.Update, и возвращает токен новой ссылки: прежняя ссылка действует до прежнего конца брони.
+	Reschedule(token string, data model.UpdateBookingData) (string, error)
+}
+
+// BookingLinkServiceImpl представляет реализацию BookingLinkService.
+type BookingLinkServiceImpl struct {
+	bookingRepo    store.BookingRepository
+	bookingService BookingService
+	// key представляет ключ, которым подписываются ссылки на брони
+	key []byte
+}
+
+func NewBookingLinkService(
+	bookingRepo store.BookingRepository, bookingService BookingService, key string,
+) *BookingLinkServiceImpl {
+	return &BookingLinkServiceImpl{
+		bookingRepo:    bookingRepo,
+		bookingService: bookingService,
+		key:            []byte(key),
+	}
+}
+
+func (s *BookingLinkServiceImpl) Sign(bookingID uint64) (string, error) {
+	booking, err := s.bookingRepo.Get(bookingID)
+	if err != nil {
+		return "", err
+	}
+
+	payload := fmt.Sprintf("%d.%d", booking.ID, booking.EndsAt().Unix())
+	return payload + "." + s.signature(payload), nil
+}
+
+func (s *BookingLinkServiceImpl) Get(token string) (*model.Booking, error) {
+	bookingID, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.bookingRepo.Get(bookingID)
+}
+
+func (s *BookingLinkServiceImpl) Cancel(token string) error {
+	bookingID, err := s.verify(token)
+	if err != nil {
+		return err
+	}
+	return s.bookingService.Cancel(bookingID, model.BookingCancelledByClient)
+}
+
+func (s *BookingLinkServiceImpl) Reschedule(token string, data model.UpdateBookingData) (string, error) {
+	bookingID, err := s.verify(token)
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.bookingService.Update(bookingID, data); err != nil {
+		return "", err
+	}
+	return s.Sign(bookingID)
+}
+
+// verify проверяет подпись и срок действия токена ссылки и возвращает ID брони из него.
+func (s *BookingLinkServiceImpl) verify(token string) (uint64, error) {
+	separator := strings.LastIndexByte(token, '.')
+	if separator < 0 {
+		return 0, ErrInvalidBookingLink
+	}
+	payload, signature := token[:separator], token[separator+1:]
+
+	// подписи сравниваются за постоянное время, чтобы подпись нельзя было подобрать по времени ответа
+	if !hmac.Equal([]byte(signature), []byte(s.signature(payload))) {
+		return 0, ErrInvalidBookingLink
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 2 {
+		return 0, ErrInvalidBookingLink
+	}
+	bookingID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidBookingLink
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidBookingLink
+	}
+
+	if !time.Now().Before(time.Unix(expiresAt, 0)) {
+		return 0, ErrBookingLinkExpired
+	}
+	return bookingID, nil
+}
+
+// signature возвращает подпись HMAC-SHA256 данных payload в кодировке base64url.
+func (s *BookingLinkServiceImpl) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
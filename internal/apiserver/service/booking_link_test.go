@@ -0,0 +1,189 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// testBookingLinkKey представляет ключ, которым подписываются ссылки на брони в тестах.
+const testBookingLinkKey = "test-booking-link-key"
+
+// bookingLinkFixture представляет бронь через неделю в 19:00 и сервисы, которые выдают ссылки на неё.
+type bookingLinkFixture struct {
+	services  *Services
+	links     *BookingLinkServiceImpl
+	bookingID uint64
+}
+
+func newBookingLinkFixture(t *testing.T) *bookingLinkFixture {
+	t.Helper()
+
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, seats := range []int{2, 4} {
+		if _, err = st.Tables().Create(restaurantID, seats, "", model.ZoneHall); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+	bookingID, err := services.BookingService.Create(model.BookingDetails{
+		RestaurantID:    restaurantID,
+		PeopleNumber:    "2",
+		DesiredDatetime: weekAt(19, 0),
+		ClientName:      "Павел",
+		ClientPhone:     "+79485722648",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &bookingLinkFixture{
+		services:  services,
+		links:     services.BookingLinkService.(*BookingLinkServiceImpl),
+		bookingID: bookingID,
+	}
+}
+
+// weekAt возвращает момент через неделю в hour:minute в формате желаемых даты и времени брони.
+func weekAt(hour, minute int) string {
+	week := time.Now().AddDate(0, 0, 7)
+	return time.Date(week.Year(), week.Month(), week.Day(), hour, minute, 0, 0, time.Local).Format("2006.01.02 15:04")
+}
+
+func TestBookingLinkService_Get(t *testing.T) {
+	f := newBookingLinkFixture(t)
+
+	token, err := f.links.Sign(f.bookingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	booking, err := f.links.Get(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booking.ID != f.bookingID {
+		t.Errorf("Get() = booking %d, want %d", booking.ID, f.bookingID)
+	}
+
+	// ссылка действует до конца брони
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[1] != fmt.Sprint(booking.EndsAt().Unix()) {
+		t.Errorf("token = %q, want it to expire at the end of the booking (%d)", token, booking.EndsAt().Unix())
+	}
+}
+
+func TestBookingLinkService_Tampered(t *testing.T) {
+	f := newBookingLinkFixture(t)
+
+	token, err := f.links.Sign(f.bookingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	payload, signature := parts[0]+"."+parts[1], parts[2]
+
+	// ссылка, подписанная другим ключом (например, до смены ключа в настройках)
+	otherKey := NewBookingLinkService(nil, nil, "other-key")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "another booking", token: fmt.Sprintf("%d.%s.%s", f.bookingID+1, parts[1], signature)},
+		{name: "extended expiry", token: fmt.Sprintf("%s.%d.%s", parts[0], time.Now().AddDate(1, 0, 0).Unix(), signature)},
+		{name: "changed signature", token: payload + "." + strings.Repeat("A", len(signature))},
+		{name: "no signature", token: payload + "."},
+		{name: "only payload", token: payload},
+		{name: "other key", token: payload + "." + otherKey.signature(payload)},
+		{name: "extra part", token: payload + ".1." + f.links.signature(payload+".1")},
+		{name: "not a number", token: "abc." + parts[1] + "." + f.links.signature("abc."+parts[1])},
+		{name: "empty", token: ""},
+		{name: "dots", token: ".."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.links.Get(tt.token); !errors.Is(err, ErrInvalidBookingLink) {
+				t.Errorf("Get(%q) = %v, want ErrInvalidBookingLink", tt.token, err)
+			}
+			if err := f.links.Cancel(tt.token); !errors.Is(err, ErrInvalidBookingLink) {
+				t.Errorf("Cancel(%q) = %v, want ErrInvalidBookingLink", tt.token, err)
+			}
+		})
+	}
+
+	// бронь не отменена по подделанным ссылкам
+	booking, err := f.services.BookingService.Get(f.bookingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booking.IsCancelled() {
+		t.Error("booking is cancelled by a tampered link")
+	}
+}
+
+func TestBookingLinkService_Expired(t *testing.T) {
+	f := newBookingLinkFixture(t)
+
+	// ссылка с верной подписью, срок действия которой истёк
+	for _, expiresAt := range []time.Time{time.Now().Add(-time.Minute), time.Now().Truncate(time.Second)} {
+		payload := fmt.Sprintf("%d.%d", f.bookingID, expiresAt.Unix())
+		token := payload + "." + f.links.signature(payload)
+
+		if _, err := f.links.Get(token); !errors.Is(err, ErrBookingLinkExpired) {
+			t.Errorf("Get() with a link expired at %s = %v, want ErrBookingLinkExpired", expiresAt, err)
+		}
+		later := weekAt(20, 0)
+		if _, err := f.links.Reschedule(token, model.UpdateBookingData{DesiredDatetime: &later}); !errors.Is(err, ErrBookingLinkExpired) {
+			t.Errorf("Reschedule() with a link expired at %s = %v, want ErrBookingLinkExpired", expiresAt, err)
+		}
+	}
+}
+
+func TestBookingLinkService_Reschedule(t *testing.T) {
+	f := newBookingLinkFixture(t)
+
+	token, err := f.links.Sign(f.bookingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	later := weekAt(20, 0)
+	newToken, err := f.links.Reschedule(token, model.UpdateBookingData{DesiredDatetime: &later})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newToken == token {
+		t.Fatal("Reschedule() returned the previous token, want a token until the new end of the booking")
+	}
+
+	// обе ссылки ведут к перенесённой брони, а отменить её можно по новой ссылке
+	for _, link := range []string{token, newToken} {
+		booking, err := f.links.Get(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := booking.BookedTimeFrom.String(); got != "20:00" {
+			t.Errorf("booking time = %s, want 20:00", got)
+		}
+	}
+	if err = f.links.Cancel(newToken); err != nil {
+		t.Fatal(err)
+	}
+	booking, err := f.links.Get(newToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !booking.IsCancelled() || booking.CancelledBy != model.BookingCancelledByClient {
+		t.Errorf("booking status = %s cancelled by %q, want cancelled by the client", booking.Status, booking.CancelledBy)
+	}
+}
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+func TestBookingService_UpdatePublishesEvent(t *testing.T) {
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = st.Tables().Create(restaurantID, 4, "", model.ZoneHall); err != nil {
+		t.Fatal(err)
+	}
+
+	hub := pubsub.NewHub()
+	services := NewServices(st, "test-admin-key", nil, nil, 0, "test-booking-link-key", hub)
+
+	week := time.Now().AddDate(0, 0, 7)
+	at := func(hour int) string {
+		return time.Date(week.Year(), week.Month(), week.Day(), hour, 0, 0, 0, time.Local).Format("2006.01.02 15:04")
+	}
+	bookingID, err := services.BookingService.Create(model.BookingDetails{
+		RestaurantID:    restaurantID,
+		PeopleNumber:    "2",
+		DesiredDatetime: at(19),
+		ClientName:      "Павел",
+		ClientPhone:     "+79485722648",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, unsubscribe := hub.Subscribe(restaurantID)
+	defer unsubscribe()
+
+	// перенос брони на время, когда ресторан закрыт, не меняет бронь, поэтому событие не публикуется
+	closed := at(23)
+	if err = services.BookingService.Update(bookingID, model.UpdateBookingData{DesiredDatetime: &closed}); err == nil {
+		t.Fatal("Update() to closing time succeeded, want error")
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("failed update published %s event", event.Event)
+	default:
+	}
+
+	// хосты получают перенесённую бронь с новым временем и количеством гостей
+	later, peopleNumber := at(20), 3
+	if err = services.BookingService.Update(bookingID, model.UpdateBookingData{
+		DesiredDatetime: &later,
+		PeopleNumber:    &peopleNumber,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-events:
+		if event.Event != model.RestaurantEventBookingUpdated {
+			t.Fatalf("event = %s, want %s", event.Event, model.RestaurantEventBookingUpdated)
+		}
+		if event.Booking == nil || event.Booking.ID != bookingID {
+			t.Fatalf("event booking = %+v, want booking %d", event.Booking, bookingID)
+		}
+		if got := event.Booking.BookedTimeFrom.String(); got != "20:00" {
+			t.Errorf("event booking time = %s, want 20:00", got)
+		}
+		if event.Booking.PeopleNumber != peopleNumber {
+			t.Errorf("event booking people number = %d, want %d", event.Booking.PeopleNumber, peopleNumber)
+		}
+	default:
+		t.Fatal("update did not publish an event")
+	}
+}
+
+func TestBookingService_Cancel(t *testing.T) {
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = st.Tables().Create(restaurantID, 4, "", model.ZoneHall); err != nil {
+		t.Fatal(err)
+	}
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+
+	details := model.BookingDetails{
+		RestaurantID:    restaurantID,
+		PeopleNumber:    "4",
+		DesiredDatetime: weekAt(19, 0),
+		ClientName:      "Павел",
+		ClientPhone:     "+79485722648",
+	}
+	bookingID, err := services.BookingService.Create(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// чужую бронь клиент не видит и не может отменить
+	if err = services.BookingService.CancelByClient(bookingID, "+79031234567"); !errors.Is(err, store.ErrBookingNotFound) {
+		t.Fatalf("CancelByClient() with another phone = %v, want ErrBookingNotFound", err)
+	}
+
+	// телефон можно указать в любом виде
+	if err = services.BookingService.CancelByClient(bookingID, "8 (948) 572-26-48"); err != nil {
+		t.Fatal(err)
+	}
+	booking, err := services.BookingService.Get(bookingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !booking.IsCancelled() || booking.CancelledBy != model.BookingCancelledByClient {
+		t.Errorf("booking status = %s cancelled by %q, want cancelled by the client", booking.Status, booking.CancelledBy)
+	}
+
+	if err = services.BookingService.Cancel(bookingID, model.BookingCancelledByRestaurant); !errors.Is(err, ErrBookingAlreadyCancelled) {
+		t.Errorf("Cancel() of a cancelled booking = %v, want ErrBookingAlreadyCancelled", err)
+	}
+
+	// столик отменённой брони снова можно забронировать на то же время
+	if _, err = services.BookingService.Create(details); err != nil {
+		t.Errorf("Create() at the time of the cancelled booking = %v, want the freed table", err)
+	}
+}
+
+func TestBookingService_CancelInPast(t *testing.T) {
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+
+	// бронь началась час назад
+	startedAt := time.Now().Add(-time.Hour)
+	bookingID, err := st.Bookings().Create(
+		restaurantID, 0, "Павел", "+79485722648", "", 2, model.BookingStatusConfirmed, startedAt, startedAt, 2*time.Hour,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = services.BookingService.Cancel(bookingID, model.BookingCancelledByRestaurant); !errors.Is(err, ErrBookingInPast) {
+		t.Errorf("Cancel() of a started booking = %v, want ErrBookingInPast", err)
+	}
+}
+
+func TestBookingService_GetAvailability(t *testing.T) {
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, seats := range []int{2, 4} {
+		if _, err = st.Tables().Create(restaurantID, seats, "", model.ZoneHall); err != nil {
+			t.Fatal(err)
+		}
+	}
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+
+	// столик на 4 места занят с 19:00 до 21:00
+	if _, err = services.BookingService.Create(model.BookingDetails{
+		RestaurantID:    restaurantID,
+		PeopleNumber:    "4",
+		DesiredDatetime: weekAt(19, 0),
+		ClientName:      "Павел",
+		ClientPhone:     "+79485722648",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Now().AddDate(0, 0, 7).Format("2006.01.02")
+	availability, err := services.BookingService.GetAvailability(restaurantID, date, "4", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if availability.Date.String() != date || availability.PeopleNumber != 4 || availability.Duration != model.DefaultBookingDuration {
+		t.Errorf("availability = %s for %d people for %d minutes, want %s for 4 people for %d minutes",
+			availability.Date, availability.PeopleNumber, availability.Duration, date, model.DefaultBookingDuration)
+	}
+
+	// по графику по умолчанию брони начинаются с 9:00 до 21:00 каждые 15 минут
+	slots := make(map[string]model.AvailabilitySlot, len(availability.Slots))
+	for _, slot := range availability.Slots {
+		slots[slot.Time.String()] = slot
+	}
+	if len(availability.Slots) != 49 || availability.Slots[0].Time.String() != "09:00" ||
+		availability.Slots[len(availability.Slots)-1].Time.String() != "21:00" {
+		t.Fatalf("slots = %+v, want every 15 minutes from 09:00 to 21:00", availability.Slots)
+	}
+
+	tests := []struct {
+		time         string
+		wantSeats    int
+		wantBookable bool
+	}{
+		{time: "09:00", wantSeats: 6, wantBookable: true},
+		{time: "16:45", wantSeats: 6, wantBookable: true},
+		// брони, которые пересекаются с занятым столиком, компании из 4 человек не подходят
+		{time: "17:15", wantSeats: 2, wantBookable: false},
+		{time: "20:45", wantSeats: 2, wantBookable: false},
+	}
+	for _, tt := range tests {
+		slot, ok := slots[tt.time]
+		if !ok {
+			t.Errorf("no slot at %s", tt.time)
+			continue
+		}
+		if slot.AvailableSeatsNumber != tt.wantSeats || slot.Bookable != tt.wantBookable {
+			t.Errorf("slot at %s = %d seats, bookable %t; want %d seats, bookable %t",
+				tt.time, slot.AvailableSeatsNumber, slot.Bookable, tt.wantSeats, tt.wantBookable)
+		}
+	}
+
+	// в зоне без столиков свободных мест нет
+	availability, err = services.BookingService.GetAvailability(restaurantID, date, "2", model.ZoneTerrace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slot := range availability.Slots {
+		if slot.AvailableSeatsNumber != 0 || slot.Bookable {
+			t.Fatalf("terrace slot at %s = %+v, want no seats", slot.Time, slot)
+		}
+	}
+
+	// на прошедший день брони уже не оформить
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006.01.02")
+	if availability, err = services.BookingService.GetAvailability(restaurantID, yesterday, "2", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(availability.Slots) != 0 {
+		t.Errorf("slots for yesterday = %+v, want none", availability.Slots)
+	}
+}
+
+func TestBookingService_GetAvailabilityInvalid(t *testing.T) {
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+	date := time.Now().AddDate(0, 0, 7).Format("2006.01.02")
+
+	tests := []struct {
+		name         string
+		date         string
+		peopleNumber string
+		zone         string
+	}{
+		{name: "date with time", date: weekAt(19, 0), peopleNumber: "2"},
+		{name: "no people", date: date, peopleNumber: "0"},
+		{name: "not a number", date: date, peopleNumber: "два"},
+		{name: "unknown zone", date: date, peopleNumber: "2", zone: "roof"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := services.BookingService.GetAvailability(restaurantID, tt.date, tt.peopleNumber, tt.zone)
+			if !errors.Is(err, ErrInvalidData) {
+				t.Errorf("GetAvailability() = %v, want ErrInvalidData", err)
+			}
+		})
+	}
+}
+
+func TestBookingService_StatusLifecycle(t *testing.T) {
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+
+	create := func(status string, startsAt time.Time) uint64 {
+		t.Helper()
+
+		bookingID, err := st.Bookings().Create(
+			restaurantID, 0, "Павел", "+79485722648", "", 2, status, startsAt, startsAt, 2*time.Hour,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bookingID
+	}
+	wantStatus := func(bookingID uint64, want string) {
+		t.Helper()
+
+		booking, err := services.BookingService.Get(bookingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if booking.Status != want {
+			t.Errorf("booking %d status = %s, want %s", bookingID, booking.Status, want)
+		}
+	}
+
+	// до брони через неделю гости ещё не пришли и могут прийти
+	later := create(model.BookingStatusPending, time.Now().AddDate(0, 0, 7))
+	if err = services.BookingService.CheckIn(later); !errors.Is(err, ErrBookingTooEarly) {
+		t.Errorf("CheckIn() a week before the booking = %v, want ErrBookingTooEarly", err)
+	}
+	if err = services.BookingService.MarkNoShow(later); !errors.Is(err, ErrBookingNotStarted) {
+		t.Errorf("MarkNoShow() before the booking = %v, want ErrBookingNotStarted", err)
+	}
+	if err = services.BookingService.Confirm(later); err != nil {
+		t.Fatal(err)
+	}
+	wantStatus(later, model.BookingStatusConfirmed)
+
+	// гости пришли за полчаса до брони, а затем ушли
+	soon := create(model.BookingStatusConfirmed, time.Now().Add(30*time.Minute))
+	if err = services.BookingService.CheckIn(soon); err != nil {
+		t.Fatal(err)
+	}
+	wantStatus(soon, model.BookingStatusSeated)
+	if err = services.BookingService.Cancel(soon, model.BookingCancelledByRestaurant); !errors.Is(err, ErrBookingStatusTransition) {
+		t.Errorf("Cancel() of a seated booking = %v, want ErrBookingStatusTransition", err)
+	}
+	if err = services.BookingService.Complete(soon); err != nil {
+		t.Fatal(err)
+	}
+	wantStatus(soon, model.BookingStatusCompleted)
+	if err = services.BookingService.Confirm(soon); !errors.Is(err, ErrBookingStatusTransition) {
+		t.Errorf("Confirm() of a completed booking = %v, want ErrBookingStatusTransition", err)
+	}
+
+	// гости не пришли к началу брони
+	started := create(model.BookingStatusConfirmed, time.Now().Add(-30*time.Minute))
+	if err = services.BookingService.Complete(started); !errors.Is(err, ErrBookingStatusTransition) {
+		t.Errorf("Complete() of a booking without guests = %v, want ErrBookingStatusTransition", err)
+	}
+	if err = services.BookingService.MarkNoShow(started); err != nil {
+		t.Fatal(err)
+	}
+	wantStatus(started, model.BookingStatusNoShow)
+	if err = services.BookingService.CheckIn(started); !errors.Is(err, ErrBookingStatusTransition) {
+		t.Errorf("CheckIn() after a no-show = %v, want ErrBookingStatusTransition", err)
+	}
+}
+
+func TestRetryTableConflicts(t *testing.T) {
+	// столики заняты одновременно с нами один раз, а со второй попытки бронь оформляется
+	attempts := 0
+	err := retryTableConflicts(func() error {
+		attempts++
+		if attempts == 1 {
+			return store.ErrTableAlreadyBooked
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("retryTableConflicts() = %v after %d attempts, want success after 2 attempts", err, attempts)
+	}
+
+	// другие ошибки не повторяются
+	attempts = 0
+	err = retryTableConflicts(func() error {
+		attempts++
+		return ErrInvalidData
+	})
+	if !errors.Is(err, ErrInvalidData) || attempts != 1 {
+		t.Errorf("retryTableConflicts() = %v after %d attempts, want ErrInvalidData after 1 attempt", err, attempts)
+	}
+
+	attempts = 0
+	err = retryTableConflicts(func() error {
+		attempts++
+		return store.ErrTableAlreadyBooked
+	})
+	if !errors.Is(err, ErrNotEnoughSeatsInRestaurant) || attempts != maxBookingAttempts {
+		t.Errorf("retryTableConflicts() = %v after %d attempts, want ErrNotEnoughSeatsInRestaurant after %d attempts",
+			err, attempts, maxBookingAttempts)
+	}
+}
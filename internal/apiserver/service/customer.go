@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	// customerSessionTTL представляет срок действия сеанса гостя на сайте.
+	customerSessionTTL = 30 * 24 * time.Hour
+	// minPasswordLength представляет минимальную длину пароля гостя.
+	minPasswordLength = 8
+)
+
+// CustomerService представляет бизнес-логику работы с учётными записями гостей на сайте.
+type CustomerService interface {
+	// Register создаёт учётную запись гостя и сразу начинает его сеанс. Если учётная запись с таким телефоном уже есть,
+	// возвращается store.ErrCustomerAlreadyExists.
+	Register(name, phone, password string) (*model.CustomerSession, error)
+	// Login начинает сеанс гостя по его телефону и паролю. Если они не подходят, возвращается ErrInvalidCredentials.
+	Login(phone, password string) (*model.CustomerSession, error)
+	// Authenticate возвращает гостя по токену его действующего сеанса. Если сеанс не найден или истёк,
+	// возвращается store.ErrCustomerNotFound.
+	Authenticate(token string) (*model.Customer, error)
+	// Logout завершает сеанс гостя.
+	Logout(token string) error
+	// GetBookingHistory возвращает предстоящие и прошедшие (в том числе отменённые) брони гостя.
+	GetBookingHistory(customerID uint64) (*model.BookingHistory, error)
+}
+
+// CustomerServiceImpl представляет реализацию CustomerService.
+type CustomerServiceImpl struct {
+	customerRepo   store.CustomerRepository
+	bookingRepo    store.BookingRepository
+	restaurantRepo store.RestaurantRepository
+}
+
+func NewCustomerService(
+	customerRepo store.CustomerRepository,
+	bookingRepo store.BookingRepository,
+	restaurantRepo store.RestaurantRepository,
+) *CustomerServiceImpl {
+	return &CustomerServiceImpl{
+		customerRepo:   customerRepo,
+		bookingRepo:    bookingRepo,
+		restaurantRepo: restaurantRepo,
+	}
+}
+
+func (s *CustomerServiceImpl) Register(name, phone, password string) (*model.CustomerSession, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: the customer's name is required", ErrInvalidData)
+	}
+
+	// телефон хранится в формате E.164, как и в бронях гостя
+	phone, err := model.NormalizePhone(phone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+
+	if len([]rune(password)) < minPasswordLength {
+		return nil, fmt.Errorf("%w: the password must be at least %d characters long", ErrInvalidData, minPasswordLength)
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.customerRepo.Create(name, phone, passwordHash)
+	if errors.Is(err, store.ErrCustomerAlreadyExists) {
+		return nil, store.ErrCustomerAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.startSession(customer)
+}
+
+func (s *CustomerServiceImpl) Login(phone, password string) (*model.CustomerSession, error) {
+	// телефон, который нельзя привести к формату E.164, не принадлежит ни одному гостю
+	if normalizedPhone, err := model.NormalizePhone(phone); err == nil {
+		phone = normalizedPhone
+	}
+
+	customer, passwordHash, err := s.customerRepo.GetByPhone(phone)
+	if errors.Is(err, store.ErrCustomerNotFound) {
+		// хешируем пароль и для несуществующего гостя, чтобы по времени ответа нельзя было узнать,
+		// зарегистрирован ли телефон
+		simulatePasswordCheck(password)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !checkPassword(passwordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return s.startSession(customer)
+}
+
+// startSession начинает сеанс гостя на customerSessionTTL.
+func (s *CustomerServiceImpl) startSession(customer *model.Customer) (*model.CustomerSession, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(customerSessionTTL)
+	if err = s.customerRepo.CreateSession(customer.ID, hashToken(token), expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &model.CustomerSession{Customer: customer, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+func (s *CustomerServiceImpl) Authenticate(token string) (*model.Customer, error) {
+	if token == "" {
+		return nil, store.ErrCustomerNotFound
+	}
+	return s.customerRepo.GetBySession(hashToken(token))
+}
+
+func (s *CustomerServiceImpl) Logout(token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.customerRepo.DeleteSession(hashToken(token))
+}
+
+func (s *CustomerServiceImpl) GetBookingHistory(customerID uint64) (*model.BookingHistory, error) {
+	bookings, err := s.bookingRepo.GetAllByCustomer(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurants, err := s.restaurantRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	restaurantNames := make(map[uint64]string, len(restaurants))
+	for _, restaurant := range restaurants {
+		restaurantNames[restaurant.ID] = restaurant.Name
+	}
+
+	history := &model.BookingHistory{
+		Upcoming: make([]model.CustomerBooking, 0),
+		Past:     make([]model.CustomerBooking, 0),
+	}
+	now := time.Now()
+	for _, booking := range bookings {
+		customerBooking := model.CustomerBooking{
+			Booking:        booking,
+			RestaurantName: restaurantNames[booking.RestaurantID],
+		}
+		if booking.IsActive() && booking.StartsAt().After(now) {
+			history.Upcoming = append(history.Upcoming, customerBooking)
+		} else {
+			history.Past = append(history.Past, customerBooking)
+		}
+	}
+
+	// ближайшие брони показываются первыми, а прошедшие - начиная с самой поздней
+	sort.SliceStable(history.Upcoming, func(i, j int) bool {
+		return history.Upcoming[i].StartsAt().Before(history.Upcoming[j].StartsAt())
+	})
+	sort.SliceStable(history.Past, func(i, j int) bool {
+		return history.Past[i].StartsAt().After(history.Past[j].StartsAt())
+	})
+	return history, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+func TestCustomer_RegisterLoginLogout(t *testing.T) {
+	st := memory.NewStore()
+	s := NewCustomerService(st.Customers(), st.Bookings(), st.Restaurants())
+
+	session, err := s.Register("Павел", "8 (927) 900-72-65", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Token == "" || session.Customer.Phone != "+79279007265" || !session.ExpiresAt.After(time.Now()) {
+		t.Fatalf("Register() = %+v, want a session of the customer with the phone in E.164", session)
+	}
+	customer, passwordHash, err := st.Customers().GetByPhone("+79279007265")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(passwordHash, "correct horse") {
+		t.Errorf("the password is stored in plain text: %q", passwordHash)
+	}
+
+	// телефон в другом формате принадлежит той же учётной записи
+	if _, err = s.Register("Павел", "+7 927 900 72 65", "another password"); !errors.Is(err, store.ErrCustomerAlreadyExists) {
+		t.Errorf("Register() with the same phone: error = %v, want store.ErrCustomerAlreadyExists", err)
+	}
+
+	authenticated, err := s.Authenticate(session.Token)
+	if err != nil || authenticated.ID != customer.ID {
+		t.Fatalf("Authenticate() = %+v, %v; want customer %d", authenticated, err, customer.ID)
+	}
+
+	for _, credentials := range []struct{ phone, password string }{
+		{phone: "+79279007265", password: "correct hors"},
+		{phone: "+79279007265", password: "Correct horse"},
+		{phone: "+79279007265", password: ""},
+		{phone: "+79485722648", password: "correct horse"},
+		{phone: "not a phone", password: "correct horse"},
+	} {
+		if _, err = s.Login(credentials.phone, credentials.password); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login(%q, %q): error = %v, want ErrInvalidCredentials", credentials.phone, credentials.password, err)
+		}
+	}
+
+	another, err := s.Login("89279007265", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if another.Token == session.Token || another.Customer.ID != customer.ID {
+		t.Fatalf("Login() = %+v, want a new session of customer %d", another, customer.ID)
+	}
+
+	// выход завершает только свой сеанс
+	if err = s.Logout(session.Token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Authenticate(session.Token); !errors.Is(err, store.ErrCustomerNotFound) {
+		t.Errorf("Authenticate() after logout: error = %v, want store.ErrCustomerNotFound", err)
+	}
+	if _, err = s.Authenticate(another.Token); err != nil {
+		t.Errorf("Authenticate() with another session: %v", err)
+	}
+}
+
+func TestCustomer_RegisterInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		customer string
+		phone    string
+		password string
+	}{
+		{name: "no name", phone: "+79279007265", password: "correct horse"},
+		{name: "invalid phone", customer: "Павел", phone: "12345", password: "correct horse"},
+		{name: "short password", customer: "Павел", phone: "+79279007265", password: "1234567"},
+		{name: "short cyrillic password", customer: "Павел", phone: "+79279007265", password: "пароль1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := memory.NewStore()
+			s := NewCustomerService(st.Customers(), st.Bookings(), st.Restaurants())
+
+			if _, err := s.Register(tt.customer, tt.phone, tt.password); !errors.Is(err, ErrInvalidData) {
+				t.Errorf("Register() error = %v, want ErrInvalidData", err)
+			}
+			if _, _, err := st.Customers().GetByPhone("+79279007265"); !errors.Is(err, store.ErrCustomerNotFound) {
+				t.Errorf("the customer was created: %v", err)
+			}
+		})
+	}
+}
+
+func TestCustomer_AuthenticateExpiredSession(t *testing.T) {
+	st := memory.NewStore()
+	s := NewCustomerService(st.Customers(), st.Bookings(), st.Restaurants())
+
+	id, err := st.Customers().Create("Павел", "+79279007265", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = st.Customers().CreateSession(id, hashToken("expired"), time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"expired", "", "unknown"} {
+		if _, err = s.Authenticate(token); !errors.Is(err, store.ErrCustomerNotFound) {
+			t.Errorf("Authenticate(%q): error = %v, want store.ErrCustomerNotFound", token, err)
+		}
+	}
+}
+
+func TestCustomer_GetBookingHistory(t *testing.T) {
+	st := memory.NewStore()
+	s := NewCustomerService(st.Customers(), st.Bookings(), st.Restaurants())
+
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableID, err := st.Tables().Create(restaurantID, 4, "", model.ZoneHall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customerID, err := st.Customers().Create("Павел", "+79279007265", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// бронь через неделю, бронь через две недели и бронь, которая уже прошла
+	book := func(day time.Time) uint64 {
+		t.Helper()
+
+		at := time.Date(day.Year(), day.Month(), day.Day(), 19, 0, 0, 0, time.Local)
+		id, err := st.Bookings().Create(
+			restaurantID, customerID, "Павел", "+79279007265", "", 2, model.BookingStatusConfirmed,
+			at, at, 2*time.Hour, tableID,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	inTwoWeeks := book(time.Now().AddDate(0, 0, 14))
+	inWeek := book(time.Now().AddDate(0, 0, 7))
+	past := book(time.Now().AddDate(0, 0, -7))
+
+	history, err := s.GetBookingHistory(customerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Upcoming) != 2 || history.Upcoming[0].ID != inWeek || history.Upcoming[1].ID != inTwoWeeks {
+		t.Errorf("upcoming bookings = %+v, want %d and %d", history.Upcoming, inWeek, inTwoWeeks)
+	}
+	if len(history.Past) != 1 || history.Past[0].ID != past || history.Past[0].RestaurantName != "Каравелла" {
+		t.Errorf("past bookings = %+v, want %d in Каравелла", history.Past, past)
+	}
+}
@@ -7,4 +7,37 @@ var (
 	ErrInvalidData = errors.New("invalid input data")
 	// ErrNotEnoughSeatsInRestaurant возникает в процессе создания брони, когда в ресторане не достаточно свободных мест.
 	ErrNotEnoughSeatsInRestaurant = errors.New("there are not enough seats in the restaurant to make a booking")
+	// ErrBookingAlreadyCancelled возникает при попытке отменить уже отменённую бронь.
+	ErrBookingAlreadyCancelled = errors.New("the booking has already been cancelled")
+	// ErrBookingInPast возникает при попытке отменить бронь, время которой уже наступило.
+	ErrBookingInPast = errors.New("the booking time has already passed")
+	// ErrBookingStatusTransition возникает при попытке перевести бронь в статус, в который её нельзя перевести
+	// из текущего статуса (например, отметить неявку гостей, которые уже сидят за столиками).
+	ErrBookingStatusTransition = errors.New("the booking status cannot be changed")
+	// ErrBookingTooEarly возникает при попытке отметить приход гостей задолго до начала брони.
+	ErrBookingTooEarly = errors.New("it is too early to check in the guests")
+	// ErrBookingNotStarted возникает при попытке отметить неявку гостей до начала брони.
+	ErrBookingNotStarted = errors.New("the booking time has not come yet")
+	// ErrGuestBlocked возникает, когда онлайн-бронирование в ресторане заблокировано для гостя из-за неявок
+	// (подробности содержит GuestBlockedError).
+	ErrGuestBlocked = errors.New("online booking is blocked for the guest due to repeated no-shows")
+	// ErrHoldExpired возникает при попытке оформить бронь по удержанию столиков, срок которого уже истёк.
+	ErrHoldExpired = errors.New("the hold has expired, the tables are no longer reserved")
+	// ErrTooManyHolds возникает при попытке удержать столики, когда у клиента уже maxActiveHoldsPerClient
+	// действующих удержаний.
+	ErrTooManyHolds = errors.New("too many active holds: confirm or release one of them first")
+	// ErrWaitlistNoOffer возникает при попытке оформить бронь по записи в листе ожидания, гостю которой места
+	// не предлагались или срок предложения уже истёк.
+	ErrWaitlistNoOffer = errors.New("there is no active offer for the waitlist entry")
+	// ErrWaitlistEntryClosed возникает при попытке покинуть лист ожидания, когда гость уже не ждёт мест
+	// (бронь оформлена, предложение истекло или гость уже покинул лист ожидания).
+	ErrWaitlistEntryClosed = errors.New("the waitlist entry is no longer active")
+	// ErrInvalidAPIKey возникает, когда по переданному ключу API не находится ни одного пользователя.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrInvalidCredentials возникает, когда гость входит на сайт с неверным телефоном или паролем.
+	ErrInvalidCredentials = errors.New("invalid phone number or password")
+	// ErrInvalidBookingLink возникает, когда ссылка на бронь повреждена или подписана другим ключом.
+	ErrInvalidBookingLink = errors.New("invalid booking link")
+	// ErrBookingLinkExpired возникает, когда срок действия ссылки на бронь истёк.
+	ErrBookingLinkExpired = errors.New("the booking link has expired")
 )
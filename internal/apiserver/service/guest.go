@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// GuestService представляет бизнес-логику работы с гостями ресторанов.
+type GuestService interface {
+	// Get возвращает гостя по его ID.
+	Get(id uint64) (*model.Guest, error)
+	// GetByPhone возвращает гостя по телефону, записанному в любом виде (он приводится к формату E.164
+	// model.NormalizePhone).
+	GetByPhone(phone string) (*model.Guest, error)
+	// GetBookings возвращает список всех броней гостя (в том числе отменённых) во всех ресторанах.
+	GetBookings(guestID uint64) ([]model.Booking, error)
+	// GetStats возвращает статистику посещений гостем каждого ресторана, в котором у него есть брони
+	// (в порядке возрастания ID ресторана).
+	GetStats(guestID uint64) ([]model.GuestRestaurantStats, error)
+}
+
+// GuestServiceImpl представляет реализацию GuestService.
+type GuestServiceImpl struct {
+	guestRepo   store.GuestRepository
+	bookingRepo store.BookingRepository
+}
+
+func NewGuestService(guestRepo store.GuestRepository, bookingRepo store.BookingRepository) *GuestServiceImpl {
+	return &GuestServiceImpl{
+		guestRepo:   guestRepo,
+		bookingRepo: bookingRepo,
+	}
+}
+
+func (s *GuestServiceImpl) Get(id uint64) (*model.Guest, error) {
+	return s.guestRepo.Get(id)
+}
+
+func (s *GuestServiceImpl) GetByPhone(phone string) (*model.Guest, error) {
+	normalizedPhone, err := model.NormalizePhone(phone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+	return s.guestRepo.GetByPhone(normalizedPhone)
+}
+
+func (s *GuestServiceImpl) GetBookings(guestID uint64) ([]model.Booking, error) {
+	if _, err := s.guestRepo.Get(guestID); err != nil {
+		return nil, err
+	}
+	return s.bookingRepo.GetAllByGuest(guestID)
+}
+
+func (s *GuestServiceImpl) GetStats(guestID uint64) ([]model.GuestRestaurantStats, error) {
+	bookings, err := s.GetBookings(guestID)
+	if err != nil {
+		return nil, err
+	}
+
+	statsByRestaurant := make(map[uint64]*model.GuestRestaurantStats)
+	peopleNumbers := make(map[uint64]int)
+	now := time.Now()
+	for _, booking := range bookings {
+		stats, ok := statsByRestaurant[booking.RestaurantID]
+		if !ok {
+			stats = &model.GuestRestaurantStats{RestaurantID: booking.RestaurantID}
+			statsByRestaurant[booking.RestaurantID] = stats
+		}
+
+		stats.BookingsNumber++
+		switch {
+		case booking.IsCancelled():
+			stats.CancellationsNumber++
+			continue
+		case booking.IsNoShow():
+			stats.NoShowsNumber++
+			continue
+		}
+		peopleNumbers[booking.RestaurantID] += booking.PeopleNumber
+
+		// гость считается посетившим ресторан, если он отмечен пришедшим или если время брони, ждущей гостей,
+		// уже наступило (приход гостей отмечается не во всех ресторанах)
+		startsAt := booking.StartsAt()
+		if booking.IsActive() && startsAt.After(now) {
+			continue
+		}
+		stats.VisitsNumber++
+		if stats.LastVisitAt == nil || startsAt.After(*stats.LastVisitAt) {
+			stats.LastVisitAt = &startsAt
+		}
+	}
+
+	restaurantsStats := make([]model.GuestRestaurantStats, 0, len(statsByRestaurant))
+	for restaurantID, stats := range statsByRestaurant {
+		if activeBookings := stats.BookingsNumber - stats.CancellationsNumber - stats.NoShowsNumber; activeBookings > 0 {
+			stats.AveragePartySize = float64(peopleNumbers[restaurantID]) / float64(activeBookings)
+		}
+		restaurantsStats = append(restaurantsStats, *stats)
+	}
+	sort.Slice(restaurantsStats, func(i, j int) bool {
+		return restaurantsStats[i].RestaurantID < restaurantsStats[j].RestaurantID
+	})
+	return restaurantsStats, nil
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	// holdTTL представляет срок, на который удерживаются столики, пока гость заполняет форму брони.
+	holdTTL = 5 * time.Minute
+	// maxActiveHoldsPerClient представляет количество действующих удержаний, которые может создать один клиент:
+	// иначе, удерживая столики снова и снова, можно не давать другим гостям забронировать их.
+	maxActiveHoldsPerClient = 3
+)
+
+// HoldService представляет бизнес-логику работы с временными удержаниями столиков.
+type HoldService interface {
+	// Create подбирает столики для компании так же, как при создании брони, и удерживает их на holdTTL:
+	// до истечения срока другие гости не могут их забронировать. Возвращаемое удержание содержит секретный токен,
+	// без которого гость не может его получить, подтвердить или снять (см. GetByID). clientKey представляет ключ клиента (например,
+	// IP-адрес гостя): если у клиента уже maxActiveHoldsPerClient действующих удержаний, возвращается
+	// ErrTooManyHolds. Пустой ключ количество удержаний не ограничивает.
+	Create(details model.BookingDetails, clientKey string) (*model.Hold, error)
+	// Get возвращает удержание по его ID и токену. Если токен не подходит, возвращается store.ErrHoldNotFound.
+	Get(id uint64, token string) (*model.Hold, error)
+	// GetByID возвращает удержание по его ID без проверки токена. Используется для пользователей API, доступ которых
+	// к ресторану удержания проверяет вызывающий код.
+	GetByID(id uint64) (*model.Hold, error)
+	// Confirm оформляет бронь гостя на удерживаемые столики и снимает удержание (токен проверяется так же, как в Get).
+	// Если срок удержания истёк, возвращается ErrHoldExpired. customerID представляет ID учётной записи гостя
+	// (0 - бронь оформляется без учётной записи). Как и при создании брони, учитываются правила надёжности гостей
+	// ресторана: если онлайн-бронирование для гостя заблокировано, возвращается *GuestBlockedError. clientEmail
+	// представляет необязательный адрес электронной почты гостя для уведомлений о брони.
+	Confirm(id uint64, token string, customerID uint64, clientName, clientPhone, clientEmail string) (uint64, error)
+	// ConfirmByID оформляет бронь гостя на удерживаемые столики так же, как Confirm, но без проверки токена
+	// (см. GetByID).
+	ConfirmByID(id uint64, clientName, clientPhone, clientEmail string) (uint64, error)
+	// Release досрочно снимает удержание по его ID и токену (например, если гость передумал бронировать).
+	Release(id uint64, token string) error
+	// ReleaseByID досрочно снимает удержание по его ID без проверки токена (см. GetByID).
+	ReleaseByID(id uint64) error
+	// ReleaseExpired снимает все удержания, срок которых истёк, и возвращает количество ресторанов,
+	// в которых освободились столики.
+	ReleaseExpired() (int, error)
+}
+
+// HoldServiceImpl представляет реализацию HoldService.
+type HoldServiceImpl struct {
+	holdRepo       store.HoldRepository
+	tableRepo      store.TableRepository
+	restaurantRepo store.RestaurantRepository
+	hoursRepo      store.OpeningHoursRepository
+	policyRepo     store.DurationPolicyRepository
+	// guestRepo, bookingRepo и reliabilityRepo нужны для проверки гостя по правилам надёжности ресторана
+	guestRepo       store.GuestRepository
+	bookingRepo     store.BookingRepository
+	reliabilityRepo store.ReliabilityPolicyRepository
+	// waitlist получает места, освободившиеся после снятия удержаний
+	waitlist WaitlistService
+	// notifier уведомляет гостей об оформлении броней по удержаниям
+	notifier notifier.Notifier
+	// reminders планирует напоминания гостям о предстоящих бронях
+	reminders ReminderService
+	// events сообщает хостам ресторана об оформлении броней по удержаниям
+	events pubsub.Publisher
+}
+
+// HoldServiceDeps представляет зависимости HoldServiceImpl.
+type HoldServiceDeps struct {
+	HoldRepo        store.HoldRepository
+	TableRepo       store.TableRepository
+	RestaurantRepo  store.RestaurantRepository
+	HoursRepo       store.OpeningHoursRepository
+	PolicyRepo      store.DurationPolicyRepository
+	GuestRepo       store.GuestRepository
+	BookingRepo     store.BookingRepository
+	ReliabilityRepo store.ReliabilityPolicyRepository
+	// Waitlist, Notifier, Reminders и Events необязательны, как и у BookingServiceDeps
+	Waitlist  WaitlistService
+	Notifier  notifier.Notifier
+	Reminders ReminderService
+	Events    pubsub.Publisher
+}
+
+func NewHoldService(deps HoldServiceDeps) *HoldServiceImpl {
+	return &HoldServiceImpl{
+		holdRepo:        deps.HoldRepo,
+		tableRepo:       deps.TableRepo,
+		restaurantRepo:  deps.RestaurantRepo,
+		hoursRepo:       deps.HoursRepo,
+		policyRepo:      deps.PolicyRepo,
+		guestRepo:       deps.GuestRepo,
+		bookingRepo:     deps.BookingRepo,
+		reliabilityRepo: deps.ReliabilityRepo,
+		waitlist:        deps.Waitlist,
+		notifier:        deps.Notifier,
+		reminders:       deps.Reminders,
+		events:          deps.Events,
+	}
+}
+
+func (s *HoldServiceImpl) Create(details model.BookingDetails, clientKey string) (*model.Hold, error) {
+	dateTime, err := parseDesiredDatetime(details.DesiredDatetime)
+	if err != nil {
+		return nil, err
+	}
+
+	// удерживать столики имеет смысл только на время, когда ресторан принимает гостей по своему графику работы
+	restaurant, err := getRestaurant(s.restaurantRepo, s.hoursRepo, s.policyRepo, details.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if !restaurant.AcceptsBookingAt(dateTime) {
+		return nil, fmt.Errorf("%w: the restaurant is closed at the desired time", ErrInvalidData)
+	}
+
+	peopleNum, err := parsePeopleNumber(details.PeopleNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// пустая зона означает, что гостю подойдёт любая зона
+	if details.Zone != "" && !model.IsZone(details.Zone) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidData, model.ErrUnknownZone.Error())
+	}
+
+	// проверка не атомарна с созданием удержания, поэтому одновременными запросами клиент может немного превысить
+	// ограничение, но не удерживать сколько угодно столиков
+	if clientKey != "" {
+		active, err := s.holdRepo.CountActive(clientKey)
+		if err != nil {
+			return nil, err
+		}
+		if active >= maxActiveHoldsPerClient {
+			return nil, ErrTooManyHolds
+		}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	holdID, err := s.hold(restaurant, details, peopleNum, dateTime, time.Now().Add(holdTTL), hashToken(token), clientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := s.holdRepo.Get(holdID)
+	if err != nil {
+		return nil, err
+	}
+	hold.Token = token
+	return hold, nil
+}
+
+// hold подбирает свободные столики для компании из peopleNum человек и удерживает их до expiresAt. Возвращает ID
+// удержания. Столики удерживаются на столько же, на сколько будет оформлена бронь.
+func (s *HoldServiceImpl) hold(
+	restaurant *model.Restaurant, details model.BookingDetails, peopleNum int, dateTime, expiresAt time.Time,
+	tokenHash, clientKey string,
+) (uint64, error) {
+	duration := restaurant.DurationPolicy.DurationFor(peopleNum)
+
+	// как и при создании брони, повторяем попытку, если подобранные столики одновременно с нами занял другой клиент
+	var holdID uint64
+	err := retryTableConflicts(func() error {
+		var err error
+		holdID, err = s.holdTables(restaurant, details, peopleNum, dateTime, duration, expiresAt, tokenHash, clientKey)
+		return err
+	})
+	return holdID, err
+}
+
+// holdTables выбирает свободные столики для компании из peopleNum человек и удерживает их.
+func (s *HoldServiceImpl) holdTables(
+	restaurant *model.Restaurant, details model.BookingDetails, peopleNum int, dateTime time.Time, duration time.Duration,
+	expiresAt time.Time, tokenHash, clientKey string,
+) (uint64, error) {
+	tables, err := s.tableRepo.GetAllAvailable(details.RestaurantID, dateTime, duration, details.Zone)
+	if err != nil {
+		return 0, err
+	}
+
+	heldTables, err := allocateTables(s.tableRepo, restaurant, tables, peopleNum)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.holdRepo.Create(
+		details.RestaurantID, peopleNum, dateTime, dateTime, duration, expiresAt, tokenHash, clientKey, heldTables...,
+	)
+}
+
+func (s *HoldServiceImpl) Get(id uint64, token string) (*model.Hold, error) {
+	hold, err := s.holdRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// ID удержаний идут подряд, поэтому без токена чужое удержание не отличается от несуществующего
+	if token == "" || subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(hold.TokenHash)) != 1 {
+		return nil, store.ErrHoldNotFound
+	}
+	return hold, nil
+}
+
+func (s *HoldServiceImpl) GetByID(id uint64) (*model.Hold, error) {
+	return s.holdRepo.Get(id)
+}
+
+func (s *HoldServiceImpl) Confirm(
+	id uint64, token string, customerID uint64, clientName, clientPhone, clientEmail string,
+) (uint64, error) {
+	if clientName == "" || clientPhone == "" {
+		return 0, fmt.Errorf("%w: the client's name and phone are required", ErrInvalidData)
+	}
+
+	hold, err := s.Get(id, token)
+	if err != nil {
+		return 0, err
+	}
+	return s.confirm(hold, customerID, clientName, clientPhone, clientEmail)
+}
+
+func (s *HoldServiceImpl) ConfirmByID(id uint64, clientName, clientPhone, clientEmail string) (uint64, error) {
+	if clientName == "" || clientPhone == "" {
+		return 0, fmt.Errorf("%w: the client's name and phone are required", ErrInvalidData)
+	}
+
+	hold, err := s.GetByID(id)
+	if err != nil {
+		return 0, err
+	}
+	return s.confirm(hold, 0, clientName, clientPhone, clientEmail)
+}
+
+// confirm оформляет бронь гостя на столики удержания hold, токен которого уже проверен, и снимает удержание.
+func (s *HoldServiceImpl) confirm(
+	hold *model.Hold, customerID uint64, clientName, clientPhone, clientEmail string,
+) (uint64, error) {
+	// истёкшее удержание могло ещё не попасть под очистку, но столики за гостем уже не закреплены
+	if hold.IsExpired() {
+		return 0, ErrHoldExpired
+	}
+
+	requireConfirmation, err := checkGuestReliability(
+		s.guestRepo, s.bookingRepo, s.reliabilityRepo, hold.RestaurantID, clientPhone,
+	)
+	if err != nil {
+		return 0, err
+	}
+	status := model.BookingStatusConfirmed
+	if requireConfirmation {
+		status = model.BookingStatusPending
+	}
+
+	bookingID, err := s.holdRepo.Confirm(hold.ID, customerID, clientName, clientPhone, clientEmail, status)
+	if errors.Is(err, store.ErrHoldNotFound) {
+		// срок удержания истёк между проверкой и оформлением брони
+		return 0, ErrHoldExpired
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	notifyGuest(s.notifier, s.bookingRepo, s.restaurantRepo, notifier.EventBookingCreated, bookingID)
+	scheduleReminder(s.reminders, bookingID)
+	publishBookingEvent(s.events, s.bookingRepo, model.RestaurantEventBookingCreated, bookingID)
+	return bookingID, nil
+}
+
+func (s *HoldServiceImpl) Release(id uint64, token string) error {
+	hold, err := s.Get(id, token)
+	if err != nil {
+		return err
+	}
+	return s.release(hold)
+}
+
+func (s *HoldServiceImpl) ReleaseByID(id uint64) error {
+	hold, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return s.release(hold)
+}
+
+// release снимает удержание hold, доступ к которому уже проверен, и предлагает освободившиеся места листу ожидания.
+func (s *HoldServiceImpl) release(hold *model.Hold) error {
+	if err := s.holdRepo.Delete(hold.ID); err != nil {
+		return err
+	}
+
+	s.offerFreedCapacity(hold.RestaurantID)
+	return nil
+}
+
+func (s *HoldServiceImpl) ReleaseExpired() (int, error) {
+	restaurantIDs, err := s.holdRepo.DeleteExpired()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, restaurantID := range restaurantIDs {
+		s.offerFreedCapacity(restaurantID)
+	}
+	return len(restaurantIDs), nil
+}
+
+// offerFreedCapacity предлагает освободившиеся после снятия удержания места гостям из листа ожидания ресторана.
+// Удержание к этому моменту уже снято, поэтому ошибка не возвращается.
+func (s *HoldServiceImpl) offerFreedCapacity(restaurantID uint64) {
+	if s.waitlist != nil {
+		_ = s.waitlist.OfferFreedCapacity(restaurantID)
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// notifyGuest отправляет гостю уведомление о событии event с бронью bookingID. Бронь к этому моменту уже изменена,
+// поэтому ошибки получения брони и отправки уведомления не возвращаются: уведомление не должно отменять бронь.
+func notifyGuest(
+	guestNotifier notifier.Notifier,
+	bookingRepo store.BookingRepository,
+	restaurantRepo store.RestaurantRepository,
+	event string,
+	bookingID uint64,
+) {
+	if guestNotifier == nil {
+		return
+	}
+
+	booking, err := bookingRepo.Get(bookingID)
+	if err != nil {
+		return
+	}
+	restaurant, err := restaurantRepo.Get(booking.RestaurantID)
+	if err != nil {
+		return
+	}
+
+	_ = guestNotifier.Notify(context.Background(), notifier.Notification{
+		Event:          event,
+		Booking:        *booking,
+		RestaurantName: restaurant.Name,
+	})
+}
+
+// scheduleReminder планирует напоминание о брони bookingID после её изменения. Бронь к этому моменту уже изменена,
+// поэтому ошибка не возвращается: без напоминания гость всё равно получил уведомление о брони.
+func scheduleReminder(reminders ReminderService, bookingID uint64) {
+	if reminders != nil {
+		_ = reminders.Schedule(bookingID)
+	}
+}
+
+// publishBookingEvent публикует событие ресторана event с бронью bookingID для хостов ресторана. Бронь к этому
+// моменту уже изменена, поэтому ошибка получения брони не возвращается.
+func publishBookingEvent(
+	publisher pubsub.Publisher, bookingRepo store.BookingRepository, event string, bookingID uint64,
+) {
+	if publisher == nil {
+		return
+	}
+
+	booking, err := bookingRepo.Get(bookingID)
+	if err != nil {
+		return
+	}
+
+	publisher.Publish(model.RestaurantEvent{
+		Event:        event,
+		RestaurantID: booking.RestaurantID,
+		OccurredAt:   time.Now(),
+		Booking:      booking,
+	})
+}
+
+// publishTableChanged публикует для хостов ресторана restaurantID событие об изменении столика tableID (если
+// столик удалён, событие публикуется без него). Столик к этому моменту уже изменён, поэтому ошибка получения
+// столика не возвращается.
+func publishTableChanged(
+	publisher pubsub.Publisher, tableRepo store.TableRepository, restaurantID, tableID uint64,
+) {
+	if publisher == nil {
+		return
+	}
+
+	table, err := tableRepo.Get(tableID)
+	if err != nil && !errors.Is(err, store.ErrTableNotFound) {
+		return
+	}
+
+	publisher.Publish(model.RestaurantEvent{
+		Event:        model.RestaurantEventTableChanged,
+		RestaurantID: restaurantID,
+		OccurredAt:   time.Now(),
+		TableID:      tableID,
+		Table:        table,
+	})
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// passwordHashAlgorithm представляет название алгоритма, которым хешируются пароли гостей.
+	passwordHashAlgorithm = "pbkdf2_sha256"
+	// passwordIterations представляет количество итераций PBKDF2 для новых паролей. Количество итераций хранится
+	// вместе с хешем, поэтому его можно увеличивать, не ломая уже сохранённые пароли.
+	passwordIterations = 310000
+	// passwordSaltLength представляет длину соли в байтах.
+	passwordSaltLength = 16
+	// passwordKeyLength представляет длину хеша пароля в байтах.
+	passwordKeyLength = 32
+)
+
+// hashPassword возвращает хеш пароля PBKDF2-HMAC-SHA256 со случайной солью в виде
+// "pbkdf2_sha256$<количество итераций>$<соль в base64>$<хеш в base64>".
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2.Key([]byte(password), salt, passwordIterations, passwordKeyLength, sha256.New)
+
+	return fmt.Sprintf("%s$%d$%s$%s",
+		passwordHashAlgorithm, passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// checkPassword проверяет, соответствует ли пароль password хешу passwordHash, полученному от hashPassword.
+func checkPassword(passwordHash, password string) bool {
+	parts := strings.Split(passwordHash, "$")
+	if len(parts) != 4 || parts[0] != passwordHashAlgorithm {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	// сравнение за постоянное время не выдаёт по времени ответа, сколько байт хеша совпало
+	return subtle.ConstantTimeCompare(key, pbkdf2.Key([]byte(password), salt, iterations, len(key), sha256.New)) == 1
+}
+
+// simulatePasswordCheck хеширует пароль password так же долго, как checkPassword проверяет пароль нового гостя,
+// но ничего не сравнивает.
+func simulatePasswordCheck(password string) {
+	_ = pbkdf2.Key([]byte(password), make([]byte, passwordSaltLength), passwordIterations, passwordKeyLength, sha256.New)
+}
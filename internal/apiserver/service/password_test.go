@@ -0,0 +1,126 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestCheckPassword_PBKDF2Vectors проверяет хеши паролей по эталонным значениям PBKDF2-HMAC-SHA256 из RFC 7914
+// (раздел 11) и общеизвестным значениям для пароля "password" с солью "salt" (как в RFC 6070 для SHA-1).
+func TestCheckPassword_PBKDF2Vectors(t *testing.T) {
+	tests := []struct {
+		password   string
+		salt       string
+		iterations int
+		key        string
+	}{
+		{
+			password: "password", salt: "salt", iterations: 1,
+			key: "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b",
+		},
+		{
+			password: "password", salt: "salt", iterations: 2,
+			key: "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43",
+		},
+		{
+			password: "password", salt: "salt", iterations: 4096,
+			key: "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a",
+		},
+		{
+			password: "passwd", salt: "salt", iterations: 1,
+			key: "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+				"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			password: "Password", salt: "NaCl", iterations: 80000,
+			key: "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56" +
+				"a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%s/%d", tt.password, tt.salt, tt.iterations), func(t *testing.T) {
+			key, err := hex.DecodeString(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			passwordHash := fmt.Sprintf("%s$%d$%s$%s", passwordHashAlgorithm, tt.iterations,
+				base64.RawStdEncoding.EncodeToString([]byte(tt.salt)), base64.RawStdEncoding.EncodeToString(key))
+
+			if !checkPassword(passwordHash, tt.password) {
+				t.Errorf("checkPassword(%q, %q) = false, want true", passwordHash, tt.password)
+			}
+			if checkPassword(passwordHash, tt.password+"!") {
+				t.Errorf("checkPassword(%q, %q) = true, want false", passwordHash, tt.password+"!")
+			}
+		})
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	const password = "correct horse battery staple"
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(passwordHash, "$")
+	if len(parts) != 4 || parts[0] != passwordHashAlgorithm || parts[1] != fmt.Sprint(passwordIterations) {
+		t.Fatalf("hashPassword() = %q, want pbkdf2_sha256$%d$<salt>$<key>", passwordHash, passwordIterations)
+	}
+	if strings.Contains(passwordHash, password) {
+		t.Fatalf("hashPassword() = %q contains the password", passwordHash)
+	}
+
+	if !checkPassword(passwordHash, password) {
+		t.Error("checkPassword() = false for the hashed password")
+	}
+	for _, wrong := range []string{"", "correct horse battery stapl", "Correct horse battery staple", password + " "} {
+		if checkPassword(passwordHash, wrong) {
+			t.Errorf("checkPassword(%q) = true, want false", wrong)
+		}
+	}
+
+	// соль случайна, поэтому хеши одного пароля не совпадают
+	another, err := hashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if another == passwordHash {
+		t.Error("hashPassword() returned the same hash twice")
+	}
+}
+
+func TestCheckPassword_Malformed(t *testing.T) {
+	passwordHash, err := hashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(passwordHash, "$")
+
+	tests := []struct {
+		name         string
+		passwordHash string
+	}{
+		{name: "empty", passwordHash: ""},
+		{name: "plain text", passwordHash: "password"},
+		{name: "unknown algorithm", passwordHash: strings.Join([]string{"pbkdf2_sha1", parts[1], parts[2], parts[3]}, "$")},
+		{name: "missing part", passwordHash: strings.Join(parts[:3], "$")},
+		{name: "extra part", passwordHash: passwordHash + "$"},
+		{name: "zero iterations", passwordHash: strings.Join([]string{parts[0], "0", parts[2], parts[3]}, "$")},
+		{name: "negative iterations", passwordHash: strings.Join([]string{parts[0], "-1", parts[2], parts[3]}, "$")},
+		{name: "invalid salt", passwordHash: strings.Join([]string{parts[0], parts[1], "!", parts[3]}, "$")},
+		{name: "invalid key", passwordHash: strings.Join([]string{parts[0], parts[1], parts[2], "!"}, "$")},
+		{name: "empty key", passwordHash: strings.Join([]string{parts[0], parts[1], parts[2], ""}, "$")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if checkPassword(tt.passwordHash, "password") {
+				t.Errorf("checkPassword(%q) = true, want false", tt.passwordHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// GuestBlockedError возникает, когда гость бронирует столик онлайн, пока бронирование в ресторане заблокировано
+// для него из-за неявок (см. model.ReliabilityPolicy). Ошибка совпадает с ErrGuestBlocked при проверке errors.Is.
+type GuestBlockedError struct {
+	// Until представляет момент, с которого гость снова может бронировать столики онлайн.
+	Until time.Time
+}
+
+func (e *GuestBlockedError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrGuestBlocked.Error(), e.Until.Format("2006.01.02 15:04"))
+}
+
+func (e *GuestBlockedError) Unwrap() error {
+	return ErrGuestBlocked
+}
+
+// checkGuestReliability проверяет гостя с телефоном phone по правилам надёжности ресторана restaurantID.
+// Если онлайн-бронирование для гостя заблокировано, возвращает *GuestBlockedError, а если брони гостя должны ждать
+// подтверждения рестораном, возвращает true. Неявки считаются по всем броням гостя в этом ресторане.
+func checkGuestReliability(
+	guestRepo store.GuestRepository,
+	bookingRepo store.BookingRepository,
+	reliabilityRepo store.ReliabilityPolicyRepository,
+	restaurantID uint64,
+	phone string,
+) (bool, error) {
+	policy, err := reliabilityRepo.Get(restaurantID)
+	if err != nil {
+		return false, err
+	}
+	if policy == (model.ReliabilityPolicy{}) {
+		return false, nil
+	}
+
+	// телефон, который нельзя привести к формату E.164, не принадлежит ни одному гостю
+	normalizedPhone, err := model.NormalizePhone(phone)
+	if err != nil {
+		return false, nil
+	}
+	guest, err := guestRepo.GetByPhone(normalizedPhone)
+	if errors.Is(err, store.ErrGuestNotFound) {
+		// гость бронирует впервые
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	bookings, err := bookingRepo.GetAllByGuest(guest.ID)
+	if err != nil {
+		return false, err
+	}
+
+	noShows := 0
+	var lastNoShowAt time.Time
+	for _, booking := range bookings {
+		if booking.RestaurantID != restaurantID || !booking.IsNoShow() {
+			continue
+		}
+		noShows++
+
+		// срок блокировки отсчитывается от момента, когда неявка была отмечена
+		noShowAt := booking.StartsAt()
+		if booking.FinishedAt != nil {
+			noShowAt = *booking.FinishedAt
+		}
+		if noShowAt.After(lastNoShowAt) {
+			lastNoShowAt = noShowAt
+		}
+	}
+
+	if blockedUntil, blocked := policy.BlockedUntil(noShows, lastNoShowAt); blocked && time.Now().Before(blockedUntil) {
+		return false, &GuestBlockedError{Until: blockedUntil}
+	}
+	return policy.RequiresConfirmation(noShows), nil
+}
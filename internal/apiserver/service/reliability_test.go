@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+func TestBookingService_CreateReliability(t *testing.T) {
+	st := memory.NewStore()
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+
+	var restaurantIDs []uint64
+	for _, name := range []string{"Каравелла", "Маяк"} {
+		restaurantID, err := st.Restaurants().Create(name, 30, 1500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 4; i++ {
+			if _, err = st.Tables().Create(restaurantID, 4, "", model.ZoneHall); err != nil {
+				t.Fatal(err)
+			}
+		}
+		restaurantIDs = append(restaurantIDs, restaurantID)
+	}
+	restaurantID, otherRestaurantID := restaurantIDs[0], restaurantIDs[1]
+
+	if err := services.RestaurantService.SetReliabilityPolicy(restaurantID, model.ReliabilityPolicy{
+		ConfirmationAfter: 1, BlockAfter: 2, BlockDays: 30,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// noShow отмечает неявку гостя по брони, которая началась полчаса назад
+	noShow := func() {
+		t.Helper()
+
+		startedAt := time.Now().Add(-30 * time.Minute)
+		bookingID, err := st.Bookings().Create(
+			restaurantID, 0, "Павел", "+79485722648", "", 2, model.BookingStatusConfirmed, startedAt, startedAt, 2*time.Hour,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = services.BookingService.MarkNoShow(bookingID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// create оформляет бронь гостя в ресторане restaurantID и возвращает её статус
+	create := func(restaurantID uint64, byRestaurant bool) (string, error) {
+		t.Helper()
+
+		bookingID, err := services.BookingService.Create(model.BookingDetails{
+			RestaurantID:    restaurantID,
+			PeopleNumber:    "2",
+			DesiredDatetime: weekAt(19, 0),
+			ClientName:      "Павел",
+			ClientPhone:     "8 (948) 572-26-48",
+			ByRestaurant:    byRestaurant,
+		})
+		if err != nil {
+			return "", err
+		}
+		booking, err := services.BookingService.Get(bookingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return booking.Status, nil
+	}
+
+	if status, err := create(restaurantID, false); err != nil || status != model.BookingStatusConfirmed {
+		t.Fatalf("booking without no-shows = %s, %v; want %s", status, err, model.BookingStatusConfirmed)
+	}
+
+	// после первой неявки брони гостя ждут подтверждения, но только в этом ресторане и только онлайн
+	noShow()
+	if status, err := create(restaurantID, false); err != nil || status != model.BookingStatusPending {
+		t.Errorf("booking after a no-show = %s, %v; want %s", status, err, model.BookingStatusPending)
+	}
+	if status, err := create(restaurantID, true); err != nil || status != model.BookingStatusConfirmed {
+		t.Errorf("booking by the restaurant after a no-show = %s, %v; want %s", status, err, model.BookingStatusConfirmed)
+	}
+	if status, err := create(otherRestaurantID, false); err != nil || status != model.BookingStatusConfirmed {
+		t.Errorf("booking in another restaurant after a no-show = %s, %v; want %s", status, err, model.BookingStatusConfirmed)
+	}
+
+	// после второй неявки онлайн-бронирование блокируется на 30 дней
+	noShow()
+	_, err := create(restaurantID, false)
+	var blockedErr *GuestBlockedError
+	if !errors.As(err, &blockedErr) || !errors.Is(err, ErrGuestBlocked) {
+		t.Fatalf("booking after two no-shows = %v, want GuestBlockedError", err)
+	}
+	if until := time.Now().AddDate(0, 0, 30); blockedErr.Until.After(until) || blockedErr.Until.Before(until.Add(-time.Minute)) {
+		t.Errorf("blocked until %s, want 30 days after the last no-show", blockedErr.Until)
+	}
+	if status, err := create(restaurantID, true); err != nil || status != model.BookingStatusConfirmed {
+		t.Errorf("booking by the restaurant of a blocked guest = %s, %v; want %s", status, err, model.BookingStatusConfirmed)
+	}
+}
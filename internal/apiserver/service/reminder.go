@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// ReminderService представляет бизнес-логику напоминаний гостям о предстоящих бронях.
+type ReminderService interface {
+	// Schedule планирует напоминание о брони за reminderBefore до её начала (или переносит уже запланированное,
+	// если бронь изменилась). Если бронь уже не ждёт гостей или до её начала осталось меньше reminderBefore,
+	// запланированное напоминание отменяется.
+	Schedule(bookingID uint64) error
+	// Send отправляет гостю напоминание о брони. Если бронь уже не ждёт гостей или её время наступило, напоминание
+	// не отправляется. Ошибка доставки возвращается, чтобы попытку можно было повторить.
+	Send(ctx context.Context, bookingID uint64) error
+}
+
+// ReminderServiceImpl представляет реализацию ReminderService.
+type ReminderServiceImpl struct {
+	jobRepo        store.JobRepository
+	bookingRepo    store.BookingRepository
+	restaurantRepo store.RestaurantRepository
+	// notifier доставляет напоминания синхронно, чтобы неудачную доставку можно было повторить
+	notifier notifier.Notifier
+	// reminderBefore представляет, за сколько до начала брони гостю отправляется напоминание (0 - не отправляется)
+	reminderBefore time.Duration
+}
+
+func NewReminderService(
+	jobRepo store.JobRepository,
+	bookingRepo store.BookingRepository,
+	restaurantRepo store.RestaurantRepository,
+	notifier notifier.Notifier,
+	reminderBefore time.Duration,
+) *ReminderServiceImpl {
+	return &ReminderServiceImpl{
+		jobRepo:        jobRepo,
+		bookingRepo:    bookingRepo,
+		restaurantRepo: restaurantRepo,
+		notifier:       notifier,
+		reminderBefore: reminderBefore,
+	}
+}
+
+func (s *ReminderServiceImpl) Schedule(bookingID uint64) error {
+	booking, err := s.bookingRepo.Get(bookingID)
+	if err != nil {
+		return err
+	}
+
+	// бронь, оформленную незадолго до начала, гость и так помнит
+	remindAt := booking.StartsAt().Add(-s.reminderBefore)
+	if s.reminderBefore <= 0 || !booking.IsActive() || !remindAt.After(time.Now()) {
+		return s.jobRepo.Unschedule(model.JobKindBookingReminder, bookingID)
+	}
+
+	_, err = s.jobRepo.Schedule(model.JobKindBookingReminder, bookingID, remindAt)
+	return err
+}
+
+func (s *ReminderServiceImpl) Send(ctx context.Context, bookingID uint64) error {
+	booking, err := s.bookingRepo.Get(bookingID)
+	if errors.Is(err, store.ErrBookingNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// после долгих повторных попыток напоминание может уже опоздать
+	if !booking.IsActive() || !time.Now().Before(booking.StartsAt()) {
+		return nil
+	}
+
+	restaurant, err := s.restaurantRepo.Get(booking.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, notifier.Notification{
+		Event:          notifier.EventBookingReminder,
+		Booking:        *booking,
+		RestaurantName: restaurant.Name,
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
@@ -15,23 +16,66 @@ type RestaurantService interface {
 	Create(name string, averageWaitingTime int, averageCheck float64) (uint64, error)
 	// GetAll получает список всех ресторанов.
 	GetAll() ([]model.Restaurant, error)
-	// GetAllAvailable возвращает список ресторанов, в которых можно забронировать столики.
-	GetAllAvailable(desiredDateTime, peopleNumber string) ([]model.Restaurant, error)
+	// GetAllAvailable возвращает список ресторанов, в которых можно забронировать столики. Если указана
+	// предпочитаемая зона (zone), учитываются только столики в этой зоне.
+	GetAllAvailable(desiredDateTime, peopleNumber, zone string) ([]model.Restaurant, error)
+	// GetAlternatives подбирает варианты для гостя, которому не нашлось ресторана на желаемое время: ближайшие моменты
+	// начала брони (раньше или позже в тот же день и в следующие дни) и рестораны, в которых можно рассадить компанию.
+	GetAlternatives(desiredDateTime, peopleNumber, zone string) (*model.Alternatives, error)
 	// Get получает ресторан по его ID.
 	Get(id uint64) (*model.Restaurant, error)
 	// Update обновляет информацию о ресторане по его ID.
 	Update(id uint64, data model.UpdateRestaurantData) error
 	// Delete удаляет ресторан по его ID.
 	Delete(id uint64) error
+	// GetOpeningHours возвращает недельный график работы ресторана.
+	GetOpeningHours(id uint64) ([]model.OpeningHours, error)
+	// SetOpeningHours заменяет недельный график работы ресторана. Пустой график означает работу по графику по умолчанию.
+	SetOpeningHours(id uint64, hours []model.OpeningHours) error
+	// GetDurationPolicy возвращает правила, по которым определяется длительность брони в ресторане.
+	GetDurationPolicy(id uint64) (model.DurationPolicy, error)
+	// SetDurationPolicy заменяет правила, по которым определяется длительность брони в ресторане.
+	SetDurationPolicy(id uint64, policy model.DurationPolicy) error
+	// GetReliabilityPolicy возвращает правила, по которым ресторан ограничивает онлайн-бронирование гостям,
+	// не приходящим по своим броням.
+	GetReliabilityPolicy(id uint64) (model.ReliabilityPolicy, error)
+	// SetReliabilityPolicy заменяет правила надёжности гостей ресторана.
+	SetReliabilityPolicy(id uint64, policy model.ReliabilityPolicy) error
 }
 
+const (
+	// alternativeDays представляет количество следующих за желаемым дней, в которые гостю подбираются варианты.
+	alternativeDays = 3
+	// maxAlternativeTimesPerSide представляет количество моментов начала брони, которые предлагаются гостю раньше
+	// и позже желаемого в тот же день.
+	maxAlternativeTimesPerSide = 2
+	// maxAlternativeRestaurants представляет количество ресторанов, которые предлагаются гостю.
+	maxAlternativeRestaurants = 3
+)
+
 // RestaurantServiceImpl представляет реализацю RestaurantService.
 type RestaurantServiceImpl struct {
-	restaurantRepo store.RestaurantRepository
+	restaurantRepo  store.RestaurantRepository
+	hoursRepo       store.OpeningHoursRepository
+	policyRepo      store.DurationPolicyRepository
+	reliabilityRepo store.ReliabilityPolicyRepository
+	tableRepo       store.TableRepository
 }
 
-func NewRestaurantService(restaurantRepo store.RestaurantRepository) *RestaurantServiceImpl {
-	return &RestaurantServiceImpl{restaurantRepo: restaurantRepo}
+func NewRestaurantService(
+	restaurantRepo store.RestaurantRepository,
+	hoursRepo store.OpeningHoursRepository,
+	policyRepo store.DurationPolicyRepository,
+	reliabilityRepo store.ReliabilityPolicyRepository,
+	tableRepo store.TableRepository,
+) *RestaurantServiceImpl {
+	return &RestaurantServiceImpl{
+		restaurantRepo:  restaurantRepo,
+		hoursRepo:       hoursRepo,
+		policyRepo:      policyRepo,
+		reliabilityRepo: reliabilityRepo,
+		tableRepo:       tableRepo,
+	}
 }
 
 func (s *RestaurantServiceImpl) Create(name string, averageWaitingTime int, averageCheck float64) (uint64, error) {
@@ -39,44 +83,179 @@ func (s *RestaurantServiceImpl) Create(name string, averageWaitingTime int, aver
 }
 
 func (s *RestaurantServiceImpl) GetAll() ([]model.Restaurant, error) {
-	return s.restaurantRepo.GetAll()
+	restaurants, err := s.restaurantRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.fillDetails(restaurants); err != nil {
+		return nil, err
+	}
+	return restaurants, nil
 }
 
-func (s *RestaurantServiceImpl) GetAllAvailable(desiredDateTime, peopleNumber string) ([]model.Restaurant, error) {
-	peopleNum, err := strconv.Atoi(peopleNumber)
+func (s *RestaurantServiceImpl) GetAllAvailable(desiredDateTime, peopleNumber, zone string) ([]model.Restaurant, error) {
+	dateTime, peopleNum, err := parseSearchQuery(desiredDateTime, peopleNumber, zone)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+		return nil, err
 	}
 
-	if peopleNum < 1 {
-		return nil, fmt.Errorf("%w: the number of people cannot be less than 1", ErrInvalidData)
+	restaurants, err := s.restaurantRepo.GetAllAvailable(dateTime, peopleNum, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.fillDetails(restaurants); err != nil {
+		return nil, err
 	}
 
-	dateTime, err := time.Parse("2006-01-02T15:04", desiredDateTime)
+	// скрываем рестораны, которые по своему графику работы не принимают брони на выбранное время
+	openRestaurants := make([]model.Restaurant, 0, len(restaurants))
+	for _, restaurant := range restaurants {
+		if restaurant.AcceptsBookingAt(dateTime) {
+			openRestaurants = append(openRestaurants, restaurant)
+		}
+	}
+	return openRestaurants, nil
+}
+
+func (s *RestaurantServiceImpl) GetAlternatives(desiredDateTime, peopleNumber, zone string) (*model.Alternatives, error) {
+	dateTime, peopleNum, err := parseSearchQuery(desiredDateTime, peopleNumber, zone)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+		return nil, err
 	}
 
-	// если указанное время пользователем в прошлом, то ругаемся на некорректный ввод
-	if time.Now().After(dateTime) {
-		return nil, fmt.Errorf("%w: the date and time of booking cannot be in the past", ErrInvalidData)
+	restaurants, err := s.restaurantRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.fillDetails(restaurants); err != nil {
+		return nil, err
+	}
+
+	// варианты ищутся с начала желаемого дня до конца последнего из следующих alternativeDays дней
+	day := time.Date(dateTime.Year(), dateTime.Month(), dateTime.Day(), 0, 0, 0, 0, dateTime.Location())
+	searchTo := day.AddDate(0, 0, alternativeDays+1)
+
+	// restaurantsBySlots содержит количество ресторанов, в которых можно рассадить компанию в каждый из моментов
+	restaurantsBySlots := make(map[time.Time]int)
+	alternatives := &model.Alternatives{
+		Times:       make([]model.AlternativeTime, 0),
+		Restaurants: make([]model.AlternativeRestaurant, 0),
+	}
+
+	for _, restaurant := range restaurants {
+		restaurant := restaurant
+		duration := restaurant.DurationPolicy.DurationFor(peopleNum)
+
+		slots := bookingSlots(&restaurant, day, searchTo)
+		availableSlots, err := slotsAvailability(s.tableRepo, &restaurant, slots, peopleNum, duration, zone)
+		if err != nil {
+			return nil, err
+		}
+
+		// ресторан предлагается на ближайшее к желаемому время, на которое в нём можно рассадить компанию
+		nearest := -1
+		for i, slot := range slots {
+			if !availableSlots[i].Bookable {
+				continue
+			}
+			restaurantsBySlots[slot]++
+			if nearest == -1 || absDuration(slot.Sub(dateTime)) < absDuration(slots[nearest].Sub(dateTime)) {
+				nearest = i
+			}
+		}
+
+		if nearest != -1 {
+			restaurant.AvailableSeatsNumber = availableSlots[nearest].AvailableSeatsNumber
+			alternatives.Restaurants = append(alternatives.Restaurants, model.AlternativeRestaurant{
+				Restaurant: restaurant,
+				DateTime:   model.FormattedDateTime(slots[nearest]),
+			})
+		}
+	}
+
+	sort.SliceStable(alternatives.Restaurants, func(i, j int) bool {
+		return absDuration(time.Time(alternatives.Restaurants[i].DateTime).Sub(dateTime)) <
+			absDuration(time.Time(alternatives.Restaurants[j].DateTime).Sub(dateTime))
+	})
+	if len(alternatives.Restaurants) > maxAlternativeRestaurants {
+		alternatives.Restaurants = alternatives.Restaurants[:maxAlternativeRestaurants]
 	}
 
-	// рестораны работают с 9:00 до 23:00 (последнюю бронь можно создать на 21:00)
-	dateTimeHour := dateTime.Hour()
-	dateTimeMinute := dateTime.Minute()
-	if dateTimeHour < 9 || dateTimeHour >= 22 || dateTimeHour == 21 && dateTimeMinute > 0 {
-		return nil, fmt.Errorf("%w: the restaurant is closed", ErrInvalidData)
+	for _, slot := range alternativeTimes(restaurantsBySlots, dateTime) {
+		alternatives.Times = append(alternatives.Times, model.AlternativeTime{
+			DateTime:          model.FormattedDateTime(slot),
+			RestaurantsNumber: restaurantsBySlots[slot],
+		})
+	}
+	return alternatives, nil
+}
+
+// alternativeTimes выбирает среди моментов, на которые компанию можно рассадить хотя бы в одном ресторане, ближайшие
+// к желаемому моменту dateTime: до maxAlternativeTimesPerSide моментов раньше и позже в тот же день и по одному
+// моменту, ближайшему к желаемому времени суток, в каждый из следующих дней. Возвращает моменты по возрастанию.
+func alternativeTimes(restaurantsBySlots map[time.Time]int, dateTime time.Time) []time.Time {
+	slots := make([]time.Time, 0, len(restaurantsBySlots))
+	for slot := range restaurantsBySlots {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Before(slots[j])
+	})
+
+	sameDay := func(a, b time.Time) bool {
+		return a.Year() == b.Year() && a.YearDay() == b.YearDay()
 	}
 
-	desiredDate := dateTime.Format("2006.01.02")
-	desiredTime := dateTime.Format("15:04")
+	var earlier, later []time.Time
+	// nextDays содержит для каждого из следующих дней момент, ближайший к желаемому времени суток
+	nextDays := make(map[time.Time]time.Time)
+	for _, slot := range slots {
+		switch {
+		case sameDay(slot, dateTime) && slot.Before(dateTime):
+			earlier = append(earlier, slot)
+		case sameDay(slot, dateTime) && slot.After(dateTime):
+			later = append(later, slot)
+		case slot.After(dateTime):
+			day := time.Date(slot.Year(), slot.Month(), slot.Day(), 0, 0, 0, 0, slot.Location())
+			clockDiff := absDuration(model.TimeOfDay(slot) - model.TimeOfDay(dateTime))
+			if best, ok := nextDays[day]; !ok || clockDiff < absDuration(model.TimeOfDay(best)-model.TimeOfDay(dateTime)) {
+				nextDays[day] = slot
+			}
+		}
+	}
 
-	return s.restaurantRepo.GetAllAvailable(desiredDate, desiredTime, peopleNum)
+	if len(earlier) > maxAlternativeTimesPerSide {
+		earlier = earlier[len(earlier)-maxAlternativeTimesPerSide:]
+	}
+	if len(later) > maxAlternativeTimesPerSide {
+		later = later[:maxAlternativeTimesPerSide]
+	}
+
+	times := make([]time.Time, 0, len(earlier)+len(later)+len(nextDays))
+	times = append(times, earlier...)
+	times = append(times, later...)
+	for _, slot := range nextDays {
+		times = append(times, slot)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	return times
+}
+
+// absDuration возвращает абсолютное значение промежутка времени d.
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
 }
 
 func (s *RestaurantServiceImpl) Get(id uint64) (*model.Restaurant, error) {
-	return s.restaurantRepo.Get(id)
+	return getRestaurant(s.restaurantRepo, s.hoursRepo, s.policyRepo, id)
 }
 
 func (s *RestaurantServiceImpl) Update(id uint64, data model.UpdateRestaurantData) error {
@@ -86,3 +265,179 @@ func (s *RestaurantServiceImpl) Update(id uint64, data model.UpdateRestaurantDat
 func (s *RestaurantServiceImpl) Delete(id uint64) error {
 	return s.restaurantRepo.Delete(id)
 }
+
+func (s *RestaurantServiceImpl) GetOpeningHours(id uint64) ([]model.OpeningHours, error) {
+	restaurant, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return restaurant.OpeningHours, nil
+}
+
+func (s *RestaurantServiceImpl) SetOpeningHours(id uint64, hours []model.OpeningHours) error {
+	for _, shift := range hours {
+		if err := shift.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+		}
+	}
+	return s.hoursRepo.Set(id, hours)
+}
+
+func (s *RestaurantServiceImpl) GetDurationPolicy(id uint64) (model.DurationPolicy, error) {
+	return s.policyRepo.Get(id)
+}
+
+func (s *RestaurantServiceImpl) SetDurationPolicy(id uint64, policy model.DurationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+	return s.policyRepo.Set(id, policy)
+}
+
+func (s *RestaurantServiceImpl) GetReliabilityPolicy(id uint64) (model.ReliabilityPolicy, error) {
+	return s.reliabilityRepo.Get(id)
+}
+
+func (s *RestaurantServiceImpl) SetReliabilityPolicy(id uint64, policy model.ReliabilityPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+	return s.reliabilityRepo.Set(id, policy)
+}
+
+// parseSearchQuery проверяет параметры поиска ресторанов и возвращает желаемые дату и время посещения ресторана
+// и количество человек. Дата и время принимаются как в формате поля ввода на сайте ("2006-01-02T15:04"), так и
+// в формате API ("2006.01.02 15:04").
+func parseSearchQuery(desiredDateTime, peopleNumber, zone string) (time.Time, int, error) {
+	peopleNum, err := parsePeopleNumber(peopleNumber)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	dateTime, err := time.ParseInLocation("2006-01-02T15:04", desiredDateTime, time.Local)
+	if err == nil {
+		err = checkNotInPast(dateTime)
+	} else {
+		dateTime, err = parseDesiredDatetime(desiredDateTime)
+	}
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	// пустая зона означает, что гостю подойдёт любая зона
+	if zone != "" && !model.IsZone(zone) {
+		return time.Time{}, 0, fmt.Errorf("%w: %s", ErrInvalidData, model.ErrUnknownZone.Error())
+	}
+
+	return dateTime, peopleNum, nil
+}
+
+// fillDetails дополняет рестораны их графиками работы и правилами длительности брони.
+func (s *RestaurantServiceImpl) fillDetails(restaurants []model.Restaurant) error {
+	hours, err := s.hoursRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	policies, err := s.policyRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	for i := range restaurants {
+		restaurants[i].OpeningHours = effectiveOpeningHours(hours[restaurants[i].ID])
+		restaurants[i].DurationPolicy = effectiveDurationPolicy(policies[restaurants[i].ID])
+	}
+	return nil
+}
+
+// parseDesiredDatetime разбирает желаемые дату и время посещения ресторана в формате API ("2006.01.02 15:04")
+// и проверяет, что этот момент ещё не наступил. Время брони указывается по часам ресторана, поэтому разбирается
+// в местном часовом поясе, как и в model.Booking.StartsAt и сетке доступности ресторана.
+func parseDesiredDatetime(desiredDatetime string) (time.Time, error) {
+	dateTime, err := time.ParseInLocation("2006.01.02 15:04", desiredDatetime, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+	if err = checkNotInPast(dateTime); err != nil {
+		return time.Time{}, err
+	}
+	return dateTime, nil
+}
+
+// parsePeopleNumber разбирает количество человек в компании и проверяет его с помощью checkPeopleNumber.
+func parsePeopleNumber(peopleNumber string) (int, error) {
+	peopleNum, err := strconv.Atoi(peopleNumber)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+	if err = checkPeopleNumber(peopleNum); err != nil {
+		return 0, err
+	}
+	return peopleNum, nil
+}
+
+// checkPeopleNumber возвращает ошибку, если в компании меньше одного или больше maxPeopleNumber человек. Большие
+// компании ресторан рассаживает сам, а подбор столиков для них занял бы слишком много времени.
+func checkPeopleNumber(peopleNum int) error {
+	if peopleNum < 1 {
+		return fmt.Errorf("%w: the number of people cannot be less than 1", ErrInvalidData)
+	}
+	if peopleNum > maxPeopleNumber {
+		return fmt.Errorf("%w: the number of people cannot be greater than %d", ErrInvalidData, maxPeopleNumber)
+	}
+	return nil
+}
+
+// checkNotInPast возвращает ошибку, если момент начала брони dateTime уже наступил.
+func checkNotInPast(dateTime time.Time) error {
+	if time.Now().After(dateTime) {
+		return fmt.Errorf("%w: the date and time of booking cannot be in the past", ErrInvalidData)
+	}
+	return nil
+}
+
+// getRestaurant возвращает ресторан по его ID вместе с графиком работы и правилами длительности брони.
+func getRestaurant(
+	restaurantRepo store.RestaurantRepository,
+	hoursRepo store.OpeningHoursRepository,
+	policyRepo store.DurationPolicyRepository,
+	id uint64,
+) (*model.Restaurant, error) {
+	restaurant, err := restaurantRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := hoursRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	restaurant.OpeningHours = effectiveOpeningHours(hours)
+
+	policy, err := policyRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	restaurant.DurationPolicy = effectiveDurationPolicy(policy)
+
+	return restaurant, nil
+}
+
+// effectiveOpeningHours возвращает график работы, по которому ресторан фактически работает:
+// собственный, если он задан, иначе - график по умолчанию.
+func effectiveOpeningHours(hours []model.OpeningHours) []model.OpeningHours {
+	if len(hours) == 0 {
+		return model.DefaultOpeningHours()
+	}
+	return hours
+}
+
+// effectiveDurationPolicy возвращает правила длительности брони, по которым ресторан фактически работает:
+// собственные, если они заданы, иначе - правила по умолчанию.
+func effectiveDurationPolicy(policy model.DurationPolicy) model.DurationPolicy {
+	if policy.DefaultDuration == 0 {
+		return model.DefaultDurationPolicy()
+	}
+	return policy
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// dayAt возвращает момент через неделю и days дней в hour:minute.
+func dayAt(days, hour, minute int) time.Time {
+	day := time.Now().AddDate(0, 0, 7+days)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local)
+}
+
+func TestRestaurantService_GetAlternatives(t *testing.T) {
+	st := memory.NewStore()
+	services := NewServices(st, "test-admin-key", nil, nil, 0, testBookingLinkKey, nil)
+
+	// в каждом ресторане единственный столик на 4 места занят по брони на 2 часа
+	bookedAt := map[string]int{"Каравелла": 19, "Маяк": 18}
+	for _, name := range []string{"Каравелла", "Маяк"} {
+		restaurantID, err := st.Restaurants().Create(name, 30, 1500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = st.Tables().Create(restaurantID, 4, "", model.ZoneHall); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = services.BookingService.Create(model.BookingDetails{
+			RestaurantID:    restaurantID,
+			PeopleNumber:    "4",
+			DesiredDatetime: weekAt(bookedAt[name], 0),
+			ClientName:      "Павел",
+			ClientPhone:     "+79485722648",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alternatives, err := services.RestaurantService.GetAlternatives(weekAt(19, 0), "4", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// рестораны предлагаются на ближайшее к желаемому время: "Маяк" освобождается раньше, чем "Каравелла"
+	wantRestaurants := []struct {
+		name     string
+		dateTime time.Time
+	}{
+		{name: "Маяк", dateTime: dayAt(0, 20, 15)},
+		{name: "Каравелла", dateTime: dayAt(0, 16, 45)},
+	}
+	if len(alternatives.Restaurants) != len(wantRestaurants) {
+		t.Fatalf("restaurants = %+v, want %d restaurants", alternatives.Restaurants, len(wantRestaurants))
+	}
+	for i, want := range wantRestaurants {
+		got := alternatives.Restaurants[i]
+		if got.Restaurant.Name != want.name || !time.Time(got.DateTime).Equal(want.dateTime) {
+			t.Errorf("restaurant %d = %s at %s, want %s at %s",
+				i, got.Restaurant.Name, got.DateTime, want.name, model.FormattedDateTime(want.dateTime))
+		}
+		if got.Restaurant.AvailableSeatsNumber != 4 {
+			t.Errorf("restaurant %s has %d available seats, want 4", got.Restaurant.Name, got.Restaurant.AvailableSeatsNumber)
+		}
+	}
+
+	// в тот же день - по два ближайших момента раньше и позже, в следующие дни - желаемое время
+	wantTimes := []struct {
+		dateTime          time.Time
+		restaurantsNumber int
+	}{
+		{dateTime: dayAt(0, 16, 30), restaurantsNumber: 1},
+		{dateTime: dayAt(0, 16, 45), restaurantsNumber: 1},
+		{dateTime: dayAt(0, 20, 15), restaurantsNumber: 1},
+		{dateTime: dayAt(0, 20, 30), restaurantsNumber: 1},
+		{dateTime: dayAt(1, 19, 0), restaurantsNumber: 2},
+		{dateTime: dayAt(2, 19, 0), restaurantsNumber: 2},
+		{dateTime: dayAt(3, 19, 0), restaurantsNumber: 2},
+	}
+	if len(alternatives.Times) != len(wantTimes) {
+		t.Fatalf("times = %+v, want %d times", alternatives.Times, len(wantTimes))
+	}
+	for i, want := range wantTimes {
+		got := alternatives.Times[i]
+		if !time.Time(got.DateTime).Equal(want.dateTime) || got.RestaurantsNumber != want.restaurantsNumber {
+			t.Errorf("time %d = %s in %d restaurants, want %s in %d restaurants",
+				i, got.DateTime, got.RestaurantsNumber, model.FormattedDateTime(want.dateTime), want.restaurantsNumber)
+		}
+	}
+}
+
+func TestAlternativeTimes(t *testing.T) {
+	restaurantsBySlots := map[time.Time]int{
+		dayAt(-1, 19, 0): 1,
+		dayAt(0, 17, 0):  1,
+		dayAt(0, 18, 0):  2,
+		dayAt(0, 18, 30): 1,
+		dayAt(0, 19, 30): 1,
+		dayAt(0, 20, 0):  3,
+		dayAt(0, 20, 30): 1,
+		dayAt(1, 12, 0):  1,
+		dayAt(1, 18, 45): 1,
+		dayAt(1, 19, 30): 2,
+		dayAt(2, 21, 0):  1,
+	}
+
+	// вчерашний момент не предлагается, а в следующие дни выбирается время, ближайшее к желаемому
+	want := []time.Time{
+		dayAt(0, 18, 0), dayAt(0, 18, 30), dayAt(0, 19, 30), dayAt(0, 20, 0), dayAt(1, 18, 45), dayAt(2, 21, 0),
+	}
+	got := alternativeTimes(restaurantsBySlots, dayAt(0, 19, 0))
+	if len(got) != len(want) {
+		t.Fatalf("alternativeTimes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("alternativeTimes()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
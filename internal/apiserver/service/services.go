@@ -1,6 +1,12 @@
 package service
 
-import "github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+import (
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
 
 // Services представляет слой бизнес-логики.
 type Services struct {
@@ -10,12 +16,97 @@ type Services struct {
 	RestaurantService RestaurantService
 	// TableService представляет бизнес-логику работы со столиками.
 	TableService TableService
+	// WaitlistService представляет бизнес-логику работы с листами ожидания ресторанов.
+	WaitlistService WaitlistService
+	// HoldService представляет бизнес-логику работы с временными удержаниями столиков.
+	HoldService HoldService
+	// UserService представляет бизнес-логику работы с пользователями API администрирования.
+	UserService UserService
+	// CustomerService представляет бизнес-логику работы с учётными записями гостей на сайте.
+	CustomerService CustomerService
+	// GuestService представляет бизнес-логику работы с гостями ресторанов.
+	GuestService GuestService
+	// ReminderService представляет бизнес-логику напоминаний гостям о предстоящих бронях.
+	ReminderService ReminderService
+	// BookingLinkService представляет бизнес-логику подписанных ссылок, по которым гости управляют бронями.
+	BookingLinkService BookingLinkService
+	// WebhookService представляет бизнес-логику работы с вебхуками ресторанов.
+	WebhookService WebhookService
 }
 
-func NewServices(store store.Store) *Services {
+// NewServices создаёт слой бизнес-логики поверх хранилища store. adminAPIKey представляет ключ API администратора
+// платформы из настроек сервиса, а guestNotifier - способ доставки гостям уведомлений о их бронях. Напоминания
+// о бронях отправляются за reminderBefore до их начала (0 - не отправляются) способом reminderNotifier: он должен
+// доставлять уведомления синхронно, чтобы неудачную доставку можно было повторить. bookingLinkKey представляет ключ,
+// которым подписываются ссылки на брони для гостей, а events - способ публикации событий ресторанов (оформление
+// и отмена броней, изменение столиков) для хостов.
+func NewServices(
+	store store.Store,
+	adminAPIKey string,
+	guestNotifier, reminderNotifier notifier.Notifier,
+	reminderBefore time.Duration,
+	bookingLinkKey string,
+	events pubsub.Publisher,
+) *Services {
+	reminderService := NewReminderService(
+		store.Jobs(), store.Bookings(), store.Restaurants(), reminderNotifier, reminderBefore,
+	)
+
+	waitlistService := NewWaitlistService(
+		store.Waitlist(), store.Tables(), store.Restaurants(), store.OpeningHours(), store.DurationPolicies(),
+		guestNotifier,
+	)
+	bookingService := NewBookingService(BookingServiceDeps{
+		BookingRepo:     store.Bookings(),
+		TableRepo:       store.Tables(),
+		RestaurantRepo:  store.Restaurants(),
+		HoursRepo:       store.OpeningHours(),
+		PolicyRepo:      store.DurationPolicies(),
+		GuestRepo:       store.Guests(),
+		ReliabilityRepo: store.ReliabilityPolicies(),
+		Waitlist:        waitlistService,
+		Notifier:        guestNotifier,
+		Reminders:       reminderService,
+		Events:          events,
+	})
+	// лист ожидания оформляет брони по принятым предложениям, а BookingService, в свою очередь, предлагает
+	// листу ожидания освободившиеся места
+	waitlistService.bookingService = bookingService
+
+	holdService := NewHoldService(HoldServiceDeps{
+		HoldRepo:        store.Holds(),
+		TableRepo:       store.Tables(),
+		RestaurantRepo:  store.Restaurants(),
+		HoursRepo:       store.OpeningHours(),
+		PolicyRepo:      store.DurationPolicies(),
+		GuestRepo:       store.Guests(),
+		BookingRepo:     store.Bookings(),
+		ReliabilityRepo: store.ReliabilityPolicies(),
+		Waitlist:        waitlistService,
+		Notifier:        guestNotifier,
+		Reminders:       reminderService,
+		Events:          events,
+	})
+	// лист ожидания удерживает за гостями предложенные им места, а HoldService предлагает листу ожидания места
+	// из снятых удержаний
+	waitlistService.holds = holdService
+
+	restaurantService := NewRestaurantService(
+		store.Restaurants(), store.OpeningHours(), store.DurationPolicies(), store.ReliabilityPolicies(), store.Tables(),
+	)
+
 	return &Services{
-		BookingService:    NewBookingService(store.Bookings(), store.Tables()),
-		RestaurantService: NewRestaurantService(store.Restaurants()),
-		TableService:      NewTableService(store.Tables()),
+		BookingService:    bookingService,
+		RestaurantService: restaurantService,
+		TableService:      NewTableService(store.Tables(), waitlistService, events),
+		WaitlistService:   waitlistService,
+		HoldService:       holdService,
+		UserService:       NewUserService(store.Users(), adminAPIKey),
+		CustomerService:   NewCustomerService(store.Customers(), store.Bookings(), store.Restaurants()),
+		GuestService:      NewGuestService(store.Guests(), store.Bookings()),
+		ReminderService:   reminderService,
+
+		BookingLinkService: NewBookingLinkService(store.Bookings(), bookingService, bookingLinkKey),
+		WebhookService:     NewWebhookService(store.Webhooks()),
 	}
 }
@@ -1,17 +1,22 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/pubsub"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
 )
 
 // TableService представляет бизнес-логику работы со столиками.
 type TableService interface {
-	// Create создаёт столик в ресторане.
-	Create(restaurantID uint64, seatsNumber int) (uint64, error)
-	// GetAllAvailable возвращает список доступных для брони столиков конкретного ресторана.
-	// Принимает desiredDate в формате "2006.01.02" и desiredTime - "15:04".
-	GetAllAvailable(restaurantID uint64, desiredDate, desiredTime string) ([]model.Table, error)
+	// Create создаёт столик в ресторане. Принимает position - расположение столика в зале и zone - зону ресторана,
+	// в которой стоит столик (по умолчанию - основной зал).
+	Create(restaurantID uint64, seatsNumber int, position, zone string) (uint64, error)
+	// GetAllAvailable возвращает список доступных для брони на duration столиков конкретного ресторана.
+	// Если указана зона (zone), возвращаются только столики в этой зоне.
+	GetAllAvailable(restaurantID uint64, desiredDateTime time.Time, duration time.Duration, zone string) ([]model.Table, error)
 	// GetAll возвращает список всех столиков ресторана.
 	GetAll(restaurantID uint64) ([]model.Table, error)
 	// Get получает столик ресторана по его ID.
@@ -20,23 +25,57 @@ type TableService interface {
 	Update(id uint64, data model.UpdateTableData) error
 	// Delete удаляет столик из ресторана по его ID, ЕСЛИ ОН НЕ ЗАБРОНИРОВАН НА БУДУЩЕЕ ВРЕМЯ.
 	Delete(id uint64) error
+	// GetJoinable возвращает список столиков, которые можно сдвинуть со столиком с ID id.
+	GetJoinable(id uint64) ([]model.Table, error)
+	// Join отмечает, что столики можно сдвинуть. Сдвигать можно только разные столики одного ресторана.
+	Join(id, joinableID uint64) error
+	// Unjoin отмечает, что столики сдвигать нельзя.
+	Unjoin(id, joinableID uint64) error
 }
 
 // TableServiceImpl представляет реализацю TableService.
 type TableServiceImpl struct {
 	tableRepo store.TableRepository
+	// waitlist получает места за новыми столиками
+	waitlist WaitlistService
+	// events сообщает хостам ресторана об изменении столиков
+	events pubsub.Publisher
 }
 
-func NewTableService(tableRepo store.TableRepository) *TableServiceImpl {
-	return &TableServiceImpl{tableRepo: tableRepo}
+func NewTableService(
+	tableRepo store.TableRepository, waitlist WaitlistService, events pubsub.Publisher,
+) *TableServiceImpl {
+	return &TableServiceImpl{tableRepo: tableRepo, waitlist: waitlist, events: events}
 }
 
-func (s *TableServiceImpl) Create(restaurantID uint64, seatsNumber int) (uint64, error) {
-	return s.tableRepo.Create(restaurantID, seatsNumber)
+func (s *TableServiceImpl) Create(restaurantID uint64, seatsNumber int, position, zone string) (uint64, error) {
+	if seatsNumber <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidData, model.ErrInvalidSeatsNumber.Error())
+	}
+	if zone == "" {
+		zone = model.ZoneHall
+	}
+	if !model.IsZone(zone) {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidData, model.ErrUnknownZone.Error())
+	}
+	id, err := s.tableRepo.Create(restaurantID, seatsNumber, position, zone)
+	if err != nil {
+		return 0, err
+	}
+	publishTableChanged(s.events, s.tableRepo, restaurantID, id)
+
+	// за новым столиком могут сесть гости из листа ожидания; столик уже создан, поэтому ошибка не возвращается
+	if s.waitlist != nil {
+		_ = s.waitlist.OfferFreedCapacity(restaurantID)
+	}
+	return id, nil
 }
 
-func (s *TableServiceImpl) GetAllAvailable(restaurantID uint64, desiredDate, desiredTime string) ([]model.Table, error) {
-	return s.tableRepo.GetAllAvailable(restaurantID, desiredDate, desiredTime)
+func (s *TableServiceImpl) GetAllAvailable(restaurantID uint64, desiredDateTime time.Time, duration time.Duration, zone string) ([]model.Table, error) {
+	if zone != "" && !model.IsZone(zone) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidData, model.ErrUnknownZone.Error())
+	}
+	return s.tableRepo.GetAllAvailable(restaurantID, desiredDateTime, duration, zone)
 }
 
 func (s *TableServiceImpl) GetAll(restaurantID uint64) ([]model.Table, error) {
@@ -48,9 +87,106 @@ func (s *TableServiceImpl) Get(id uint64) (*model.Table, error) {
 }
 
 func (s *TableServiceImpl) Update(id uint64, data model.UpdateTableData) error {
-	return s.tableRepo.Update(id, data)
+	if data.SeatsNumber != nil && *data.SeatsNumber <= 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidData, model.ErrInvalidSeatsNumber.Error())
+	}
+	if data.Zone != nil && !model.IsZone(*data.Zone) {
+		return fmt.Errorf("%w: %s", ErrInvalidData, model.ErrUnknownZone.Error())
+	}
+	table, err := s.tableRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.tableRepo.Update(id, data); err != nil {
+		return err
+	}
+	publishTableChanged(s.events, s.tableRepo, table.RestaurantID, id)
+	return nil
 }
 
 func (s *TableServiceImpl) Delete(id uint64) error {
-	return s.tableRepo.Delete(id)
+	table, err := s.tableRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.tableRepo.Delete(id); err != nil {
+		return err
+	}
+	publishTableChanged(s.events, s.tableRepo, table.RestaurantID, id)
+	// удалённый столик больше нельзя сдвинуть со столиками, с которыми его можно было сдвинуть
+	for _, joinableID := range table.JoinableWith {
+		publishTableChanged(s.events, s.tableRepo, table.RestaurantID, joinableID)
+	}
+	return nil
+}
+
+func (s *TableServiceImpl) GetJoinable(id uint64) ([]model.Table, error) {
+	table, err := s.tableRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := s.tableRepo.GetAll(table.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	joinable := make([]model.Table, 0, len(table.JoinableWith))
+	for _, t := range tables {
+		if table.IsJoinableWith(t.ID) {
+			joinable = append(joinable, t)
+		}
+	}
+	return joinable, nil
+}
+
+func (s *TableServiceImpl) Join(id, joinableID uint64) error {
+	return s.changeJoin(id, joinableID, s.tableRepo.Join)
+}
+
+func (s *TableServiceImpl) Unjoin(id, joinableID uint64) error {
+	return s.changeJoin(id, joinableID, s.tableRepo.Unjoin)
+}
+
+// changeJoin отмечает функцией change, можно ли сдвигать столики, и сообщает хостам ресторана об изменении
+// обоих столиков.
+func (s *TableServiceImpl) changeJoin(id, joinableID uint64, change func(id, joinableID uint64) error) error {
+	if err := s.checkJoinable(id, joinableID); err != nil {
+		return err
+	}
+
+	if err := change(id, joinableID); err != nil {
+		return err
+	}
+
+	// столики относятся к одному ресторану, это проверено в checkJoinable
+	if table, err := s.tableRepo.Get(id); err == nil {
+		publishTableChanged(s.events, s.tableRepo, table.RestaurantID, id)
+		publishTableChanged(s.events, s.tableRepo, table.RestaurantID, joinableID)
+	}
+	return nil
+}
+
+// checkJoinable проверяет, что столики существуют, различаются и относятся к одному ресторану.
+func (s *TableServiceImpl) checkJoinable(id, joinableID uint64) error {
+	if id == joinableID {
+		return fmt.Errorf("%w: a table cannot be joined with itself", ErrInvalidData)
+	}
+
+	table, err := s.tableRepo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	joinableTable, err := s.tableRepo.Get(joinableID)
+	if err != nil {
+		return err
+	}
+
+	if table.RestaurantID != joinableTable.RestaurantID {
+		return fmt.Errorf("%w: tables of different restaurants cannot be joined", ErrInvalidData)
+	}
+	return nil
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// apiKeyPrefix представляет префикс ключей API, по которому их легко узнать (например, в логах или коде).
+const apiKeyPrefix = "rtb_"
+
+// UserService представляет бизнес-логику работы с пользователями API администрирования и их аутентификации.
+type UserService interface {
+	// Authenticate возвращает пользователя по его ключу API. Ключ администратора из настроек сервиса даёт права
+	// администратора платформы. Если ключ не подходит, возвращается ErrInvalidAPIKey.
+	Authenticate(apiKey string) (*model.User, error)
+	// Create создаёт пользователя и возвращает его вместе с ключом API. Ключ не хранится и больше не показывается.
+	Create(name, role string, restaurantIDs []uint64) (*model.User, string, error)
+	// GetAll возвращает список всех пользователей.
+	GetAll() ([]model.User, error)
+	// Get возвращает пользователя по его ID.
+	Get(id uint64) (*model.User, error)
+	// RegenerateAPIKey выпускает пользователю новый ключ API; прежний ключ перестаёт действовать.
+	RegenerateAPIKey(id uint64) (string, error)
+	// Delete удаляет пользователя по его ID.
+	Delete(id uint64) error
+}
+
+// UserServiceImpl представляет реализацию UserService.
+type UserServiceImpl struct {
+	userRepo store.UserRepository
+	// adminAPIKey представляет ключ API администратора платформы из настроек сервиса (пустая строка - ключа нет).
+	// С ним создаются первые пользователи.
+	adminAPIKey string
+}
+
+func NewUserService(userRepo store.UserRepository, adminAPIKey string) *UserServiceImpl {
+	return &UserServiceImpl{userRepo: userRepo, adminAPIKey: adminAPIKey}
+}
+
+func (s *UserServiceImpl) Authenticate(apiKey string) (*model.User, error) {
+	if apiKey == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if s.adminAPIKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(s.adminAPIKey)) == 1 {
+		return &model.User{Name: "admin", Role: model.UserRoleAdmin, RestaurantIDs: []uint64{}}, nil
+	}
+
+	// ключи API хранятся только в виде хешей, поэтому пользователь ищется по хешу переданного ключа
+	user, err := s.userRepo.GetByAPIKeyHash(hashToken(apiKey))
+	if errors.Is(err, store.ErrUserNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	return user, err
+}
+
+func (s *UserServiceImpl) Create(name, role string, restaurantIDs []uint64) (*model.User, string, error) {
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: the user's name is required", ErrInvalidData)
+	}
+
+	if !model.IsUserRole(role) {
+		return nil, "", fmt.Errorf("%w: unknown user role", ErrInvalidData)
+	}
+
+	// администратору доступны все рестораны, а менеджер и сотрудник работают только с закреплёнными за ними
+	if role == model.UserRoleAdmin {
+		restaurantIDs = nil
+	} else if len(restaurantIDs) == 0 {
+		return nil, "", fmt.Errorf("%w: managers and staff must be assigned at least one restaurant", ErrInvalidData)
+	}
+
+	apiKey, err := newAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, err := s.userRepo.Create(name, role, hashToken(apiKey), restaurantIDs...)
+	if errors.Is(err, store.ErrRestaurantNotFound) {
+		return nil, "", fmt.Errorf("%w: %s", ErrInvalidData, store.ErrRestaurantNotFound.Error())
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.userRepo.Get(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, apiKey, nil
+}
+
+func (s *UserServiceImpl) GetAll() ([]model.User, error) {
+	return s.userRepo.GetAll()
+}
+
+func (s *UserServiceImpl) Get(id uint64) (*model.User, error) {
+	return s.userRepo.Get(id)
+}
+
+func (s *UserServiceImpl) RegenerateAPIKey(id uint64) (string, error) {
+	apiKey, err := newAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.userRepo.SetAPIKeyHash(id, hashToken(apiKey)); err != nil {
+		return "", err
+	}
+	return apiKey, nil
+}
+
+func (s *UserServiceImpl) Delete(id uint64) error {
+	return s.userRepo.Delete(id)
+}
+
+// newAPIKey генерирует случайный ключ API.
+func newAPIKey() (string, error) {
+	key, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + key, nil
+}
+
+// newToken генерирует случайный секретный токен из 32 байт в шестнадцатеричном виде.
+func newToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// hashToken возвращает SHA-256 хеш секретного токена (ключа API или токена сеанса гостя) в шестнадцатеричном виде,
+// в котором токен хранится в БД.
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// waitlistOfferTTL представляет срок, в течение которого гость из листа ожидания может принять предложение
+// и оформить бронь на освободившиеся места: всё это время места удерживаются за ним.
+const waitlistOfferTTL = 15 * time.Minute
+
+// WaitlistService представляет бизнес-логику работы с листами ожидания ресторанов.
+type WaitlistService interface {
+	// Join ставит гостя в лист ожидания ресторана на выбранные дату, время и количество человек. Если места уже
+	// свободны, гостю сразу предлагается оформить бронь.
+	Join(details model.BookingDetails) (uint64, error)
+	// GetAll возвращает все записи листа ожидания ресторана в порядке очереди.
+	GetAll(restaurantID uint64) ([]model.WaitlistEntry, error)
+	// Get возвращает запись в листе ожидания по её ID.
+	Get(id uint64) (*model.WaitlistEntry, error)
+	// GetByClient возвращает запись в листе ожидания, если указанный гостем телефон совпадает с телефоном в записи.
+	GetByClient(id uint64, clientPhone string) (*model.WaitlistEntry, error)
+	// Accept принимает предложение, сделанное гостю из листа ожидания, и оформляет бронь. Возвращает ID брони.
+	Accept(id uint64) (uint64, error)
+	// AcceptByClient принимает предложение по просьбе гостя, если указанный им телефон совпадает с телефоном в записи.
+	AcceptByClient(id uint64, clientPhone string) (uint64, error)
+	// Leave убирает гостя из листа ожидания. Если гостю были предложены места, они предлагаются следующему гостю.
+	Leave(id uint64) error
+	// LeaveByClient убирает гостя из листа ожидания по его просьбе, если указанный им телефон совпадает с телефоном
+	// в записи.
+	LeaveByClient(id uint64, clientPhone string) error
+	// OfferFreedCapacity предлагает освободившиеся в ресторане места первому гостю из листа ожидания, компанию которого
+	// можно рассадить на желаемое им время: столики удерживаются за гостем, пока действует предложение, а гость получает
+	// уведомление. Вызывается, когда отменяется бронь или в ресторане появляется столик.
+	OfferFreedCapacity(restaurantID uint64) error
+}
+
+// WaitlistServiceImpl представляет реализацию WaitlistService.
+type WaitlistServiceImpl struct {
+	waitlistRepo   store.WaitlistRepository
+	tableRepo      store.TableRepository
+	restaurantRepo store.RestaurantRepository
+	hoursRepo      store.OpeningHoursRepository
+	policyRepo     store.DurationPolicyRepository
+	// notifier уведомляет гостей о предложенных им местах
+	notifier notifier.Notifier
+	// holds удерживает столики, предложенные гостям, и оформляет по ним брони, когда гости принимают предложения
+	holds *HoldServiceImpl
+	// bookingService оформляет брони по принятым предложениям, если места за гостем не удерживаются
+	bookingService BookingService
+}
+
+func NewWaitlistService(
+	waitlistRepo store.WaitlistRepository,
+	tableRepo store.TableRepository,
+	restaurantRepo store.RestaurantRepository,
+	hoursRepo store.OpeningHoursRepository,
+	policyRepo store.DurationPolicyRepository,
+	notifier notifier.Notifier,
+) *WaitlistServiceImpl {
+	return &WaitlistServiceImpl{
+		waitlistRepo:   waitlistRepo,
+		tableRepo:      tableRepo,
+		restaurantRepo: restaurantRepo,
+		hoursRepo:      hoursRepo,
+		policyRepo:     policyRepo,
+		notifier:       notifier,
+	}
+}
+
+func (s *WaitlistServiceImpl) Join(details model.BookingDetails) (uint64, error) {
+	// ждать мест в прошлом бессмысленно
+	dateTime, err := parseDesiredDatetime(details.DesiredDatetime)
+	if err != nil {
+		return 0, err
+	}
+
+	peopleNum, err := parsePeopleNumber(details.PeopleNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	// места освободятся только в то время, когда ресторан принимает гостей по своему графику работы
+	restaurant, err := getRestaurant(s.restaurantRepo, s.hoursRepo, s.policyRepo, details.RestaurantID)
+	if err != nil {
+		return 0, err
+	}
+	if !restaurant.AcceptsBookingAt(dateTime) {
+		return 0, fmt.Errorf("%w: the restaurant is closed at the desired time", ErrInvalidData)
+	}
+
+	id, err := s.waitlistRepo.Create(
+		details.RestaurantID, details.ClientName, details.ClientPhone, peopleNum, dateTime, dateTime,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// места могли освободиться, пока гость заполнял форму
+	if err = s.OfferFreedCapacity(details.RestaurantID); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *WaitlistServiceImpl) GetAll(restaurantID uint64) ([]model.WaitlistEntry, error) {
+	entries, err := s.waitlistRepo.GetAll(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	expired, err := s.expireEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	// места из просроченных предложений снова можно предложить другим гостям
+	if expired {
+		if err = s.OfferFreedCapacity(restaurantID); err != nil {
+			return nil, err
+		}
+		return s.waitlistRepo.GetAll(restaurantID)
+	}
+	return entries, nil
+}
+
+func (s *WaitlistServiceImpl) Get(id uint64) (*model.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []model.WaitlistEntry{*entry}
+	expired, err := s.expireEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if expired {
+		if err = s.OfferFreedCapacity(entry.RestaurantID); err != nil {
+			return nil, err
+		}
+	}
+	return &entries[0], nil
+}
+
+func (s *WaitlistServiceImpl) GetByClient(id uint64, clientPhone string) (*model.WaitlistEntry, error) {
+	entry, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// не раскрываем существование чужой записи: при несовпадении телефона запись считается ненайденной
+	if !samePhone(entry.ClientPhone, clientPhone) {
+		return nil, store.ErrWaitlistEntryNotFound
+	}
+	return entry, nil
+}
+
+func (s *WaitlistServiceImpl) Accept(id uint64) (uint64, error) {
+	entry, err := s.Get(id)
+	if err != nil {
+		return 0, err
+	}
+
+	if entry.Status != model.WaitlistStatusOffered {
+		return 0, ErrWaitlistNoOffer
+	}
+
+	// сначала занимаем предложение, чтобы одновременные запросы не оформили по нему две брони
+	accepted, err := s.waitlistRepo.UpdateStatus(
+		id, model.WaitlistStatusOffered, model.WaitlistStatusBooked, entry.OfferExpiresAt, entry.HoldID, nil,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if !accepted {
+		return 0, ErrWaitlistNoOffer
+	}
+
+	bookingID, err := s.book(entry)
+	if err != nil {
+		// предложенные места успели занять другие гости: гость снова ждёт своей очереди, иначе предложение
+		// остаётся в силе
+		status := model.WaitlistStatusOffered
+		offerExpiresAt, holdID := entry.OfferExpiresAt, entry.HoldID
+		if errors.Is(err, ErrNotEnoughSeatsInRestaurant) {
+			status, offerExpiresAt, holdID = model.WaitlistStatusWaiting, nil, nil
+		}
+		if _, updateErr := s.waitlistRepo.UpdateStatus(
+			id, model.WaitlistStatusBooked, status, offerExpiresAt, holdID, nil,
+		); updateErr != nil {
+			return 0, updateErr
+		}
+		return 0, err
+	}
+
+	// удержание снято при оформлении брони
+	if _, err = s.waitlistRepo.UpdateStatus(
+		id, model.WaitlistStatusBooked, model.WaitlistStatusBooked, entry.OfferExpiresAt, nil, &bookingID,
+	); err != nil {
+		return 0, err
+	}
+	return bookingID, nil
+}
+
+// book оформляет бронь по предложению на столики, удерживаемые за гостем. Если места за гостем не удерживаются
+// (например, предложение сделано до того, как места стали удерживаться) или срок удержания истёк, бронь оформляется
+// на свободные в этот момент столики.
+func (s *WaitlistServiceImpl) book(entry *model.WaitlistEntry) (uint64, error) {
+	if entry.HoldID != nil {
+		hold, err := s.holds.holdRepo.Get(*entry.HoldID)
+		if err != nil && !errors.Is(err, store.ErrHoldNotFound) {
+			return 0, err
+		}
+		if err == nil {
+			bookingID, err := s.holds.confirm(hold, 0, entry.ClientName, entry.ClientPhone, "")
+			if !errors.Is(err, ErrHoldExpired) {
+				return bookingID, err
+			}
+		}
+	}
+
+	return s.bookingService.Create(entry.BookingDetails())
+}
+
+func (s *WaitlistServiceImpl) AcceptByClient(id uint64, clientPhone string) (uint64, error) {
+	if _, err := s.GetByClient(id, clientPhone); err != nil {
+		return 0, err
+	}
+	return s.Accept(id)
+}
+
+func (s *WaitlistServiceImpl) Leave(id uint64) error {
+	entry, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if !entry.IsActive() {
+		return ErrWaitlistEntryClosed
+	}
+
+	left, err := s.waitlistRepo.UpdateStatus(id, entry.Status, model.WaitlistStatusCancelled, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	if !left {
+		return ErrWaitlistEntryClosed
+	}
+
+	// места, которые предлагались гостю, достаются следующему в очереди
+	if entry.Status == model.WaitlistStatusOffered {
+		if err = s.releaseOfferHold(*entry); err != nil {
+			return err
+		}
+		return s.OfferFreedCapacity(entry.RestaurantID)
+	}
+	return nil
+}
+
+func (s *WaitlistServiceImpl) LeaveByClient(id uint64, clientPhone string) error {
+	if _, err := s.GetByClient(id, clientPhone); err != nil {
+		return err
+	}
+	return s.Leave(id)
+}
+
+func (s *WaitlistServiceImpl) OfferFreedCapacity(restaurantID uint64) error {
+	entries, err := s.waitlistRepo.GetAll(restaurantID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.expireEntries(entries); err != nil {
+		return err
+	}
+
+	restaurant, err := getRestaurant(s.restaurantRepo, s.hoursRepo, s.policyRepo, restaurantID)
+	if err != nil {
+		return err
+	}
+
+	// гости получают предложения в порядке очереди: места достаются первому, чью компанию можно рассадить
+	for _, entry := range entries {
+		if entry.Status != model.WaitlistStatusWaiting {
+			continue
+		}
+
+		offered, err := s.offer(restaurant, entry)
+		if err != nil {
+			return err
+		}
+		if offered {
+			return nil
+		}
+	}
+	return nil
+}
+
+// offer удерживает за гостем из листа ожидания столики на желаемое им время, пока действует предложение, и сообщает
+// гостю о предложении. Возвращает false, если компанию гостя сейчас рассадить нельзя или запись одновременно изменил
+// другой запрос.
+func (s *WaitlistServiceImpl) offer(restaurant *model.Restaurant, entry model.WaitlistEntry) (bool, error) {
+	dateTime := entry.DesiredAt()
+	if !restaurant.AcceptsBookingAt(dateTime) {
+		return false, nil
+	}
+
+	// токен удержания никому не выдаётся: бронь по нему оформляет только лист ожидания
+	token, err := newToken()
+	if err != nil {
+		return false, err
+	}
+
+	offerExpiresAt := time.Now().Add(waitlistOfferTTL)
+	holdID, err := s.holds.hold(
+		restaurant, entry.BookingDetails(), entry.PeopleNumber, dateTime, offerExpiresAt, hashToken(token), "",
+	)
+	if errors.Is(err, ErrNotEnoughSeatsInRestaurant) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	offered, err := s.waitlistRepo.UpdateStatus(
+		entry.ID, model.WaitlistStatusWaiting, model.WaitlistStatusOffered, &offerExpiresAt, &holdID, nil,
+	)
+	if err != nil || !offered {
+		// удержанные столики не должны пропадать до истечения срока удержания
+		if deleteErr := s.holds.holdRepo.Delete(holdID); err == nil && deleteErr != nil {
+			err = deleteErr
+		}
+		return false, err
+	}
+
+	entry.Status, entry.OfferExpiresAt, entry.HoldID = model.WaitlistStatusOffered, &offerExpiresAt, &holdID
+	s.notifyOffer(restaurant, entry)
+	return true, nil
+}
+
+// notifyOffer сообщает гостю из листа ожидания, что ему предложены места. Предложение к этому моменту уже сделано,
+// поэтому ошибка не возвращается: гость увидит предложение и на странице листа ожидания.
+func (s *WaitlistServiceImpl) notifyOffer(restaurant *model.Restaurant, entry model.WaitlistEntry) {
+	if s.notifier == nil {
+		return
+	}
+
+	hold, err := s.holds.holdRepo.Get(*entry.HoldID)
+	if err != nil {
+		return
+	}
+
+	_ = s.notifier.Notify(context.Background(), notifier.Notification{
+		Event: notifier.EventWaitlistOffer,
+		Booking: model.Booking{
+			RestaurantID:   restaurant.ID,
+			ClientName:     entry.ClientName,
+			ClientPhone:    entry.ClientPhone,
+			PeopleNumber:   entry.PeopleNumber,
+			BookedDate:     hold.HeldDate,
+			BookedTimeFrom: hold.HeldTimeFrom,
+			BookedTimeTo:   hold.HeldTimeTo,
+		},
+		WaitlistEntry:  entry,
+		RestaurantName: restaurant.Name,
+	})
+}
+
+// releaseOfferHold снимает удержание столиков, предложенных гостю, когда предложение перестаёт действовать.
+func (s *WaitlistServiceImpl) releaseOfferHold(entry model.WaitlistEntry) error {
+	if entry.HoldID == nil {
+		return nil
+	}
+
+	// удержание могло уже истечь и быть снято в фоне
+	if err := s.holds.holdRepo.Delete(*entry.HoldID); err != nil && !errors.Is(err, store.ErrHoldNotFound) {
+		return err
+	}
+	return nil
+}
+
+// expireEntries переводит в состояние model.WaitlistStatusExpired записи, срок предложения которых истёк или желаемое
+// время посещения которых уже наступило. Записи обновляются на месте. Возвращает true, если истекло хотя бы одно
+// предложение (и предложенные места можно предложить другим гостям).
+func (s *WaitlistServiceImpl) expireEntries(entries []model.WaitlistEntry) (bool, error) {
+	now := time.Now()
+	offerExpired := false
+
+	for i, entry := range entries {
+		expired := false
+		switch entry.Status {
+		case model.WaitlistStatusWaiting:
+			expired = !now.Before(entry.DesiredAt())
+		case model.WaitlistStatusOffered:
+			expired = !now.Before(entry.DesiredAt()) || entry.OfferExpiresAt != nil && now.After(*entry.OfferExpiresAt)
+		}
+		if !expired {
+			continue
+		}
+
+		updated, err := s.waitlistRepo.UpdateStatus(
+			entry.ID, entry.Status, model.WaitlistStatusExpired, entry.OfferExpiresAt, nil, nil,
+		)
+		if err != nil {
+			return offerExpired, err
+		}
+		if !updated {
+			continue
+		}
+
+		if entry.Status == model.WaitlistStatusOffered {
+			if err = s.releaseOfferHold(entry); err != nil {
+				return offerExpired, err
+			}
+			offerExpired = true
+		}
+		entries[i].Status, entries[i].HoldID = model.WaitlistStatusExpired, nil
+	}
+	return offerExpired, nil
+}
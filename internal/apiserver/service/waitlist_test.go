@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/notifier"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// recordingNotifier запоминает отправленные уведомления.
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []notifier.Notification
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification notifier.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+// events возвращает уведомления о событии event.
+func (n *recordingNotifier) events(event string) []notifier.Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var notifications []notifier.Notification
+	for _, notification := range n.notifications {
+		if notification.Event == event {
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications
+}
+
+// waitlistFixture представляет ресторан с одним столиком на 4 места, который забронирован через неделю в 19:00,
+// и гостя, который ждёт этот столик в листе ожидания.
+type waitlistFixture struct {
+	store     store.Store
+	services  *Services
+	notifier  *recordingNotifier
+	details   model.BookingDetails
+	tableID   uint64
+	bookingID uint64
+	entryID   uint64
+}
+
+func newWaitlistFixture(t *testing.T) *waitlistFixture {
+	t.Helper()
+
+	st := memory.NewStore()
+	restaurantID, err := st.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableID, err := st.Tables().Create(restaurantID, 4, "", model.ZoneHall)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &recordingNotifier{}
+	services := NewServices(st, "test-admin-key", rec, nil, 0, "test-booking-link-key", nil)
+
+	week := time.Now().AddDate(0, 0, 7)
+	details := model.BookingDetails{
+		RestaurantID:    restaurantID,
+		PeopleNumber:    "4",
+		DesiredDatetime: time.Date(week.Year(), week.Month(), week.Day(), 19, 0, 0, 0, time.Local).Format("2006.01.02 15:04"),
+		ClientName:      "Павел",
+		ClientPhone:     "+79485722648",
+	}
+	bookingID, err := services.BookingService.Create(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waiting := details
+	waiting.ClientName, waiting.ClientPhone = "Анна", "+79279007265"
+	entryID, err := services.WaitlistService.Join(waiting)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &waitlistFixture{
+		store:     st,
+		services:  services,
+		notifier:  rec,
+		details:   details,
+		tableID:   tableID,
+		bookingID: bookingID,
+		entryID:   entryID,
+	}
+}
+
+// entry возвращает запись гостя в листе ожидания.
+func (f *waitlistFixture) entry(t *testing.T) *model.WaitlistEntry {
+	t.Helper()
+
+	entry, err := f.services.WaitlistService.Get(f.entryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+// offer отменяет бронь, освобождая столик для гостя из листа ожидания, и проверяет, что столик удержан за гостем.
+func (f *waitlistFixture) offer(t *testing.T) *model.WaitlistEntry {
+	t.Helper()
+
+	if entry := f.entry(t); entry.Status != model.WaitlistStatusWaiting {
+		t.Fatalf("entry status = %s before the booking is cancelled, want %s", entry.Status, model.WaitlistStatusWaiting)
+	}
+	if err := f.services.BookingService.Cancel(f.bookingID, model.BookingCancelledByRestaurant); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := f.entry(t)
+	if entry.Status != model.WaitlistStatusOffered || entry.HoldID == nil {
+		t.Fatalf("entry status = %s, hold = %v; want an offer backed by a hold", entry.Status, entry.HoldID)
+	}
+	hold, err := f.store.Holds().Get(*entry.HoldID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hold.TableIDs) != 1 || hold.TableIDs[0] != f.tableID || hold.PeopleNumber != entry.PeopleNumber {
+		t.Errorf("hold = %+v, want the freed table for %d people", hold, entry.PeopleNumber)
+	}
+	if !hold.ExpiresAt.Equal(*entry.OfferExpiresAt) {
+		t.Errorf("hold expires at %s, want when the offer expires at %s", hold.ExpiresAt, entry.OfferExpiresAt)
+	}
+
+	// пока предложение действует, места не достаются другим гостям
+	if _, err = f.services.BookingService.Create(f.details); !errors.Is(err, ErrNotEnoughSeatsInRestaurant) {
+		t.Errorf("booking the offered table: error = %v, want ErrNotEnoughSeatsInRestaurant", err)
+	}
+	return entry
+}
+
+func TestWaitlist_OfferHoldsTablesAndNotifies(t *testing.T) {
+	f := newWaitlistFixture(t)
+	entry := f.offer(t)
+
+	offers := f.notifier.events(notifier.EventWaitlistOffer)
+	if len(offers) != 1 {
+		t.Fatalf("sent %d offer notifications, want 1", len(offers))
+	}
+	if offers[0].Booking.ClientPhone != entry.ClientPhone || offers[0].WaitlistEntry.ID != entry.ID {
+		t.Errorf("offer notification = %+v, want it for entry %d to %s", offers[0], entry.ID, entry.ClientPhone)
+	}
+	message, err := notifier.Render(offers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(message.Text, entry.OfferExpiresAt.Format("15:04")) ||
+		!strings.Contains(message.Text, "19:00") {
+		t.Errorf("offer message does not mention the time and the offer deadline: %s", message.Text)
+	}
+
+	bookingID, err := f.services.WaitlistService.Accept(entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	booking, err := f.store.Bookings().Get(bookingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(booking.TableIDs) != 1 || booking.TableIDs[0] != f.tableID || booking.ClientPhone != entry.ClientPhone {
+		t.Errorf("booking = %+v, want the held table for %s", booking, entry.ClientPhone)
+	}
+	if _, err = f.store.Holds().Get(*entry.HoldID); !errors.Is(err, store.ErrHoldNotFound) {
+		t.Errorf("hold is not released after the offer is accepted: %v", err)
+	}
+	if created := f.notifier.events(notifier.EventBookingCreated); len(created) != 2 {
+		t.Errorf("sent %d booking notifications, want 2", len(created))
+	}
+}
+
+func TestWaitlist_LeaveReleasesHold(t *testing.T) {
+	f := newWaitlistFixture(t)
+	entry := f.offer(t)
+
+	if err := f.services.WaitlistService.Leave(entry.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.store.Holds().Get(*entry.HoldID); !errors.Is(err, store.ErrHoldNotFound) {
+		t.Errorf("hold is not released after the guest left: %v", err)
+	}
+	if _, err := f.services.BookingService.Create(f.details); err != nil {
+		t.Errorf("booking the released table: %v", err)
+	}
+}
+
+func TestWaitlist_ExpiredOfferReleasesHold(t *testing.T) {
+	f := newWaitlistFixture(t)
+	entry := f.offer(t)
+
+	// предложение истекло
+	expired := time.Now().Add(-time.Minute)
+	if _, err := f.store.Waitlist().UpdateStatus(
+		entry.ID, model.WaitlistStatusOffered, model.WaitlistStatusOffered, &expired, entry.HoldID, nil,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry = f.entry(t); entry.Status != model.WaitlistStatusExpired {
+		t.Errorf("entry status = %s, want %s", entry.Status, model.WaitlistStatusExpired)
+	}
+	if _, err := f.services.BookingService.Create(f.details); err != nil {
+		t.Errorf("booking the table from the expired offer: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// webhookDeliveriesLimit ограничивает количество последних доставок, которые показывает журнал доставки вебхука.
+const webhookDeliveriesLimit = 50
+
+// WebhookService представляет бизнес-логику работы с вебхуками ресторанов.
+type WebhookService interface {
+	// Create создаёт вебхук ресторана и возвращает его вместе с ключом, которым подписываются события.
+	// Ключ больше не показывается.
+	Create(restaurantID uint64, data model.CreateWebhookData) (*model.Webhook, string, error)
+	// GetAll возвращает список вебхуков ресторана.
+	GetAll(restaurantID uint64) ([]model.Webhook, error)
+	// Get возвращает вебхук по его ID.
+	Get(id uint64) (*model.Webhook, error)
+	// Delete удаляет вебхук по его ID вместе с журналом доставки ему событий.
+	Delete(id uint64) error
+	// GetDeliveries возвращает последние доставки событий вебхуку (первыми идут самые новые).
+	GetDeliveries(webhookID uint64) ([]model.WebhookDelivery, error)
+}
+
+// WebhookServiceImpl представляет реализацию WebhookService.
+type WebhookServiceImpl struct {
+	webhookRepo store.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo store.WebhookRepository) *WebhookServiceImpl {
+	return &WebhookServiceImpl{webhookRepo: webhookRepo}
+}
+
+func (s *WebhookServiceImpl) Create(
+	restaurantID uint64, data model.CreateWebhookData,
+) (*model.Webhook, string, error) {
+	if err := data.Validate(); err != nil {
+		return nil, "", fmt.Errorf("%w: %s", ErrInvalidData, err.Error())
+	}
+
+	secret, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, err := s.webhookRepo.Create(restaurantID, data.URL, secret, data.Events)
+	if err != nil {
+		return nil, "", err
+	}
+
+	webhook, err := s.webhookRepo.Get(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return webhook, secret, nil
+}
+
+func (s *WebhookServiceImpl) GetAll(restaurantID uint64) ([]model.Webhook, error) {
+	return s.webhookRepo.GetAll(restaurantID)
+}
+
+func (s *WebhookServiceImpl) Get(id uint64) (*model.Webhook, error) {
+	return s.webhookRepo.Get(id)
+}
+
+func (s *WebhookServiceImpl) Delete(id uint64) error {
+	if _, err := s.webhookRepo.Get(id); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(id)
+}
+
+func (s *WebhookServiceImpl) GetDeliveries(webhookID uint64) ([]model.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.Get(webhookID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetDeliveries(webhookID, webhookDeliveriesLimit)
+}
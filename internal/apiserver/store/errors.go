@@ -7,8 +7,29 @@ var (
 	ErrRestaurantNotFound = errors.New("restaurant not found")
 	// ErrTableNotFound возникает, когда по введённому ID в БД не находится искомого ресторана.
 	ErrTableNotFound = errors.New("table not found")
+	// ErrBookingNotFound возникает, когда по введённому ID в БД не находится искомой брони.
+	ErrBookingNotFound = errors.New("booking not found")
+	// ErrWaitlistEntryNotFound возникает, когда по введённому ID в БД не находится искомой записи в листе ожидания.
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	// ErrHoldNotFound возникает, когда по введённому ID в БД не находится действующего удержания столиков.
+	ErrHoldNotFound = errors.New("hold not found")
+	// ErrUserNotFound возникает, когда по введённому ID или ключу API в БД не находится искомого пользователя.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrCustomerNotFound возникает, когда по введённому ID, телефону или токену сеанса в БД не находится учётной
+	// записи гостя.
+	ErrCustomerNotFound = errors.New("customer not found")
+	// ErrGuestNotFound возникает, когда по введённому ID или телефону в БД не находится искомого гостя.
+	ErrGuestNotFound = errors.New("guest not found")
+	// ErrWebhookNotFound возникает, когда по введённому ID в БД не находится искомого вебхука.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrCustomerAlreadyExists возникает при попытке зарегистрировать гостя с телефоном, на который уже есть учётная
+	// запись.
+	ErrCustomerAlreadyExists = errors.New("a customer with this phone number already exists")
 	// ErrRestaurantIsBooked возникает при попытке удалить ресторан, в который ещё придут клиенты.
 	ErrRestaurantIsBooked = errors.New("clients are expected in the restaurant today or in the future")
 	// ErrTableIsBooked возникает при попытке удалить столик, за которым должны будут сидеть клиенты.
 	ErrTableIsBooked = errors.New("the table is booked for today or in the future")
+	// ErrTableAlreadyBooked возникает, когда столик уже забронирован на пересекающееся время (например, другим клиентом,
+	// который оформил бронь одновременно с текущим).
+	ErrTableAlreadyBooked = errors.New("the table is already booked for the desired time")
 )
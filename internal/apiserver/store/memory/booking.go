@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.BookingRepository = (*BookingRepository)(nil)
+
+// BookingRepository представляет реализацю store.BookingRepository.
+type BookingRepository struct {
+	store *Store
+}
+
+func NewBookingRepository(store *Store) *BookingRepository {
+	return &BookingRepository{store: store}
+}
+
+func (r *BookingRepository) Create(
+	restaurantID, customerID uint64, clientName, clientPhone, clientEmail string, peopleNumber int, status string,
+	bookedDate, bookedTimeFrom time.Time, duration time.Duration, tableIDs ...uint64,
+) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничений внешних ключей fk_bookings_restaurants и fk_bookings_tables_tables: ресторан и все столики
+	// должны существовать, иначе бронь не создаётся (как при откате транзакции)
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return 0, fmt.Errorf("create booking: %w", store.ErrRestaurantNotFound)
+	}
+	for _, tableID := range tableIDs {
+		if _, ok := r.store.tables[tableID]; !ok {
+			return 0, fmt.Errorf("create booking: %w", store.ErrTableNotFound)
+		}
+	}
+
+	// аналог ограничения внешнего ключа fk_bookings_customers
+	if _, ok := r.store.customers[customerID]; customerID != 0 && !ok {
+		return 0, fmt.Errorf("create booking: %w", store.ErrCustomerNotFound)
+	}
+
+	bookingID, err := r.store.insertBooking(
+		restaurantID, customerID, clientName, clientPhone, clientEmail, peopleNumber, status,
+		bookedDate, bookedTimeFrom, duration, tableIDs...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("create booking: %w", err)
+	}
+	return bookingID, nil
+}
+
+// insertBooking добавляет бронь и привязывает к ней столики, если все они свободны на время брони.
+// Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) insertBooking(
+	restaurantID, customerID uint64, clientName, clientPhone, clientEmail string, peopleNumber int, status string,
+	bookedDate, bookedTimeFrom time.Time, duration time.Duration, tableIDs ...uint64,
+) (uint64, error) {
+	// приводим значения к виду, в котором они хранятся в колонках DATE и TIME
+	dateYear, dateMonth, dateDay := bookedDate.Date()
+	timeFrom := time.Date(0, 1, 1, bookedTimeFrom.Hour(), bookedTimeFrom.Minute(), bookedTimeFrom.Second(), 0, time.UTC)
+
+	// аналог ограничения excl_bookings_tables_overlap: столики проверяются под той же блокировкой, под которой
+	// создаётся бронь, поэтому одновременные брони не могут занять один столик на пересекающееся время
+	for _, tableID := range tableIDs {
+		if !s.isTableAvailable(tableID, bookedDate, model.TimeOfDay(timeFrom), duration) {
+			return 0, store.ErrTableAlreadyBooked
+		}
+	}
+
+	s.bookingSeq++
+	bookingID := s.bookingSeq
+	booking := model.Booking{
+		ID:             bookingID,
+		RestaurantID:   restaurantID,
+		ClientName:     clientName,
+		ClientPhone:    clientPhone,
+		ClientEmail:    clientEmail,
+		PeopleNumber:   peopleNumber,
+		Status:         status,
+		BookedDate:     model.ShortFormattedDate(time.Date(dateYear, dateMonth, dateDay, 0, 0, 0, 0, time.UTC)),
+		BookedTimeFrom: model.ShortFormattedTime(timeFrom),
+		BookedTimeTo:   model.ShortFormattedTime(timeFrom.Add(duration)),
+	}
+	if customerID != 0 {
+		booking.CustomerID = &customerID
+	}
+	if guestID, ok := s.upsertGuest(clientName, clientPhone); ok {
+		booking.GuestID = &guestID
+	}
+	s.bookings[bookingID] = booking
+
+	// привязываем все столики, которые мы хотим забранировать, к только что созданной брони
+	for _, tableID := range tableIDs {
+		s.bookingsTablesSeq++
+		s.bookingsTables[s.bookingsTablesSeq] = model.BookingsTables{
+			ID:        s.bookingsTablesSeq,
+			BookingID: bookingID,
+			TableID:   tableID,
+		}
+	}
+
+	// сообщаем вебхукам ресторана о новой брони
+	s.addOutboxEvent(model.WebhookEventBookingCreated, bookingID)
+
+	return bookingID, nil
+}
+
+func (r *BookingRepository) GetAll(restaurantID uint64) ([]model.Booking, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var bookings []model.Booking
+	for _, booking := range r.store.bookings {
+		if booking.RestaurantID == restaurantID {
+			bookings = append(bookings, booking)
+		}
+	}
+	sort.Slice(bookings, func(i, j int) bool {
+		return bookings[i].ID < bookings[j].ID
+	})
+	return bookings, nil
+}
+
+func (r *BookingRepository) GetAllByCustomer(customerID uint64) ([]model.Booking, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var bookings []model.Booking
+	for _, booking := range r.store.bookings {
+		if booking.CustomerID != nil && *booking.CustomerID == customerID {
+			bookings = append(bookings, booking)
+		}
+	}
+	sort.Slice(bookings, func(i, j int) bool {
+		return bookings[i].ID < bookings[j].ID
+	})
+	return bookings, nil
+}
+
+func (r *BookingRepository) GetAllByGuest(guestID uint64) ([]model.Booking, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var bookings []model.Booking
+	for _, booking := range r.store.bookings {
+		if booking.GuestID != nil && *booking.GuestID == guestID {
+			bookings = append(bookings, booking)
+		}
+	}
+	sort.Slice(bookings, func(i, j int) bool {
+		return bookings[i].ID < bookings[j].ID
+	})
+	return bookings, nil
+}
+
+func (r *BookingRepository) Get(id uint64) (*model.Booking, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	booking, ok := r.store.getBooking(id)
+	if !ok {
+		return nil, store.ErrBookingNotFound
+	}
+	return &booking, nil
+}
+
+// getBooking возвращает бронь по её ID вместе с ID забронированных столиков. Вызывающий код должен удерживать
+// блокировку s.mu.
+func (s *Store) getBooking(id uint64) (model.Booking, bool) {
+	booking, ok := s.bookings[id]
+	if !ok {
+		return model.Booking{}, false
+	}
+
+	// получаем столики, забронированные в рамках брони
+	for _, bt := range s.bookingsTables {
+		if bt.BookingID == id {
+			booking.TableIDs = append(booking.TableIDs, bt.TableID)
+		}
+	}
+	sort.Slice(booking.TableIDs, func(i, j int) bool {
+		return booking.TableIDs[i] < booking.TableIDs[j]
+	})
+	return booking, true
+}
+
+func (r *BookingRepository) Update(
+	id uint64, peopleNumber int, bookedDate, bookedTimeFrom time.Time, duration time.Duration, tableIDs ...uint64,
+) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// изменить можно только бронь, которая ждёт гостей
+	booking, ok := r.store.bookings[id]
+	if !ok || !booking.IsActive() {
+		return fmt.Errorf("update booking: %w", store.ErrBookingNotFound)
+	}
+	for _, tableID := range tableIDs {
+		if _, ok = r.store.tables[tableID]; !ok {
+			return fmt.Errorf("update booking: %w", store.ErrTableNotFound)
+		}
+	}
+
+	// приводим значения к виду, в котором они хранятся в колонках DATE и TIME
+	dateYear, dateMonth, dateDay := bookedDate.Date()
+	timeFrom := time.Date(0, 1, 1, bookedTimeFrom.Hour(), bookedTimeFrom.Minute(), bookedTimeFrom.Second(), 0, time.UTC)
+
+	// аналог ограничения excl_bookings_tables_overlap: прежние столики самой брони не мешают занять их на новое время
+	for _, tableID := range tableIDs {
+		if !r.store.isTableAvailableExcept(tableID, bookedDate, model.TimeOfDay(timeFrom), duration, id) {
+			return fmt.Errorf("update booking: %w", store.ErrTableAlreadyBooked)
+		}
+	}
+
+	booking.PeopleNumber = peopleNumber
+	booking.BookedDate = model.ShortFormattedDate(time.Date(dateYear, dateMonth, dateDay, 0, 0, 0, 0, time.UTC))
+	booking.BookedTimeFrom = model.ShortFormattedTime(timeFrom)
+	booking.BookedTimeTo = model.ShortFormattedTime(timeFrom.Add(duration))
+	r.store.bookings[id] = booking
+
+	// заменяем столики брони новыми
+	r.store.deleteBookingsTables(id)
+	for _, tableID := range tableIDs {
+		r.store.bookingsTablesSeq++
+		r.store.bookingsTables[r.store.bookingsTablesSeq] = model.BookingsTables{
+			ID:        r.store.bookingsTablesSeq,
+			BookingID: id,
+			TableID:   tableID,
+		}
+	}
+
+	// сообщаем вебхукам ресторана об изменении брони
+	r.store.addOutboxEvent(model.WebhookEventBookingUpdated, id)
+	return nil
+}
+
+func (r *BookingRepository) Cancel(id uint64, cancelledBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	booking, ok := r.store.bookings[id]
+	if !ok || !booking.IsActive() {
+		return fmt.Errorf("cancel booking: %w", store.ErrBookingNotFound)
+	}
+
+	// запоминаем, кто и когда отменил бронь
+	cancelledAt := time.Now()
+	booking.Status = model.BookingStatusCancelled
+	booking.CancelledAt = &cancelledAt
+	booking.CancelledBy = cancelledBy
+	r.store.bookings[id] = booking
+
+	// освобождаем столики: без связей с бронью они снова становятся доступными для бронирования
+	r.store.deleteBookingsTables(id)
+
+	// сообщаем вебхукам ресторана об отмене брони
+	r.store.addOutboxEvent(model.WebhookEventBookingCancelled, id)
+	return nil
+}
+
+func (r *BookingRepository) UpdateStatus(id uint64, from, to string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	booking, ok := r.store.bookings[id]
+	if !ok || booking.Status != from {
+		return false, nil
+	}
+
+	now := time.Now()
+	booking.Status = to
+	switch to {
+	case model.BookingStatusSeated:
+		booking.SeatedAt = &now
+	case model.BookingStatusCompleted, model.BookingStatusNoShow:
+		// столики освобождаются с этого момента: isTableAvailable не учитывает время брони после него
+		booking.FinishedAt = &now
+	}
+	r.store.bookings[id] = booking
+
+	// сообщаем вебхукам ресторана о новом статусе брони
+	r.store.addOutboxEvent(model.WebhookEventBookingStatusChanged, id)
+	return true, nil
+}
+
+// deleteBookingsTables удаляет связи брони со столиками. Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) deleteBookingsTables(bookingID uint64) {
+	for btID, bt := range s.bookingsTables {
+		if bt.BookingID == bookingID {
+			delete(s.bookingsTables, btID)
+		}
+	}
+}
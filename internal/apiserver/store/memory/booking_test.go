@@ -0,0 +1,127 @@
+package memory_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// TestBookingRepository_CreateOvernight проверяет, что бронь, которая заканчивается после полуночи, занимает столик
+// до конца брони на следующий день, а не до начала той же даты.
+func TestBookingRepository_CreateOvernight(t *testing.T) {
+	date := time.Now().AddDate(0, 0, 7)
+	at := func(day time.Time, hour, minute int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		duration time.Duration
+		wantErr  error
+	}{
+		{name: "overlaps in the evening", from: at(date, 21, 30), duration: 2 * time.Hour, wantErr: store.ErrTableAlreadyBooked},
+		{name: "overlaps after midnight", from: at(date.AddDate(0, 0, 1), 0, 0), duration: time.Hour, wantErr: store.ErrTableAlreadyBooked},
+		{name: "before the booking", from: at(date, 18, 0), duration: 2 * time.Hour},
+		{name: "next morning", from: at(date.AddDate(0, 0, 1), 9, 0), duration: 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := memory.NewStore()
+			restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tableID, err := s.Tables().Create(restaurantID, 8, "", model.ZoneHall)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// бронь с 21:00 до 00:00 следующего дня
+			evening := at(date, 21, 0)
+			if _, err = s.Bookings().Create(
+				restaurantID, 0, "Павел", "+79485722648", "", 8, model.BookingStatusConfirmed,
+				evening, evening, 3*time.Hour, tableID,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			available, err := s.Tables().GetAllAvailable(restaurantID, tt.from, tt.duration, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if free := len(available) == 1; free != (tt.wantErr == nil) {
+				t.Errorf("GetAllAvailable() returned %d tables, want the table to be free = %v", len(available), tt.wantErr == nil)
+			}
+
+			_, err = s.Bookings().Create(
+				restaurantID, 0, "Анна", "+79279007265", "", 4, model.BookingStatusConfirmed,
+				tt.from, tt.from, tt.duration, tableID,
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestBookingRepository_CreateConcurrent одновременно бронирует один столик на пересекающееся время в обход проверки
+// свободных столиков в сервисе и проверяет, что хранилище (как ограничение-исключение в PostgreSQL) оформляет только
+// одну бронь, а остальные отклоняет с ошибкой store.ErrTableAlreadyBooked.
+func TestBookingRepository_CreateConcurrent(t *testing.T) {
+	const requests = 32
+
+	s := memory.NewStore()
+	restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableID, err := s.Tables().Create(restaurantID, 4, "", model.ZoneHall)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// брони начинаются в 19:00, 19:30 и 20:00 и длятся по 2 часа, поэтому все они пересекаются по времени
+	week := time.Now().AddDate(0, 0, 7)
+	evening := time.Date(week.Year(), week.Month(), week.Day(), 19, 0, 0, 0, time.Local)
+
+	// репозитории хранилища создаются при первом обращении, поэтому репозиторий броней получаем заранее
+	bookings := s.Bookings()
+	start := make(chan struct{})
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			from := evening.Add(time.Duration(i%3) * 30 * time.Minute)
+			<-start
+			_, errs[i] = bookings.Create(
+				restaurantID, 0, "Павел", fmt.Sprintf("+7948572%04d", i), "", 4, model.BookingStatusConfirmed,
+				from, from, 2*time.Hour, tableID,
+			)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, store.ErrTableAlreadyBooked):
+			t.Errorf("booking %d: error = %v, want store.ErrTableAlreadyBooked", i, err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("created %d overlapping bookings of one table, want 1", created)
+	}
+}
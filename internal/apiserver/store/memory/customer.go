@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.CustomerRepository = (*CustomerRepository)(nil)
+
+// customerRecord представляет строку таблицы учётных записей гостей: гостя и хеш его пароля.
+type customerRecord struct {
+	customer     model.Customer
+	passwordHash string
+}
+
+// customerSession представляет строку таблицы сеансов гостей.
+type customerSession struct {
+	customerID uint64
+	expiresAt  time.Time
+}
+
+// CustomerRepository представляет реализацю store.CustomerRepository.
+type CustomerRepository struct {
+	store *Store
+}
+
+func NewCustomerRepository(store *Store) *CustomerRepository {
+	return &CustomerRepository{store: store}
+}
+
+func (r *CustomerRepository) Create(name, phone, passwordHash string) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничения уникальности uq_customers_phone
+	for _, record := range r.store.customers {
+		if record.customer.Phone == phone {
+			return 0, fmt.Errorf("create customer: %w", store.ErrCustomerAlreadyExists)
+		}
+	}
+
+	r.store.customerSeq++
+	id := r.store.customerSeq
+	r.store.customers[id] = customerRecord{
+		customer: model.Customer{
+			ID:        id,
+			Name:      name,
+			Phone:     phone,
+			CreatedAt: time.Now(),
+		},
+		passwordHash: passwordHash,
+	}
+	return id, nil
+}
+
+func (r *CustomerRepository) Get(id uint64) (*model.Customer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record, ok := r.store.customers[id]
+	if !ok {
+		return nil, store.ErrCustomerNotFound
+	}
+
+	customer := record.customer
+	return &customer, nil
+}
+
+func (r *CustomerRepository) GetByPhone(phone string) (*model.Customer, string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, record := range r.store.customers {
+		if record.customer.Phone == phone {
+			customer := record.customer
+			return &customer, record.passwordHash, nil
+		}
+	}
+	return nil, "", store.ErrCustomerNotFound
+}
+
+func (r *CustomerRepository) GetBySession(tokenHash string) (*model.Customer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	session, ok := r.store.customerSessions[tokenHash]
+	if !ok || !time.Now().Before(session.expiresAt) {
+		return nil, store.ErrCustomerNotFound
+	}
+
+	record, ok := r.store.customers[session.customerID]
+	if !ok {
+		return nil, store.ErrCustomerNotFound
+	}
+
+	customer := record.customer
+	return &customer, nil
+}
+
+func (r *CustomerRepository) CreateSession(customerID uint64, tokenHash string, expiresAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничения внешнего ключа fk_customer_sessions_customers
+	if _, ok := r.store.customers[customerID]; !ok {
+		return fmt.Errorf("create customer session: %w", store.ErrCustomerNotFound)
+	}
+
+	now := time.Now()
+	for hash, session := range r.store.customerSessions {
+		if session.customerID == customerID && !now.Before(session.expiresAt) {
+			delete(r.store.customerSessions, hash)
+		}
+	}
+
+	r.store.customerSessions[tokenHash] = customerSession{customerID: customerID, expiresAt: expiresAt}
+	return nil
+}
+
+func (r *CustomerRepository) DeleteSession(tokenHash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.customerSessions, tokenHash)
+	return nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.DurationPolicyRepository = (*DurationPolicyRepository)(nil)
+
+// DurationPolicyRepository представляет реализацю store.DurationPolicyRepository.
+type DurationPolicyRepository struct {
+	store *Store
+}
+
+func NewDurationPolicyRepository(store *Store) *DurationPolicyRepository {
+	return &DurationPolicyRepository{store: store}
+}
+
+func (r *DurationPolicyRepository) GetAll() (map[uint64]model.DurationPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	policies := make(map[uint64]model.DurationPolicy, len(r.store.restaurants))
+	for restaurantID := range r.store.restaurants {
+		policies[restaurantID] = copyDurationPolicy(r.store.durationPolicy(restaurantID))
+	}
+	return policies, nil
+}
+
+func (r *DurationPolicyRepository) Get(restaurantID uint64) (model.DurationPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return model.DurationPolicy{}, store.ErrRestaurantNotFound
+	}
+	return copyDurationPolicy(r.store.durationPolicy(restaurantID)), nil
+}
+
+func (r *DurationPolicyRepository) Set(restaurantID uint64, policy model.DurationPolicy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return fmt.Errorf("set duration policy: %w", store.ErrRestaurantNotFound)
+	}
+
+	policy = copyDurationPolicy(policy)
+	sort.Slice(policy.Rules, func(i, j int) bool {
+		return policy.Rules[i].MinPeople < policy.Rules[j].MinPeople
+	})
+	r.store.durationPolicies[restaurantID] = policy
+	return nil
+}
+
+// copyDurationPolicy возвращает копию правил, чтобы изменения у вызывающего кода не затрагивали хранилище.
+func copyDurationPolicy(policy model.DurationPolicy) model.DurationPolicy {
+	policy.Rules = append([]model.DurationRule(nil), policy.Rules...)
+	return policy
+}
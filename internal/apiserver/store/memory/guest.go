@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.GuestRepository = (*GuestRepository)(nil)
+
+// GuestRepository представляет реализацю store.GuestRepository.
+type GuestRepository struct {
+	store *Store
+}
+
+func NewGuestRepository(store *Store) *GuestRepository {
+	return &GuestRepository{store: store}
+}
+
+func (r *GuestRepository) Get(id uint64) (*model.Guest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	guest, ok := r.store.guests[id]
+	if !ok {
+		return nil, store.ErrGuestNotFound
+	}
+	return &guest, nil
+}
+
+func (r *GuestRepository) GetByPhone(phone string) (*model.Guest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if guest, ok := r.store.findGuest(phone); ok {
+		return &guest, nil
+	}
+	return nil, store.ErrGuestNotFound
+}
+
+// findGuest возвращает гостя по телефону в формате E.164. Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) findGuest(phone string) (model.Guest, bool) {
+	for _, guest := range s.guests {
+		if guest.Phone == phone {
+			return guest, true
+		}
+	}
+	return model.Guest{}, false
+}
+
+// upsertGuest возвращает ID гостя с телефоном clientPhone, запоминая имя из новой брони; если такого гостя ещё нет,
+// он создаётся. Если телефон нельзя привести к формату E.164, гость не определяется и возвращается false.
+// Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) upsertGuest(clientName, clientPhone string) (uint64, bool) {
+	phone, err := model.NormalizePhone(clientPhone)
+	if err != nil {
+		return 0, false
+	}
+
+	// аналог INSERT ... ON CONFLICT (phone) DO UPDATE по ограничению уникальности uq_guests_phone
+	if guest, ok := s.findGuest(phone); ok {
+		guest.Name = clientName
+		s.guests[guest.ID] = guest
+		return guest.ID, true
+	}
+
+	s.guestSeq++
+	s.guests[s.guestSeq] = model.Guest{
+		ID:        s.guestSeq,
+		Phone:     phone,
+		Name:      clientName,
+		CreatedAt: time.Now(),
+	}
+	return s.guestSeq, true
+}
@@ -0,0 +1,66 @@
+package memory_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/memory"
+)
+
+// TestBookingRepository_CreateGuest проверяет, что брони с одним телефоном, записанным по-разному, относятся к одному
+// гостю, имя которого берётся из его последней брони.
+func TestBookingRepository_CreateGuest(t *testing.T) {
+	s := memory.NewStore()
+	restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	week := time.Now().AddDate(0, 0, 7)
+	create := func(clientName, clientPhone string, hour int) model.Booking {
+		t.Helper()
+
+		at := time.Date(week.Year(), week.Month(), week.Day(), hour, 0, 0, 0, time.Local)
+		bookingID, err := s.Bookings().Create(
+			restaurantID, 0, clientName, clientPhone, "", 2, model.BookingStatusConfirmed, at, at, time.Hour,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		booking, err := s.Bookings().Get(bookingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *booking
+	}
+
+	first := create("Павел", "8 927 900-72-65", 12)
+	second := create("Паша", "+7 (927) 900-72-65", 14)
+	other := create("Анна", "9031234567", 16)
+	invalid := create("Гость", "не указан", 18)
+
+	if first.GuestID == nil || second.GuestID == nil || *first.GuestID != *second.GuestID {
+		t.Fatalf("bookings with the same phone have guests %v and %v, want one guest", first.GuestID, second.GuestID)
+	}
+	if other.GuestID == nil || *other.GuestID == *first.GuestID {
+		t.Fatalf("booking with another phone has guest %v, want a new guest", other.GuestID)
+	}
+	if invalid.GuestID != nil {
+		t.Errorf("booking with an invalid phone has guest %d, want none", *invalid.GuestID)
+	}
+
+	guest, err := s.Guests().GetByPhone("+79279007265")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guest.ID != *first.GuestID || guest.Name != "Паша" {
+		t.Errorf("guest = %d %q, want %d %q", guest.ID, guest.Name, *first.GuestID, "Паша")
+	}
+
+	if _, err = s.Guests().GetByPhone("89279007265"); !errors.Is(err, store.ErrGuestNotFound) {
+		t.Errorf("GetByPhone() with a phone not in E.164 = %v, want ErrGuestNotFound", err)
+	}
+}
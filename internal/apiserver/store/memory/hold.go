@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.HoldRepository = (*HoldRepository)(nil)
+
+// HoldRepository представляет реализацю store.HoldRepository.
+type HoldRepository struct {
+	store *Store
+}
+
+func NewHoldRepository(store *Store) *HoldRepository {
+	return &HoldRepository{store: store}
+}
+
+func (r *HoldRepository) Create(
+	restaurantID uint64, peopleNumber int, heldDate, heldTimeFrom time.Time, duration time.Duration,
+	expiresAt time.Time, tokenHash, clientKey string, tableIDs ...uint64,
+) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничений внешних ключей fk_holds_restaurants и fk_holds_tables_tables
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return 0, fmt.Errorf("create hold: %w", store.ErrRestaurantNotFound)
+	}
+	for _, tableID := range tableIDs {
+		if _, ok := r.store.tables[tableID]; !ok {
+			return 0, fmt.Errorf("create hold: %w", store.ErrTableNotFound)
+		}
+	}
+
+	// приводим значения к виду, в котором они хранятся в колонках DATE и TIME
+	dateYear, dateMonth, dateDay := heldDate.Date()
+	timeFrom := time.Date(0, 1, 1, heldTimeFrom.Hour(), heldTimeFrom.Minute(), heldTimeFrom.Second(), 0, time.UTC)
+
+	// столики проверяются под той же блокировкой, под которой создаётся удержание, поэтому их не может одновременно
+	// занять ни бронь, ни другое удержание
+	for _, tableID := range tableIDs {
+		if !r.store.isTableAvailable(tableID, heldDate, model.TimeOfDay(timeFrom), duration) {
+			return 0, fmt.Errorf("create hold: %w", store.ErrTableAlreadyBooked)
+		}
+	}
+
+	r.store.holdSeq++
+	id := r.store.holdSeq
+	r.store.holds[id] = model.Hold{
+		ID:           id,
+		RestaurantID: restaurantID,
+		PeopleNumber: peopleNumber,
+		HeldDate:     model.ShortFormattedDate(time.Date(dateYear, dateMonth, dateDay, 0, 0, 0, 0, time.UTC)),
+		HeldTimeFrom: model.ShortFormattedTime(timeFrom),
+		HeldTimeTo:   model.ShortFormattedTime(timeFrom.Add(duration)),
+		ExpiresAt:    expiresAt,
+		TableIDs:     append([]uint64(nil), tableIDs...),
+		TokenHash:    tokenHash,
+		ClientKey:    clientKey,
+	}
+	return id, nil
+}
+
+func (r *HoldRepository) Get(id uint64) (*model.Hold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	hold, ok := r.store.holds[id]
+	if !ok {
+		return nil, store.ErrHoldNotFound
+	}
+
+	hold.TableIDs = append([]uint64(nil), hold.TableIDs...)
+	sort.Slice(hold.TableIDs, func(i, j int) bool {
+		return hold.TableIDs[i] < hold.TableIDs[j]
+	})
+	return &hold, nil
+}
+
+func (r *HoldRepository) CountActive(clientKey string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, hold := range r.store.holds {
+		if hold.ClientKey == clientKey && !hold.IsExpired() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *HoldRepository) Confirm(id, customerID uint64, clientName, clientPhone, clientEmail, status string) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	hold, ok := r.store.holds[id]
+	if !ok || hold.IsExpired() {
+		return 0, fmt.Errorf("confirm hold: %w", store.ErrHoldNotFound)
+	}
+	if _, ok = r.store.customers[customerID]; customerID != 0 && !ok {
+		return 0, fmt.Errorf("confirm hold: %w", store.ErrCustomerNotFound)
+	}
+
+	// снимаем удержание, чтобы оно не мешало занять те же столики бронью; если бронь не оформится,
+	// возвращаем удержание на место (как при откате транзакции)
+	delete(r.store.holds, id)
+
+	heldFrom, heldTo := hold.HeldTimeFrom.TimeOfDay(), hold.HeldTimeTo.TimeOfDay()
+	duration := heldTo - heldFrom
+	if duration < 0 {
+		// бронь заканчивается после полуночи
+		duration += 24 * time.Hour
+	}
+
+	bookingID, err := r.store.insertBooking(
+		hold.RestaurantID, customerID, clientName, clientPhone, clientEmail, hold.PeopleNumber, status,
+		time.Time(hold.HeldDate), time.Time(hold.HeldTimeFrom), duration, hold.TableIDs...,
+	)
+	if err != nil {
+		r.store.holds[id] = hold
+		return 0, fmt.Errorf("confirm hold: %w", err)
+	}
+	return bookingID, nil
+}
+
+func (r *HoldRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.holds[id]; !ok {
+		return fmt.Errorf("delete hold: %w", store.ErrHoldNotFound)
+	}
+	delete(r.store.holds, id)
+	return nil
+}
+
+func (r *HoldRepository) DeleteExpired() ([]uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	released := make(map[uint64]struct{})
+	for id, hold := range r.store.holds {
+		if hold.IsExpired() {
+			released[hold.RestaurantID] = struct{}{}
+			delete(r.store.holds, id)
+		}
+	}
+
+	restaurantIDs := make([]uint64, 0, len(released))
+	for restaurantID := range released {
+		restaurantIDs = append(restaurantIDs, restaurantID)
+	}
+	sort.Slice(restaurantIDs, func(i, j int) bool {
+		return restaurantIDs[i] < restaurantIDs[j]
+	})
+	return restaurantIDs, nil
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.JobRepository = (*JobRepository)(nil)
+
+// jobRecord представляет строку таблицы фоновых задач: задачу и момент, до которого она закреплена за исполнителем.
+type jobRecord struct {
+	job         model.Job
+	lockedUntil time.Time
+}
+
+// JobRepository представляет реализацю store.JobRepository.
+type JobRepository struct {
+	store *Store
+}
+
+func NewJobRepository(store *Store) *JobRepository {
+	return &JobRepository{store: store}
+}
+
+func (r *JobRepository) Schedule(kind string, bookingID uint64, runAt time.Time) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.bookings[bookingID]; !ok {
+		return 0, fmt.Errorf("schedule job: %w", store.ErrBookingNotFound)
+	}
+
+	r.store.deletePendingJob(kind, bookingID)
+
+	r.store.jobSeq++
+	r.store.jobs[r.store.jobSeq] = jobRecord{
+		job: model.Job{
+			ID:        r.store.jobSeq,
+			Kind:      kind,
+			BookingID: bookingID,
+			RunAt:     runAt,
+			Status:    model.JobStatusPending,
+		},
+	}
+	return r.store.jobSeq, nil
+}
+
+func (r *JobRepository) Unschedule(kind string, bookingID uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.deletePendingJob(kind, bookingID)
+	return nil
+}
+
+func (r *JobRepository) Claim(limit int, lockedUntil time.Time) ([]model.Job, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var due []jobRecord
+	for _, record := range r.store.jobs {
+		if record.job.Status == model.JobStatusPending && !record.job.RunAt.After(now) && !record.lockedUntil.After(now) {
+			due = append(due, record)
+		}
+	}
+
+	// как и в PostgreSQL, первыми выполняются задачи, время которых наступило раньше
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].job.RunAt.Equal(due[j].job.RunAt) {
+			return due[i].job.ID < due[j].job.ID
+		}
+		return due[i].job.RunAt.Before(due[j].job.RunAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	jobs := make([]model.Job, 0, len(due))
+	for _, record := range due {
+		record.job.Attempts++
+		record.lockedUntil = lockedUntil
+		r.store.jobs[record.job.ID] = record
+		jobs = append(jobs, record.job)
+	}
+	return jobs, nil
+}
+
+func (r *JobRepository) Complete(id uint64) error {
+	return r.update(id, func(record *jobRecord) {
+		record.job.Status = model.JobStatusDone
+		record.lockedUntil = time.Time{}
+	})
+}
+
+func (r *JobRepository) Retry(id uint64, runAt time.Time, lastError string) error {
+	return r.update(id, func(record *jobRecord) {
+		record.job.RunAt = runAt
+		record.job.LastError = lastError
+		record.lockedUntil = time.Time{}
+	})
+}
+
+func (r *JobRepository) Fail(id uint64, lastError string) error {
+	return r.update(id, func(record *jobRecord) {
+		record.job.Status = model.JobStatusFailed
+		record.job.LastError = lastError
+		record.lockedUntil = time.Time{}
+	})
+}
+
+func (r *JobRepository) Release(id uint64) error {
+	return r.update(id, func(record *jobRecord) {
+		record.job.Attempts--
+		record.lockedUntil = time.Time{}
+	})
+}
+
+// update изменяет ещё не выполненную задачу функцией change. Если задача уже отменена или выполнена,
+// это не считается ошибкой.
+func (r *JobRepository) update(id uint64, change func(record *jobRecord)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record, ok := r.store.jobs[id]
+	if !ok || record.job.Status != model.JobStatusPending {
+		return nil
+	}
+
+	change(&record)
+	r.store.jobs[id] = record
+	return nil
+}
+
+// deletePendingJob удаляет ещё не выполненную задачу вида kind по брони bookingID. Вызывающий код должен удерживать
+// блокировку s.mu.
+func (s *Store) deletePendingJob(kind string, bookingID uint64) {
+	for id, record := range s.jobs {
+		if record.job.Kind == kind && record.job.BookingID == bookingID && record.job.Status == model.JobStatusPending {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// deleteBookingJobs удаляет все задачи по брони bookingID. Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) deleteBookingJobs(bookingID uint64) {
+	for id, record := range s.jobs {
+		if record.job.BookingID == bookingID {
+			delete(s.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.OpeningHoursRepository = (*OpeningHoursRepository)(nil)
+
+// OpeningHoursRepository представляет реализацю store.OpeningHoursRepository.
+type OpeningHoursRepository struct {
+	store *Store
+}
+
+func NewOpeningHoursRepository(store *Store) *OpeningHoursRepository {
+	return &OpeningHoursRepository{store: store}
+}
+
+func (r *OpeningHoursRepository) GetAll() (map[uint64][]model.OpeningHours, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	hours := make(map[uint64][]model.OpeningHours, len(r.store.openingHours))
+	for restaurantID, shifts := range r.store.openingHours {
+		hours[restaurantID] = append([]model.OpeningHours(nil), shifts...)
+	}
+	return hours, nil
+}
+
+func (r *OpeningHoursRepository) Get(restaurantID uint64) ([]model.OpeningHours, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return append([]model.OpeningHours(nil), r.store.openingHours[restaurantID]...), nil
+}
+
+func (r *OpeningHoursRepository) Set(restaurantID uint64, hours []model.OpeningHours) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничения внешнего ключа fk_opening_hours_restaurants
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return fmt.Errorf("set opening hours: %w", store.ErrRestaurantNotFound)
+	}
+
+	if len(hours) == 0 {
+		delete(r.store.openingHours, restaurantID)
+		return nil
+	}
+
+	shifts := append([]model.OpeningHours(nil), hours...)
+	sortOpeningHours(shifts)
+	r.store.openingHours[restaurantID] = shifts
+	return nil
+}
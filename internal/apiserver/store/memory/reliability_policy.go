@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.ReliabilityPolicyRepository = (*ReliabilityPolicyRepository)(nil)
+
+// ReliabilityPolicyRepository представляет реализацю store.ReliabilityPolicyRepository.
+type ReliabilityPolicyRepository struct {
+	store *Store
+}
+
+func NewReliabilityPolicyRepository(store *Store) *ReliabilityPolicyRepository {
+	return &ReliabilityPolicyRepository{store: store}
+}
+
+func (r *ReliabilityPolicyRepository) Get(restaurantID uint64) (model.ReliabilityPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return model.ReliabilityPolicy{}, store.ErrRestaurantNotFound
+	}
+	return r.store.reliabilityPolicies[restaurantID], nil
+}
+
+func (r *ReliabilityPolicyRepository) Set(restaurantID uint64, policy model.ReliabilityPolicy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return fmt.Errorf("set reliability policy: %w", store.ErrRestaurantNotFound)
+	}
+
+	r.store.reliabilityPolicies[restaurantID] = policy
+	return nil
+}
@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.RestaurantRepository = (*RestaurantRepository)(nil)
+
+// RestaurantRepository представляет реализацю store.RestaurantRepository.
+type RestaurantRepository struct {
+	store *Store
+}
+
+func NewRestaurantRepository(store *Store) *RestaurantRepository {
+	return &RestaurantRepository{store: store}
+}
+
+func (r *RestaurantRepository) Create(name string, averageWaitingTime int, averageCheck float64) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.restaurantSeq++
+	id := r.store.restaurantSeq
+	r.store.restaurants[id] = model.Restaurant{
+		ID:                 id,
+		Name:               name,
+		AverageWaitingTime: averageWaitingTime,
+		AverageCheck:       averageCheck,
+		AllocationStrategy: model.AllocationStrategyBestFit,
+	}
+	return id, nil
+}
+
+func (r *RestaurantRepository) GetAll() ([]model.Restaurant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var restaurants []model.Restaurant
+	for _, restaurant := range r.store.restaurants {
+		restaurants = append(restaurants, restaurant)
+	}
+	sortRestaurants(restaurants)
+	return restaurants, nil
+}
+
+func (r *RestaurantRepository) GetAllAvailable(desiredDateTime time.Time, peopleNumber int, zone string) ([]model.Restaurant, error) {
+	date, from := desiredDateTime, model.TimeOfDay(desiredDateTime)
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// суммируем количество свободных мест по ресторанам и их зонам: длительность брони у каждого ресторана своя
+	availableSeats := make(map[uint64]map[string]int)
+	for restaurantID := range r.store.restaurants {
+		duration := r.store.durationPolicy(restaurantID).DurationFor(peopleNumber)
+		for _, table := range r.store.getAvailableTables(date, from, duration) {
+			if table.RestaurantID != restaurantID || zone != "" && table.Zone != zone {
+				continue
+			}
+			if availableSeats[restaurantID] == nil {
+				availableSeats[restaurantID] = make(map[string]int)
+			}
+			availableSeats[restaurantID][table.Zone] += table.SeatsNumber
+		}
+	}
+
+	var restaurants []model.Restaurant
+	for restaurantID, zoneSeats := range availableSeats {
+		restaurant, ok := r.store.restaurants[restaurantID]
+		if !ok {
+			continue
+		}
+		for tableZone, seatsNumber := range zoneSeats {
+			restaurant.AvailableSeatsNumber += seatsNumber
+			restaurant.AvailableZones = append(restaurant.AvailableZones, model.ZoneAvailability{
+				Zone:                 tableZone,
+				AvailableSeatsNumber: seatsNumber,
+			})
+		}
+		if restaurant.AvailableSeatsNumber <= peopleNumber {
+			continue
+		}
+		model.SortZoneAvailability(restaurant.AvailableZones)
+		restaurants = append(restaurants, restaurant)
+	}
+	sortRestaurants(restaurants)
+	return restaurants, nil
+}
+
+func (r *RestaurantRepository) Get(id uint64) (*model.Restaurant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	restaurant, ok := r.store.restaurants[id]
+	if !ok {
+		return nil, store.ErrRestaurantNotFound
+	}
+	return &restaurant, nil
+}
+
+func (r *RestaurantRepository) Update(id uint64, data model.UpdateRestaurantData) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	restaurant, ok := r.store.restaurants[id]
+	if !ok {
+		return nil
+	}
+
+	if data.Name != nil {
+		restaurant.Name = *data.Name
+	}
+
+	if data.AverageWaitingTime != nil {
+		restaurant.AverageWaitingTime = *data.AverageWaitingTime
+	}
+
+	if data.AverageCheck != nil {
+		restaurant.AverageCheck = *data.AverageCheck
+	}
+
+	if data.AllocationStrategy != nil {
+		restaurant.AllocationStrategy = *data.AllocationStrategy
+	}
+
+	r.store.restaurants[id] = restaurant
+	return nil
+}
+
+func (r *RestaurantRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// мы не можем удалить ресторан, если видим по оформленным броням, что клиенты посетят этот ресторан (сегодня или в будущем)
+	for _, bt := range r.store.bookingsTables {
+		table, ok := r.store.tables[bt.TableID]
+		if !ok || table.RestaurantID != id {
+			continue
+		}
+		booking, ok := r.store.bookings[bt.BookingID]
+		if ok && notBeforeToday(time.Time(booking.BookedDate)) {
+			return fmt.Errorf("delete restaurant: %w", store.ErrRestaurantIsBooked)
+		}
+	}
+
+	// вместе с рестораном удаляются его столики, брони, их связи и фоновые задачи, график работы, правила длительности
+	// брони, лист ожидания, удержания столиков, вебхуки с событиями для них и закрепление ресторана за пользователями
+	// (аналог ON DELETE CASCADE)
+	for tableID, table := range r.store.tables {
+		if table.RestaurantID == id {
+			r.store.deleteTable(tableID)
+		}
+	}
+	for bookingID, booking := range r.store.bookings {
+		if booking.RestaurantID == id {
+			r.store.deleteBookingsTables(bookingID)
+			r.store.deleteBookingJobs(bookingID)
+			delete(r.store.bookings, bookingID)
+		}
+	}
+	r.store.deleteRestaurantWebhooks(id)
+	delete(r.store.openingHours, id)
+	delete(r.store.durationPolicies, id)
+	delete(r.store.reliabilityPolicies, id)
+	for entryID, entry := range r.store.waitlist {
+		if entry.RestaurantID == id {
+			delete(r.store.waitlist, entryID)
+		}
+	}
+	for holdID, hold := range r.store.holds {
+		if hold.RestaurantID == id {
+			delete(r.store.holds, holdID)
+		}
+	}
+	for userID, record := range r.store.users {
+		record.user.RestaurantIDs = removeID(record.user.RestaurantIDs, id)
+		r.store.users[userID] = record
+	}
+	delete(r.store.restaurants, id)
+	return nil
+}
@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.Store = (*Store)(nil)
+
+// Store представляет реализацию store.Store, хранящую все данные в оперативной памяти.
+// Используется для запуска сервиса без PostgreSQL (тесты, демонстрационный режим).
+type Store struct {
+	// mu защищает все данные хранилища: репозитории работают с общими "таблицами",
+	// поэтому блокировка одна на всё хранилище (как транзакция в БД).
+	mu sync.RWMutex
+
+	restaurants    map[uint64]model.Restaurant
+	tables         map[uint64]model.Table
+	bookings       map[uint64]model.Booking
+	bookingsTables map[uint64]model.BookingsTables
+	// tableJoins содержит пары столиков, которые можно сдвинуть (ID первого столика всегда меньше ID второго)
+	tableJoins map[[2]uint64]struct{}
+	// openingHours содержит графики работы ресторанов по их ID
+	openingHours map[uint64][]model.OpeningHours
+	// durationPolicies содержит правила длительности брони ресторанов по их ID
+	durationPolicies map[uint64]model.DurationPolicy
+	// reliabilityPolicies содержит правила надёжности гостей ресторанов по их ID
+	reliabilityPolicies map[uint64]model.ReliabilityPolicy
+	// waitlist содержит записи листов ожидания ресторанов
+	waitlist map[uint64]model.WaitlistEntry
+	// holds содержит временные удержания столиков (вместе с ID удерживаемых столиков)
+	holds map[uint64]model.Hold
+	// users содержит пользователей API администрирования вместе с хешами их ключей API
+	users map[uint64]userRecord
+	// customers содержит учётные записи гостей на сайте вместе с хешами их паролей
+	customers map[uint64]customerRecord
+	// customerSessions содержит сеансы гостей по SHA-256 хешам их токенов
+	customerSessions map[string]customerSession
+	// guests содержит гостей ресторанов по их ID
+	guests map[uint64]model.Guest
+	// jobs содержит фоновые задачи, связанные с бронями
+	jobs map[uint64]jobRecord
+	// webhooks содержит вебхуки ресторанов
+	webhooks map[uint64]model.Webhook
+	// outbox содержит исходящие события с бронями для вебхуков
+	outbox map[uint64]outboxRecord
+	// webhookDeliveries содержит доставки событий вебхукам
+	webhookDeliveries map[uint64]webhookDeliveryRecord
+
+	// последние выданные ID записей (аналог последовательностей SERIAL в PostgreSQL)
+	restaurantSeq     uint64
+	tableSeq          uint64
+	bookingSeq        uint64
+	bookingsTablesSeq uint64
+	waitlistSeq       uint64
+	holdSeq           uint64
+	userSeq           uint64
+	customerSeq       uint64
+	guestSeq          uint64
+	jobSeq            uint64
+	webhookSeq        uint64
+	outboxSeq         uint64
+
+	webhookDeliverySeq uint64
+
+	restaurantRepo  store.RestaurantRepository
+	tableRepo       store.TableRepository
+	bookingRepo     store.BookingRepository
+	hoursRepo       store.OpeningHoursRepository
+	policyRepo      store.DurationPolicyRepository
+	reliabilityRepo store.ReliabilityPolicyRepository
+	waitlistRepo    store.WaitlistRepository
+	holdRepo        store.HoldRepository
+	userRepo        store.UserRepository
+	customerRepo    store.CustomerRepository
+	guestRepo       store.GuestRepository
+	jobRepo         store.JobRepository
+	webhookRepo     store.WebhookRepository
+	outboxRepo      store.OutboxRepository
+}
+
+func NewStore() *Store {
+	return &Store{
+		restaurants:    make(map[uint64]model.Restaurant),
+		tables:         make(map[uint64]model.Table),
+		bookings:       make(map[uint64]model.Booking),
+		bookingsTables: make(map[uint64]model.BookingsTables),
+		tableJoins:     make(map[[2]uint64]struct{}),
+		openingHours:   make(map[uint64][]model.OpeningHours),
+
+		durationPolicies:    make(map[uint64]model.DurationPolicy),
+		reliabilityPolicies: make(map[uint64]model.ReliabilityPolicy),
+		waitlist:            make(map[uint64]model.WaitlistEntry),
+		holds:               make(map[uint64]model.Hold),
+		users:               make(map[uint64]userRecord),
+		customers:           make(map[uint64]customerRecord),
+		customerSessions:    make(map[string]customerSession),
+		guests:              make(map[uint64]model.Guest),
+		jobs:                make(map[uint64]jobRecord),
+		webhooks:            make(map[uint64]model.Webhook),
+		outbox:              make(map[uint64]outboxRecord),
+		webhookDeliveries:   make(map[uint64]webhookDeliveryRecord),
+	}
+}
+
+func (s *Store) Restaurants() store.RestaurantRepository {
+	if s.restaurantRepo != nil {
+		return s.restaurantRepo
+	}
+
+	s.restaurantRepo = NewRestaurantRepository(s)
+
+	return s.restaurantRepo
+}
+
+func (s *Store) Tables() store.TableRepository {
+	if s.tableRepo != nil {
+		return s.tableRepo
+	}
+
+	s.tableRepo = NewTableRepository(s)
+
+	return s.tableRepo
+}
+
+func (s *Store) Bookings() store.BookingRepository {
+	if s.bookingRepo != nil {
+		return s.bookingRepo
+	}
+
+	s.bookingRepo = NewBookingRepository(s)
+
+	return s.bookingRepo
+}
+
+func (s *Store) OpeningHours() store.OpeningHoursRepository {
+	if s.hoursRepo != nil {
+		return s.hoursRepo
+	}
+
+	s.hoursRepo = NewOpeningHoursRepository(s)
+
+	return s.hoursRepo
+}
+
+func (s *Store) DurationPolicies() store.DurationPolicyRepository {
+	if s.policyRepo != nil {
+		return s.policyRepo
+	}
+
+	s.policyRepo = NewDurationPolicyRepository(s)
+
+	return s.policyRepo
+}
+
+func (s *Store) ReliabilityPolicies() store.ReliabilityPolicyRepository {
+	if s.reliabilityRepo != nil {
+		return s.reliabilityRepo
+	}
+
+	s.reliabilityRepo = NewReliabilityPolicyRepository(s)
+
+	return s.reliabilityRepo
+}
+
+func (s *Store) Waitlist() store.WaitlistRepository {
+	if s.waitlistRepo != nil {
+		return s.waitlistRepo
+	}
+
+	s.waitlistRepo = NewWaitlistRepository(s)
+
+	return s.waitlistRepo
+}
+
+func (s *Store) Holds() store.HoldRepository {
+	if s.holdRepo != nil {
+		return s.holdRepo
+	}
+
+	s.holdRepo = NewHoldRepository(s)
+
+	return s.holdRepo
+}
+
+func (s *Store) Users() store.UserRepository {
+	if s.userRepo != nil {
+		return s.userRepo
+	}
+
+	s.userRepo = NewUserRepository(s)
+
+	return s.userRepo
+}
+
+func (s *Store) Customers() store.CustomerRepository {
+	if s.customerRepo != nil {
+		return s.customerRepo
+	}
+
+	s.customerRepo = NewCustomerRepository(s)
+
+	return s.customerRepo
+}
+
+func (s *Store) Guests() store.GuestRepository {
+	if s.guestRepo != nil {
+		return s.guestRepo
+	}
+
+	s.guestRepo = NewGuestRepository(s)
+
+	return s.guestRepo
+}
+
+func (s *Store) Jobs() store.JobRepository {
+	if s.jobRepo != nil {
+		return s.jobRepo
+	}
+
+	s.jobRepo = NewJobRepository(s)
+
+	return s.jobRepo
+}
+
+func (s *Store) Webhooks() store.WebhookRepository {
+	if s.webhookRepo != nil {
+		return s.webhookRepo
+	}
+
+	s.webhookRepo = NewWebhookRepository(s)
+
+	return s.webhookRepo
+}
+
+func (s *Store) Outbox() store.OutboxRepository {
+	if s.outboxRepo != nil {
+		return s.outboxRepo
+	}
+
+	s.outboxRepo = NewOutboxRepository(s)
+
+	return s.outboxRepo
+}
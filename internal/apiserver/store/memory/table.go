@@ -0,0 +1,251 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.TableRepository = (*TableRepository)(nil)
+
+// TableRepository представляет реализацю store.TableRepository.
+type TableRepository struct {
+	store *Store
+}
+
+func NewTableRepository(store *Store) *TableRepository {
+	return &TableRepository{store: store}
+}
+
+func (r *TableRepository) Create(restaurantID uint64, seatsNumber int, position, zone string) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничения внешнего ключа fk_tables_restaurants
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return 0, fmt.Errorf("create table: %w", store.ErrRestaurantNotFound)
+	}
+
+	r.store.tableSeq++
+	id := r.store.tableSeq
+	r.store.tables[id] = model.Table{
+		ID:           id,
+		RestaurantID: restaurantID,
+		SeatsNumber:  seatsNumber,
+		Position:     position,
+		Zone:         zone,
+	}
+	return id, nil
+}
+
+func (r *TableRepository) GetAllAvailable(restaurantID uint64, desiredDateTime time.Time, duration time.Duration, zone string) ([]model.Table, error) {
+	date, from := desiredDateTime, model.TimeOfDay(desiredDateTime)
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tables []model.Table
+	for _, table := range r.store.getAvailableTables(date, from, duration) {
+		if table.RestaurantID == restaurantID && (zone == "" || table.Zone == zone) {
+			tables = append(tables, table)
+		}
+	}
+	r.store.fillJoinableTables(tables)
+	return tables, nil
+}
+
+func (r *TableRepository) GetAllAvailableForBooking(bookingID uint64, desiredDateTime time.Time, duration time.Duration) ([]model.Table, error) {
+	date, from := desiredDateTime, model.TimeOfDay(desiredDateTime)
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	booking, ok := r.store.bookings[bookingID]
+	if !ok {
+		return nil, nil
+	}
+
+	var tables []model.Table
+	for _, table := range r.store.tables {
+		if table.RestaurantID == booking.RestaurantID && r.store.isTableAvailableExcept(table.ID, date, from, duration, bookingID) {
+			tables = append(tables, table)
+		}
+	}
+	sortTables(tables)
+	r.store.fillJoinableTables(tables)
+	return tables, nil
+}
+
+func (r *TableRepository) GetAllAvailableBySlots(restaurantID uint64, slots []time.Time, duration time.Duration, zone string) ([][]model.Table, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var restaurantTables []model.Table
+	for _, table := range r.store.tables {
+		if table.RestaurantID == restaurantID && (zone == "" || table.Zone == zone) {
+			restaurantTables = append(restaurantTables, table)
+		}
+	}
+	sortTables(restaurantTables)
+
+	// все моменты проверяются под одной блокировкой, поэтому сетка отражает одно и то же состояние броней
+	available := make([][]model.Table, len(slots))
+	for i, slot := range slots {
+		for _, table := range restaurantTables {
+			if r.store.isTableAvailable(table.ID, slot, model.TimeOfDay(slot), duration) {
+				available[i] = append(available[i], table)
+			}
+		}
+		r.store.fillJoinableTables(available[i])
+	}
+	return available, nil
+}
+
+func (r *TableRepository) GetAll(restaurantID uint64) ([]model.Table, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tables []model.Table
+	for _, table := range r.store.tables {
+		if table.RestaurantID == restaurantID {
+			tables = append(tables, table)
+		}
+	}
+	sortTables(tables)
+	r.store.fillJoinableTables(tables)
+	return tables, nil
+}
+
+func (r *TableRepository) Get(id uint64) (*model.Table, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	table, ok := r.store.tables[id]
+	if !ok {
+		return nil, store.ErrTableNotFound
+	}
+
+	tables := []model.Table{table}
+	r.store.fillJoinableTables(tables)
+	return &tables[0], nil
+}
+
+func (r *TableRepository) Update(id uint64, data model.UpdateTableData) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	table, ok := r.store.tables[id]
+	if !ok {
+		return nil
+	}
+
+	if data.SeatsNumber != nil {
+		table.SeatsNumber = *data.SeatsNumber
+	}
+
+	if data.Position != nil {
+		table.Position = *data.Position
+	}
+
+	if data.Zone != nil {
+		table.Zone = *data.Zone
+	}
+
+	r.store.tables[id] = table
+	return nil
+}
+
+func (r *TableRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// мы не можем удалить столик из ресторана, если видим, что клиенты в будущем (или сегодня) придут и сядут за него
+	for _, bt := range r.store.bookingsTables {
+		if bt.TableID != id {
+			continue
+		}
+		booking, ok := r.store.bookings[bt.BookingID]
+		if ok && notBeforeToday(time.Time(booking.BookedDate)) {
+			return fmt.Errorf("delete table: %w", store.ErrTableIsBooked)
+		}
+	}
+
+	r.store.deleteTable(id)
+	return nil
+}
+
+func (r *TableRepository) Join(id, joinableID uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничений внешних ключей fk_table_joins_tables и fk_table_joins_joinable_tables
+	for _, tableID := range []uint64{id, joinableID} {
+		if _, ok := r.store.tables[tableID]; !ok {
+			return fmt.Errorf("join tables: %w", store.ErrTableNotFound)
+		}
+	}
+
+	r.store.tableJoins[tableJoinKey(id, joinableID)] = struct{}{}
+	return nil
+}
+
+func (r *TableRepository) Unjoin(id, joinableID uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.tableJoins, tableJoinKey(id, joinableID))
+	return nil
+}
+
+// deleteTable удаляет столик вместе с его связями с бронями и другими столиками (аналог ON DELETE CASCADE).
+// Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) deleteTable(id uint64) {
+	for btID, bt := range s.bookingsTables {
+		if bt.TableID == id {
+			delete(s.bookingsTables, btID)
+		}
+	}
+	for key := range s.tableJoins {
+		if key[0] == id || key[1] == id {
+			delete(s.tableJoins, key)
+		}
+	}
+	for holdID, hold := range s.holds {
+		if containsTable(hold.TableIDs, id) {
+			hold.TableIDs = removeID(hold.TableIDs, id)
+			s.holds[holdID] = hold
+		}
+	}
+	delete(s.tables, id)
+}
+
+// fillJoinableTables дополняет столики ID столиков, которые можно с ними сдвинуть.
+// Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) fillJoinableTables(tables []model.Table) {
+	for i := range tables {
+		joinable := make([]uint64, 0)
+		for key := range s.tableJoins {
+			switch tables[i].ID {
+			case key[0]:
+				joinable = append(joinable, key[1])
+			case key[1]:
+				joinable = append(joinable, key[0])
+			}
+		}
+		sort.Slice(joinable, func(a, b int) bool {
+			return joinable[a] < joinable[b]
+		})
+		tables[i].JoinableWith = joinable
+	}
+}
+
+// tableJoinKey возвращает ключ пары столиков в s.tableJoins (аналог хранения пары в таблице table_joins).
+func tableJoinKey(id, joinableID uint64) [2]uint64 {
+	if id > joinableID {
+		id, joinableID = joinableID, id
+	}
+	return [2]uint64{id, joinableID}
+}
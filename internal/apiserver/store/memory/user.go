@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.UserRepository = (*UserRepository)(nil)
+
+// userRecord представляет строку таблицы пользователей: пользователя и хеш его ключа API.
+type userRecord struct {
+	user       model.User
+	apiKeyHash string
+}
+
+// UserRepository представляет реализацю store.UserRepository.
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) Create(name, role, apiKeyHash string, restaurantIDs ...uint64) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничения внешнего ключа fk_users_restaurants_restaurants
+	for _, restaurantID := range restaurantIDs {
+		if _, ok := r.store.restaurants[restaurantID]; !ok {
+			return 0, fmt.Errorf("create user: %w", store.ErrRestaurantNotFound)
+		}
+	}
+
+	r.store.userSeq++
+	id := r.store.userSeq
+	r.store.users[id] = userRecord{
+		user: model.User{
+			ID:            id,
+			Name:          name,
+			Role:          role,
+			RestaurantIDs: uniqueIDs(restaurantIDs),
+			CreatedAt:     time.Now(),
+		},
+		apiKeyHash: apiKeyHash,
+	}
+	return id, nil
+}
+
+func (r *UserRepository) GetAll() ([]model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]model.User, 0, len(r.store.users))
+	for _, record := range r.store.users {
+		users = append(users, copyUser(record.user))
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users, nil
+}
+
+func (r *UserRepository) Get(id uint64) (*model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	record, ok := r.store.users[id]
+	if !ok {
+		return nil, store.ErrUserNotFound
+	}
+
+	user := copyUser(record.user)
+	return &user, nil
+}
+
+func (r *UserRepository) GetByAPIKeyHash(apiKeyHash string) (*model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, record := range r.store.users {
+		if record.apiKeyHash == apiKeyHash {
+			user := copyUser(record.user)
+			return &user, nil
+		}
+	}
+	return nil, store.ErrUserNotFound
+}
+
+func (r *UserRepository) SetAPIKeyHash(id uint64, apiKeyHash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record, ok := r.store.users[id]
+	if !ok {
+		return fmt.Errorf("set user api key: %w", store.ErrUserNotFound)
+	}
+
+	record.apiKeyHash = apiKeyHash
+	r.store.users[id] = record
+	return nil
+}
+
+func (r *UserRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return fmt.Errorf("delete user: %w", store.ErrUserNotFound)
+	}
+	delete(r.store.users, id)
+	return nil
+}
+
+// copyUser возвращает копию пользователя, чтобы вызывающий код не мог изменить список его ресторанов в хранилище.
+func copyUser(user model.User) model.User {
+	user.RestaurantIDs = append([]uint64{}, user.RestaurantIDs...)
+	return user
+}
+
+// uniqueIDs возвращает ID без повторов по возрастанию (как при выборке из таблицы связей с ORDER BY).
+func uniqueIDs(ids []uint64) []uint64 {
+	unique := make([]uint64, 0, len(ids))
+	seen := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		return unique[i] < unique[j]
+	})
+	return unique
+}
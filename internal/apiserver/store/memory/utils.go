@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// notBeforeToday проверяет, приходится ли дата на сегодняшний день или на будущее (аналог booked_date >= current_date).
+func notBeforeToday(date time.Time) bool {
+	ty, tm, td := time.Now().Date()
+	today := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC)
+	dy, dm, dd := date.Date()
+	return !time.Date(dy, dm, dd, 0, 0, 0, 0, time.UTC).Before(today)
+}
+
+// isTableAvailable повторяет логику SQL-функций is_table_available и is_table_held: столик можно забронировать
+// на duration, если желаемый промежуток времени не накладывается (и не соприкасается) ни с одной из броней этого
+// столика и столик не удерживается другим гостем. Промежутки сравниваются как моменты времени, а не как время суток,
+// поэтому бронь, которая заканчивается после полуночи, занимает столик и в начале следующего дня.
+// Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) isTableAvailable(tableID uint64, date time.Time, from, duration time.Duration) bool {
+	return s.isTableAvailableExcept(tableID, date, from, duration, 0)
+}
+
+// isTableAvailableExcept работает так же, как isTableAvailable, но не учитывает бронь с ID exceptBookingID (например,
+// когда эту бронь переносят на другое время). Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) isTableAvailableExcept(tableID uint64, date time.Time, from, duration time.Duration, exceptBookingID uint64) bool {
+	desiredFrom := atTimeOfDay(date, from)
+	desiredTo := desiredFrom.Add(duration)
+	if s.isTableHeld(tableID, desiredFrom, desiredTo) {
+		return false
+	}
+	for _, bt := range s.bookingsTables {
+		if bt.TableID != tableID || bt.BookingID == exceptBookingID {
+			continue
+		}
+		booking, ok := s.bookings[bt.BookingID]
+		if !ok {
+			continue
+		}
+		bookedFrom, bookedTo, occupied := occupiedPeriod(booking)
+		if occupied && overlaps(desiredFrom, desiredTo, bookedFrom, bookedTo) {
+			return false
+		}
+	}
+	return true
+}
+
+// atTimeOfDay возвращает момент времени в дату date, когда с начала суток прошло clock (аналог date + time в SQL).
+func atTimeOfDay(date time.Time, clock time.Duration) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, int(clock/time.Second), 0, time.Local)
+}
+
+// overlaps проверяет, накладываются ли (или соприкасаются) промежутки [aFrom, aTo] и [bFrom, bTo]
+// (аналог оператора && для промежутков tsrange с включёнными границами).
+func overlaps(aFrom, aTo, bFrom, bTo time.Time) bool {
+	return !aFrom.After(bTo) && !bFrom.After(aTo)
+}
+
+// occupiedPeriod возвращает промежуток времени, на который столики брони заняты (аналог bookings_tables.booked_during):
+// от начала до конца брони или, если гости ушли раньше (или не пришли), до момента завершения брони. Если бронь
+// завершена раньше, чем началась, столики не заняты вовсе и возвращается false.
+func occupiedPeriod(booking model.Booking) (time.Time, time.Time, bool) {
+	bookedFrom, bookedTo := booking.StartsAt(), booking.EndsAt()
+	if booking.FinishedAt != nil && booking.FinishedAt.Before(bookedTo) {
+		bookedTo = *booking.FinishedAt
+	}
+	return bookedFrom, bookedTo, bookedFrom.Before(bookedTo)
+}
+
+// isTableHeld проверяет, удерживается ли столик действующим удержанием на промежуток времени, который накладывается
+// на промежуток [from, to]. Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) isTableHeld(tableID uint64, from, to time.Time) bool {
+	for _, hold := range s.holds {
+		if hold.IsExpired() || !containsTable(hold.TableIDs, tableID) {
+			continue
+		}
+		if overlaps(from, to, hold.StartsAt(), hold.EndsAt()) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTable проверяет, есть ли столик с ID tableID среди tableIDs.
+func containsTable(tableIDs []uint64, tableID uint64) bool {
+	for _, id := range tableIDs {
+		if id == tableID {
+			return true
+		}
+	}
+	return false
+}
+
+// removeID возвращает копию ids без ID removed (например, список столиков удержания без удалённого столика).
+func removeID(ids []uint64, removed uint64) []uint64 {
+	kept := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if id != removed {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// getAvailableTables повторяет логику SQL-функции get_available_tables: возвращает столики всех ресторанов,
+// свободные для бронирования на duration в выбранные дату и время. Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) getAvailableTables(date time.Time, from, duration time.Duration) []model.Table {
+	var tables []model.Table
+	for _, table := range s.tables {
+		if s.isTableAvailable(table.ID, date, from, duration) {
+			tables = append(tables, table)
+		}
+	}
+	sortTables(tables)
+	return tables
+}
+
+// durationPolicy возвращает правила длительности брони ресторана (аналог SQL-функции get_booking_duration).
+// Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) durationPolicy(restaurantID uint64) model.DurationPolicy {
+	if policy, ok := s.durationPolicies[restaurantID]; ok {
+		return policy
+	}
+	return model.DefaultDurationPolicy()
+}
+
+// sortTables сортирует столики по возрастанию ID, т.е. в порядке их добавления.
+func sortTables(tables []model.Table) {
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].ID < tables[j].ID
+	})
+}
+
+// sortRestaurants сортирует рестораны по возрастанию среднего времени ожидания и среднего чека
+// (как ORDER BY average_waiting_time, average_check в PostgreSQL).
+func sortRestaurants(restaurants []model.Restaurant) {
+	sort.Slice(restaurants, func(i, j int) bool {
+		if restaurants[i].AverageWaitingTime != restaurants[j].AverageWaitingTime {
+			return restaurants[i].AverageWaitingTime < restaurants[j].AverageWaitingTime
+		}
+		if restaurants[i].AverageCheck != restaurants[j].AverageCheck {
+			return restaurants[i].AverageCheck < restaurants[j].AverageCheck
+		}
+		return restaurants[i].ID < restaurants[j].ID
+	})
+}
+
+// sortOpeningHours сортирует смены по дню недели и времени открытия.
+func sortOpeningHours(hours []model.OpeningHours) {
+	sort.Slice(hours, func(i, j int) bool {
+		if hours[i].Weekday != hours[j].Weekday {
+			return hours[i].Weekday < hours[j].Weekday
+		}
+		return hours[i].OpensAt.TimeOfDay() < hours[j].OpensAt.TimeOfDay()
+	})
+}
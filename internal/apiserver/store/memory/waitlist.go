@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var _ store.WaitlistRepository = (*WaitlistRepository)(nil)
+
+// WaitlistRepository представляет реализацю store.WaitlistRepository.
+type WaitlistRepository struct {
+	store *Store
+}
+
+func NewWaitlistRepository(store *Store) *WaitlistRepository {
+	return &WaitlistRepository{store: store}
+}
+
+func (r *WaitlistRepository) Create(
+	restaurantID uint64, clientName, clientPhone string, peopleNumber int, desiredDate, desiredTime time.Time,
+) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничения внешнего ключа fk_waitlist_restaurants
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return 0, fmt.Errorf("create waitlist entry: %w", store.ErrRestaurantNotFound)
+	}
+
+	// приводим значения к виду, в котором они хранятся в колонках DATE и TIME
+	dateYear, dateMonth, dateDay := desiredDate.Date()
+
+	r.store.waitlistSeq++
+	id := r.store.waitlistSeq
+	r.store.waitlist[id] = model.WaitlistEntry{
+		ID:           id,
+		RestaurantID: restaurantID,
+		ClientName:   clientName,
+		ClientPhone:  clientPhone,
+		PeopleNumber: peopleNumber,
+		DesiredDate:  model.ShortFormattedDate(time.Date(dateYear, dateMonth, dateDay, 0, 0, 0, 0, time.UTC)),
+		DesiredTime:  model.NewShortFormattedTime(desiredTime.Hour(), desiredTime.Minute()),
+		Status:       model.WaitlistStatusWaiting,
+		CreatedAt:    time.Now(),
+	}
+	return id, nil
+}
+
+func (r *WaitlistRepository) GetAll(restaurantID uint64) ([]model.WaitlistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var entries []model.WaitlistEntry
+	for _, entry := range r.store.waitlist {
+		if entry.RestaurantID == restaurantID {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+func (r *WaitlistRepository) Get(id uint64) (*model.WaitlistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entry, ok := r.store.waitlist[id]
+	if !ok {
+		return nil, store.ErrWaitlistEntryNotFound
+	}
+	return &entry, nil
+}
+
+func (r *WaitlistRepository) UpdateStatus(
+	id uint64, from, to string, offerExpiresAt *time.Time, holdID, bookingID *uint64,
+) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entry, ok := r.store.waitlist[id]
+	if !ok || entry.Status != from {
+		return false, nil
+	}
+
+	entry.Status = to
+	entry.OfferExpiresAt = offerExpiresAt
+	entry.HoldID = holdID
+	entry.BookingID = bookingID
+	r.store.waitlist[id] = entry
+	return true, nil
+}
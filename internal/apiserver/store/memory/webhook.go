@@ -0,0 +1,309 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+var (
+	_ store.WebhookRepository = (*WebhookRepository)(nil)
+	_ store.OutboxRepository  = (*OutboxRepository)(nil)
+)
+
+// outboxRecord представляет строку таблицы исходящих событий: событие, его тело и признак того, что событие разобрано.
+type outboxRecord struct {
+	event   model.BookingEvent
+	payload []byte
+	relayed bool
+}
+
+// webhookDeliveryRecord представляет строку таблицы доставок событий вебхукам: доставку и момент, до которого она
+// закреплена за исполнителем.
+type webhookDeliveryRecord struct {
+	delivery    model.WebhookDelivery
+	lockedUntil time.Time
+}
+
+// WebhookRepository представляет реализацю store.WebhookRepository.
+type WebhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{store: store}
+}
+
+func (r *WebhookRepository) Create(restaurantID uint64, url, secret string, events []string) (uint64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// аналог ограничения внешнего ключа fk_webhooks_restaurants
+	if _, ok := r.store.restaurants[restaurantID]; !ok {
+		return 0, fmt.Errorf("create webhook: %w", store.ErrRestaurantNotFound)
+	}
+
+	r.store.webhookSeq++
+	r.store.webhooks[r.store.webhookSeq] = model.Webhook{
+		ID:           r.store.webhookSeq,
+		RestaurantID: restaurantID,
+		URL:          url,
+		Secret:       secret,
+		Events:       append([]string{}, events...),
+		CreatedAt:    time.Now(),
+	}
+	return r.store.webhookSeq, nil
+}
+
+func (r *WebhookRepository) GetAll(restaurantID uint64) ([]model.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var webhooks []model.Webhook
+	for _, webhook := range r.store.webhooks {
+		if webhook.RestaurantID == restaurantID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Get(id uint64) (*model.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhook, ok := r.store.webhooks[id]
+	if !ok {
+		return nil, store.ErrWebhookNotFound
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) Delete(id uint64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.deleteWebhook(id)
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveries(webhookID uint64, limit int) ([]model.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var deliveries []model.WebhookDelivery
+	for _, record := range r.store.webhookDeliveries {
+		if record.delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, record.delivery)
+		}
+	}
+
+	// как и в PostgreSQL, первыми идут самые новые доставки
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID > deliveries[j].ID
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// OutboxRepository представляет реализацю store.OutboxRepository.
+type OutboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+func (r *OutboxRepository) Relay(limit int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var eventIDs []uint64
+	for id, record := range r.store.outbox {
+		if !record.relayed {
+			eventIDs = append(eventIDs, id)
+		}
+	}
+	sort.Slice(eventIDs, func(i, j int) bool {
+		return eventIDs[i] < eventIDs[j]
+	})
+	if len(eventIDs) > limit {
+		eventIDs = eventIDs[:limit]
+	}
+
+	for _, eventID := range eventIDs {
+		record := r.store.outbox[eventID]
+
+		// планируем доставку события всем вебхукам ресторана, подписанным на него
+		for _, webhook := range r.store.webhooks {
+			if webhook.RestaurantID != record.event.RestaurantID || !webhook.Subscribed(record.event.Event) {
+				continue
+			}
+			r.store.webhookDeliverySeq++
+			now := time.Now()
+			r.store.webhookDeliveries[r.store.webhookDeliverySeq] = webhookDeliveryRecord{
+				delivery: model.WebhookDelivery{
+					ID:            r.store.webhookDeliverySeq,
+					WebhookID:     webhook.ID,
+					EventID:       eventID,
+					Event:         record.event.Event,
+					Payload:       record.payload,
+					Status:        model.WebhookDeliveryPending,
+					NextAttemptAt: now,
+					CreatedAt:     now,
+				},
+			}
+		}
+
+		record.relayed = true
+		r.store.outbox[eventID] = record
+	}
+	return len(eventIDs), nil
+}
+
+func (r *OutboxRepository) ClaimDeliveries(limit int, lockedUntil time.Time) ([]model.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	var due []webhookDeliveryRecord
+	for _, record := range r.store.webhookDeliveries {
+		if record.delivery.Status == model.WebhookDeliveryPending && !record.delivery.NextAttemptAt.After(now) &&
+			!record.lockedUntil.After(now) {
+			due = append(due, record)
+		}
+	}
+
+	// как и в PostgreSQL, первыми выполняются доставки, время которых наступило раньше
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].delivery.NextAttemptAt.Equal(due[j].delivery.NextAttemptAt) {
+			return due[i].delivery.ID < due[j].delivery.ID
+		}
+		return due[i].delivery.NextAttemptAt.Before(due[j].delivery.NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(due))
+	for _, record := range due {
+		record.delivery.Attempts++
+		record.lockedUntil = lockedUntil
+		r.store.webhookDeliveries[record.delivery.ID] = record
+		deliveries = append(deliveries, record.delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *OutboxRepository) CompleteDelivery(id uint64, statusCode int) error {
+	return r.updateDelivery(id, func(record *webhookDeliveryRecord) {
+		deliveredAt := time.Now()
+		record.delivery.Status = model.WebhookDeliveryDelivered
+		record.delivery.LastStatusCode = statusCode
+		record.delivery.LastError = ""
+		record.delivery.DeliveredAt = &deliveredAt
+		record.lockedUntil = time.Time{}
+	})
+}
+
+func (r *OutboxRepository) RetryDelivery(id uint64, runAt time.Time, statusCode int, lastError string) error {
+	return r.updateDelivery(id, func(record *webhookDeliveryRecord) {
+		record.delivery.NextAttemptAt = runAt
+		record.delivery.LastStatusCode = statusCode
+		record.delivery.LastError = lastError
+		record.lockedUntil = time.Time{}
+	})
+}
+
+func (r *OutboxRepository) FailDelivery(id uint64, statusCode int, lastError string) error {
+	return r.updateDelivery(id, func(record *webhookDeliveryRecord) {
+		record.delivery.Status = model.WebhookDeliveryFailed
+		record.delivery.LastStatusCode = statusCode
+		record.delivery.LastError = lastError
+		record.lockedUntil = time.Time{}
+	})
+}
+
+func (r *OutboxRepository) ReleaseDelivery(id uint64) error {
+	return r.updateDelivery(id, func(record *webhookDeliveryRecord) {
+		record.delivery.Attempts--
+		record.lockedUntil = time.Time{}
+	})
+}
+
+// updateDelivery изменяет ещё не завершённую доставку функцией change. Если доставка уже удалена или завершена,
+// это не считается ошибкой.
+func (r *OutboxRepository) updateDelivery(id uint64, change func(record *webhookDeliveryRecord)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	record, ok := r.store.webhookDeliveries[id]
+	if !ok || record.delivery.Status != model.WebhookDeliveryPending {
+		return nil
+	}
+
+	change(&record)
+	r.store.webhookDeliveries[id] = record
+	return nil
+}
+
+// addOutboxEvent добавляет в outbox событие event с бронью bookingID сразу после изменения брони, пока удерживается
+// та же блокировка (аналог добавления события в транзакции, в которой изменена бронь). Вызывающий код должен
+// удерживать блокировку s.mu.
+func (s *Store) addOutboxEvent(event string, bookingID uint64) {
+	booking, ok := s.getBooking(bookingID)
+	if !ok {
+		return
+	}
+
+	s.outboxSeq++
+	bookingEvent := model.BookingEvent{
+		ID:           s.outboxSeq,
+		Event:        event,
+		RestaurantID: booking.RestaurantID,
+		OccurredAt:   time.Now(),
+		Booking:      booking,
+	}
+	// бронь состоит из простых значений, поэтому ошибки кодирования быть не может
+	payload, _ := json.Marshal(bookingEvent)
+	s.outbox[s.outboxSeq] = outboxRecord{
+		event:   bookingEvent,
+		payload: payload,
+	}
+}
+
+// deleteWebhook удаляет вебхук вместе с журналом доставки ему событий (аналог ON DELETE CASCADE). Вызывающий код
+// должен удерживать блокировку s.mu.
+func (s *Store) deleteWebhook(id uint64) {
+	delete(s.webhooks, id)
+	for deliveryID, record := range s.webhookDeliveries {
+		if record.delivery.WebhookID == id {
+			delete(s.webhookDeliveries, deliveryID)
+		}
+	}
+}
+
+// deleteRestaurantWebhooks удаляет вебхуки ресторана, журнал доставки им событий и события с бронями ресторана
+// из outbox. Вызывающий код должен удерживать блокировку s.mu.
+func (s *Store) deleteRestaurantWebhooks(restaurantID uint64) {
+	for id, webhook := range s.webhooks {
+		if webhook.RestaurantID == restaurantID {
+			s.deleteWebhook(id)
+		}
+	}
+	for id, record := range s.outbox {
+		if record.event.RestaurantID == restaurantID {
+			delete(s.outbox, id)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -27,7 +28,10 @@ func NewBookingRepository(store *Store) *BookingRepository {
 	return &BookingRepository{store: store}
 }
 
-func (r *BookingRepository) Create(clientName, clientPhone string, bookedDate, bookedTimeFrom time.Time, tableIDs ...uint64) (uint64, error) {
+func (r *BookingRepository) Create(
+	restaurantID, customerID uint64, clientName, clientPhone, clientEmail string, peopleNumber int, status string,
+	bookedDate, bookedTimeFrom time.Time, duration time.Duration, tableIDs ...uint64,
+) (uint64, error) {
 	// хелпер-функция для выхода с ошибкой
 	fail := func(err error) (uint64, error) {
 		return 0, fmt.Errorf("create booking: %w", err)
@@ -41,30 +45,63 @@ func (r *BookingRepository) Create(clientName, clientPhone string, bookedDate, b
 	}
 	defer tx.Rollback()
 
+	// столики, удерживаемые другими гостями, забронировать нельзя; блокировка строк столиков не даёт удержать их
+	// параллельно с проверкой
+	if err = lockTables(ctx, tx, tableIDs); err != nil {
+		return fail(err)
+	}
+	bookedFrom, bookedTo := bookedPeriod(bookedDate, bookedTimeFrom, duration)
+	held, err := tablesHeld(ctx, tx, tableIDs, bookedFrom, bookedTo)
+	if err != nil {
+		return fail(err)
+	}
+	if held {
+		return fail(store.ErrTableAlreadyBooked)
+	}
+
+	// определяем гостя по телефону из брони
+	guestID, err := upsertGuest(ctx, tx, clientName, clientPhone)
+	if err != nil {
+		return fail(err)
+	}
+
 	// добавляем в таблицу с бронями новую бронь, возвращая её ID
 	createBookingQuery := fmt.Sprintf(
-		"INSERT INTO %s (client_name, client_phone, booked_date, booked_time_from, booked_time_to) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		"INSERT INTO %s (restaurant_id, customer_id, guest_id, client_name, client_phone, client_email, people_number, status, booked_date, booked_time_from, booked_time_to) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11) RETURNING id",
 		bookingTable,
 	)
 	var bookingID uint64
 	if err = tx.QueryRowContext(ctx,
-		createBookingQuery, clientName, clientPhone, bookedDate, bookedTimeFrom, bookedTimeFrom.Add(2*time.Hour),
+		createBookingQuery, restaurantID, customerIDArg(customerID), guestID, clientName, clientPhone, clientEmail, peopleNumber, status, bookedDate, bookedTimeFrom, bookedTimeFrom.Add(duration),
 	).Scan(&bookingID); err != nil {
+		if isForeignKeyViolation(err) {
+			return fail(store.ErrCustomerNotFound)
+		}
 		return fail(err)
 	}
 
-	// привязываем все столики, которые мы хотим забранировать, к только что созданной брони
+	// привязываем все столики, которые мы хотим забранировать, к только что созданной брони; ограничение-исключение
+	// excl_bookings_tables_overlap не даст занять столик, который уже забронирован на пересекающееся время
+	// (в том числе параллельной транзакцией)
 	createBookingsTablesQuery := fmt.Sprintf(
-		"INSERT INTO %s (booking_id, table_id) VALUES ($1, $2)",
+		"INSERT INTO %s (booking_id, table_id, booked_during) VALUES ($1, $2, tsrange($3::timestamp, $4::timestamp, '[]'))",
 		bookingsTablesTable,
 	)
 	for _, tableID := range tableIDs {
-		_, err = tx.ExecContext(ctx, createBookingsTablesQuery, bookingID, tableID)
+		_, err = tx.ExecContext(ctx, createBookingsTablesQuery, bookingID, tableID, timestampArg(bookedFrom), timestampArg(bookedTo))
 		if err != nil {
+			if isExclusionViolation(err) {
+				return fail(store.ErrTableAlreadyBooked)
+			}
 			return fail(err)
 		}
 	}
 
+	// сообщаем вебхукам ресторана о новой брони
+	if err = insertOutboxEvent(ctx, tx, model.WebhookEventBookingCreated, bookingID); err != nil {
+		return fail(err)
+	}
+
 	// завершаем транзакцию
 	if err = tx.Commit(); err != nil {
 		return fail(err)
@@ -73,14 +110,58 @@ func (r *BookingRepository) Create(clientName, clientPhone string, bookedDate, b
 	return bookingID, nil
 }
 
+// bookingColumns представляет список колонок таблицы с бронями в порядке, в котором их сканирует scanBooking.
+const bookingColumns = "id, restaurant_id, customer_id, guest_id, client_name, client_phone, COALESCE(client_email, ''), COALESCE(people_number, 0), status, booked_date, booked_time_from, booked_time_to, seated_at, finished_at, cancelled_at, COALESCE(cancelled_by, '')"
+
+// rowScanner представляет общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBooking считывает бронь, выбранную с колонками bookingColumns.
+func scanBooking(row rowScanner, booking *model.Booking) error {
+	var customerID, guestID sql.NullInt64
+	var seatedAt, finishedAt, cancelledAt sql.NullTime
+	if err := row.Scan(
+		&booking.ID, &booking.RestaurantID, &customerID, &guestID, &booking.ClientName, &booking.ClientPhone,
+		&booking.ClientEmail, &booking.PeopleNumber, &booking.Status, &booking.BookedDate, &booking.BookedTimeFrom,
+		&booking.BookedTimeTo, &seatedAt, &finishedAt, &cancelledAt, &booking.CancelledBy,
+	); err != nil {
+		return err
+	}
+	if customerID.Valid {
+		id := uint64(customerID.Int64)
+		booking.CustomerID = &id
+	}
+	if guestID.Valid {
+		id := uint64(guestID.Int64)
+		booking.GuestID = &id
+	}
+	if seatedAt.Valid {
+		booking.SeatedAt = &seatedAt.Time
+	}
+	if finishedAt.Valid {
+		booking.FinishedAt = &finishedAt.Time
+	}
+	if cancelledAt.Valid {
+		booking.CancelledAt = &cancelledAt.Time
+	}
+	return nil
+}
+
+// customerIDArg переводит ID учётной записи гостя в значение колонки customer_id (NULL, если бронь оформляется
+// без учётной записи).
+func customerIDArg(customerID uint64) interface{} {
+	if customerID == 0 {
+		return nil
+	}
+	return customerID
+}
+
 func (r *BookingRepository) GetAll(restaurantID uint64) ([]model.Booking, error) {
 	getAllBookingsQuery := fmt.Sprintf(
-		"SELECT DISTINCT b.* "+
-			"FROM %s b "+
-			"JOIN bookings_tables bt on b.id = bt.booking_id "+
-			"JOIN tables t on t.id = bt.table_id "+
-			"WHERE restaurant_id = $1",
-		bookingTable,
+		"SELECT %s FROM %s WHERE restaurant_id = $1 ORDER BY id",
+		bookingColumns, bookingTable,
 	)
 
 	rows, err := r.store.db.Query(getAllBookingsQuery, restaurantID)
@@ -93,9 +174,34 @@ func (r *BookingRepository) GetAll(restaurantID uint64) ([]model.Booking, error)
 
 	for rows.Next() {
 		var booking model.Booking
-		if err = rows.Scan(
-			&booking.ID, &booking.ClientName, &booking.ClientPhone, &booking.BookedDate, &booking.BookedTimeFrom, &booking.BookedTimeTo,
-		); err != nil {
+		if err = scanBooking(rows, &booking); err != nil {
+			return bookings, err
+		}
+		bookings = append(bookings, booking)
+	}
+	if err = rows.Err(); err != nil {
+		return bookings, err
+	}
+	return bookings, nil
+}
+
+func (r *BookingRepository) GetAllByCustomer(customerID uint64) ([]model.Booking, error) {
+	getAllBookingsQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE customer_id = $1 AND restaurant_id IS NOT NULL ORDER BY id",
+		bookingColumns, bookingTable,
+	)
+
+	rows, err := r.store.db.Query(getAllBookingsQuery, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []model.Booking
+
+	for rows.Next() {
+		var booking model.Booking
+		if err = scanBooking(rows, &booking); err != nil {
 			return bookings, err
 		}
 		bookings = append(bookings, booking)
@@ -105,3 +211,293 @@ func (r *BookingRepository) GetAll(restaurantID uint64) ([]model.Booking, error)
 	}
 	return bookings, nil
 }
+
+func (r *BookingRepository) GetAllByGuest(guestID uint64) ([]model.Booking, error) {
+	getAllBookingsQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE guest_id = $1 AND restaurant_id IS NOT NULL ORDER BY id",
+		bookingColumns, bookingTable,
+	)
+
+	rows, err := r.store.db.Query(getAllBookingsQuery, guestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []model.Booking
+
+	for rows.Next() {
+		var booking model.Booking
+		if err = scanBooking(rows, &booking); err != nil {
+			return bookings, err
+		}
+		bookings = append(bookings, booking)
+	}
+	if err = rows.Err(); err != nil {
+		return bookings, err
+	}
+	return bookings, nil
+}
+
+func (r *BookingRepository) Get(id uint64) (*model.Booking, error) {
+	return selectBooking(context.Background(), r.store.db, id)
+}
+
+// queryer представляет общий интерфейс *sql.DB и *sql.Tx для выполнения запросов.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// selectBooking возвращает бронь по её ID вместе с ID забронированных столиков. Внутри транзакции бронь выбирается
+// с учётом изменений, ещё не завершённых этой транзакцией.
+func selectBooking(ctx context.Context, q queryer, id uint64) (*model.Booking, error) {
+	getBookingQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = $1 AND restaurant_id IS NOT NULL",
+		bookingColumns, bookingTable,
+	)
+
+	booking := &model.Booking{}
+	if err := scanBooking(q.QueryRowContext(ctx, getBookingQuery, id), booking); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrBookingNotFound
+		}
+		return nil, err
+	}
+
+	// получаем столики, забронированные в рамках брони
+	getBookingTablesQuery := fmt.Sprintf(
+		"SELECT table_id FROM %s WHERE booking_id = $1 ORDER BY table_id",
+		bookingsTablesTable,
+	)
+	rows, err := q.QueryContext(ctx, getBookingTablesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableID uint64
+		if err = rows.Scan(&tableID); err != nil {
+			return nil, err
+		}
+		booking.TableIDs = append(booking.TableIDs, tableID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+func (r *BookingRepository) Update(
+	id uint64, peopleNumber int, bookedDate, bookedTimeFrom time.Time, duration time.Duration, tableIDs ...uint64,
+) error {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) error {
+		return fmt.Errorf("update booking: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	// новые столики не должны удерживаться другими гостями
+	if err = lockTables(ctx, tx, tableIDs); err != nil {
+		return fail(err)
+	}
+	bookedFrom, bookedTo := bookedPeriod(bookedDate, bookedTimeFrom, duration)
+	held, err := tablesHeld(ctx, tx, tableIDs, bookedFrom, bookedTo)
+	if err != nil {
+		return fail(err)
+	}
+	if held {
+		return fail(store.ErrTableAlreadyBooked)
+	}
+
+	// изменяем количество человек, дату и время брони (изменить можно только бронь, которая ждёт гостей)
+	updateBookingQuery := fmt.Sprintf(
+		"UPDATE %s SET people_number = $1, booked_date = $2, booked_time_from = $3, booked_time_to = $4 "+
+			"WHERE id = $5 AND status IN ($6, $7)",
+		bookingTable,
+	)
+	res, err := tx.ExecContext(ctx,
+		updateBookingQuery, peopleNumber, bookedDate, bookedTimeFrom, bookedTimeFrom.Add(duration), id,
+		model.BookingStatusPending, model.BookingStatusConfirmed,
+	)
+	if err != nil {
+		return fail(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fail(err)
+	}
+	if affected == 0 {
+		return fail(store.ErrBookingNotFound)
+	}
+
+	// освобождаем прежние столики брони: внутри транзакции они больше не мешают занять их же на новое время
+	deleteBookingsTablesQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE booking_id = $1",
+		bookingsTablesTable,
+	)
+	if _, err = tx.ExecContext(ctx, deleteBookingsTablesQuery, id); err != nil {
+		return fail(err)
+	}
+
+	// привязываем к брони новые столики; ограничение-исключение excl_bookings_tables_overlap не даст занять столик,
+	// который забронирован другой бронью на пересекающееся время
+	createBookingsTablesQuery := fmt.Sprintf(
+		"INSERT INTO %s (booking_id, table_id, booked_during) VALUES ($1, $2, tsrange($3::timestamp, $4::timestamp, '[]'))",
+		bookingsTablesTable,
+	)
+	for _, tableID := range tableIDs {
+		_, err = tx.ExecContext(ctx, createBookingsTablesQuery, id, tableID, timestampArg(bookedFrom), timestampArg(bookedTo))
+		if err != nil {
+			if isExclusionViolation(err) {
+				return fail(store.ErrTableAlreadyBooked)
+			}
+			return fail(err)
+		}
+	}
+
+	// сообщаем вебхукам ресторана об изменении брони
+	if err = insertOutboxEvent(ctx, tx, model.WebhookEventBookingUpdated, id); err != nil {
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return nil
+}
+
+func (r *BookingRepository) Cancel(id uint64, cancelledBy string) error {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) error {
+		return fmt.Errorf("cancel booking: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	// запоминаем, кто и когда отменил бронь (отменить можно только бронь, которая ждёт гостей)
+	cancelBookingQuery := fmt.Sprintf(
+		"UPDATE %s SET status = $1, cancelled_at = now(), cancelled_by = $2 WHERE id = $3 AND status IN ($4, $5)",
+		bookingTable,
+	)
+	res, err := tx.ExecContext(ctx,
+		cancelBookingQuery, model.BookingStatusCancelled, cancelledBy, id,
+		model.BookingStatusPending, model.BookingStatusConfirmed,
+	)
+	if err != nil {
+		return fail(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fail(err)
+	}
+	if affected == 0 {
+		return fail(store.ErrBookingNotFound)
+	}
+
+	// освобождаем столики: без связей с бронью они снова становятся доступными для бронирования
+	deleteBookingsTablesQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE booking_id = $1",
+		bookingsTablesTable,
+	)
+	if _, err = tx.ExecContext(ctx, deleteBookingsTablesQuery, id); err != nil {
+		return fail(err)
+	}
+
+	// сообщаем вебхукам ресторана об отмене брони
+	if err = insertOutboxEvent(ctx, tx, model.WebhookEventBookingCancelled, id); err != nil {
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return nil
+}
+
+func (r *BookingRepository) UpdateStatus(id uint64, from, to string) (bool, error) {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) (bool, error) {
+		return false, fmt.Errorf("update booking status: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var seatedAt, finishedAt interface{}
+	switch to {
+	case model.BookingStatusSeated:
+		seatedAt = now
+	case model.BookingStatusCompleted, model.BookingStatusNoShow:
+		finishedAt = now
+	}
+
+	// статус меняется, только если бронь всё ещё в статусе from: так два одновременных запроса не смогут,
+	// например, отметить приход гостей по уже отменённой брони
+	updateBookingStatusQuery := fmt.Sprintf(
+		"UPDATE %s SET status = $1, seated_at = COALESCE($2::timestamptz, seated_at), "+
+			"finished_at = COALESCE($3::timestamptz, finished_at) WHERE id = $4 AND status = $5",
+		bookingTable,
+	)
+	res, err := tx.ExecContext(ctx, updateBookingStatusQuery, to, seatedAt, finishedAt, id, from)
+	if err != nil {
+		return fail(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fail(err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	// освобождаем столики с момента завершения брони: промежуток, на который они заняты, заканчивается этим моментом
+	// (или становится пустым, если бронь завершена раньше, чем началась), и столики снова становятся доступными
+	if finishedAt != nil {
+		freeBookingsTablesQuery := fmt.Sprintf(
+			"UPDATE %s SET booked_during = CASE WHEN lower(booked_during) < $2::timestamp "+
+				"THEN tsrange(lower(booked_during), $2::timestamp, '[]') ELSE 'empty'::tsrange END "+
+				"WHERE booking_id = $1 AND upper(booked_during) > $2::timestamp",
+			bookingsTablesTable,
+		)
+		if _, err = tx.ExecContext(ctx, freeBookingsTablesQuery, id, timestampArg(now)); err != nil {
+			return fail(err)
+		}
+	}
+
+	// сообщаем вебхукам ресторана о новом статусе брони
+	if err = insertOutboxEvent(ctx, tx, model.WebhookEventBookingStatusChanged, id); err != nil {
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package postgres_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// TestBookingRepository_CreateConcurrent одновременно бронирует один столик на пересекающееся время в обход проверки
+// свободных столиков в сервисе и проверяет, что ограничение-исключение excl_bookings_tables_overlap пропускает только
+// одну бронь, а остальные отклоняются с ошибкой store.ErrTableAlreadyBooked.
+func TestBookingRepository_CreateConcurrent(t *testing.T) {
+	const requests = 32
+
+	s := newTestStore(t)
+	restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableID, err := s.Tables().Create(restaurantID, 4, "", model.ZoneHall)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// брони начинаются в 19:00, 19:30 и 20:00 и длятся по 2 часа, поэтому все они пересекаются по времени
+	week := time.Now().AddDate(0, 0, 7)
+	evening := time.Date(week.Year(), week.Month(), week.Day(), 19, 0, 0, 0, time.Local)
+
+	// репозитории хранилища создаются при первом обращении, поэтому репозиторий броней получаем заранее
+	bookings := s.Bookings()
+	start := make(chan struct{})
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			from := evening.Add(time.Duration(i%3) * 30 * time.Minute)
+			<-start
+			_, errs[i] = bookings.Create(
+				restaurantID, 0, "Павел", fmt.Sprintf("+7948572%04d", i), "", 4, model.BookingStatusConfirmed,
+				from, from, 2*time.Hour, tableID,
+			)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, store.ErrTableAlreadyBooked):
+			t.Errorf("booking %d: error = %v, want store.ErrTableAlreadyBooked", i, err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("created %d overlapping bookings of one table, want 1", created)
+	}
+}
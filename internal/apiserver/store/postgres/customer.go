@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	// customerTable представляет название таблицы в БД, содержащей учётные записи гостей на сайте.
+	customerTable = "customers"
+	// customerSessionTable представляет название таблицы в БД, содержащей сеансы гостей на сайте.
+	customerSessionTable = "customer_sessions"
+)
+
+var _ store.CustomerRepository = (*CustomerRepository)(nil)
+
+// CustomerRepository представляет реализацю store.CustomerRepository.
+type CustomerRepository struct {
+	store *Store
+}
+
+func NewCustomerRepository(store *Store) *CustomerRepository {
+	return &CustomerRepository{store: store}
+}
+
+func (r *CustomerRepository) Create(name, phone, passwordHash string) (uint64, error) {
+	createCustomerQuery := fmt.Sprintf(
+		"INSERT INTO %s (name, phone, password_hash) VALUES ($1, $2, $3) RETURNING id",
+		customerTable,
+	)
+
+	var customerID uint64
+	if err := r.store.db.QueryRow(createCustomerQuery, name, phone, passwordHash).Scan(&customerID); err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("create customer: %w", store.ErrCustomerAlreadyExists)
+		}
+		return 0, fmt.Errorf("create customer: %w", err)
+	}
+	return customerID, nil
+}
+
+func (r *CustomerRepository) Get(id uint64) (*model.Customer, error) {
+	getCustomerQuery := fmt.Sprintf(
+		"SELECT id, name, phone, created_at FROM %s WHERE id = $1",
+		customerTable,
+	)
+
+	customer := &model.Customer{}
+	if err := r.store.db.QueryRow(getCustomerQuery, id).Scan(
+		&customer.ID, &customer.Name, &customer.Phone, &customer.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (r *CustomerRepository) GetByPhone(phone string) (*model.Customer, string, error) {
+	getCustomerQuery := fmt.Sprintf(
+		"SELECT id, name, phone, created_at, password_hash FROM %s WHERE phone = $1",
+		customerTable,
+	)
+
+	customer := &model.Customer{}
+	var passwordHash string
+	if err := r.store.db.QueryRow(getCustomerQuery, phone).Scan(
+		&customer.ID, &customer.Name, &customer.Phone, &customer.CreatedAt, &passwordHash,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", store.ErrCustomerNotFound
+		}
+		return nil, "", err
+	}
+	return customer, passwordHash, nil
+}
+
+func (r *CustomerRepository) GetBySession(tokenHash string) (*model.Customer, error) {
+	getCustomerQuery := fmt.Sprintf(
+		"SELECT c.id, c.name, c.phone, c.created_at FROM %s c JOIN %s cs ON cs.customer_id = c.id "+
+			"WHERE cs.token_hash = $1 AND cs.expires_at > now()",
+		customerTable, customerSessionTable,
+	)
+
+	customer := &model.Customer{}
+	if err := r.store.db.QueryRow(getCustomerQuery, tokenHash).Scan(
+		&customer.ID, &customer.Name, &customer.Phone, &customer.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (r *CustomerRepository) CreateSession(customerID uint64, tokenHash string, expiresAt time.Time) error {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) error {
+		return fmt.Errorf("create customer session: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	// истёкшие сеансы гостя больше не нужны
+	deleteExpiredSessionsQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE customer_id = $1 AND expires_at <= now()",
+		customerSessionTable,
+	)
+	if _, err = tx.ExecContext(ctx, deleteExpiredSessionsQuery, customerID); err != nil {
+		return fail(err)
+	}
+
+	createSessionQuery := fmt.Sprintf(
+		"INSERT INTO %s (token_hash, customer_id, expires_at) VALUES ($1, $2, $3)",
+		customerSessionTable,
+	)
+	if _, err = tx.ExecContext(ctx, createSessionQuery, tokenHash, customerID, expiresAt); err != nil {
+		if isForeignKeyViolation(err) {
+			return fail(store.ErrCustomerNotFound)
+		}
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return nil
+}
+
+func (r *CustomerRepository) DeleteSession(tokenHash string) error {
+	deleteSessionQuery := fmt.Sprintf("DELETE FROM %s WHERE token_hash = $1", customerSessionTable)
+
+	_, err := r.store.db.Exec(deleteSessionQuery, tokenHash)
+	return err
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// bookingDurationTable представляет название таблицы в БД, содержащей правила длительности брони в ресторанах.
+const bookingDurationTable = "booking_durations"
+
+var _ store.DurationPolicyRepository = (*DurationPolicyRepository)(nil)
+
+// DurationPolicyRepository представляет реализацю store.DurationPolicyRepository. Длительность брони по умолчанию
+// хранится в таблице ресторанов, а правила для компаний разного размера - в отдельной таблице.
+type DurationPolicyRepository struct {
+	store *Store
+}
+
+func NewDurationPolicyRepository(store *Store) *DurationPolicyRepository {
+	return &DurationPolicyRepository{store: store}
+}
+
+func (r *DurationPolicyRepository) GetAll() (map[uint64]model.DurationPolicy, error) {
+	getAllPoliciesQuery := fmt.Sprintf(
+		"SELECT r.id, r.booking_duration, bd.min_people, bd.duration "+
+			"FROM %s r "+
+			"LEFT JOIN %s bd ON bd.restaurant_id = r.id "+
+			"ORDER BY r.id, bd.min_people",
+		restaurantTable, bookingDurationTable,
+	)
+
+	rows, err := r.store.db.Query(getAllPoliciesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := make(map[uint64]model.DurationPolicy)
+
+	for rows.Next() {
+		var restaurantID uint64
+		var defaultDuration int
+		var minPeople, duration sql.NullInt64
+		if err = rows.Scan(&restaurantID, &defaultDuration, &minPeople, &duration); err != nil {
+			return policies, err
+		}
+
+		policy := policies[restaurantID]
+		policy.DefaultDuration = defaultDuration
+		if minPeople.Valid {
+			policy.Rules = append(policy.Rules, model.DurationRule{
+				MinPeople: int(minPeople.Int64),
+				Duration:  int(duration.Int64),
+			})
+		}
+		policies[restaurantID] = policy
+	}
+	if err = rows.Err(); err != nil {
+		return policies, err
+	}
+	return policies, nil
+}
+
+func (r *DurationPolicyRepository) Get(restaurantID uint64) (model.DurationPolicy, error) {
+	policy := model.DurationPolicy{}
+
+	getDefaultDurationQuery := fmt.Sprintf(
+		"SELECT booking_duration FROM %s WHERE id = $1",
+		restaurantTable,
+	)
+	if err := r.store.db.QueryRow(getDefaultDurationQuery, restaurantID).Scan(&policy.DefaultDuration); err != nil {
+		if err == sql.ErrNoRows {
+			return policy, store.ErrRestaurantNotFound
+		}
+		return policy, err
+	}
+
+	getRulesQuery := fmt.Sprintf(
+		"SELECT min_people, duration FROM %s WHERE restaurant_id = $1 ORDER BY min_people",
+		bookingDurationTable,
+	)
+
+	rows, err := r.store.db.Query(getRulesQuery, restaurantID)
+	if err != nil {
+		return policy, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rule model.DurationRule
+		if err = rows.Scan(&rule.MinPeople, &rule.Duration); err != nil {
+			return policy, err
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	if err = rows.Err(); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+func (r *DurationPolicyRepository) Set(restaurantID uint64, policy model.DurationPolicy) error {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) error {
+		return fmt.Errorf("set duration policy: %w", err)
+	}
+
+	// инициируем транзакцию, чтобы правила заменились целиком
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	updateDefaultDurationQuery := fmt.Sprintf(
+		"UPDATE %s SET booking_duration = $1 WHERE id = $2",
+		restaurantTable,
+	)
+	if _, err = tx.ExecContext(ctx, updateDefaultDurationQuery, policy.DefaultDuration, restaurantID); err != nil {
+		return fail(err)
+	}
+
+	deleteRulesQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE restaurant_id = $1",
+		bookingDurationTable,
+	)
+	if _, err = tx.ExecContext(ctx, deleteRulesQuery, restaurantID); err != nil {
+		return fail(err)
+	}
+
+	createRuleQuery := fmt.Sprintf(
+		"INSERT INTO %s (restaurant_id, min_people, duration) VALUES ($1, $2, $3)",
+		bookingDurationTable,
+	)
+	for _, rule := range policy.Rules {
+		if _, err = tx.ExecContext(ctx, createRuleQuery, restaurantID, rule.MinPeople, rule.Duration); err != nil {
+			return fail(err)
+		}
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// guestTable представляет название таблицы в БД, содержащей информацию о гостях ресторанов.
+const guestTable = "guests"
+
+var _ store.GuestRepository = (*GuestRepository)(nil)
+
+// GuestRepository представляет реализацю store.GuestRepository.
+type GuestRepository struct {
+	store *Store
+}
+
+func NewGuestRepository(store *Store) *GuestRepository {
+	return &GuestRepository{store: store}
+}
+
+func (r *GuestRepository) Get(id uint64) (*model.Guest, error) {
+	getGuestQuery := fmt.Sprintf(
+		"SELECT id, phone, name, created_at FROM %s WHERE id = $1",
+		guestTable,
+	)
+
+	guest := &model.Guest{}
+	if err := r.store.db.QueryRow(getGuestQuery, id).Scan(
+		&guest.ID, &guest.Phone, &guest.Name, &guest.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrGuestNotFound
+		}
+		return nil, err
+	}
+	return guest, nil
+}
+
+func (r *GuestRepository) GetByPhone(phone string) (*model.Guest, error) {
+	getGuestQuery := fmt.Sprintf(
+		"SELECT id, phone, name, created_at FROM %s WHERE phone = $1",
+		guestTable,
+	)
+
+	guest := &model.Guest{}
+	if err := r.store.db.QueryRow(getGuestQuery, phone).Scan(
+		&guest.ID, &guest.Phone, &guest.Name, &guest.CreatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrGuestNotFound
+		}
+		return nil, err
+	}
+	return guest, nil
+}
+
+// upsertGuest возвращает значение колонки guest_id для брони с телефоном clientPhone: ID гостя с тем же телефоном
+// в формате E.164, которому запоминается имя из новой брони, или ID нового гостя. Если телефон нельзя привести
+// к формату E.164, гость не определяется и возвращается NULL.
+func upsertGuest(ctx context.Context, tx *sql.Tx, clientName, clientPhone string) (interface{}, error) {
+	phone, err := model.NormalizePhone(clientPhone)
+	if err != nil {
+		return nil, nil
+	}
+
+	// ограничение уникальности uq_guests_phone не даёт параллельным транзакциям создать двух гостей с одним телефоном
+	upsertGuestQuery := fmt.Sprintf(
+		"INSERT INTO %s (phone, name) VALUES ($1, $2) ON CONFLICT (phone) DO UPDATE SET name = EXCLUDED.name RETURNING id",
+		guestTable,
+	)
+	var guestID uint64
+	if err := tx.QueryRowContext(ctx, upsertGuestQuery, phone, clientName).Scan(&guestID); err != nil {
+		return nil, err
+	}
+	return guestID, nil
+}
@@ -0,0 +1,114 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// TestBookingRepository_CreateGuest проверяет, что брони с одним телефоном, записанным по-разному, относятся к одному
+// гостю, имя которого берётся из его последней брони.
+func TestBookingRepository_CreateGuest(t *testing.T) {
+	s := newTestStore(t)
+
+	restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	week := time.Now().AddDate(0, 0, 7)
+	create := func(clientName, clientPhone string, hour int) model.Booking {
+		t.Helper()
+
+		at := time.Date(week.Year(), week.Month(), week.Day(), hour, 0, 0, 0, time.Local)
+		bookingID, err := s.Bookings().Create(
+			restaurantID, 0, clientName, clientPhone, "", 2, model.BookingStatusConfirmed, at, at, time.Hour,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		booking, err := s.Bookings().Get(bookingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *booking
+	}
+
+	first := create("Павел", "8 927 900-72-65", 12)
+	second := create("Паша", "+7 (927) 900-72-65", 14)
+	invalid := create("Гость", "не указан", 18)
+
+	if first.GuestID == nil || second.GuestID == nil || *first.GuestID != *second.GuestID {
+		t.Fatalf("bookings with the same phone have guests %v and %v, want one guest", first.GuestID, second.GuestID)
+	}
+	if invalid.GuestID != nil {
+		t.Errorf("booking with an invalid phone has guest %d, want none", *invalid.GuestID)
+	}
+
+	guest, err := s.Guests().GetByPhone("+79279007265")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guest.ID != *first.GuestID || guest.Name != "Паша" {
+		t.Errorf("guest = %d %q, want %d %q", guest.ID, guest.Name, *first.GuestID, "Паша")
+	}
+}
+
+// TestGuestsMigration_Backfill проверяет перенос гостей из броней, оформленных до появления гостей.
+func TestGuestsMigration_Backfill(t *testing.T) {
+	conn := newMigrationConn(t)
+	ctx := context.Background()
+
+	// временные таблицы скрывают таблицы БД с теми же названиями, поэтому перенос затрагивает только брони теста
+	if _, err := conn.ExecContext(ctx,
+		"CREATE TEMP TABLE bookings (id SERIAL PRIMARY KEY, client_name VARCHAR(255) NOT NULL, "+
+			"client_phone VARCHAR(11) NOT NULL, guest_id INTEGER); "+
+			"CREATE TEMP TABLE guests (id SERIAL PRIMARY KEY, phone VARCHAR(11) NOT NULL UNIQUE, name VARCHAR(255) NOT NULL); "+
+			"INSERT INTO bookings (client_name, client_phone) VALUES "+
+			"('Павел', '89279007265'), ('Анна', '9031234567'), ('Паша', '+7927900726'), ('Гость', 'нет'), "+
+			"('Павел Петрович', '7 927 9007265')",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := execMigrationPart(t, conn, "20261018210000_guests.up.sql", "-- normalize_phone", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// гость с телефоном 89279007265 получает имя из последней брони, а брони без цифр в телефоне не привязываются
+	tests := []struct {
+		bookingID uint64
+		// wantPhone - телефон гостя брони (пустая строка - бронь не должна привязаться к гостю)
+		wantPhone string
+		wantName  string
+	}{
+		{bookingID: 1, wantPhone: "89279007265", wantName: "Павел Петрович"},
+		{bookingID: 2, wantPhone: "89031234567", wantName: "Анна"},
+		{bookingID: 3, wantPhone: "7927900726", wantName: "Паша"},
+		{bookingID: 4},
+		{bookingID: 5, wantPhone: "89279007265", wantName: "Павел Петрович"},
+	}
+	for _, tt := range tests {
+		var phone, name sql.NullString
+		if err := conn.QueryRowContext(ctx,
+			"SELECT g.phone, g.name FROM bookings b LEFT JOIN guests g ON g.id = b.guest_id WHERE b.id = $1",
+			tt.bookingID,
+		).Scan(&phone, &name); err != nil {
+			t.Fatal(err)
+		}
+		if phone.String != tt.wantPhone || name.String != tt.wantName {
+			t.Errorf("booking %d guest = %q %q, want %q %q", tt.bookingID, phone.String, name.String, tt.wantPhone, tt.wantName)
+		}
+	}
+
+	var guests int
+	if err := conn.QueryRowContext(ctx, "SELECT count(*) FROM guests").Scan(&guests); err != nil {
+		t.Fatal(err)
+	}
+	if guests != 3 {
+		t.Errorf("migration created %d guests, want 3", guests)
+	}
+}
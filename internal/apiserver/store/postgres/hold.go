@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	// holdTable представляет название таблицы в БД, содержащей временные удержания столиков.
+	holdTable = "holds"
+	// holdsTablesTable представляет название таблицы в БД, содержащей информацию о связях между удержаниями и столиками.
+	holdsTablesTable = "holds_tables"
+)
+
+var _ store.HoldRepository = (*HoldRepository)(nil)
+
+// HoldRepository представляет реализацю store.HoldRepository.
+type HoldRepository struct {
+	store *Store
+}
+
+func NewHoldRepository(store *Store) *HoldRepository {
+	return &HoldRepository{store: store}
+}
+
+func (r *HoldRepository) Create(
+	restaurantID uint64, peopleNumber int, heldDate, heldTimeFrom time.Time, duration time.Duration,
+	expiresAt time.Time, tokenHash, clientKey string, tableIDs ...uint64,
+) (uint64, error) {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) (uint64, error) {
+		return 0, fmt.Errorf("create hold: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	if err = lockTables(ctx, tx, tableIDs); err != nil {
+		return fail(err)
+	}
+
+	// столики не должны быть заняты ни бронью, ни другим действующим удержанием
+	heldFrom, heldTo := bookedPeriod(heldDate, heldTimeFrom, duration)
+	checkBookedQuery := fmt.Sprintf(
+		"SELECT EXISTS (SELECT 1 FROM %s WHERE table_id = ANY($1) AND booked_during && tsrange($2::timestamp, $3::timestamp, '[]'))",
+		bookingsTablesTable,
+	)
+	var booked bool
+	if err = tx.QueryRowContext(ctx,
+		checkBookedQuery, idsArg(tableIDs), timestampArg(heldFrom), timestampArg(heldTo),
+	).Scan(&booked); err != nil {
+		return fail(err)
+	}
+	held, err := tablesHeld(ctx, tx, tableIDs, heldFrom, heldTo)
+	if err != nil {
+		return fail(err)
+	}
+	if booked || held {
+		return fail(store.ErrTableAlreadyBooked)
+	}
+
+	// добавляем удержание, возвращая его ID
+	createHoldQuery := fmt.Sprintf(
+		"INSERT INTO %s (restaurant_id, people_number, held_date, held_time_from, held_time_to, expires_at, token_hash, client_key) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+		holdTable,
+	)
+	var holdID uint64
+	if err = tx.QueryRowContext(ctx,
+		createHoldQuery, restaurantID, peopleNumber, heldDate, heldTimeFrom, heldTimeFrom.Add(duration), expiresAt,
+		tokenHash, clientKey,
+	).Scan(&holdID); err != nil {
+		return fail(err)
+	}
+
+	// привязываем удерживаемые столики к только что созданному удержанию
+	createHoldsTablesQuery := fmt.Sprintf(
+		"INSERT INTO %s (hold_id, table_id, held_during) VALUES ($1, $2, tsrange($3::timestamp, $4::timestamp, '[]'))",
+		holdsTablesTable,
+	)
+	for _, tableID := range tableIDs {
+		_, err = tx.ExecContext(ctx, createHoldsTablesQuery, holdID, tableID, timestampArg(heldFrom), timestampArg(heldTo))
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return holdID, nil
+}
+
+func (r *HoldRepository) Get(id uint64) (*model.Hold, error) {
+	getHoldQuery := fmt.Sprintf(
+		"SELECT id, restaurant_id, people_number, held_date, held_time_from, held_time_to, expires_at, token_hash, client_key "+
+			"FROM %s WHERE id = $1",
+		holdTable,
+	)
+
+	hold := &model.Hold{}
+	if err := r.store.db.QueryRow(getHoldQuery, id).Scan(
+		&hold.ID, &hold.RestaurantID, &hold.PeopleNumber, &hold.HeldDate, &hold.HeldTimeFrom, &hold.HeldTimeTo, &hold.ExpiresAt,
+		&hold.TokenHash, &hold.ClientKey,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrHoldNotFound
+		}
+		return nil, err
+	}
+
+	// получаем удерживаемые столики
+	getHoldTablesQuery := fmt.Sprintf(
+		"SELECT table_id FROM %s WHERE hold_id = $1 ORDER BY table_id",
+		holdsTablesTable,
+	)
+	rows, err := r.store.db.Query(getHoldTablesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hold.TableIDs = make([]uint64, 0)
+	for rows.Next() {
+		var tableID uint64
+		if err = rows.Scan(&tableID); err != nil {
+			return nil, err
+		}
+		hold.TableIDs = append(hold.TableIDs, tableID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (r *HoldRepository) CountActive(clientKey string) (int, error) {
+	countActiveHoldsQuery := fmt.Sprintf(
+		"SELECT COUNT(*) FROM %s WHERE client_key = $1 AND expires_at > now()",
+		holdTable,
+	)
+
+	var count int
+	if err := r.store.db.QueryRow(countActiveHoldsQuery, clientKey).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *HoldRepository) Confirm(id, customerID uint64, clientName, clientPhone, clientEmail, status string) (uint64, error) {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) (uint64, error) {
+		return 0, fmt.Errorf("confirm hold: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	// блокируем действующее удержание до конца транзакции, поэтому одно удержание нельзя подтвердить дважды
+	lockHoldQuery := fmt.Sprintf(
+		"SELECT id FROM %s WHERE id = $1 AND expires_at > now() FOR UPDATE",
+		holdTable,
+	)
+	if err = tx.QueryRowContext(ctx, lockHoldQuery, id).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return fail(store.ErrHoldNotFound)
+		}
+		return fail(err)
+	}
+
+	// определяем гостя по телефону из брони
+	guestID, err := upsertGuest(ctx, tx, clientName, clientPhone)
+	if err != nil {
+		return fail(err)
+	}
+
+	// оформляем бронь на то же время, на которое удерживались столики
+	createBookingQuery := fmt.Sprintf(
+		"INSERT INTO %s (restaurant_id, customer_id, guest_id, client_name, client_phone, client_email, people_number, status, booked_date, booked_time_from, booked_time_to) "+
+			"SELECT restaurant_id, $2::integer, $3::integer, $4, $5, NULLIF($6, ''), people_number, $7, held_date, held_time_from, held_time_to FROM %s WHERE id = $1 "+
+			"RETURNING id",
+		bookingTable, holdTable,
+	)
+	var bookingID uint64
+	if err = tx.QueryRowContext(ctx,
+		createBookingQuery, id, customerIDArg(customerID), guestID, clientName, clientPhone, clientEmail, status,
+	).Scan(&bookingID); err != nil {
+		if isForeignKeyViolation(err) {
+			return fail(store.ErrCustomerNotFound)
+		}
+		return fail(err)
+	}
+
+	// привязываем к брони удерживаемые столики; ограничение-исключение excl_bookings_tables_overlap
+	// по-прежнему не даст занять уже забронированный столик
+	createBookingsTablesQuery := fmt.Sprintf(
+		"INSERT INTO %s (booking_id, table_id, booked_during) "+
+			"SELECT $1, table_id, held_during FROM %s WHERE hold_id = $2",
+		bookingsTablesTable, holdsTablesTable,
+	)
+	if _, err = tx.ExecContext(ctx, createBookingsTablesQuery, bookingID, id); err != nil {
+		if isExclusionViolation(err) {
+			return fail(store.ErrTableAlreadyBooked)
+		}
+		return fail(err)
+	}
+
+	// снимаем удержание (связи удержания со столиками удаляются каскадно)
+	deleteHoldQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", holdTable)
+	if _, err = tx.ExecContext(ctx, deleteHoldQuery, id); err != nil {
+		return fail(err)
+	}
+
+	// сообщаем вебхукам ресторана о новой брони
+	if err = insertOutboxEvent(ctx, tx, model.WebhookEventBookingCreated, bookingID); err != nil {
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return bookingID, nil
+}
+
+func (r *HoldRepository) Delete(id uint64) error {
+	deleteHoldQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", holdTable)
+
+	res, err := r.store.db.Exec(deleteHoldQuery, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("delete hold: %w", store.ErrHoldNotFound)
+	}
+	return nil
+}
+
+func (r *HoldRepository) DeleteExpired() ([]uint64, error) {
+	deleteExpiredHoldsQuery := fmt.Sprintf(
+		"WITH deleted AS (DELETE FROM %s WHERE expires_at <= now() RETURNING restaurant_id) "+
+			"SELECT DISTINCT restaurant_id FROM deleted ORDER BY restaurant_id",
+		holdTable,
+	)
+
+	rows, err := r.store.db.Query(deleteExpiredHoldsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	restaurantIDs := make([]uint64, 0)
+	for rows.Next() {
+		var restaurantID uint64
+		if err = rows.Scan(&restaurantID); err != nil {
+			return restaurantIDs, err
+		}
+		restaurantIDs = append(restaurantIDs, restaurantID)
+	}
+	if err = rows.Err(); err != nil {
+		return restaurantIDs, err
+	}
+	return restaurantIDs, nil
+}
+
+// lockTables блокирует строки столиков до конца транзакции. Брони и удержания одних и тех же столиков оформляются
+// по очереди, поэтому между проверкой удержаний и бронированием столик не может занять параллельная транзакция.
+func lockTables(ctx context.Context, tx *sql.Tx, tableIDs []uint64) error {
+	lockTablesQuery := fmt.Sprintf(
+		"SELECT id FROM %s WHERE id = ANY($1) ORDER BY id FOR UPDATE",
+		tableTable,
+	)
+	rows, err := tx.QueryContext(ctx, lockTablesQuery, idsArg(tableIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// tablesHeld проверяет, удерживается ли хотя бы один из столиков действующим удержанием на промежуток времени,
+// который накладывается на промежуток [from, to].
+func tablesHeld(ctx context.Context, tx *sql.Tx, tableIDs []uint64, from, to time.Time) (bool, error) {
+	checkHeldQuery := fmt.Sprintf(
+		"SELECT EXISTS (SELECT 1 FROM %s ht JOIN %s h ON h.id = ht.hold_id "+
+			"WHERE ht.table_id = ANY($1) AND h.expires_at > now() "+
+			"AND ht.held_during && tsrange($2::timestamp, $3::timestamp, '[]'))",
+		holdsTablesTable, holdTable,
+	)
+
+	var held bool
+	if err := tx.QueryRowContext(ctx,
+		checkHeldQuery, idsArg(tableIDs), timestampArg(from), timestampArg(to),
+	).Scan(&held); err != nil {
+		return false, err
+	}
+	return held, nil
+}
+
+// idsArg переводит ID записей (например, столиков) в массив PostgreSQL для передачи в запросы (ANY($1)).
+func idsArg(ids []uint64) interface{} {
+	arg := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		arg = append(arg, int64(id))
+	}
+	return pq.Array(arg)
+}
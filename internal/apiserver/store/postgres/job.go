@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// jobTable представляет название таблицы в БД, содержащей фоновые задачи, связанные с бронями.
+const jobTable = "jobs"
+
+var _ store.JobRepository = (*JobRepository)(nil)
+
+// JobRepository представляет реализацю store.JobRepository.
+type JobRepository struct {
+	store *Store
+}
+
+func NewJobRepository(store *Store) *JobRepository {
+	return &JobRepository{store: store}
+}
+
+func (r *JobRepository) Schedule(kind string, bookingID uint64, runAt time.Time) (uint64, error) {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) (uint64, error) {
+		return 0, fmt.Errorf("schedule job: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	// прежняя задача того же вида по брони больше не нужна
+	unscheduleQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE kind = $1 AND booking_id = $2 AND status = $3",
+		jobTable,
+	)
+	if _, err = tx.ExecContext(ctx, unscheduleQuery, kind, bookingID, model.JobStatusPending); err != nil {
+		return fail(err)
+	}
+
+	scheduleQuery := fmt.Sprintf(
+		"INSERT INTO %s (kind, booking_id, run_at, status) VALUES ($1, $2, $3, $4) RETURNING id",
+		jobTable,
+	)
+	var jobID uint64
+	if err = tx.QueryRowContext(ctx,
+		scheduleQuery, kind, bookingID, runAt, model.JobStatusPending,
+	).Scan(&jobID); err != nil {
+		if isForeignKeyViolation(err) {
+			return fail(store.ErrBookingNotFound)
+		}
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return jobID, nil
+}
+
+func (r *JobRepository) Unschedule(kind string, bookingID uint64) error {
+	unscheduleQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE kind = $1 AND booking_id = $2 AND status = $3",
+		jobTable,
+	)
+	if _, err := r.store.db.Exec(unscheduleQuery, kind, bookingID, model.JobStatusPending); err != nil {
+		return fmt.Errorf("unschedule job: %w", err)
+	}
+	return nil
+}
+
+func (r *JobRepository) Claim(limit int, lockedUntil time.Time) ([]model.Job, error) {
+	// FOR UPDATE SKIP LOCKED не даёт нескольким экземплярам сервиса одновременно закрепить за собой одну задачу
+	claimQuery := fmt.Sprintf(
+		"UPDATE %[1]s SET locked_until = $2, attempts = attempts + 1 "+
+			"WHERE id IN ("+
+			"SELECT id FROM %[1]s "+
+			"WHERE status = $3 AND run_at <= now() AND (locked_until IS NULL OR locked_until <= now()) "+
+			"ORDER BY run_at, id LIMIT $1 FOR UPDATE SKIP LOCKED"+
+			") "+
+			"RETURNING id, kind, booking_id, run_at, status, attempts, COALESCE(last_error, '')",
+		jobTable,
+	)
+	rows, err := r.store.db.Query(claimQuery, limit, lockedUntil, model.JobStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("claim jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []model.Job
+	for rows.Next() {
+		var job model.Job
+		if err = rows.Scan(
+			&job.ID, &job.Kind, &job.BookingID, &job.RunAt, &job.Status, &job.Attempts, &job.LastError,
+		); err != nil {
+			return nil, fmt.Errorf("claim jobs: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *JobRepository) Complete(id uint64) error {
+	completeQuery := fmt.Sprintf(
+		"UPDATE %s SET status = $2, locked_until = NULL, finished_at = now() WHERE id = $1 AND status = $3",
+		jobTable,
+	)
+	if _, err := r.store.db.Exec(completeQuery, id, model.JobStatusDone, model.JobStatusPending); err != nil {
+		return fmt.Errorf("complete job: %w", err)
+	}
+	return nil
+}
+
+func (r *JobRepository) Retry(id uint64, runAt time.Time, lastError string) error {
+	retryQuery := fmt.Sprintf(
+		"UPDATE %s SET run_at = $2, last_error = $3, locked_until = NULL WHERE id = $1 AND status = $4",
+		jobTable,
+	)
+	if _, err := r.store.db.Exec(retryQuery, id, runAt, lastError, model.JobStatusPending); err != nil {
+		return fmt.Errorf("retry job: %w", err)
+	}
+	return nil
+}
+
+func (r *JobRepository) Fail(id uint64, lastError string) error {
+	failQuery := fmt.Sprintf(
+		"UPDATE %s SET status = $2, last_error = $3, locked_until = NULL, finished_at = now() "+
+			"WHERE id = $1 AND status = $4",
+		jobTable,
+	)
+	if _, err := r.store.db.Exec(failQuery, id, model.JobStatusFailed, lastError, model.JobStatusPending); err != nil {
+		return fmt.Errorf("fail job: %w", err)
+	}
+	return nil
+}
+
+func (r *JobRepository) Release(id uint64) error {
+	releaseQuery := fmt.Sprintf(
+		"UPDATE %s SET attempts = attempts - 1, locked_until = NULL WHERE id = $1 AND status = $2",
+		jobTable,
+	)
+	if _, err := r.store.db.Exec(releaseQuery, id, model.JobStatusPending); err != nil {
+		return fmt.Errorf("release job: %w", err)
+	}
+	return nil
+}
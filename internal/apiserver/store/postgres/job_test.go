@@ -0,0 +1,84 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// claimJob закрепляет задачи, время которых наступило, и возвращает задачу jobID, если она оказалась среди них.
+func claimJob(t *testing.T, jobRepo store.JobRepository, jobID uint64) (model.Job, bool) {
+	t.Helper()
+
+	jobs, err := jobRepo.Claim(1000, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range jobs {
+		if job.ID == jobID {
+			return job, true
+		}
+	}
+	return model.Job{}, false
+}
+
+// TestJobRepository_Lifecycle проверяет, что задача хранится в БД между подключениями (перезапусками сервиса),
+// закреплённая задача не выбирается повторно, а неудачная попытка переносит задачу на новое время.
+func TestJobRepository_Lifecycle(t *testing.T) {
+	s := newTestStore(t)
+
+	restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	week := time.Now().AddDate(0, 0, 7)
+	bookingID, err := s.Bookings().Create(
+		restaurantID, 0, "Павел", "+79485722648", "", 2, model.BookingStatusConfirmed, week, week, time.Hour,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobID, err := s.Jobs().Schedule(model.JobKindBookingReminder, bookingID, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// задачу выбирает экземпляр сервиса с новым подключением к БД
+	jobRepo := newTestStore(t).Jobs()
+	job, ok := claimJob(t, jobRepo, jobID)
+	if !ok || job.Attempts != 1 || job.BookingID != bookingID {
+		t.Fatalf("first claim = %+v, %v; want job %d at attempt 1", job, ok, jobID)
+	}
+	if _, ok = claimJob(t, jobRepo, jobID); ok {
+		t.Fatal("claimed job is claimed again")
+	}
+
+	// неудачная попытка переносит задачу, и она выбирается снова, когда наступает новое время
+	if err = jobRepo.Retry(jobID, time.Now().Add(-time.Second), "smtp is unavailable"); err != nil {
+		t.Fatal(err)
+	}
+	job, ok = claimJob(t, jobRepo, jobID)
+	if !ok || job.Attempts != 2 || job.LastError != "smtp is unavailable" {
+		t.Fatalf("claim after retry = %+v, %v; want job %d at attempt 2 with the last error", job, ok, jobID)
+	}
+
+	// попытка, к которой так и не приступили, не засчитывается
+	if err = jobRepo.Release(jobID); err != nil {
+		t.Fatal(err)
+	}
+	if job, ok = claimJob(t, jobRepo, jobID); !ok || job.Attempts != 2 {
+		t.Fatalf("claim after release = %+v, %v; want job %d at attempt 2", job, ok, jobID)
+	}
+
+	if err = jobRepo.Complete(jobID); err != nil {
+		t.Fatal(err)
+	}
+	if err = jobRepo.Release(jobID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = claimJob(t, jobRepo, jobID); ok {
+		t.Fatal("completed job is claimed again")
+	}
+}
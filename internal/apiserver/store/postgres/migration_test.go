@@ -0,0 +1,73 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/postgres"
+)
+
+// migrationsDir представляет папку с миграциями относительно папки пакета.
+const migrationsDir = "../../../../migrations"
+
+// newMigrationConn возвращает отдельное соединение с БД, строка подключения к которой указана в переменной окружения
+// TEST_POSTGRES_DSN, для проверки миграций. Временные функции и таблицы видны только в создавшем их соединении,
+// а временные таблицы скрывают одноимённые таблицы БД, поэтому соединение не возвращается в общий пул: оно
+// закрывается вместе со своей БД по окончании теста. Если переменная не задана, тест пропускается.
+func newMigrationConn(t *testing.T) *sql.Conn {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := postgres.NewDB(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// execMigrationPart выполняет в соединении conn часть миграции name: от строки from до строки to (или до конца
+// миграции, если to пустая). Так проверяются перенос и преобразование данных без изменения схемы БД.
+func execMigrationPart(t *testing.T, conn *sql.Conn, name, from, to string) error {
+	t.Helper()
+
+	migration, err := ioutil.ReadFile(filepath.Join(migrationsDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part := string(migration)
+	start := strings.Index(part, from)
+	if start < 0 {
+		t.Fatalf("%s: %q is not found", name, from)
+	}
+	part = part[start:]
+	if to != "" {
+		end := strings.Index(part, to)
+		if end < 0 {
+			t.Fatalf("%s: %q is not found", name, to)
+		}
+		part = part[:end]
+	}
+
+	_, err = conn.ExecContext(context.Background(), part)
+	return err
+}
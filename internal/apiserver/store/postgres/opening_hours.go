@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// openingHoursTable представляет название таблицы в БД, содержащей графики работы ресторанов.
+const openingHoursTable = "opening_hours"
+
+var _ store.OpeningHoursRepository = (*OpeningHoursRepository)(nil)
+
+// OpeningHoursRepository представляет реализацю store.OpeningHoursRepository.
+type OpeningHoursRepository struct {
+	store *Store
+}
+
+func NewOpeningHoursRepository(store *Store) *OpeningHoursRepository {
+	return &OpeningHoursRepository{store: store}
+}
+
+func (r *OpeningHoursRepository) GetAll() (map[uint64][]model.OpeningHours, error) {
+	getAllOpeningHoursQuery := fmt.Sprintf(
+		"SELECT restaurant_id, weekday, opens_at, closes_at, last_seating FROM %s ORDER BY restaurant_id, weekday, opens_at",
+		openingHoursTable,
+	)
+
+	rows, err := r.store.db.Query(getAllOpeningHoursQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hours := make(map[uint64][]model.OpeningHours)
+
+	for rows.Next() {
+		var restaurantID uint64
+		var shift model.OpeningHours
+		if err = rows.Scan(
+			&restaurantID, &shift.Weekday, &shift.OpensAt, &shift.ClosesAt, &shift.LastSeating,
+		); err != nil {
+			return hours, err
+		}
+		hours[restaurantID] = append(hours[restaurantID], shift)
+	}
+	if err = rows.Err(); err != nil {
+		return hours, err
+	}
+	return hours, nil
+}
+
+func (r *OpeningHoursRepository) Get(restaurantID uint64) ([]model.OpeningHours, error) {
+	getOpeningHoursQuery := fmt.Sprintf(
+		"SELECT weekday, opens_at, closes_at, last_seating FROM %s WHERE restaurant_id = $1 ORDER BY weekday, opens_at",
+		openingHoursTable,
+	)
+
+	rows, err := r.store.db.Query(getOpeningHoursQuery, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hours []model.OpeningHours
+
+	for rows.Next() {
+		var shift model.OpeningHours
+		if err = rows.Scan(
+			&shift.Weekday, &shift.OpensAt, &shift.ClosesAt, &shift.LastSeating,
+		); err != nil {
+			return hours, err
+		}
+		hours = append(hours, shift)
+	}
+	if err = rows.Err(); err != nil {
+		return hours, err
+	}
+	return hours, nil
+}
+
+func (r *OpeningHoursRepository) Set(restaurantID uint64, hours []model.OpeningHours) error {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) error {
+		return fmt.Errorf("set opening hours: %w", err)
+	}
+
+	// инициируем транзакцию, чтобы график работы заменился целиком
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	deleteOpeningHoursQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE restaurant_id = $1",
+		openingHoursTable,
+	)
+	if _, err = tx.ExecContext(ctx, deleteOpeningHoursQuery, restaurantID); err != nil {
+		return fail(err)
+	}
+
+	createOpeningHoursQuery := fmt.Sprintf(
+		"INSERT INTO %s (restaurant_id, weekday, opens_at, closes_at, last_seating) VALUES ($1, $2, $3, $4, $5)",
+		openingHoursTable,
+	)
+	for _, shift := range hours {
+		if _, err = tx.ExecContext(ctx, createOpeningHoursQuery,
+			restaurantID, shift.Weekday, shift.OpensAt.String(), shift.ClosesAt.String(), shift.LastSeating.String(),
+		); err != nil {
+			return fail(err)
+		}
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return nil
+}
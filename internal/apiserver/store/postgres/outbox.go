@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	// outboxTable представляет название таблицы в БД, содержащей исходящие события с бронями.
+	outboxTable = "outbox"
+	// webhookDeliveryTable представляет название таблицы в БД, содержащей доставки событий вебхукам.
+	webhookDeliveryTable = "webhook_deliveries"
+)
+
+var _ store.OutboxRepository = (*OutboxRepository)(nil)
+
+// OutboxRepository представляет реализацю store.OutboxRepository.
+type OutboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+// insertOutboxEvent добавляет в outbox событие event с бронью bookingID в транзакции tx, в которой изменена бронь:
+// событие сохраняется тогда и только тогда, когда сохраняется изменение брони.
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, event string, bookingID uint64) error {
+	booking, err := selectBooking(ctx, tx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	// ID события нужен в теле события, поэтому он выдаётся до добавления события
+	var eventID uint64
+	if err = tx.QueryRowContext(ctx,
+		"SELECT nextval(pg_get_serial_sequence($1, 'id'))", outboxTable,
+	).Scan(&eventID); err != nil {
+		return err
+	}
+
+	occurredAt := time.Now()
+	payload, err := json.Marshal(model.BookingEvent{
+		ID:           eventID,
+		Event:        event,
+		RestaurantID: booking.RestaurantID,
+		OccurredAt:   occurredAt,
+		Booking:      *booking,
+	})
+	if err != nil {
+		return err
+	}
+
+	insertEventQuery := fmt.Sprintf(
+		"INSERT INTO %s (id, restaurant_id, event, payload, created_at) VALUES ($1, $2, $3, $4, $5)",
+		outboxTable,
+	)
+	// тело передаётся строкой: []byte драйвер передал бы как bytea
+	_, err = tx.ExecContext(ctx, insertEventQuery, eventID, booking.RestaurantID, event, string(payload), occurredAt)
+	return err
+}
+
+func (r *OutboxRepository) Relay(limit int) (int, error) {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) (int, error) {
+		return 0, fmt.Errorf("relay outbox events: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE SKIP LOCKED не даёт нескольким экземплярам сервиса одновременно разобрать одно событие
+	selectEventsQuery := fmt.Sprintf(
+		"SELECT id FROM %s WHERE relayed_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED",
+		outboxTable,
+	)
+	rows, err := tx.QueryContext(ctx, selectEventsQuery, limit)
+	if err != nil {
+		return fail(err)
+	}
+	var eventIDs []uint64
+	for rows.Next() {
+		var eventID uint64
+		if err = rows.Scan(&eventID); err != nil {
+			rows.Close()
+			return fail(err)
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return fail(err)
+	}
+	if len(eventIDs) == 0 {
+		return 0, nil
+	}
+
+	// планируем доставку каждого события всем вебхукам ресторана, подписанным на него
+	createDeliveriesQuery := fmt.Sprintf(
+		"INSERT INTO %s (webhook_id, event_id) "+
+			"SELECT w.id, o.id FROM %s o JOIN %s w ON w.restaurant_id = o.restaurant_id "+
+			"WHERE o.id = ANY($1) AND (cardinality(w.events) = 0 OR o.event = ANY(w.events)) "+
+			"ON CONFLICT ON CONSTRAINT uq_webhook_deliveries_webhook_event DO NOTHING",
+		webhookDeliveryTable, outboxTable, webhookTable,
+	)
+	if _, err = tx.ExecContext(ctx, createDeliveriesQuery, idsArg(eventIDs)); err != nil {
+		return fail(err)
+	}
+
+	relayEventsQuery := fmt.Sprintf(
+		"UPDATE %s SET relayed_at = now() WHERE id = ANY($1)",
+		outboxTable,
+	)
+	if _, err = tx.ExecContext(ctx, relayEventsQuery, idsArg(eventIDs)); err != nil {
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return len(eventIDs), nil
+}
+
+func (r *OutboxRepository) ClaimDeliveries(limit int, lockedUntil time.Time) ([]model.WebhookDelivery, error) {
+	// FOR UPDATE SKIP LOCKED не даёт нескольким экземплярам сервиса одновременно закрепить за собой одну доставку
+	claimQuery := fmt.Sprintf(
+		"UPDATE %[1]s d SET locked_until = $2, attempts = d.attempts + 1 FROM %[2]s o "+
+			"WHERE o.id = d.event_id AND d.id IN ("+
+			"SELECT id FROM %[1]s "+
+			"WHERE status = $3 AND next_attempt_at <= now() AND (locked_until IS NULL OR locked_until <= now()) "+
+			"ORDER BY next_attempt_at, id LIMIT $1 FOR UPDATE SKIP LOCKED"+
+			") "+
+			"RETURNING %[3]s, o.payload",
+		webhookDeliveryTable, outboxTable, webhookDeliveryColumns,
+	)
+	rows, err := r.store.db.Query(claimQuery, limit, lockedUntil, model.WebhookDeliveryPending)
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+	for rows.Next() {
+		// тело события считывается в []byte: database/sql копирует такие значения, не ссылаясь на буфер драйвера
+		var delivery model.WebhookDelivery
+		var payload []byte
+		if err = scanWebhookDelivery(rows, &delivery, &payload); err != nil {
+			return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *OutboxRepository) CompleteDelivery(id uint64, statusCode int) error {
+	completeQuery := fmt.Sprintf(
+		"UPDATE %s SET status = $2, last_status_code = $3, last_error = NULL, locked_until = NULL, "+
+			"delivered_at = now() WHERE id = $1 AND status = $4",
+		webhookDeliveryTable,
+	)
+	if _, err := r.store.db.Exec(completeQuery,
+		id, model.WebhookDeliveryDelivered, statusCode, model.WebhookDeliveryPending,
+	); err != nil {
+		return fmt.Errorf("complete webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) RetryDelivery(id uint64, runAt time.Time, statusCode int, lastError string) error {
+	retryQuery := fmt.Sprintf(
+		"UPDATE %s SET next_attempt_at = $2, last_status_code = NULLIF($3, 0), last_error = $4, locked_until = NULL "+
+			"WHERE id = $1 AND status = $5",
+		webhookDeliveryTable,
+	)
+	if _, err := r.store.db.Exec(retryQuery,
+		id, runAt, statusCode, lastError, model.WebhookDeliveryPending,
+	); err != nil {
+		return fmt.Errorf("retry webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) FailDelivery(id uint64, statusCode int, lastError string) error {
+	failQuery := fmt.Sprintf(
+		"UPDATE %s SET status = $2, last_status_code = NULLIF($3, 0), last_error = $4, locked_until = NULL "+
+			"WHERE id = $1 AND status = $5",
+		webhookDeliveryTable,
+	)
+	if _, err := r.store.db.Exec(failQuery,
+		id, model.WebhookDeliveryFailed, statusCode, lastError, model.WebhookDeliveryPending,
+	); err != nil {
+		return fmt.Errorf("fail webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) ReleaseDelivery(id uint64) error {
+	releaseQuery := fmt.Sprintf(
+		"UPDATE %s SET attempts = attempts - 1, locked_until = NULL WHERE id = $1 AND status = $2",
+		webhookDeliveryTable,
+	)
+	if _, err := r.store.db.Exec(releaseQuery, id, model.WebhookDeliveryPending); err != nil {
+		return fmt.Errorf("release webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// webhookDeliveryColumns представляет список колонок доставки события вебхуку (вместе с видом события из outbox
+// с псевдонимом o) в порядке, в котором их сканирует scanWebhookDelivery.
+const webhookDeliveryColumns = "d.id, d.webhook_id, d.event_id, o.event, d.status, d.attempts, " +
+	"COALESCE(d.last_status_code, 0), COALESCE(d.last_error, ''), d.next_attempt_at, d.created_at, d.delivered_at"
+
+// scanWebhookDelivery считывает доставку события вебхуку, выбранную с колонками webhookDeliveryColumns, и
+// дополнительные колонки extra, выбранные после них.
+func scanWebhookDelivery(row rowScanner, delivery *model.WebhookDelivery, extra ...interface{}) error {
+	var deliveredAt sql.NullTime
+	dest := []interface{}{
+		&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.Event, &delivery.Status, &delivery.Attempts,
+		&delivery.LastStatusCode, &delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt, &deliveredAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return nil
+}
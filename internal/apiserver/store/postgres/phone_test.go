@@ -0,0 +1,85 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// phoneMigration представляет миграцию, приводящую телефоны к формату E.164.
+const phoneMigration = "20261018220000_phone_e164.up.sql"
+
+// newPhoneMigrationConn возвращает соединение с БД, в котором объявлены временные функции миграции телефонов.
+func newPhoneMigrationConn(t *testing.T) *sql.Conn {
+	t.Helper()
+
+	conn := newMigrationConn(t)
+	// выполняем только объявления функций: проверка и обновление телефонов в таблицах не нужны
+	if err := execMigrationPart(t, conn, phoneMigration, "CREATE FUNCTION pg_temp.to_e164", "\nDO\n"); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// callPhoneFunction вызывает функцию миграции телефонов и возвращает её результат (пустую строку вместо NULL).
+func callPhoneFunction(t *testing.T, conn *sql.Conn, function, phone string) string {
+	t.Helper()
+
+	var got sql.NullString
+	if err := conn.QueryRowContext(context.Background(), "SELECT pg_temp."+function+"($1)", phone).Scan(&got); err != nil {
+		t.Fatal(err)
+	}
+	return got.String
+}
+
+func TestPhoneMigration_ToE164(t *testing.T) {
+	conn := newPhoneMigrationConn(t)
+
+	// миграция должна приводить сохранённые телефоны к тому же виду, что и model.NormalizePhone
+	phones := []string{
+		"89279007265", "79279007265", "+79279007265", "9279007265", "84951234567", "+7 701 234 56 78",
+		"8 927 900 72 65", "8-927-900-72-65", "8 (927) 900-72-65", "+7.927.900.72.65", "+7\t927 900 7265",
+		"  +7 927 900-72-65\n", " +7 927 900-72-65",
+		"+44 20 7946 0958", "0044 20 7946 0958", "+1 (202) 555-0143", "+49 30 901820", "+2901234", "+123456789012345",
+		"", "   ", "+", "8 927 900 72 6five", "+7 927 900-72-65 ext. 1", "8+9279007265", "++79279007265",
+		"8/927/9007265", "８９２７９００７２６５", "89279007265' OR '1'='1", "8927900726", "892790072650",
+		"+7927900726", "+792790072650", "4951234567", "442079460958", "+290123", "+1234567890123456",
+		"+0123456789", "000442079460958",
+	}
+	for _, phone := range phones {
+		want, err := model.NormalizePhone(phone)
+		if err != nil {
+			want = ""
+		}
+		if got := callPhoneFunction(t, conn, "to_e164", phone); got != want {
+			t.Errorf("to_e164(%q) = %q; want %q", phone, got, want)
+		}
+	}
+}
+
+func TestPhoneMigration_GuestToE164(t *testing.T) {
+	conn := newPhoneMigrationConn(t)
+
+	// телефоны гостей до миграции содержат только цифры, а российские номера записаны через 8
+	tests := []struct {
+		phone string
+		// want - телефон в формате E.164 (пустая строка - телефон нельзя привести к формату E.164)
+		want string
+	}{
+		{phone: "89279007265", want: "+79279007265"},
+		{phone: "84951234567", want: "+74951234567"},
+		{phone: "12025550143", want: "+12025550143"},
+		{phone: "4930901820", want: "+4930901820"},
+		{phone: "79279007"},
+		{phone: "123456"},
+		{phone: "0123456789"},
+		{phone: ""},
+	}
+	for _, tt := range tests {
+		if got := callPhoneFunction(t, conn, "guest_to_e164", tt.phone); got != tt.want {
+			t.Errorf("guest_to_e164(%q) = %q; want %q", tt.phone, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// reliabilityPolicyTable представляет название таблицы в БД, содержащей правила надёжности гостей ресторанов.
+const reliabilityPolicyTable = "reliability_policies"
+
+var _ store.ReliabilityPolicyRepository = (*ReliabilityPolicyRepository)(nil)
+
+// ReliabilityPolicyRepository представляет реализацю store.ReliabilityPolicyRepository. У ресторана, который
+// не задал правила, в таблице нет строки.
+type ReliabilityPolicyRepository struct {
+	store *Store
+}
+
+func NewReliabilityPolicyRepository(store *Store) *ReliabilityPolicyRepository {
+	return &ReliabilityPolicyRepository{store: store}
+}
+
+func (r *ReliabilityPolicyRepository) Get(restaurantID uint64) (model.ReliabilityPolicy, error) {
+	policy := model.ReliabilityPolicy{}
+
+	getPolicyQuery := fmt.Sprintf(
+		"SELECT COALESCE(rp.confirmation_after, 0), COALESCE(rp.block_after, 0), COALESCE(rp.block_days, 0) "+
+			"FROM %s r "+
+			"LEFT JOIN %s rp ON rp.restaurant_id = r.id "+
+			"WHERE r.id = $1",
+		restaurantTable, reliabilityPolicyTable,
+	)
+	if err := r.store.db.QueryRow(getPolicyQuery, restaurantID).Scan(
+		&policy.ConfirmationAfter, &policy.BlockAfter, &policy.BlockDays,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return policy, store.ErrRestaurantNotFound
+		}
+		return policy, err
+	}
+	return policy, nil
+}
+
+func (r *ReliabilityPolicyRepository) Set(restaurantID uint64, policy model.ReliabilityPolicy) error {
+	setPolicyQuery := fmt.Sprintf(
+		"INSERT INTO %s (restaurant_id, confirmation_after, block_after, block_days) VALUES ($1, $2, $3, $4) "+
+			"ON CONFLICT (restaurant_id) DO UPDATE SET confirmation_after = EXCLUDED.confirmation_after, "+
+			"block_after = EXCLUDED.block_after, block_days = EXCLUDED.block_days",
+		reliabilityPolicyTable,
+	)
+	if _, err := r.store.db.Exec(
+		setPolicyQuery, restaurantID, policy.ConfirmationAfter, policy.BlockAfter, policy.BlockDays,
+	); err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("set reliability policy: %w", store.ErrRestaurantNotFound)
+		}
+		return fmt.Errorf("set reliability policy: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
@@ -12,6 +13,9 @@ import (
 // restaurantTable представляет название таблицы в БД, содержащей информацию о ресторанах.
 const restaurantTable = "restaurants"
 
+// restaurantColumns представляет список колонок таблицы с ресторанами, которые считываются в model.Restaurant.
+const restaurantColumns = "id, name, average_waiting_time, average_check, allocation_strategy"
+
 var _ store.RestaurantRepository = (*RestaurantRepository)(nil)
 
 // RestaurantRepository представляет реализацю store.RestaurantRepository.
@@ -42,8 +46,8 @@ func (r *RestaurantRepository) Create(name string, averageWaitingTime int, avera
 
 func (r *RestaurantRepository) GetAll() ([]model.Restaurant, error) {
 	getAllRestaurantsQuery := fmt.Sprintf(
-		"SELECT * FROM %s ORDER BY average_waiting_time, average_check",
-		restaurantTable,
+		"SELECT %s FROM %s ORDER BY average_waiting_time, average_check",
+		restaurantColumns, restaurantTable,
 	)
 
 	rows, err := r.store.db.Query(getAllRestaurantsQuery)
@@ -57,7 +61,7 @@ func (r *RestaurantRepository) GetAll() ([]model.Restaurant, error) {
 	for rows.Next() {
 		var restaurant model.Restaurant
 		if err = rows.Scan(
-			&restaurant.ID, &restaurant.Name, &restaurant.AverageWaitingTime, &restaurant.AverageCheck,
+			&restaurant.ID, &restaurant.Name, &restaurant.AverageWaitingTime, &restaurant.AverageCheck, &restaurant.AllocationStrategy,
 		); err != nil {
 			return restaurants, err
 		}
@@ -69,18 +73,33 @@ func (r *RestaurantRepository) GetAll() ([]model.Restaurant, error) {
 	return restaurants, nil
 }
 
-func (r *RestaurantRepository) GetAllAvailable(desiredDate, desiredTime string, peopleNumber int) ([]model.Restaurant, error) {
+func (r *RestaurantRepository) GetAllAvailable(desiredDateTime time.Time, peopleNumber int, zone string) ([]model.Restaurant, error) {
+	// свободные столики всех ресторанов находятся одним запросом: у каждого ресторана своя длительность брони
+	// (desired), а столик свободен, если у него нет броней и действующих удержаний, пересекающихся с желаемым
+	// промежутком времени. Свободные места подсчитываются по зонам каждого ресторана, а оконная функция дополняет их
+	// общим количеством свободных мест в ресторане
 	getAllAvailableRestaurantsQuery := fmt.Sprintf(
-		`SELECT r.id, r.name, r.average_waiting_time, r.average_check, SUM(t.seats_number) as available_seats_number
-				FROM get_available_tables(date '%s', time '%s') t
-				JOIN %s r ON r.id = t.restaurant_id
-				GROUP BY r.id, r.name, r.average_waiting_time, r.average_check
-				HAVING SUM(t.seats_number) > $1
-				ORDER BY r.average_waiting_time, r.average_check`,
-		desiredDate, desiredTime, restaurantTable,
+		`WITH desired AS (SELECT r.id AS restaurant_id,
+						tsrange($1::timestamp, $1::timestamp + get_booking_duration(r.id, $2), '[]') AS during
+					FROM %[1]s r)
+			SELECT id, name, average_waiting_time, average_check, allocation_strategy, zone, zone_seats_number, available_seats_number
+				FROM (SELECT r.id, r.name, r.average_waiting_time, r.average_check, r.allocation_strategy, t.zone,
+						SUM(t.seats_number) AS zone_seats_number,
+						SUM(SUM(t.seats_number)) OVER (PARTITION BY r.id) AS available_seats_number
+					FROM %[1]s r
+					JOIN desired d ON d.restaurant_id = r.id
+					JOIN %[2]s t ON t.restaurant_id = r.id AND ($3::text = '' OR t.zone = $3::text)
+					WHERE NOT EXISTS (SELECT 1 FROM %[3]s bt
+							WHERE bt.table_id = t.id AND bt.booked_during && d.during)
+						AND NOT EXISTS (SELECT 1 FROM %[4]s ht JOIN %[5]s h ON h.id = ht.hold_id
+							WHERE ht.table_id = t.id AND h.expires_at > now() AND ht.held_during && d.during)
+					GROUP BY r.id, r.name, r.average_waiting_time, r.average_check, r.allocation_strategy, t.zone) z
+				WHERE available_seats_number > $2
+				ORDER BY average_waiting_time, average_check, id`,
+		restaurantTable, tableTable, bookingsTablesTable, holdsTablesTable, holdTable,
 	)
 
-	rows, err := r.store.db.Query(getAllAvailableRestaurantsQuery, peopleNumber)
+	rows, err := r.store.db.Query(getAllAvailableRestaurantsQuery, timestampArg(desiredDateTime), peopleNumber, zone)
 	if err != nil {
 		return nil, err
 	}
@@ -90,29 +109,44 @@ func (r *RestaurantRepository) GetAllAvailable(desiredDate, desiredTime string,
 
 	for rows.Next() {
 		var restaurant model.Restaurant
+		var zoneAvailability model.ZoneAvailability
 		if err = rows.Scan(
-			&restaurant.ID, &restaurant.Name, &restaurant.AverageWaitingTime, &restaurant.AverageCheck, &restaurant.AvailableSeatsNumber,
+			&restaurant.ID, &restaurant.Name, &restaurant.AverageWaitingTime, &restaurant.AverageCheck, &restaurant.AllocationStrategy,
+			&zoneAvailability.Zone, &zoneAvailability.AvailableSeatsNumber, &restaurant.AvailableSeatsNumber,
 		); err != nil {
 			return restaurants, err
 		}
+
+		// строки одного ресторана идут подряд: каждая из них содержит свободные места в одной из его зон
+		if n := len(restaurants); n > 0 && restaurants[n-1].ID == restaurant.ID {
+			restaurants[n-1].AvailableZones = append(restaurants[n-1].AvailableZones, zoneAvailability)
+			continue
+		}
+		restaurant.AvailableZones = []model.ZoneAvailability{zoneAvailability}
 		restaurants = append(restaurants, restaurant)
 	}
 	if err = rows.Err(); err != nil {
 		return restaurants, err
 	}
+
+	for _, restaurant := range restaurants {
+		model.SortZoneAvailability(restaurant.AvailableZones)
+	}
 	return restaurants, nil
 }
 
 func (r *RestaurantRepository) Get(id uint64) (*model.Restaurant, error) {
 	getRestaurantQuery := fmt.Sprintf(
-		"SELECT * FROM %s WHERE id = $1",
-		restaurantTable,
+		"SELECT %s FROM %s WHERE id = $1",
+		restaurantColumns, restaurantTable,
 	)
 
 	restaurant := &model.Restaurant{}
 	if err := r.store.db.QueryRow(
 		getRestaurantQuery, id,
-	).Scan(&restaurant.ID, &restaurant.Name, &restaurant.AverageWaitingTime, &restaurant.AverageCheck); err != nil {
+	).Scan(
+		&restaurant.ID, &restaurant.Name, &restaurant.AverageWaitingTime, &restaurant.AverageCheck, &restaurant.AllocationStrategy,
+	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, store.ErrRestaurantNotFound
 		}
@@ -122,8 +156,8 @@ func (r *RestaurantRepository) Get(id uint64) (*model.Restaurant, error) {
 }
 
 func (r *RestaurantRepository) Update(id uint64, data model.UpdateRestaurantData) error {
-	setValues := make([]string, 0, 3)
-	args := make([]interface{}, 0, 3)
+	setValues := make([]string, 0, 4)
+	args := make([]interface{}, 0, 4)
 	argId := 1
 
 	if data.Name != nil {
@@ -144,6 +178,12 @@ func (r *RestaurantRepository) Update(id uint64, data model.UpdateRestaurantData
 		argId++
 	}
 
+	if data.AllocationStrategy != nil {
+		setValues = append(setValues, fmt.Sprintf("allocation_strategy=$%d", argId))
+		args = append(args, *data.AllocationStrategy)
+		argId++
+	}
+
 	setQuery := strings.Join(setValues, ", ")
 
 	updateRestaurantQuery := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d",
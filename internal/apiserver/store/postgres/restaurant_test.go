@@ -0,0 +1,91 @@
+package postgres_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store/postgres"
+)
+
+// newTestStore подключается к БД с применёнными миграциями, строка подключения к которой указана в переменной
+// окружения TEST_POSTGRES_DSN. Если переменная не задана, тест пропускается.
+func newTestStore(t *testing.T) *postgres.Store {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := postgres.NewDB(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return postgres.NewStore(db)
+}
+
+func TestRestaurantRepository_GetAllAvailable(t *testing.T) {
+	s := newTestStore(t)
+
+	restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookedTableID, err := s.Tables().Create(restaurantID, 4, "", model.ZoneHall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Tables().Create(restaurantID, 2, "", model.ZoneTerrace); err != nil {
+		t.Fatal(err)
+	}
+
+	// столик на 4 места занят с 19:00 до 21:00
+	week := time.Now().AddDate(0, 0, 7)
+	at := func(hour, minute int) time.Time {
+		return time.Date(week.Year(), week.Month(), week.Day(), hour, minute, 0, 0, time.Local)
+	}
+	if _, err = s.Bookings().Create(
+		restaurantID, 0, "Павел", "+79485722648", "", 4, model.BookingStatusConfirmed,
+		at(19, 0), at(19, 0), 2*time.Hour, bookedTableID,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		from         time.Time
+		peopleNumber int
+		zone         string
+		// wantSeats - количество свободных мест в ресторане (0 - ресторан не должен найтись)
+		wantSeats int
+	}{
+		{name: "before the booking", from: at(16, 59), peopleNumber: 1, wantSeats: 6},
+		{name: "ends when the booking starts", from: at(17, 0), peopleNumber: 1, wantSeats: 2},
+		{name: "during the booking", from: at(20, 0), peopleNumber: 1, wantSeats: 2},
+		{name: "not enough seats during the booking", from: at(20, 0), peopleNumber: 2},
+		{name: "after the booking", from: at(21, 1), peopleNumber: 3, wantSeats: 6},
+		{name: "zone", from: at(16, 0), peopleNumber: 1, zone: model.ZoneTerrace, wantSeats: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restaurants, err := s.Restaurants().GetAllAvailable(tt.from, tt.peopleNumber, tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			seats := 0
+			for _, restaurant := range restaurants {
+				if restaurant.ID == restaurantID {
+					seats = restaurant.AvailableSeatsNumber
+				}
+			}
+			if seats != tt.wantSeats {
+				t.Errorf("available seats = %d, want %d", seats, tt.wantSeats)
+			}
+		})
+	}
+}
@@ -9,10 +9,21 @@ import (
 var _ store.Store = (*Store)(nil)
 
 type Store struct {
-	db             *sql.DB
-	restaurantRepo store.RestaurantRepository
-	tableRepo      store.TableRepository
-	bookingRepo    store.BookingRepository
+	db              *sql.DB
+	restaurantRepo  store.RestaurantRepository
+	tableRepo       store.TableRepository
+	bookingRepo     store.BookingRepository
+	hoursRepo       store.OpeningHoursRepository
+	policyRepo      store.DurationPolicyRepository
+	reliabilityRepo store.ReliabilityPolicyRepository
+	waitlistRepo    store.WaitlistRepository
+	holdRepo        store.HoldRepository
+	userRepo        store.UserRepository
+	customerRepo    store.CustomerRepository
+	guestRepo       store.GuestRepository
+	jobRepo         store.JobRepository
+	webhookRepo     store.WebhookRepository
+	outboxRepo      store.OutboxRepository
 }
 
 func NewStore(db *sql.DB) *Store {
@@ -48,3 +59,113 @@ func (s *Store) Bookings() store.BookingRepository {
 
 	return s.bookingRepo
 }
+
+func (s *Store) OpeningHours() store.OpeningHoursRepository {
+	if s.hoursRepo != nil {
+		return s.hoursRepo
+	}
+
+	s.hoursRepo = NewOpeningHoursRepository(s)
+
+	return s.hoursRepo
+}
+
+func (s *Store) DurationPolicies() store.DurationPolicyRepository {
+	if s.policyRepo != nil {
+		return s.policyRepo
+	}
+
+	s.policyRepo = NewDurationPolicyRepository(s)
+
+	return s.policyRepo
+}
+
+func (s *Store) ReliabilityPolicies() store.ReliabilityPolicyRepository {
+	if s.reliabilityRepo != nil {
+		return s.reliabilityRepo
+	}
+
+	s.reliabilityRepo = NewReliabilityPolicyRepository(s)
+
+	return s.reliabilityRepo
+}
+
+func (s *Store) Waitlist() store.WaitlistRepository {
+	if s.waitlistRepo != nil {
+		return s.waitlistRepo
+	}
+
+	s.waitlistRepo = NewWaitlistRepository(s)
+
+	return s.waitlistRepo
+}
+
+func (s *Store) Holds() store.HoldRepository {
+	if s.holdRepo != nil {
+		return s.holdRepo
+	}
+
+	s.holdRepo = NewHoldRepository(s)
+
+	return s.holdRepo
+}
+
+func (s *Store) Users() store.UserRepository {
+	if s.userRepo != nil {
+		return s.userRepo
+	}
+
+	s.userRepo = NewUserRepository(s)
+
+	return s.userRepo
+}
+
+func (s *Store) Customers() store.CustomerRepository {
+	if s.customerRepo != nil {
+		return s.customerRepo
+	}
+
+	s.customerRepo = NewCustomerRepository(s)
+
+	return s.customerRepo
+}
+
+func (s *Store) Guests() store.GuestRepository {
+	if s.guestRepo != nil {
+		return s.guestRepo
+	}
+
+	s.guestRepo = NewGuestRepository(s)
+
+	return s.guestRepo
+}
+
+func (s *Store) Jobs() store.JobRepository {
+	if s.jobRepo != nil {
+		return s.jobRepo
+	}
+
+	s.jobRepo = NewJobRepository(s)
+
+	return s.jobRepo
+}
+
+func (s *Store) Webhooks() store.WebhookRepository {
+	if s.webhookRepo != nil {
+		return s.webhookRepo
+	}
+
+	s.webhookRepo = NewWebhookRepository(s)
+
+	return s.webhookRepo
+}
+
+func (s *Store) Outbox() store.OutboxRepository {
+	if s.outboxRepo != nil {
+		return s.outboxRepo
+	}
+
+	s.outboxRepo = NewOutboxRepository(s)
+
+	return s.outboxRepo
+}
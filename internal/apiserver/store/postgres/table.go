@@ -3,14 +3,25 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
 	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
 )
 
-// tableTable представляет название таблицы в БД, содержащей записи о столиках в ресторанах.
-const tableTable = "tables"
+const (
+	// tableTable представляет название таблицы в БД, содержащей записи о столиках в ресторанах.
+	tableTable = "tables"
+	// tableJoinTable представляет название таблицы в БД, содержащей пары столиков, которые можно сдвинуть.
+	tableJoinTable = "table_joins"
+)
+
+// tableColumns представляет список колонок таблицы со столиками, которые считываются в model.Table.
+const tableColumns = "id, restaurant_id, seats_number, position, zone"
 
 var _ store.TableRepository = (*TableRepository)(nil)
 
@@ -23,15 +34,15 @@ func NewTableRepository(store *Store) *TableRepository {
 	return &TableRepository{store: store}
 }
 
-func (r *TableRepository) Create(restaurantID uint64, seatsNumber int) (uint64, error) {
+func (r *TableRepository) Create(restaurantID uint64, seatsNumber int, position, zone string) (uint64, error) {
 	createTableQuery := fmt.Sprintf(
-		"INSERT INTO %s (restaurant_id, seats_number) VALUES ($1, $2) RETURNING id",
+		"INSERT INTO %s (restaurant_id, seats_number, position, zone) VALUES ($1, $2, $3, $4) RETURNING id",
 		tableTable,
 	)
 
 	var id uint64
 	err := r.store.db.QueryRow(
-		createTableQuery, restaurantID, seatsNumber,
+		createTableQuery, restaurantID, seatsNumber, position, zone,
 	).Scan(&id)
 	if err != nil {
 		return 0, err
@@ -39,13 +50,25 @@ func (r *TableRepository) Create(restaurantID uint64, seatsNumber int) (uint64,
 	return id, nil
 }
 
-func (r *TableRepository) GetAllAvailable(restaurantID uint64, desiredDate, desiredTime string) ([]model.Table, error) {
+func (r *TableRepository) GetAllAvailable(restaurantID uint64, desiredDateTime time.Time, duration time.Duration, zone string) ([]model.Table, error) {
+	// проверяются только столики ресторана: столик свободен, если у него нет броней и действующих удержаний,
+	// пересекающихся с [desiredDateTime, desiredDateTime + duration] (как в GetAllAvailableBySlots)
 	getAllAvailableTablesQuery := fmt.Sprintf(
-		"SELECT * FROM get_available_tables(date '%s', time '%s') WHERE restaurant_id = $1",
-		desiredDate, desiredTime,
+		"SELECT t.id, t.restaurant_id, t.seats_number, t.position, t.zone "+
+			"FROM %s t "+
+			"WHERE t.restaurant_id = $1 AND ($4::text = '' OR t.zone = $4::text) "+
+			"AND NOT EXISTS (SELECT 1 FROM %s bt "+
+			"WHERE bt.table_id = t.id "+
+			"AND bt.booked_during && tsrange($2::timestamp, $3::timestamp, '[]')) "+
+			"AND NOT EXISTS (SELECT 1 FROM %s ht JOIN %s h ON h.id = ht.hold_id "+
+			"WHERE ht.table_id = t.id AND h.expires_at > now() "+
+			"AND ht.held_during && tsrange($2::timestamp, $3::timestamp, '[]')) "+
+			"ORDER BY t.id",
+		tableTable, bookingsTablesTable, holdsTablesTable, holdTable,
 	)
 
-	rows, err := r.store.db.Query(getAllAvailableTablesQuery, restaurantID)
+	bookedFrom, bookedTo := bookedPeriod(desiredDateTime, desiredDateTime, duration)
+	rows, err := r.store.db.Query(getAllAvailableTablesQuery, restaurantID, timestampArg(bookedFrom), timestampArg(bookedTo), zone)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +79,7 @@ func (r *TableRepository) GetAllAvailable(restaurantID uint64, desiredDate, desi
 	for rows.Next() {
 		var table model.Table
 		if err = rows.Scan(
-			&table.ID, &table.RestaurantID, &table.SeatsNumber,
+			&table.ID, &table.RestaurantID, &table.SeatsNumber, &table.Position, &table.Zone,
 		); err != nil {
 			return tables, err
 		}
@@ -65,13 +88,124 @@ func (r *TableRepository) GetAllAvailable(restaurantID uint64, desiredDate, desi
 	if err = rows.Err(); err != nil {
 		return tables, err
 	}
+
+	if err = r.fillJoinableTables(tables, restaurantID); err != nil {
+		return tables, err
+	}
 	return tables, nil
 }
 
+func (r *TableRepository) GetAllAvailableForBooking(bookingID uint64, desiredDateTime time.Time, duration time.Duration) ([]model.Table, error) {
+	// свободными считаются столики ресторана брони, у которых нет других броней и действующих удержаний
+	// на пересекающийся промежуток времени (по тем же правилам, что и в ограничении-исключении excl_bookings_tables_overlap)
+	getAllAvailableTablesQuery := fmt.Sprintf(
+		"SELECT t.id, t.restaurant_id, t.seats_number, t.position, t.zone "+
+			"FROM %s t "+
+			"WHERE t.restaurant_id = (SELECT b.restaurant_id FROM %s b WHERE b.id = $1) "+
+			"AND NOT EXISTS (SELECT 1 FROM %s bt "+
+			"WHERE bt.table_id = t.id AND bt.booking_id <> $1 "+
+			"AND bt.booked_during && tsrange($2::timestamp, $3::timestamp, '[]')) "+
+			"AND NOT EXISTS (SELECT 1 FROM %s ht JOIN %s h ON h.id = ht.hold_id "+
+			"WHERE ht.table_id = t.id AND h.expires_at > now() "+
+			"AND ht.held_during && tsrange($2::timestamp, $3::timestamp, '[]')) "+
+			"ORDER BY t.id",
+		tableTable, bookingTable, bookingsTablesTable, holdsTablesTable, holdTable,
+	)
+
+	bookedFrom, bookedTo := bookedPeriod(desiredDateTime, desiredDateTime, duration)
+	rows, err := r.store.db.Query(getAllAvailableTablesQuery, bookingID, timestampArg(bookedFrom), timestampArg(bookedTo))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []model.Table
+
+	for rows.Next() {
+		var table model.Table
+		if err = rows.Scan(
+			&table.ID, &table.RestaurantID, &table.SeatsNumber, &table.Position, &table.Zone,
+		); err != nil {
+			return tables, err
+		}
+		tables = append(tables, table)
+	}
+	if err = rows.Err(); err != nil {
+		return tables, err
+	}
+
+	if len(tables) == 0 {
+		return tables, nil
+	}
+	if err = r.fillJoinableTables(tables, tables[0].RestaurantID); err != nil {
+		return tables, err
+	}
+	return tables, nil
+}
+
+func (r *TableRepository) GetAllAvailableBySlots(restaurantID uint64, slots []time.Time, duration time.Duration, zone string) ([][]model.Table, error) {
+	// моменты начала брони разворачиваются в строки и сопоставляются со столиками ресторана за один запрос: столик
+	// свободен в момент slot, если у него нет броней и действующих удержаний, пересекающихся с [slot, slot + duration]
+	getAvailableBySlotsQuery := fmt.Sprintf(
+		"SELECT s.idx, t.id, t.restaurant_id, t.seats_number, t.position, t.zone "+
+			"FROM unnest($2::timestamp[]) WITH ORDINALITY AS s(slot, idx) "+
+			"JOIN %s t ON t.restaurant_id = $1 AND ($4::text = '' OR t.zone = $4::text) "+
+			"WHERE NOT EXISTS (SELECT 1 FROM %s bt "+
+			"WHERE bt.table_id = t.id "+
+			"AND bt.booked_during && tsrange(s.slot, s.slot + make_interval(mins => $3), '[]')) "+
+			"AND NOT EXISTS (SELECT 1 FROM %s ht JOIN %s h ON h.id = ht.hold_id "+
+			"WHERE ht.table_id = t.id AND h.expires_at > now() "+
+			"AND ht.held_during && tsrange(s.slot, s.slot + make_interval(mins => $3), '[]')) "+
+			"ORDER BY s.idx, t.id",
+		tableTable, bookingsTablesTable, holdsTablesTable, holdTable,
+	)
+
+	slotArgs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		slotArgs = append(slotArgs, timestampArg(slot))
+	}
+
+	rows, err := r.store.db.Query(getAvailableBySlotsQuery, restaurantID, pq.Array(slotArgs), durationMinutes(duration), zone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// столики всех моментов собираются в один список, чтобы получить столики, которые можно сдвинуть, одним запросом
+	var tables []model.Table
+	var slotIndexes []int
+
+	for rows.Next() {
+		var table model.Table
+		var idx int
+		if err = rows.Scan(
+			&idx, &table.ID, &table.RestaurantID, &table.SeatsNumber, &table.Position, &table.Zone,
+		); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+		// WITH ORDINALITY нумерует строки с единицы
+		slotIndexes = append(slotIndexes, idx-1)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = r.fillJoinableTables(tables, restaurantID); err != nil {
+		return nil, err
+	}
+
+	available := make([][]model.Table, len(slots))
+	for i, table := range tables {
+		available[slotIndexes[i]] = append(available[slotIndexes[i]], table)
+	}
+	return available, nil
+}
+
 func (r *TableRepository) GetAll(restaurantID uint64) ([]model.Table, error) {
 	getAllTablesQuery := fmt.Sprintf(
-		"SELECT * FROM %s WHERE restaurant_id = $1",
-		tableTable,
+		"SELECT %s FROM %s WHERE restaurant_id = $1 ORDER BY id",
+		tableColumns, tableTable,
 	)
 
 	rows, err := r.store.db.Query(getAllTablesQuery, restaurantID)
@@ -85,7 +219,7 @@ func (r *TableRepository) GetAll(restaurantID uint64) ([]model.Table, error) {
 	for rows.Next() {
 		var table model.Table
 		if err = rows.Scan(
-			&table.ID, &table.RestaurantID, &table.SeatsNumber,
+			&table.ID, &table.RestaurantID, &table.SeatsNumber, &table.Position, &table.Zone,
 		); err != nil {
 			return tables, err
 		}
@@ -94,30 +228,39 @@ func (r *TableRepository) GetAll(restaurantID uint64) ([]model.Table, error) {
 	if err = rows.Err(); err != nil {
 		return tables, err
 	}
+
+	if err = r.fillJoinableTables(tables, restaurantID); err != nil {
+		return tables, err
+	}
 	return tables, nil
 }
 
 func (r *TableRepository) Get(id uint64) (*model.Table, error) {
 	getTableQuery := fmt.Sprintf(
-		"SELECT * FROM %s WHERE id = $1",
-		tableTable,
+		"SELECT %s FROM %s WHERE id = $1",
+		tableColumns, tableTable,
 	)
 
 	table := &model.Table{}
 	if err := r.store.db.QueryRow(
 		getTableQuery, id,
-	).Scan(&table.ID, &table.RestaurantID, &table.SeatsNumber); err != nil {
+	).Scan(&table.ID, &table.RestaurantID, &table.SeatsNumber, &table.Position, &table.Zone); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, store.ErrTableNotFound
 		}
 		return nil, err
 	}
-	return table, nil
+
+	tables := []model.Table{*table}
+	if err := r.fillJoinableTables(tables, table.RestaurantID); err != nil {
+		return nil, err
+	}
+	return &tables[0], nil
 }
 
 func (r *TableRepository) Update(id uint64, data model.UpdateTableData) error {
-	setValues := make([]string, 0, 1)
-	args := make([]interface{}, 0, 1)
+	setValues := make([]string, 0, 3)
+	args := make([]interface{}, 0, 3)
 	argId := 1
 
 	if data.SeatsNumber != nil {
@@ -126,6 +269,18 @@ func (r *TableRepository) Update(id uint64, data model.UpdateTableData) error {
 		argId++
 	}
 
+	if data.Position != nil {
+		setValues = append(setValues, fmt.Sprintf("position=$%d", argId))
+		args = append(args, *data.Position)
+		argId++
+	}
+
+	if data.Zone != nil {
+		setValues = append(setValues, fmt.Sprintf("zone=$%d", argId))
+		args = append(args, *data.Zone)
+		argId++
+	}
+
 	setQuery := strings.Join(setValues, ", ")
 
 	updateTableQuery := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d",
@@ -170,3 +325,61 @@ func (r *TableRepository) Delete(id uint64) error {
 	_, err = r.store.db.Exec(deleteTableQuery, id)
 	return err
 }
+
+func (r *TableRepository) Join(id, joinableID uint64) error {
+	// пара столиков хранится один раз: ID первого столика всегда меньше ID второго
+	joinTablesQuery := fmt.Sprintf(
+		"INSERT INTO %s (table_id, joinable_table_id) VALUES (LEAST($1::int, $2::int), GREATEST($1::int, $2::int)) "+
+			"ON CONFLICT DO NOTHING",
+		tableJoinTable,
+	)
+	_, err := r.store.db.Exec(joinTablesQuery, id, joinableID)
+	return err
+}
+
+func (r *TableRepository) Unjoin(id, joinableID uint64) error {
+	unjoinTablesQuery := fmt.Sprintf(
+		"DELETE FROM %s WHERE table_id = LEAST($1::int, $2::int) AND joinable_table_id = GREATEST($1::int, $2::int)",
+		tableJoinTable,
+	)
+	_, err := r.store.db.Exec(unjoinTablesQuery, id, joinableID)
+	return err
+}
+
+// fillJoinableTables дополняет столики ресторана ID столиков, которые можно с ними сдвинуть.
+func (r *TableRepository) fillJoinableTables(tables []model.Table, restaurantID uint64) error {
+	getJoinsQuery := fmt.Sprintf(
+		"SELECT j.table_id, j.joinable_table_id "+
+			"FROM %s j "+
+			"JOIN %s t ON t.id = j.table_id "+
+			"WHERE t.restaurant_id = $1",
+		tableJoinTable, tableTable,
+	)
+
+	rows, err := r.store.db.Query(getJoinsQuery, restaurantID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	joinable := make(map[uint64][]uint64)
+	for rows.Next() {
+		var tableID, joinableID uint64
+		if err = rows.Scan(&tableID, &joinableID); err != nil {
+			return err
+		}
+		joinable[tableID] = append(joinable[tableID], joinableID)
+		joinable[joinableID] = append(joinable[joinableID], tableID)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range tables {
+		tables[i].JoinableWith = append([]uint64{}, joinable[tables[i].ID]...)
+		sort.Slice(tables[i].JoinableWith, func(a, b int) bool {
+			return tables[i].JoinableWith[a] < tables[i].JoinableWith[b]
+		})
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+func TestTableRepository_GetAllAvailable(t *testing.T) {
+	s := newTestStore(t)
+
+	restaurantID, err := s.Restaurants().Create("Каравелла", 30, 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookedTableID, err := s.Tables().Create(restaurantID, 4, "", model.ZoneHall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heldTableID, err := s.Tables().Create(restaurantID, 4, "", model.ZoneHall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	terraceTableID, err := s.Tables().Create(restaurantID, 2, "", model.ZoneTerrace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// свободные столики другого ресторана не должны попадать в список
+	otherRestaurantID, err := s.Restaurants().Create("Маяк", 15, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Tables().Create(otherRestaurantID, 4, "", model.ZoneHall); err != nil {
+		t.Fatal(err)
+	}
+
+	// первый столик занят бронью с 19:00 до 21:00, второй удерживается с 19:00 до 21:00
+	week := time.Now().AddDate(0, 0, 7)
+	at := func(hour, minute int) time.Time {
+		return time.Date(week.Year(), week.Month(), week.Day(), hour, minute, 0, 0, time.Local)
+	}
+	if _, err = s.Bookings().Create(
+		restaurantID, 0, "Павел", "+79485722648", "", 4, model.BookingStatusConfirmed,
+		at(19, 0), at(19, 0), 2*time.Hour, bookedTableID,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Holds().Create(
+		restaurantID, 4, at(19, 0), at(19, 0), 2*time.Hour, time.Now().Add(time.Hour), "token-hash", "client",
+		heldTableID,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		from time.Time
+		zone string
+		want []uint64
+	}{
+		{name: "before the booking", from: at(16, 59), want: []uint64{bookedTableID, heldTableID, terraceTableID}},
+		{name: "ends when the booking starts", from: at(17, 0), want: []uint64{terraceTableID}},
+		{name: "during the booking", from: at(20, 0), want: []uint64{terraceTableID}},
+		{name: "after the booking", from: at(21, 1), want: []uint64{bookedTableID, heldTableID, terraceTableID}},
+		{name: "zone", from: at(16, 0), zone: model.ZoneHall, want: []uint64{bookedTableID, heldTableID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := s.Tables().GetAllAvailable(restaurantID, tt.from, 2*time.Hour, tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []uint64
+			for _, table := range tables {
+				got = append(got, table.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("available tables = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("available tables = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
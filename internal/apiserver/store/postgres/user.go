@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	// userTable представляет название таблицы в БД, содержащей пользователей API администрирования.
+	userTable = "users"
+	// usersRestaurantsTable представляет название таблицы в БД, содержащей рестораны, закреплённые за пользователями.
+	usersRestaurantsTable = "users_restaurants"
+)
+
+var _ store.UserRepository = (*UserRepository)(nil)
+
+// UserRepository представляет реализацю store.UserRepository.
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) Create(name, role, apiKeyHash string, restaurantIDs ...uint64) (uint64, error) {
+	// хелпер-функция для выхода с ошибкой
+	fail := func(err error) (uint64, error) {
+		return 0, fmt.Errorf("create user: %w", err)
+	}
+
+	// инициируем транзакцию
+	ctx := context.Background()
+	tx, err := r.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer tx.Rollback()
+
+	createUserQuery := fmt.Sprintf(
+		"INSERT INTO %s (name, role, api_key_hash) VALUES ($1, $2, $3) RETURNING id",
+		userTable,
+	)
+	var userID uint64
+	if err = tx.QueryRowContext(ctx, createUserQuery, name, role, apiKeyHash).Scan(&userID); err != nil {
+		return fail(err)
+	}
+
+	// закрепляем за пользователем рестораны
+	createUsersRestaurantsQuery := fmt.Sprintf(
+		"INSERT INTO %s (user_id, restaurant_id) SELECT $1, unnest($2::integer[]) ON CONFLICT DO NOTHING",
+		usersRestaurantsTable,
+	)
+	if _, err = tx.ExecContext(ctx, createUsersRestaurantsQuery, userID, idsArg(restaurantIDs)); err != nil {
+		if isForeignKeyViolation(err) {
+			return fail(store.ErrRestaurantNotFound)
+		}
+		return fail(err)
+	}
+
+	// завершаем транзакцию
+	if err = tx.Commit(); err != nil {
+		return fail(err)
+	}
+
+	return userID, nil
+}
+
+func (r *UserRepository) GetAll() ([]model.User, error) {
+	getAllUsersQuery := fmt.Sprintf(
+		"SELECT u.id, u.name, u.role, u.created_at, "+
+			"COALESCE(array_agg(ur.restaurant_id ORDER BY ur.restaurant_id) FILTER (WHERE ur.restaurant_id IS NOT NULL), '{}') "+
+			"FROM %s u LEFT JOIN %s ur ON ur.user_id = u.id GROUP BY u.id ORDER BY u.id",
+		userTable, usersRestaurantsTable,
+	)
+
+	rows, err := r.store.db.Query(getAllUsersQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]model.User, 0)
+	for rows.Next() {
+		var user model.User
+		if err = scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Get(id uint64) (*model.User, error) {
+	return r.getBy("u.id = $1", id)
+}
+
+func (r *UserRepository) GetByAPIKeyHash(apiKeyHash string) (*model.User, error) {
+	return r.getBy("u.api_key_hash = $1", apiKeyHash)
+}
+
+// getBy возвращает пользователя, подходящего под условие condition с единственным параметром arg.
+func (r *UserRepository) getBy(condition string, arg interface{}) (*model.User, error) {
+	getUserQuery := fmt.Sprintf(
+		"SELECT u.id, u.name, u.role, u.created_at, "+
+			"COALESCE(array_agg(ur.restaurant_id ORDER BY ur.restaurant_id) FILTER (WHERE ur.restaurant_id IS NOT NULL), '{}') "+
+			"FROM %s u LEFT JOIN %s ur ON ur.user_id = u.id WHERE %s GROUP BY u.id",
+		userTable, usersRestaurantsTable, condition,
+	)
+
+	user := &model.User{}
+	if err := scanUser(r.store.db.QueryRow(getUserQuery, arg), user); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// scanUser считывает пользователя вместе с массивом ID закреплённых за ним ресторанов.
+func scanUser(row rowScanner, user *model.User) error {
+	var restaurantIDs []int64
+	if err := row.Scan(&user.ID, &user.Name, &user.Role, &user.CreatedAt, pq.Array(&restaurantIDs)); err != nil {
+		return err
+	}
+
+	user.RestaurantIDs = make([]uint64, 0, len(restaurantIDs))
+	for _, id := range restaurantIDs {
+		user.RestaurantIDs = append(user.RestaurantIDs, uint64(id))
+	}
+	return nil
+}
+
+func (r *UserRepository) SetAPIKeyHash(id uint64, apiKeyHash string) error {
+	setAPIKeyHashQuery := fmt.Sprintf("UPDATE %s SET api_key_hash = $2 WHERE id = $1", userTable)
+
+	res, err := r.store.db.Exec(setAPIKeyHashQuery, id, apiKeyHash)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("set user api key: %w", store.ErrUserNotFound)
+	}
+	return nil
+}
+
+func (r *UserRepository) Delete(id uint64) error {
+	deleteUserQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", userTable)
+
+	res, err := r.store.db.Exec(deleteUserQuery, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("delete user: %w", store.ErrUserNotFound)
+	}
+	return nil
+}
@@ -2,8 +2,19 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+)
+
+const (
+	// exclusionViolation представляет код ошибки PostgreSQL, возникающей при нарушении ограничения-исключения.
+	exclusionViolation = "23P01"
+	// foreignKeyViolation представляет код ошибки PostgreSQL, возникающей при нарушении ограничения внешнего ключа.
+	foreignKeyViolation = "23503"
+	// uniqueViolation представляет код ошибки PostgreSQL, возникающей при нарушении ограничения уникальности.
+	uniqueViolation = "23505"
 )
 
 // NewDB устанавливает соединение с базой данных по переданной строке подключения.
@@ -20,3 +31,48 @@ func NewDB(dsn string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// durationMinutes переводит длительность в целое количество минут для передачи в SQL-запросы (make_interval).
+func durationMinutes(duration time.Duration) int {
+	return int(duration / time.Minute)
+}
+
+// dateTimeArgs раскладывает момент времени на дату и время суток в текстовом виде, который PostgreSQL однозначно
+// приводит к типам date и time. Значения передаются в запросы только как параметры, без подстановки в текст запроса.
+func dateTimeArgs(t time.Time) (string, string) {
+	return t.Format("2006-01-02"), t.Format("15:04:05")
+}
+
+// timestampArg переводит момент времени в текстовый вид, который PostgreSQL приводит к типу timestamp без учёта
+// часового пояса.
+func timestampArg(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// bookedPeriod возвращает начало и конец промежутка времени, на который столики заняты в рамках брони
+// (значения для колонки booked_during).
+func bookedPeriod(bookedDate, bookedTimeFrom time.Time, duration time.Duration) (time.Time, time.Time) {
+	bookedFrom := time.Date(
+		bookedDate.Year(), bookedDate.Month(), bookedDate.Day(),
+		bookedTimeFrom.Hour(), bookedTimeFrom.Minute(), bookedTimeFrom.Second(), 0, time.UTC,
+	)
+	return bookedFrom, bookedFrom.Add(duration)
+}
+
+// isExclusionViolation проверяет, вызвана ли ошибка нарушением ограничения-исключения.
+func isExclusionViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == exclusionViolation
+}
+
+// isForeignKeyViolation проверяет, вызвана ли ошибка нарушением ограничения внешнего ключа.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation
+}
+
+// isUniqueViolation проверяет, вызвана ли ошибка нарушением ограничения уникальности.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
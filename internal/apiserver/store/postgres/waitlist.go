@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// waitlistTable представляет название таблицы в БД, содержащей записи листов ожидания ресторанов.
+const waitlistTable = "waitlist"
+
+// waitlistColumns представляет список колонок таблицы с листами ожидания в порядке, в котором их сканирует
+// scanWaitlistEntry.
+const waitlistColumns = "id, restaurant_id, client_name, client_phone, people_number, desired_date, desired_time, status, created_at, offer_expires_at, hold_id, booking_id"
+
+var _ store.WaitlistRepository = (*WaitlistRepository)(nil)
+
+// WaitlistRepository представляет реализацю store.WaitlistRepository.
+type WaitlistRepository struct {
+	store *Store
+}
+
+func NewWaitlistRepository(store *Store) *WaitlistRepository {
+	return &WaitlistRepository{store: store}
+}
+
+func (r *WaitlistRepository) Create(
+	restaurantID uint64, clientName, clientPhone string, peopleNumber int, desiredDate, desiredTime time.Time,
+) (uint64, error) {
+	createWaitlistEntryQuery := fmt.Sprintf(
+		"INSERT INTO %s (restaurant_id, client_name, client_phone, people_number, desired_date, desired_time) "+
+			"VALUES ($1, $2, $3, $4, $5::date, $6::time) RETURNING id",
+		waitlistTable,
+	)
+
+	date, _ := dateTimeArgs(desiredDate)
+	_, clock := dateTimeArgs(desiredTime)
+
+	var id uint64
+	if err := r.store.db.QueryRow(
+		createWaitlistEntryQuery, restaurantID, clientName, clientPhone, peopleNumber, date, clock,
+	).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// scanWaitlistEntry считывает запись в листе ожидания, выбранную с колонками waitlistColumns.
+func scanWaitlistEntry(row rowScanner, entry *model.WaitlistEntry) error {
+	var offerExpiresAt sql.NullTime
+	var holdID, bookingID sql.NullInt64
+	if err := row.Scan(
+		&entry.ID, &entry.RestaurantID, &entry.ClientName, &entry.ClientPhone, &entry.PeopleNumber,
+		&entry.DesiredDate, &entry.DesiredTime, &entry.Status, &entry.CreatedAt, &offerExpiresAt, &holdID, &bookingID,
+	); err != nil {
+		return err
+	}
+	if offerExpiresAt.Valid {
+		entry.OfferExpiresAt = &offerExpiresAt.Time
+	}
+	if holdID.Valid {
+		id := uint64(holdID.Int64)
+		entry.HoldID = &id
+	}
+	if bookingID.Valid {
+		id := uint64(bookingID.Int64)
+		entry.BookingID = &id
+	}
+	return nil
+}
+
+func (r *WaitlistRepository) GetAll(restaurantID uint64) ([]model.WaitlistEntry, error) {
+	getAllWaitlistEntriesQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE restaurant_id = $1 ORDER BY id",
+		waitlistColumns, waitlistTable,
+	)
+
+	rows, err := r.store.db.Query(getAllWaitlistEntriesQuery, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.WaitlistEntry
+
+	for rows.Next() {
+		var entry model.WaitlistEntry
+		if err = scanWaitlistEntry(rows, &entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return entries, err
+	}
+	return entries, nil
+}
+
+func (r *WaitlistRepository) Get(id uint64) (*model.WaitlistEntry, error) {
+	getWaitlistEntryQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = $1",
+		waitlistColumns, waitlistTable,
+	)
+
+	entry := &model.WaitlistEntry{}
+	if err := scanWaitlistEntry(r.store.db.QueryRow(getWaitlistEntryQuery, id), entry); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrWaitlistEntryNotFound
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *WaitlistRepository) UpdateStatus(
+	id uint64, from, to string, offerExpiresAt *time.Time, holdID, bookingID *uint64,
+) (bool, error) {
+	// состояние меняется, только если запись всё ещё находится в состоянии from: так два одновременных запроса
+	// не смогут, например, дважды принять одно предложение
+	updateWaitlistStatusQuery := fmt.Sprintf(
+		"UPDATE %s SET status = $1, offer_expires_at = $2, hold_id = $3, booking_id = $4 WHERE id = $5 AND status = $6",
+		waitlistTable,
+	)
+
+	res, err := r.store.db.Exec(updateWaitlistStatusQuery, to, offerExpiresAt, holdID, bookingID, id, from)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+// webhookTable представляет название таблицы в БД, содержащей вебхуки ресторанов.
+const webhookTable = "webhooks"
+
+var _ store.WebhookRepository = (*WebhookRepository)(nil)
+
+// WebhookRepository представляет реализацю store.WebhookRepository.
+type WebhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{store: store}
+}
+
+func (r *WebhookRepository) Create(restaurantID uint64, url, secret string, events []string) (uint64, error) {
+	createWebhookQuery := fmt.Sprintf(
+		"INSERT INTO %s (restaurant_id, url, secret, events) VALUES ($1, $2, $3, $4) RETURNING id",
+		webhookTable,
+	)
+	if events == nil {
+		events = []string{}
+	}
+
+	var webhookID uint64
+	if err := r.store.db.QueryRow(
+		createWebhookQuery, restaurantID, url, secret, pq.StringArray(events),
+	).Scan(&webhookID); err != nil {
+		if isForeignKeyViolation(err) {
+			return 0, fmt.Errorf("create webhook: %w", store.ErrRestaurantNotFound)
+		}
+		return 0, fmt.Errorf("create webhook: %w", err)
+	}
+	return webhookID, nil
+}
+
+// webhookColumns представляет список колонок таблицы с вебхуками в порядке, в котором их сканирует scanWebhook.
+const webhookColumns = "id, restaurant_id, url, secret, events, created_at"
+
+// scanWebhook считывает вебхук, выбранный с колонками webhookColumns.
+func scanWebhook(row rowScanner, webhook *model.Webhook) error {
+	var events pq.StringArray
+	if err := row.Scan(
+		&webhook.ID, &webhook.RestaurantID, &webhook.URL, &webhook.Secret, &events, &webhook.CreatedAt,
+	); err != nil {
+		return err
+	}
+	webhook.Events = events
+	return nil
+}
+
+func (r *WebhookRepository) GetAll(restaurantID uint64) ([]model.Webhook, error) {
+	getAllWebhooksQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE restaurant_id = $1 ORDER BY id",
+		webhookColumns, webhookTable,
+	)
+
+	rows, err := r.store.db.Query(getAllWebhooksQuery, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []model.Webhook
+
+	for rows.Next() {
+		var webhook model.Webhook
+		if err = scanWebhook(rows, &webhook); err != nil {
+			return webhooks, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err = rows.Err(); err != nil {
+		return webhooks, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Get(id uint64) (*model.Webhook, error) {
+	getWebhookQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id = $1",
+		webhookColumns, webhookTable,
+	)
+
+	webhook := &model.Webhook{}
+	if err := scanWebhook(r.store.db.QueryRow(getWebhookQuery, id), webhook); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepository) Delete(id uint64) error {
+	// журнал доставки событий вебхуку удаляется каскадно
+	deleteWebhookQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1", webhookTable)
+	if _, err := r.store.db.Exec(deleteWebhookQuery, id); err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveries(webhookID uint64, limit int) ([]model.WebhookDelivery, error) {
+	getDeliveriesQuery := fmt.Sprintf(
+		"SELECT %s FROM %s d JOIN %s o ON o.id = d.event_id WHERE d.webhook_id = $1 ORDER BY d.id DESC LIMIT $2",
+		webhookDeliveryColumns, webhookDeliveryTable, outboxTable,
+	)
+
+	rows, err := r.store.db.Query(getDeliveriesQuery, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.WebhookDelivery
+
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		if err = scanWebhookDelivery(rows, &delivery); err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return deliveries, err
+	}
+	return deliveries, nil
+}
@@ -13,8 +13,10 @@ type RestaurantRepository interface {
 	// GetAll возвращает список всех ресторанов.
 	GetAll() ([]model.Restaurant, error)
 	// GetAllAvailable возвращает список ресторанов, в которых можно забронировать столики на выбранные дату,
-	// время и количество человек. Принимает desiredDate в формате "2006.01.02" и desiredTime - "15:04".
-	GetAllAvailable(desiredDate, desiredTime string, peopleNumber int) ([]model.Restaurant, error)
+	// время и количество человек. Длительность брони в каждом ресторане определяется его правилами (model.DurationPolicy).
+	// Если указана зона (zone), учитываются только столики в этой зоне. Свободные места подсчитываются как всего,
+	// так и по зонам ресторана.
+	GetAllAvailable(desiredDateTime time.Time, peopleNumber int, zone string) ([]model.Restaurant, error)
 	// Get возвращает ресторан по его ID.
 	Get(id uint64) (*model.Restaurant, error)
 	// Update обновляет информацию о ресторане по его ID.
@@ -26,10 +28,17 @@ type RestaurantRepository interface {
 // TableRepository представляет методы работы с информацией о столиках в ресторанах.
 type TableRepository interface {
 	// Create создаёт новую запись о столике в ресторане.
-	Create(restaurantID uint64, seatsNumber int) (uint64, error)
-	// GetAllAvailable возвращает список всех столиков, доступных для бронирования, в конкретном ресторане.
-	// Принимает desiredDate в формате "2006.01.02" и desiredTime - "15:04".
-	GetAllAvailable(restaurantID uint64, desiredDate, desiredTime string) ([]model.Table, error)
+	Create(restaurantID uint64, seatsNumber int, position, zone string) (uint64, error)
+	// GetAllAvailable возвращает список всех столиков, доступных для бронирования на duration, в конкретном ресторане.
+	// Если указана зона (zone), возвращаются только столики в этой зоне.
+	GetAllAvailable(restaurantID uint64, desiredDateTime time.Time, duration time.Duration, zone string) ([]model.Table, error)
+	// GetAllAvailableForBooking возвращает список столиков ресторана брони bookingID, доступных для её переноса
+	// на новые дату, время и длительность duration: столики, занятые самой этой бронью, считаются свободными.
+	GetAllAvailableForBooking(bookingID uint64, desiredDateTime time.Time, duration time.Duration) ([]model.Table, error)
+	// GetAllAvailableBySlots возвращает для каждого из моментов начала брони slots список столиков ресторана,
+	// доступных для бронирования на duration (в том же порядке, что и slots). Все моменты проверяются одним запросом.
+	// Если указана зона (zone), возвращаются только столики в этой зоне.
+	GetAllAvailableBySlots(restaurantID uint64, slots []time.Time, duration time.Duration, zone string) ([][]model.Table, error)
 	// GetAll возвращает список всех столиков ресторана.
 	GetAll(restaurantID uint64) ([]model.Table, error)
 	// Get возвращает столик ресторана по его ID.
@@ -38,12 +47,225 @@ type TableRepository interface {
 	Update(id uint64, data model.UpdateTableData) error
 	// Delete удаляет столик из ресторана по его ID, ЕСЛИ ОН НЕ ЗАБРОНИРОВАН НА БУДУЩЕЕ ВРЕМЯ.
 	Delete(id uint64) error
+	// Join отмечает, что столики с ID id и joinableID можно сдвинуть. Повторная отметка не считается ошибкой.
+	Join(id, joinableID uint64) error
+	// Unjoin отмечает, что столики с ID id и joinableID сдвигать нельзя.
+	Unjoin(id, joinableID uint64) error
 }
 
-// BookingRepository представляет методы работы с информацией о совершённых клиентами бронях.
+// BookingRepository представляет методы работы с информацией о совершённых клиентами бронях. Вместе с каждым
+// изменением брони в той же транзакции в outbox добавляется событие для вебхуков ресторана (см. OutboxRepository).
 type BookingRepository interface {
-	// Create создаёт новую запись о брони и связывает созданную бронь со столиками, которые бронируются в рамках неё.
-	Create(clientName, clientPhone string, bookedDate, bookedTimeFrom time.Time, tableIDs ...uint64) (uint64, error)
-	// GetAll возвращает список всех броней ресторана.
+	// Create создаёт новую запись о брони длительностью duration и связывает созданную бронь со столиками,
+	// которые бронируются в рамках неё. Если хотя бы один из столиков уже занят на пересекающееся время (в том числе
+	// действующим удержанием), бронь не создаётся и возвращается ErrTableAlreadyBooked. customerID представляет ID
+	// учётной записи гостя (0 - бронь оформляется без учётной записи). Бронь привязывается к гостю с тем же
+	// телефоном в формате E.164 (model.NormalizePhone), а если такого гостя ещё нет, он создаётся. clientEmail
+	// представляет адрес электронной почты для уведомлений (пустая строка - не указан). status представляет
+	// начальный статус брони (model.BookingStatusPending или model.BookingStatusConfirmed).
+	Create(
+		restaurantID, customerID uint64, clientName, clientPhone, clientEmail string, peopleNumber int, status string,
+		bookedDate, bookedTimeFrom time.Time, duration time.Duration, tableIDs ...uint64,
+	) (uint64, error)
+	// GetAll возвращает список всех броней ресторана (в том числе отменённых).
 	GetAll(restaurantID uint64) ([]model.Booking, error)
+	// GetAllByCustomer возвращает список всех броней гостя с учётной записью customerID (в том числе отменённых)
+	// во всех ресторанах.
+	GetAllByCustomer(customerID uint64) ([]model.Booking, error)
+	// GetAllByGuest возвращает список всех броней гостя guestID (в том числе отменённых) во всех ресторанах.
+	GetAllByGuest(guestID uint64) ([]model.Booking, error)
+	// Get возвращает бронь по её ID вместе с ID забронированных столиков.
+	Get(id uint64) (*model.Booking, error)
+	// Update изменяет количество человек, дату, время и длительность брони и заменяет забронированные в рамках неё
+	// столики на tableIDs. Изменение происходит атомарно: если хотя бы один из новых столиков уже занят другой бронью
+	// или действующим удержанием на пересекающееся время, бронь остаётся прежней и возвращается ErrTableAlreadyBooked.
+	// Изменить можно только бронь, которая ждёт гостей (model.Booking.IsActive), иначе возвращается ErrBookingNotFound.
+	Update(
+		id uint64, peopleNumber int, bookedDate, bookedTimeFrom time.Time, duration time.Duration, tableIDs ...uint64,
+	) error
+	// Cancel отменяет бронь по её ID: освобождает забронированные в рамках неё столики и запоминает,
+	// кто и когда отменил бронь. Отменить можно только бронь, которая ждёт гостей (model.Booking.IsActive),
+	// иначе возвращается ErrBookingNotFound.
+	Cancel(id uint64, cancelledBy string) error
+	// UpdateStatus переводит бронь из статуса from в статус to и возвращает false, если бронь уже не в статусе from
+	// (например, её статус одновременно с нами изменил другой пользователь). При переводе в model.BookingStatusSeated
+	// запоминается момент прихода гостей, а при переводе в model.BookingStatusCompleted или model.BookingStatusNoShow -
+	// момент завершения брони: с него столики брони свободны, даже если время брони ещё не истекло.
+	UpdateStatus(id uint64, from, to string) (bool, error)
+}
+
+// OpeningHoursRepository представляет методы работы с графиками работы ресторанов.
+type OpeningHoursRepository interface {
+	// GetAll возвращает графики работы всех ресторанов, у которых они заданы, сгруппированные по ID ресторанов.
+	GetAll() (map[uint64][]model.OpeningHours, error)
+	// Get возвращает график работы ресторана (пустой список, если он не задан).
+	Get(restaurantID uint64) ([]model.OpeningHours, error)
+	// Set заменяет график работы ресторана новым.
+	Set(restaurantID uint64, hours []model.OpeningHours) error
+}
+
+// DurationPolicyRepository представляет методы работы с правилами, по которым определяется длительность брони.
+type DurationPolicyRepository interface {
+	// GetAll возвращает правила всех ресторанов, сгруппированные по ID ресторанов.
+	GetAll() (map[uint64]model.DurationPolicy, error)
+	// Get возвращает правила ресторана.
+	Get(restaurantID uint64) (model.DurationPolicy, error)
+	// Set заменяет правила ресторана новыми.
+	Set(restaurantID uint64, policy model.DurationPolicy) error
+}
+
+// ReliabilityPolicyRepository представляет методы работы с правилами надёжности гостей ресторанов.
+type ReliabilityPolicyRepository interface {
+	// Get возвращает правила ресторана. Если ресторан не задал правила, возвращаются нулевые правила без ограничений.
+	Get(restaurantID uint64) (model.ReliabilityPolicy, error)
+	// Set заменяет правила ресторана новыми.
+	Set(restaurantID uint64, policy model.ReliabilityPolicy) error
+}
+
+// WaitlistRepository представляет методы работы с листами ожидания ресторанов.
+type WaitlistRepository interface {
+	// Create создаёт новую запись в листе ожидания ресторана в состоянии model.WaitlistStatusWaiting.
+	Create(
+		restaurantID uint64, clientName, clientPhone string, peopleNumber int, desiredDate, desiredTime time.Time,
+	) (uint64, error)
+	// GetAll возвращает все записи листа ожидания ресторана в порядке очереди.
+	GetAll(restaurantID uint64) ([]model.WaitlistEntry, error)
+	// Get возвращает запись в листе ожидания по её ID.
+	Get(id uint64) (*model.WaitlistEntry, error)
+	// UpdateStatus переводит запись из состояния from в состояние to, запоминая срок предложения, ID удержания
+	// предложенных столиков и ID брони. Возвращает false, если запись уже не находится в состоянии from (например,
+	// её одновременно изменил другой запрос).
+	UpdateStatus(id uint64, from, to string, offerExpiresAt *time.Time, holdID, bookingID *uint64) (bool, error)
+}
+
+// HoldRepository представляет методы работы с временными удержаниями столиков.
+type HoldRepository interface {
+	// Create удерживает столики ресторана на duration в выбранные дату и время до момента expiresAt. tokenHash
+	// представляет SHA-256 хеш секретного токена удержания, а clientKey - ключ клиента, удерживающего столики.
+	// Если хотя бы один из столиков уже занят бронью или другим действующим удержанием на пересекающееся время,
+	// удержание не создаётся и возвращается ErrTableAlreadyBooked.
+	Create(
+		restaurantID uint64, peopleNumber int, heldDate, heldTimeFrom time.Time, duration time.Duration,
+		expiresAt time.Time, tokenHash, clientKey string, tableIDs ...uint64,
+	) (uint64, error)
+	// Get возвращает удержание по его ID вместе с ID удерживаемых столиков (в том числе истёкшее, но ещё не снятое).
+	Get(id uint64) (*model.Hold, error)
+	// CountActive возвращает количество действующих удержаний клиента с ключом clientKey.
+	CountActive(clientKey string) (int, error)
+	// Confirm атомарно оформляет бронь гостя на удерживаемые столики и снимает удержание. Возвращает ID брони.
+	// Если удержания нет или его срок истёк, возвращается ErrHoldNotFound. customerID представляет ID учётной записи
+	// гостя (0 - бронь оформляется без учётной записи). Бронь привязывается к гостю так же, как в BookingRepository.Create,
+	// и оформляется в статусе status.
+	Confirm(id, customerID uint64, clientName, clientPhone, clientEmail, status string) (uint64, error)
+	// Delete снимает удержание по его ID.
+	Delete(id uint64) error
+	// DeleteExpired снимает все удержания, срок которых истёк, и возвращает ID ресторанов, в которых освободились
+	// столики (без повторов).
+	DeleteExpired() ([]uint64, error)
+}
+
+// UserRepository представляет методы работы с пользователями API администрирования.
+type UserRepository interface {
+	// Create создаёт пользователя с ролью role, закреплёнными за ним ресторанами restaurantIDs и SHA-256 хешем
+	// (apiKeyHash) его ключа API. Сам ключ не хранится. Возвращает ID созданного пользователя.
+	Create(name, role, apiKeyHash string, restaurantIDs ...uint64) (uint64, error)
+	// GetAll возвращает список всех пользователей.
+	GetAll() ([]model.User, error)
+	// Get возвращает пользователя по его ID.
+	Get(id uint64) (*model.User, error)
+	// GetByAPIKeyHash возвращает пользователя по SHA-256 хешу его ключа API.
+	GetByAPIKeyHash(apiKeyHash string) (*model.User, error)
+	// SetAPIKeyHash заменяет хеш ключа API пользователя (прежний ключ перестаёт действовать).
+	SetAPIKeyHash(id uint64, apiKeyHash string) error
+	// Delete удаляет пользователя по его ID.
+	Delete(id uint64) error
+}
+
+// CustomerRepository представляет методы работы с учётными записями гостей на сайте и их сеансами.
+type CustomerRepository interface {
+	// Create создаёт учётную запись гостя с хешем его пароля passwordHash. Если учётная запись с таким телефоном
+	// уже есть, возвращается ErrCustomerAlreadyExists.
+	Create(name, phone, passwordHash string) (uint64, error)
+	// Get возвращает гостя по ID его учётной записи.
+	Get(id uint64) (*model.Customer, error)
+	// GetByPhone возвращает гостя по телефону вместе с хешем его пароля.
+	GetByPhone(phone string) (*model.Customer, string, error)
+	// GetBySession возвращает гостя по SHA-256 хешу (tokenHash) токена его действующего сеанса.
+	GetBySession(tokenHash string) (*model.Customer, error)
+	// CreateSession начинает сеанс гостя customerID, действующий до expiresAt. Вместо токена сеанса хранится его
+	// SHA-256 хеш tokenHash. Истёкшие сеансы гостя при этом удаляются.
+	CreateSession(customerID uint64, tokenHash string, expiresAt time.Time) error
+	// DeleteSession завершает сеанс по SHA-256 хешу его токена. Завершение несуществующего сеанса не считается ошибкой.
+	DeleteSession(tokenHash string) error
+}
+
+// GuestRepository представляет методы работы с гостями ресторанов, которые различаются по телефону в формате E.164.
+type GuestRepository interface {
+	// Get возвращает гостя по его ID.
+	Get(id uint64) (*model.Guest, error)
+	// GetByPhone возвращает гостя по телефону в формате E.164 (model.NormalizePhone).
+	GetByPhone(phone string) (*model.Guest, error)
+}
+
+// JobRepository представляет методы работы с фоновыми задачами, связанными с бронями.
+type JobRepository interface {
+	// Schedule планирует задачу вида kind по брони bookingID на момент runAt. Ещё не выполненная задача того же вида
+	// по этой брони при этом отменяется, поэтому у брони остаётся одна задача каждого вида. Возвращает ID задачи.
+	// Если брони нет, возвращается ErrBookingNotFound.
+	Schedule(kind string, bookingID uint64, runAt time.Time) (uint64, error)
+	// Unschedule отменяет ещё не выполненную задачу вида kind по брони bookingID. Если такой задачи нет,
+	// это не считается ошибкой.
+	Unschedule(kind string, bookingID uint64) error
+	// Claim выбирает до limit задач, время которых наступило, и закрепляет их за вызывающим до момента lockedUntil,
+	// увеличивая количество попыток. Закреплённые задачи не выбираются повторно, пока закрепление не истечёт, поэтому
+	// одну задачу не выполнят одновременно несколько экземпляров сервиса.
+	Claim(limit int, lockedUntil time.Time) ([]model.Job, error)
+	// Complete отмечает задачу выполненной.
+	Complete(id uint64) error
+	// Retry снимает закрепление задачи после неудачной попытки и переносит её на момент runAt.
+	Retry(id uint64, runAt time.Time, lastError string) error
+	// Fail отмечает задачу невыполненной после последней неудачной попытки.
+	Fail(id uint64, lastError string) error
+	// Release снимает закрепление задачи, к выполнению которой так и не приступили (например, при остановке
+	// сервиса), не засчитывая попытку.
+	Release(id uint64) error
+}
+
+// WebhookRepository представляет методы работы с вебхуками ресторанов и журналом доставки им событий.
+type WebhookRepository interface {
+	// Create создаёт вебхук ресторана, подписанный на события events (пустой список - все события), и возвращает
+	// его ID. secret представляет ключ, которым подписываются события вебхука. Если ресторана нет, возвращается
+	// ErrRestaurantNotFound.
+	Create(restaurantID uint64, url, secret string, events []string) (uint64, error)
+	// GetAll возвращает все вебхуки ресторана.
+	GetAll(restaurantID uint64) ([]model.Webhook, error)
+	// Get возвращает вебхук по его ID (вместе с ключом, которым подписываются его события).
+	Get(id uint64) (*model.Webhook, error)
+	// Delete удаляет вебхук вместе с журналом доставки ему событий.
+	Delete(id uint64) error
+	// GetDeliveries возвращает до limit последних доставок событий вебхуку, начиная с самых новых.
+	GetDeliveries(webhookID uint64, limit int) ([]model.WebhookDelivery, error)
+}
+
+// OutboxRepository представляет методы работы с исходящими событиями с бронями (transactional outbox) и доставкой
+// их вебхукам. События добавляются в outbox методами BookingRepository и HoldRepository в той же
+// транзакции, что и изменение брони.
+type OutboxRepository interface {
+	// Relay разбирает до limit ещё не разобранных событий в порядке их появления: для каждого события планирует
+	// доставку всем вебхукам ресторана, подписанным на него, и отмечает событие разобранным. Событие разбирается
+	// атомарно, поэтому доставка не теряется и не дублируется, а одно событие не разберут одновременно несколько
+	// экземпляров сервиса. Возвращает количество разобранных событий.
+	Relay(limit int) (int, error)
+	// ClaimDeliveries выбирает до limit доставок, время которых наступило, и закрепляет их за вызывающим до момента
+	// lockedUntil, увеличивая количество попыток (как JobRepository.Claim).
+	ClaimDeliveries(limit int, lockedUntil time.Time) ([]model.WebhookDelivery, error)
+	// CompleteDelivery отмечает, что получатель принял событие, ответив кодом statusCode.
+	CompleteDelivery(id uint64, statusCode int) error
+	// RetryDelivery снимает закрепление доставки после неудачной попытки и переносит её на момент runAt.
+	// statusCode представляет код ответа получателя (0 - ответа не было).
+	RetryDelivery(id uint64, runAt time.Time, statusCode int, lastError string) error
+	// FailDelivery отмечает доставку невыполненной после последней неудачной попытки.
+	FailDelivery(id uint64, statusCode int, lastError string) error
+	// ReleaseDelivery снимает закрепление доставки, к которой так и не приступили, не засчитывая попытку.
+	ReleaseDelivery(id uint64) error
 }
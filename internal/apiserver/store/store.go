@@ -8,4 +8,27 @@ type Store interface {
 	Tables() TableRepository
 	// Bookings позволяет обратиться к таблице с информацией о совершённых клиентами бронях.
 	Bookings() BookingRepository
+	// OpeningHours позволяет обратиться к таблице с графиками работы ресторанов.
+	OpeningHours() OpeningHoursRepository
+	// DurationPolicies позволяет обратиться к правилам, по которым определяется длительность брони в ресторанах.
+	DurationPolicies() DurationPolicyRepository
+	// ReliabilityPolicies позволяет обратиться к правилам, по которым рестораны ограничивают бронирование гостям,
+	// не приходящим по своим броням.
+	ReliabilityPolicies() ReliabilityPolicyRepository
+	// Waitlist позволяет обратиться к таблице с листами ожидания ресторанов.
+	Waitlist() WaitlistRepository
+	// Holds позволяет обратиться к таблице с временными удержаниями столиков.
+	Holds() HoldRepository
+	// Users позволяет обратиться к таблице с пользователями API администрирования.
+	Users() UserRepository
+	// Customers позволяет обратиться к таблицам с учётными записями гостей на сайте и их сеансами.
+	Customers() CustomerRepository
+	// Guests позволяет обратиться к таблице с гостями ресторанов.
+	Guests() GuestRepository
+	// Jobs позволяет обратиться к таблице с фоновыми задачами, связанными с бронями.
+	Jobs() JobRepository
+	// Webhooks позволяет обратиться к таблице с вебхуками ресторанов и журналу доставки им событий.
+	Webhooks() WebhookRepository
+	// Outbox позволяет обратиться к исходящим событиям с бронями и доставке их вебхукам.
+	Outbox() OutboxRepository
 }
@@ -0,0 +1,286 @@
+// Package webhook представляет доставку событий с бронями на вебхуки ресторанов (например, в кассовую систему
+// или CRM ресторана). События разбираются из outbox, куда они попадают в одной транзакции с изменением брони,
+// отправляются подписанными POST-запросами, а неудачные попытки повторяются с нарастающей задержкой.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/store"
+)
+
+const (
+	// SignatureHeader представляет заголовок с подписью события вида "t=<unix-время>,v1=<подпись>" (см. Sign).
+	SignatureHeader = "X-Webhook-Signature"
+	// EventHeader представляет заголовок с видом события (например, model.WebhookEventBookingCreated).
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader представляет заголовок с ID доставки события.
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+// maxResponseBodySize ограничивает, сколько байт ответа получателя вычитывается, чтобы переиспользовать соединение.
+const maxResponseBodySize = 64 << 10
+
+// ErrNonPublicAddress возвращается при попытке отправить событие на адрес, который не ведёт в интернет
+// (см. model.IsPublicIP).
+var ErrNonPublicAddress = errors.New("webhook destination is not a public address")
+
+// Config представляет настройки доставки событий вебхукам.
+type Config struct {
+	// PollInterval представляет период, с которым проверяется, нет ли новых событий и доставок, время которых
+	// наступило.
+	PollInterval time.Duration
+	// BatchSize представляет, сколько событий разбирается и сколько доставок выполняется за один раз.
+	BatchSize int
+	// Timeout ограничивает время одного запроса к получателю.
+	Timeout time.Duration
+	// MaxAttempts представляет количество попыток доставить событие, после которых доставка считается невыполненной.
+	MaxAttempts int
+	// RetryDelay представляет задержку перед первой повторной попыткой: каждая следующая задержка вдвое больше
+	// предыдущей, но не больше MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Dispatcher периодически разбирает новые события из outbox и доставляет их вебхукам ресторанов.
+type Dispatcher struct {
+	outboxRepo  store.OutboxRepository
+	webhookRepo store.WebhookRepository
+	client      *http.Client
+	cfg         Config
+	logger      *logrus.Logger
+}
+
+func NewDispatcher(
+	outboxRepo store.OutboxRepository, webhookRepo store.WebhookRepository, cfg Config, logger *logrus.Logger,
+) *Dispatcher {
+	return &Dispatcher{
+		outboxRepo:  outboxRepo,
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Transport: newTransport(),
+			Timeout:   cfg.Timeout,
+			// перенаправление считается неудачной попыткой: событие доставляется только по адресу вебхука
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// newTransport возвращает транспорт для запросов к вебхукам, который устанавливает соединения только с публичными
+// адресами. Адрес проверяется при каждом соединении уже после разрешения имени хоста, поэтому вебхук не направить
+// во внутреннюю сеть ни DNS-записью, указывающей на внутренний адрес, ни сменив её после создания вебхука.
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkPublicAddress,
+	}
+	return &http.Transport{
+		// запрос через прокси ушёл бы на адрес прокси, а не получателя, и обошёл бы проверку
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// checkPublicAddress запрещает соединение с адресом address, если он не ведёт в интернет. Вызывается net.Dialer
+// перед соединением с каждым адресом, в который разрешилось имя хоста.
+func checkPublicAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !model.IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+	}
+	return nil
+}
+
+// Sign возвращает подпись тела события payload, отправленного в момент timestamp: HMAC-SHA256 строки
+// "<unix-время>.<тело>" с ключом вебхука secret в шестнадцатеричном виде. Время входит в подпись, чтобы получатель
+// мог отклонить перехваченный и повторно отправленный запрос.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run разбирает и доставляет события, пока не будет отменён ctx. Начатые доставки при отмене дорабатывают
+// (но не дольше Timeout), а выбранные, но ещё не начатые доставки возвращаются в очередь.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.relay(ctx)
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay разбирает новые события из outbox, пока они не закончатся или не будет отменён ctx.
+func (d *Dispatcher) relay(ctx context.Context) {
+	for ctx.Err() == nil {
+		relayed, err := d.outboxRepo.Relay(d.cfg.BatchSize)
+		if err != nil {
+			d.logger.Errorf("failed to relay outbox events: %s", err)
+			return
+		}
+		if relayed < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// deliverDue выполняет доставки, время которых наступило, пока они не закончатся или не будет отменён ctx.
+// Доставки одной выборки выполняются параллельно, поэтому медленный получатель не задерживает остальных.
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// доставка закрепляется с запасом: за это время её точно успеют выполнить или вернуть в очередь
+		deliveries, err := d.outboxRepo.ClaimDeliveries(d.cfg.BatchSize, time.Now().Add(2*d.cfg.Timeout))
+		if err != nil {
+			d.logger.Errorf("failed to claim webhook deliveries: %s", err)
+			return
+		}
+
+		if ctx.Err() != nil {
+			d.release(deliveries)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func(delivery model.WebhookDelivery) {
+				defer wg.Done()
+				d.deliver(delivery)
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(deliveries) < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// deliver отправляет событие вебхуку и сохраняет результат: отмечает событие доставленным, планирует повторную
+// попытку или, если попытки закончились, отмечает доставку невыполненной.
+func (d *Dispatcher) deliver(delivery model.WebhookDelivery) {
+	webhook, err := d.webhookRepo.Get(delivery.WebhookID)
+	if errors.Is(err, store.ErrWebhookNotFound) {
+		// вебхук удалён вместе с журналом доставки, пока событие ждало отправки
+		return
+	}
+	if err != nil {
+		d.logger.Errorf("failed to get webhook %d: %s", delivery.WebhookID, err)
+		d.release([]model.WebhookDelivery{delivery})
+		return
+	}
+
+	statusCode, err := d.send(webhook, delivery)
+	if err == nil {
+		if err = d.outboxRepo.CompleteDelivery(delivery.ID, statusCode); err != nil {
+			d.logger.Errorf("failed to complete webhook delivery %d: %s", delivery.ID, err)
+		}
+		return
+	}
+
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		d.logger.Errorf("webhook delivery %d (%s) failed after %d attempts: %s",
+			delivery.ID, delivery.Event, delivery.Attempts, err)
+		if err = d.outboxRepo.FailDelivery(delivery.ID, statusCode, err.Error()); err != nil {
+			d.logger.Errorf("failed to mark webhook delivery %d as failed: %s", delivery.ID, err)
+		}
+		return
+	}
+
+	retryAt := time.Now().Add(d.retryDelay(delivery.Attempts))
+	d.logger.Warnf("webhook delivery %d (%s) failed, retrying at %s: %s",
+		delivery.ID, delivery.Event, retryAt.Format(time.RFC3339), err)
+	if err = d.outboxRepo.RetryDelivery(delivery.ID, retryAt, statusCode, err.Error()); err != nil {
+		d.logger.Errorf("failed to reschedule webhook delivery %d: %s", delivery.ID, err)
+	}
+}
+
+// send отправляет событие POST-запросом на адрес вебхука и возвращает код ответа (0, если ответа не было).
+// Событие считается доставленным, если получатель ответил кодом 2xx.
+func (d *Dispatcher) send(webhook *model.Webhook, delivery model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(SignatureHeader,
+		fmt.Sprintf("t=%d,v1=%s", now.Unix(), Sign(webhook.Secret, now, delivery.Payload)),
+	)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay возвращает задержку перед повторной попыткой после attempts неудачных попыток.
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.cfg.RetryDelay
+	for i := 1; i < attempts && delay < d.cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.cfg.MaxRetryDelay {
+		delay = d.cfg.MaxRetryDelay
+	}
+	return delay
+}
+
+// release возвращает в очередь доставки, к которым так и не приступили.
+func (d *Dispatcher) release(deliveries []model.WebhookDelivery) {
+	for _, delivery := range deliveries {
+		if err := d.outboxRepo.ReleaseDelivery(delivery.ID); err != nil {
+			d.logger.Errorf("failed to release webhook delivery %d: %s", delivery.ID, err)
+		}
+	}
+}
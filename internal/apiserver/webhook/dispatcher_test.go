@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/tmrrwnxtsn/restaurant-table-booking-app/internal/apiserver/model"
+)
+
+// TestDispatcher_SendToNonPublicAddress проверяет, что событие не отправляется на адрес во внутренней сети, даже
+// если вебхук уже создан с таким адресом (например, имя хоста стало указывать на внутренний адрес).
+func TestDispatcher_SendToNonPublicAddress(t *testing.T) {
+	var received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.StoreInt32(&received, 1)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	d := NewDispatcher(nil, nil, Config{Timeout: 5 * time.Second}, logger)
+
+	// имя localhost при создании вебхука отклоняется, а здесь проверяется только соединение
+	for _, url := range []string{server.URL, "http://localhost" + server.URL[len("http://127.0.0.1"):]} {
+		webhook := &model.Webhook{ID: 1, URL: url, Secret: "secret"}
+		delivery := model.WebhookDelivery{ID: 1, Event: model.WebhookEventBookingCreated, Payload: []byte(`{}`)}
+
+		statusCode, err := d.send(webhook, delivery)
+		if !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("send(%s) = %d, %v; want ErrNonPublicAddress", url, statusCode, err)
+		}
+	}
+	if atomic.LoadInt32(&received) != 0 {
+		t.Error("the event was delivered to a loopback address")
+	}
+}
+
+func TestCheckPublicAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "93.184.216.34:443", want: true},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443", want: true},
+		{address: "127.0.0.1:80"},
+		{address: "[::1]:80"},
+		{address: "10.0.0.1:443"},
+		{address: "169.254.169.254:80"},
+		{address: "[::ffff:192.168.1.1]:80"},
+	}
+
+	for _, tt := range tests {
+		err := checkPublicAddress("tcp", tt.address, nil)
+		if tt.want && err != nil || !tt.want && !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("checkPublicAddress(%s) = %v, want public: %t", tt.address, err, tt.want)
+		}
+	}
+}
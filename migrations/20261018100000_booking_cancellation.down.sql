@@ -0,0 +1,7 @@
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS fk_bookings_restaurants;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS cancelled_by,
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS restaurant_id;
@@ -0,0 +1,14 @@
+ALTER TABLE bookings
+    ADD COLUMN restaurant_id INTEGER,
+    ADD COLUMN cancelled_at  TIMESTAMP,
+    ADD COLUMN cancelled_by  VARCHAR(255);
+
+-- у уже оформленных броней ресторан определяется по забронированным столикам
+UPDATE bookings b
+SET restaurant_id = t.restaurant_id
+FROM bookings_tables bt
+         JOIN tables t ON t.id = bt.table_id
+WHERE bt.booking_id = b.id;
+
+ALTER TABLE bookings
+    ADD CONSTRAINT fk_bookings_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE;
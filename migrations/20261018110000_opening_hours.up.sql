@@ -0,0 +1,24 @@
+/*
+ Таблица opening_hours содержит недельные графики работы ресторанов. Каждая запись представляет смену в один из дней
+ недели, поэтому у ресторана может быть несколько смен в день (например, обеденная и вечерняя).
+ Если у ресторана нет ни одной смены, он работает по графику по умолчанию: ежедневно с 9:00 до 23:00.
+ Смена, время закрытия которой не позже времени открытия, заканчивается на следующий день (например, с 18:00 до 02:00),
+ и последнюю бронь в ней можно оформить как до полуночи, так и после неё до закрытия.
+ */
+CREATE TABLE IF NOT EXISTS opening_hours
+(
+    id            SERIAL PRIMARY KEY,
+    restaurant_id INTEGER  NOT NULL,
+    weekday       SMALLINT NOT NULL, -- день недели (0 - воскресенье, как в EXTRACT(DOW FROM ...))
+    opens_at      TIME     NOT NULL, -- время открытия
+    closes_at     TIME     NOT NULL, -- время закрытия
+    last_seating  TIME     NOT NULL, -- время, на которое можно оформить последнюю бронь в рамках смены
+    CONSTRAINT fk_opening_hours_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE,
+    CONSTRAINT chk_opening_hours_weekday CHECK (weekday BETWEEN 0 AND 6),
+    CONSTRAINT chk_opening_hours_interval CHECK (
+            (opens_at < closes_at AND last_seating BETWEEN opens_at AND closes_at) OR
+            (opens_at >= closes_at AND (last_seating >= opens_at OR last_seating <= closes_at))
+        )
+);
+
+CREATE INDEX IF NOT EXISTS idx_opening_hours_restaurant_id ON opening_hours (restaurant_id);
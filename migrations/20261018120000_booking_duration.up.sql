@@ -0,0 +1,163 @@
+-- длительность брони по умолчанию (в минутах)
+ALTER TABLE restaurants
+    ADD COLUMN booking_duration INTEGER NOT NULL DEFAULT 120;
+
+ALTER TABLE restaurants
+    ADD CONSTRAINT chk_restaurants_booking_duration CHECK (booking_duration BETWEEN 1 AND 720);
+
+/*
+ Таблица booking_durations содержит правила, по которым длительность брони зависит от количества человек:
+ для компании применяется правило с наибольшим min_people, не превышающим её размер. Если ни одно правило не подошло,
+ используется длительность брони ресторана по умолчанию (restaurants.booking_duration).
+ */
+CREATE TABLE IF NOT EXISTS booking_durations
+(
+    id            SERIAL PRIMARY KEY,
+    restaurant_id INTEGER NOT NULL,
+    min_people    INTEGER NOT NULL, -- минимальное количество человек, с которого действует правило
+    duration      INTEGER NOT NULL, -- длительность брони в минутах
+    CONSTRAINT fk_booking_durations_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE,
+    CONSTRAINT uq_booking_durations_min_people UNIQUE (restaurant_id, min_people),
+    CONSTRAINT chk_booking_durations_min_people CHECK (min_people >= 1),
+    CONSTRAINT chk_booking_durations_duration CHECK (duration BETWEEN 1 AND 720)
+);
+
+-- количество человек в брони (у ранее оформленных броней - суммарная вместимость забронированных столиков)
+ALTER TABLE bookings
+    ADD COLUMN people_number INTEGER;
+
+UPDATE bookings b
+SET people_number = seats.total
+FROM (SELECT bt.booking_id, SUM(t.seats_number) AS total
+      FROM bookings_tables bt
+               JOIN tables t ON t.id = bt.table_id
+      GROUP BY bt.booking_id) seats
+WHERE seats.booking_id = b.id;
+
+/*
+ Функция get_booking_duration возвращает длительность брони в ресторане для компании из people_number человек.
+ */
+CREATE OR REPLACE FUNCTION get_booking_duration(
+    checked_restaurant_id int, -- ID ресторана
+    people_number int -- количество человек
+)
+    RETURNS interval
+AS
+$$
+SELECT make_interval(mins => COALESCE(
+        (SELECT bd.duration
+         FROM booking_durations bd
+         WHERE bd.restaurant_id = checked_restaurant_id
+           AND bd.min_people <= people_number
+         ORDER BY bd.min_people DESC
+         LIMIT 1),
+        (SELECT r.booking_duration FROM restaurants r WHERE r.id = checked_restaurant_id)
+    ));
+$$ LANGUAGE sql STABLE;
+
+-- длительность брони теперь передаётся в функции явно
+DROP FUNCTION IF EXISTS get_available_tables(date, time);
+DROP FUNCTION IF EXISTS is_table_available(int, date, time);
+
+/*
+ Функция get_available_tables возвращает таблицу вида tables с информацией о столиках, свободных для бронирования.
+ */
+CREATE OR REPLACE FUNCTION get_available_tables(
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS TABLE
+            (
+                id            INTEGER,
+                restaurant_id INTEGER,
+                seats_number  INTEGER
+            )
+AS
+$$
+BEGIN
+    -- столики которые ни разу не бронировались
+    RETURN QUERY
+        SELECT tables.id, tables.restaurant_id, tables.seats_number
+        FROM tables
+        WHERE tables.id NOT IN (SELECT bookings_tables.table_id FROM bookings_tables)
+        UNION
+        -- столики которые хотя бы раз бронировались
+        SELECT tables.id, tables.restaurant_id, tables.seats_number
+        FROM tables
+                 JOIN bookings_tables bt on tables.id = bt.table_id
+                 JOIN bookings b on b.id = bt.booking_id
+        WHERE is_table_available(bt.table_id, desired_booking_date, desired_booking_time, booking_duration);
+END;
+$$ LANGUAGE plpgsql;
+
+/*
+    Функция is_table_available проверяет, можно ли забронировать столик на booking_duration в желаемые дату и время.
+    Алгоритм описан в первоначальной миграции: временные промежутки броней столика в желаемую дату сливаются
+    с желаемым, и если количество промежутков изменилось, значит, желаемая бронь накладывается на существующие.
+ */
+CREATE OR REPLACE FUNCTION is_table_available(
+    checked_table_id int, -- ID столика
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS BOOLEAN -- возвращает TRUE, если забронировать можно, иначе - FALSE
+AS
+$$
+DECLARE
+    rows_before_merge INTEGER;
+    rows_after_merge  INTEGER;
+BEGIN
+    -- ищем временные промежутки броней столиков, которые хотя бы раз бронировались в выбранный день
+    CREATE TEMP TABLE booking_intervals
+    AS
+    SELECT booked_time_from, booked_time_to
+    FROM tables
+             JOIN bookings_tables bt on tables.id = bt.table_id
+             JOIN bookings b on b.id = bt.booking_id
+    WHERE booked_date = desired_booking_date
+      AND table_id = checked_table_id
+    UNION
+    -- добавляем временной промежуток желаемой брони к полученным
+    VALUES (desired_booking_time, desired_booking_time + booking_duration);
+
+    /*
+    если остался только один временной промежуток (время желаемой брони), значит, столик вообще не бронировался
+    в выбранную дату, и его можно забронировать
+     */
+    rows_before_merge := (SELECT COUNT(*) FROM booking_intervals);
+    IF rows_before_merge = 1 THEN
+        DROP TABLE booking_intervals;
+        RETURN TRUE;
+    END IF;
+
+    rows_after_merge := (
+        SELECT COUNT(*)
+        FROM (
+                 WITH rng(s, e) AS (
+                     SELECT *
+                     FROM booking_intervals
+                 )
+                 SELECT -- min/max по группе
+                        min(s) s,
+                        max(e) e
+                 FROM (
+                          SELECT *,
+                                 sum(ns::integer) OVER (ORDER BY s, e) grp -- определение групп
+                          FROM (
+                                   SELECT *,
+                                          coalesce(s > max(e)
+                                                       OVER (ORDER BY s, e ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING),
+                                                   TRUE) ns -- начало правее самого правого из предыдущих концов == разрыв
+                                   FROM rng
+                               ) t
+                      ) t
+                 GROUP BY grp
+             ) merged_intervals
+    );
+
+    DROP TABLE booking_intervals;
+    RETURN rows_before_merge = rows_after_merge;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,7 @@
+ALTER TABLE bookings_tables
+    DROP CONSTRAINT IF EXISTS excl_bookings_tables_overlap;
+
+ALTER TABLE bookings_tables
+    DROP COLUMN IF EXISTS booked_during;
+
+DROP EXTENSION IF EXISTS btree_gist;
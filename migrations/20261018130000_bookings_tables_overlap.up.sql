@@ -0,0 +1,53 @@
+-- btree_gist нужен, чтобы в одном ограничении-исключении сравнивать ID столика (=) и промежутки времени (&&)
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- промежуток времени, на который столик занят в рамках брони (копия даты и времени брони для ограничения-исключения)
+ALTER TABLE bookings_tables
+    ADD COLUMN booked_during tsrange;
+
+/*
+ Границы включаются в промежуток, поэтому брони, которые соприкасаются по времени, тоже считаются пересекающимися
+ (так же, как в функции is_table_available). Бронь, которая заканчивается после полуночи, заканчивается на следующий день.
+ */
+UPDATE bookings_tables bt
+SET booked_during = tsrange(
+        b.booked_date + b.booked_time_from,
+        b.booked_date + b.booked_time_to +
+        CASE WHEN b.booked_time_to < b.booked_time_from THEN interval '1 day' ELSE interval '0' END,
+        '[]'
+    )
+FROM bookings b
+WHERE b.id = bt.booking_id;
+
+ALTER TABLE bookings_tables
+    ALTER COLUMN booked_during SET NOT NULL;
+
+/*
+ Одновременные запросы могли успеть забронировать один столик на пересекающиеся промежутки времени. С такими бронями
+ ограничение-исключение не добавить, а выбрать, какую из них оставить, может только ресторан (гостей нужно
+ предупредить), поэтому миграция прерывается со списком пересекающихся броней. Отменённые брони столиков не занимают.
+ */
+DO
+$$
+    DECLARE
+        conflicts TEXT;
+    BEGIN
+        SELECT string_agg(
+                       format('table %s: bookings %s and %s', a.table_id, a.booking_id, b.booking_id),
+                       '; ' ORDER BY a.table_id, a.booking_id, b.booking_id
+                   )
+        INTO conflicts
+        FROM bookings_tables a
+                 JOIN bookings_tables b
+                      ON b.table_id = a.table_id AND b.booking_id > a.booking_id AND b.booked_during && a.booked_during;
+
+        IF conflicts IS NOT NULL THEN
+            RAISE EXCEPTION 'tables are double-booked: %', conflicts
+                USING HINT = 'cancel or move one booking of each pair to another table and apply the migration again';
+        END IF;
+    END
+$$;
+
+-- один столик не может быть забронирован на пересекающиеся промежутки времени, даже если брони оформляются одновременно
+ALTER TABLE bookings_tables
+    ADD CONSTRAINT excl_bookings_tables_overlap EXCLUDE USING gist (table_id WITH =, booked_during WITH &&);
@@ -0,0 +1,6 @@
+-- стратегия, по которой для брони выбираются столики ресторана
+ALTER TABLE restaurants
+    ADD COLUMN allocation_strategy VARCHAR(20) NOT NULL DEFAULT 'best_fit';
+
+ALTER TABLE restaurants
+    ADD CONSTRAINT chk_restaurants_allocation_strategy CHECK (allocation_strategy IN ('best_fit', 'smallest_first'));
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS table_joins;
+
+ALTER TABLE tables
+    DROP COLUMN IF EXISTS position;
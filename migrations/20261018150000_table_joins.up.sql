@@ -0,0 +1,19 @@
+-- расположение столика в зале (например, номер на схеме зала)
+ALTER TABLE tables
+    ADD COLUMN position VARCHAR(50) NOT NULL DEFAULT '';
+
+/*
+ Таблица table_joins содержит пары столиков, которые можно сдвинуть, чтобы посадить за них одну компанию.
+ Каждая пара хранится один раз: ID первого столика всегда меньше ID второго.
+ */
+CREATE TABLE IF NOT EXISTS table_joins
+(
+    table_id          INTEGER NOT NULL,
+    joinable_table_id INTEGER NOT NULL,
+    CONSTRAINT pk_table_joins PRIMARY KEY (table_id, joinable_table_id),
+    CONSTRAINT fk_table_joins_tables FOREIGN KEY (table_id) REFERENCES tables (id) ON DELETE CASCADE,
+    CONSTRAINT fk_table_joins_joinable_tables FOREIGN KEY (joinable_table_id) REFERENCES tables (id) ON DELETE CASCADE,
+    CONSTRAINT chk_table_joins_order CHECK (table_id < joinable_table_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_table_joins_joinable_table_id ON table_joins (joinable_table_id);
@@ -0,0 +1,6 @@
+-- зона ресторана, в которой стоит столик
+ALTER TABLE tables
+    ADD COLUMN zone VARCHAR(20) NOT NULL DEFAULT 'hall';
+
+ALTER TABLE tables
+    ADD CONSTRAINT chk_tables_zone CHECK (zone IN ('hall', 'terrace', 'vip', 'non_smoking'));
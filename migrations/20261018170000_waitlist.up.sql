@@ -0,0 +1,24 @@
+/*
+ Таблица waitlist содержит листы ожидания ресторанов: гости, которым не хватило мест, ждут, пока они освободятся.
+ Когда места освобождаются, первому подходящему гостю предлагается оформить бронь до offer_expires_at.
+ */
+CREATE TABLE IF NOT EXISTS waitlist
+(
+    id               SERIAL PRIMARY KEY,
+    restaurant_id    INTEGER      NOT NULL,
+    client_name      VARCHAR(255) NOT NULL,
+    client_phone     VARCHAR(11)  NOT NULL,
+    people_number    INTEGER      NOT NULL,
+    desired_date     DATE         NOT NULL,
+    desired_time     TIME         NOT NULL,
+    status           VARCHAR(20)  NOT NULL DEFAULT 'waiting',
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    offer_expires_at TIMESTAMPTZ,
+    booking_id       INTEGER,
+    CONSTRAINT fk_waitlist_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE,
+    CONSTRAINT fk_waitlist_bookings FOREIGN KEY (booking_id) REFERENCES bookings (id) ON DELETE SET NULL,
+    CONSTRAINT chk_waitlist_people_number CHECK (people_number >= 1),
+    CONSTRAINT chk_waitlist_status CHECK (status IN ('waiting', 'offered', 'booked', 'expired', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_restaurant_status ON waitlist (restaurant_id, status);
@@ -0,0 +1,38 @@
+-- возвращаем функцию get_available_tables, которая не учитывает удержания столиков
+/*
+ Функция get_available_tables возвращает таблицу вида tables с информацией о столиках, свободных для бронирования.
+ */
+CREATE OR REPLACE FUNCTION get_available_tables(
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS TABLE
+            (
+                id            INTEGER,
+                restaurant_id INTEGER,
+                seats_number  INTEGER
+            )
+AS
+$$
+BEGIN
+    -- столики которые ни разу не бронировались
+    RETURN QUERY
+        SELECT tables.id, tables.restaurant_id, tables.seats_number
+        FROM tables
+        WHERE tables.id NOT IN (SELECT bookings_tables.table_id FROM bookings_tables)
+        UNION
+        -- столики которые хотя бы раз бронировались
+        SELECT tables.id, tables.restaurant_id, tables.seats_number
+        FROM tables
+                 JOIN bookings_tables bt on tables.id = bt.table_id
+                 JOIN bookings b on b.id = bt.booking_id
+        WHERE is_table_available(bt.table_id, desired_booking_date, desired_booking_time, booking_duration);
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS is_table_held(int, date, time, interval);
+
+DROP TABLE IF EXISTS holds_tables;
+
+DROP TABLE IF EXISTS holds;
@@ -0,0 +1,93 @@
+/*
+ Таблица holds содержит временные удержания столиков: пока гость заполняет форму брони, подобранные для него столики
+ считаются занятыми до expires_at. Удержание либо превращается в бронь, либо снимается по истечении срока.
+ */
+CREATE TABLE IF NOT EXISTS holds
+(
+    id             SERIAL PRIMARY KEY,
+    restaurant_id  INTEGER     NOT NULL,
+    people_number  INTEGER     NOT NULL,
+    held_date      DATE        NOT NULL,
+    held_time_from TIME        NOT NULL,
+    held_time_to   TIME        NOT NULL,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_holds_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE,
+    CONSTRAINT chk_holds_people_number CHECK (people_number >= 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_expires_at ON holds (expires_at);
+
+-- столики, удерживаемые в рамках удержания (held_during устроен так же, как bookings_tables.booked_during)
+CREATE TABLE IF NOT EXISTS holds_tables
+(
+    id          SERIAL PRIMARY KEY,
+    hold_id     INTEGER NOT NULL,
+    table_id    INTEGER NOT NULL,
+    held_during tsrange NOT NULL,
+    CONSTRAINT fk_holds_tables_holds FOREIGN KEY (hold_id) REFERENCES holds (id) ON DELETE CASCADE,
+    CONSTRAINT fk_holds_tables_tables FOREIGN KEY (table_id) REFERENCES tables (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_tables_table_id ON holds_tables (table_id);
+
+/*
+ Функция is_table_held проверяет, удерживается ли столик действующим удержанием на промежуток времени,
+ который накладывается на желаемый.
+ */
+CREATE OR REPLACE FUNCTION is_table_held(
+    checked_table_id int, -- ID столика
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS BOOLEAN
+AS
+$$
+SELECT EXISTS(SELECT 1
+              FROM holds_tables ht
+                       JOIN holds h ON h.id = ht.hold_id
+              WHERE ht.table_id = checked_table_id
+                AND h.expires_at > now()
+                AND ht.held_during && tsrange(
+                      desired_booking_date + desired_booking_time,
+                      desired_booking_date + desired_booking_time + booking_duration,
+                      '[]'
+                  ));
+$$ LANGUAGE sql STABLE;
+
+/*
+ Функция get_available_tables возвращает таблицу вида tables с информацией о столиках, свободных для бронирования.
+ Столики, удерживаемые другими гостями, считаются занятыми.
+ */
+CREATE OR REPLACE FUNCTION get_available_tables(
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS TABLE
+            (
+                id            INTEGER,
+                restaurant_id INTEGER,
+                seats_number  INTEGER
+            )
+AS
+$$
+BEGIN
+    RETURN QUERY
+        SELECT a.id, a.restaurant_id, a.seats_number
+        FROM (
+                 -- столики которые ни разу не бронировались
+                 SELECT tables.id, tables.restaurant_id, tables.seats_number
+                 FROM tables
+                 WHERE tables.id NOT IN (SELECT bookings_tables.table_id FROM bookings_tables)
+                 UNION
+                 -- столики которые хотя бы раз бронировались
+                 SELECT tables.id, tables.restaurant_id, tables.seats_number
+                 FROM tables
+                          JOIN bookings_tables bt on tables.id = bt.table_id
+                          JOIN bookings b on b.id = bt.booking_id
+                 WHERE is_table_available(bt.table_id, desired_booking_date, desired_booking_time, booking_duration)
+             ) a
+        WHERE NOT is_table_held(a.id, desired_booking_date, desired_booking_time, booking_duration);
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS users_restaurants;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,24 @@
+/*
+ Таблица users содержит пользователей API администрирования ресторанов. Вместо ключа API хранится его хеш SHA-256:
+ сам ключ показывается пользователю один раз, при создании (или перевыпуске).
+ */
+CREATE TABLE IF NOT EXISTS users
+(
+    id           SERIAL PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    role         VARCHAR(20)  NOT NULL,
+    api_key_hash CHAR(64)     NOT NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT uq_users_api_key_hash UNIQUE (api_key_hash),
+    CONSTRAINT chk_users_role CHECK (role IN ('admin', 'manager', 'staff'))
+);
+
+-- рестораны, закреплённые за менеджерами и сотрудниками
+CREATE TABLE IF NOT EXISTS users_restaurants
+(
+    user_id       INTEGER NOT NULL,
+    restaurant_id INTEGER NOT NULL,
+    PRIMARY KEY (user_id, restaurant_id),
+    CONSTRAINT fk_users_restaurants_users FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_users_restaurants_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE
+);
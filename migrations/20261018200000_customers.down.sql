@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_bookings_customer_id;
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS fk_bookings_customers,
+    DROP COLUMN IF EXISTS customer_id;
+DROP TABLE IF EXISTS customer_sessions;
+DROP TABLE IF EXISTS customers;
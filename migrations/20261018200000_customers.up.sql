@@ -0,0 +1,32 @@
+/*
+ Таблица customers содержит учётные записи гостей на сайте. Вместо пароля хранится его хеш PBKDF2-HMAC-SHA256
+ вместе с солью и количеством итераций.
+ */
+CREATE TABLE IF NOT EXISTS customers
+(
+    id            SERIAL PRIMARY KEY,
+    name          VARCHAR(255) NOT NULL,
+    phone         VARCHAR(11)  NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT uq_customers_phone UNIQUE (phone)
+);
+
+/*
+ Таблица customer_sessions содержит сеансы гостей на сайте. Вместо токена сеанса (он хранится в cookie гостя)
+ хранится его хеш SHA-256.
+ */
+CREATE TABLE IF NOT EXISTS customer_sessions
+(
+    token_hash  CHAR(64)    PRIMARY KEY,
+    customer_id INTEGER     NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_customer_sessions_customers FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE
+);
+
+-- брони, оформленные гостями с учётной записью; брони без учётной записи по-прежнему оформляются с пустым customer_id
+ALTER TABLE bookings
+    ADD COLUMN customer_id INTEGER,
+    ADD CONSTRAINT fk_bookings_customers FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_customer_id ON bookings (customer_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bookings_guest_id;
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS fk_bookings_guests,
+    DROP COLUMN IF EXISTS guest_id;
+DROP TABLE IF EXISTS guests;
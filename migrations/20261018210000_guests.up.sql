@@ -0,0 +1,41 @@
+/*
+ Таблица guests содержит гостей ресторанов. Гости различаются по нормализованному телефону (только цифры, российский
+ номер записывается через 8), поэтому брони с одним телефоном, записанным по-разному, относятся к одному гостю.
+ */
+CREATE TABLE IF NOT EXISTS guests
+(
+    id         SERIAL PRIMARY KEY,
+    phone      VARCHAR(11)  NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT uq_guests_phone UNIQUE (phone)
+);
+
+ALTER TABLE bookings
+    ADD COLUMN guest_id INTEGER,
+    ADD CONSTRAINT fk_bookings_guests FOREIGN KEY (guest_id) REFERENCES guests (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_guest_id ON bookings (guest_id);
+
+-- normalize_phone повторяет model.NormalizePhone и нужна только для переноса уже оформленных броней
+CREATE FUNCTION pg_temp.normalize_phone(phone TEXT) RETURNS TEXT AS
+$$
+SELECT CASE
+           WHEN length(digits) = 11 AND left(digits, 1) = '7' THEN '8' || substr(digits, 2)
+           WHEN length(digits) = 10 AND left(digits, 1) = '9' THEN '8' || digits
+           ELSE digits
+           END
+FROM (SELECT regexp_replace(phone, '[^0-9]', '', 'g') AS digits) AS d
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- создаём гостей по телефонам из уже оформленных броней; имя гостя берётся из его последней брони
+INSERT INTO guests (phone, name)
+SELECT DISTINCT ON (pg_temp.normalize_phone(client_phone)) pg_temp.normalize_phone(client_phone), client_name
+FROM bookings
+WHERE pg_temp.normalize_phone(client_phone) <> ''
+ORDER BY pg_temp.normalize_phone(client_phone), id DESC;
+
+UPDATE bookings b
+SET guest_id = g.id
+FROM guests g
+WHERE g.phone = pg_temp.normalize_phone(b.client_phone);
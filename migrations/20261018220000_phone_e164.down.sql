@@ -0,0 +1,25 @@
+-- российские номера снова записываются через 8; иностранные номера длиннее 11 символов не помещаются в прежние
+-- колонки, поэтому при их наличии откат завершится ошибкой
+UPDATE bookings
+SET client_phone = '8' || substr(client_phone, 3)
+WHERE client_phone ~ '^\+7[0-9]{10}$';
+UPDATE waitlist
+SET client_phone = '8' || substr(client_phone, 3)
+WHERE client_phone ~ '^\+7[0-9]{10}$';
+UPDATE customers
+SET phone = '8' || substr(phone, 3)
+WHERE phone ~ '^\+7[0-9]{10}$';
+UPDATE guests
+SET phone = CASE
+                WHEN phone ~ '^\+7[0-9]{10}$' THEN '8' || substr(phone, 3)
+                ELSE ltrim(phone, '+')
+    END;
+
+ALTER TABLE guests
+    ALTER COLUMN phone TYPE VARCHAR(11);
+ALTER TABLE customers
+    ALTER COLUMN phone TYPE VARCHAR(11);
+ALTER TABLE waitlist
+    ALTER COLUMN client_phone TYPE VARCHAR(11);
+ALTER TABLE bookings
+    ALTER COLUMN client_phone TYPE VARCHAR(11);
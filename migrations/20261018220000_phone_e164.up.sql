@@ -0,0 +1,107 @@
+/*
+ Телефоны гостей хранятся в формате E.164 (+79279007265): в нём помещаются и иностранные номера (до 15 цифр).
+ */
+ALTER TABLE bookings
+    ALTER COLUMN client_phone TYPE VARCHAR(16);
+ALTER TABLE waitlist
+    ALTER COLUMN client_phone TYPE VARCHAR(16);
+ALTER TABLE customers
+    ALTER COLUMN phone TYPE VARCHAR(16);
+ALTER TABLE guests
+    ALTER COLUMN phone TYPE VARCHAR(16);
+
+/*
+ to_e164 повторяет model.NormalizePhone для телефонов, сохранённых до перехода на E.164: российские номера
+ приводятся к записи через +7, а международные номера (через "+" или "00") теряют разделители. Если телефон нельзя
+ привести к формату E.164, функция возвращает NULL.
+ */
+CREATE FUNCTION pg_temp.to_e164(phone TEXT) RETURNS TEXT AS
+$$
+DECLARE
+    international BOOLEAN;
+    digits        TEXT;
+BEGIN
+    phone := btrim(phone, E' \t\n\r\v\f\u00a0');
+    international := left(phone, 1) = '+';
+    IF international THEN
+        phone := substr(phone, 2);
+    END IF;
+
+    -- кроме цифр в телефоне допускаются только разделители (в том числе неразрывный пробел)
+    IF phone ~ E'[^0-9 \t().\u00a0-]' THEN
+        RETURN NULL;
+    END IF;
+    digits := regexp_replace(phone, '[^0-9]', '', 'g');
+
+    IF NOT international THEN
+        IF left(digits, 2) = '00' THEN
+            digits := substr(digits, 3);
+        ELSIF length(digits) = 11 AND left(digits, 1) IN ('7', '8') THEN
+            digits := '7' || substr(digits, 2);
+        ELSIF length(digits) = 10 AND left(digits, 1) = '9' THEN
+            digits := '7' || digits;
+        ELSE
+            -- без кода страны нельзя понять, в какой стране зарегистрирован номер
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    -- код страны не начинается с 0, а российские номера всегда состоят из 11 цифр
+    IF length(digits) NOT BETWEEN 7 AND 15 OR left(digits, 1) = '0' OR
+       (left(digits, 1) = '7' AND length(digits) <> 11) THEN
+        RETURN NULL;
+    END IF;
+    RETURN '+' || digits;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- телефоны гостей уже содержат только цифры, а российские номера записаны через 8, поэтому остальным номерам
+-- достаточно вернуть "+"
+CREATE FUNCTION pg_temp.guest_to_e164(phone TEXT) RETURNS TEXT AS
+$$
+SELECT pg_temp.to_e164(CASE WHEN length(phone) = 11 AND left(phone, 1) = '8' THEN phone ELSE '+' || phone END)
+$$ LANGUAGE SQL IMMUTABLE;
+
+/*
+ Телефон, который нельзя привести к формату E.164, не нашёлся бы ни при поиске броней гостя, ни при привязке броней
+ к гостям, поэтому миграция прерывается со списком таких телефонов: их нужно исправить (например, дописать код страны)
+ и применить миграцию снова.
+ */
+DO
+$$
+    DECLARE
+        invalid TEXT;
+    BEGIN
+        SELECT string_agg(format('%s %s: %L', t.table_name, t.id, t.phone), '; ' ORDER BY t.table_name, t.id)
+        INTO invalid
+        FROM (SELECT 'bookings' AS table_name, id, client_phone AS phone
+              FROM bookings
+              WHERE pg_temp.to_e164(client_phone) IS NULL
+              UNION ALL
+              SELECT 'waitlist', id, client_phone
+              FROM waitlist
+              WHERE pg_temp.to_e164(client_phone) IS NULL
+              UNION ALL
+              SELECT 'customers', id, phone
+              FROM customers
+              WHERE pg_temp.to_e164(phone) IS NULL
+              UNION ALL
+              SELECT 'guests', id, phone
+              FROM guests
+              WHERE pg_temp.guest_to_e164(phone) IS NULL) AS t;
+
+        IF invalid IS NOT NULL THEN
+            RAISE EXCEPTION 'phones cannot be converted to E.164: %', invalid
+                USING HINT = 'fix these phones (for example, add the country code) and apply the migration again';
+        END IF;
+    END
+$$;
+
+UPDATE bookings
+SET client_phone = pg_temp.to_e164(client_phone);
+UPDATE waitlist
+SET client_phone = pg_temp.to_e164(client_phone);
+UPDATE customers
+SET phone = pg_temp.to_e164(phone);
+UPDATE guests
+SET phone = pg_temp.guest_to_e164(phone);
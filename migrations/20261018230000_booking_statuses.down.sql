@@ -0,0 +1,5 @@
+ALTER TABLE bookings
+    DROP CONSTRAINT IF EXISTS chk_bookings_status,
+    DROP COLUMN IF EXISTS finished_at,
+    DROP COLUMN IF EXISTS seated_at,
+    DROP COLUMN IF EXISTS status;
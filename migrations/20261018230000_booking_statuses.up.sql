@@ -0,0 +1,16 @@
+/*
+ Статус брони: pending (ожидает подтверждения рестораном), confirmed (подтверждена), seated (гости пришли),
+ completed (гости ушли), no_show (гости не пришли) или cancelled (бронь отменена). seated_at и finished_at хранят
+ моменты прихода гостей и завершения брони; с момента завершения столики брони свободны.
+ */
+ALTER TABLE bookings
+    ADD COLUMN status      VARCHAR(16) NOT NULL DEFAULT 'confirmed',
+    ADD COLUMN seated_at   TIMESTAMPTZ,
+    ADD COLUMN finished_at TIMESTAMPTZ,
+    ADD CONSTRAINT chk_bookings_status
+        CHECK (status IN ('pending', 'confirmed', 'seated', 'completed', 'no_show', 'cancelled'));
+
+-- уже отменённые брони переводятся в статус cancelled, остальные считаются подтверждёнными
+UPDATE bookings
+SET status = 'cancelled'
+WHERE cancelled_at IS NOT NULL;
@@ -0,0 +1,15 @@
+/*
+ Таблица reliability_policies содержит правила, по которым рестораны ограничивают онлайн-бронирование гостям,
+ не приходящим по своим броням. Нулевые значения отключают ограничение; у ресторана без правил строки нет.
+ */
+CREATE TABLE IF NOT EXISTS reliability_policies
+(
+    restaurant_id      INTEGER PRIMARY KEY,
+    confirmation_after INTEGER NOT NULL DEFAULT 0, -- количество неявок, с которого брони гостя ждут подтверждения
+    block_after        INTEGER NOT NULL DEFAULT 0, -- количество неявок, с которого гостю блокируется бронирование
+    block_days         INTEGER NOT NULL DEFAULT 0, -- срок блокировки в днях после последней неявки
+    CONSTRAINT fk_reliability_policies_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE,
+    CONSTRAINT chk_reliability_policies_confirmation_after CHECK (confirmation_after >= 0),
+    CONSTRAINT chk_reliability_policies_block CHECK (
+            (block_after = 0 AND block_days = 0) OR (block_after > 0 AND block_days BETWEEN 1 AND 365))
+);
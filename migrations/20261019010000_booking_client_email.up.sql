@@ -0,0 +1,6 @@
+/*
+ Необязательный адрес электронной почты клиента, на который отправляются уведомления об оформлении, подтверждении,
+ изменении и отмене брони.
+ */
+ALTER TABLE bookings
+    ADD COLUMN client_email VARCHAR(254);
@@ -0,0 +1,26 @@
+/*
+ Таблица jobs содержит фоновые задачи, связанные с бронями (например, напоминания гостям). Задача выполняется, когда
+ наступает run_at; после неудачной попытки run_at переносится на более поздний момент. Пока задачу выполняет один
+ из экземпляров сервиса, она закреплена за ним до locked_until и не выбирается другими.
+ */
+CREATE TABLE IF NOT EXISTS jobs
+(
+    id           SERIAL PRIMARY KEY,
+    kind         VARCHAR(32) NOT NULL,
+    booking_id   INTEGER     NOT NULL,
+    run_at       TIMESTAMPTZ NOT NULL,
+    status       VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    locked_until TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at  TIMESTAMPTZ,
+    CONSTRAINT fk_jobs_bookings FOREIGN KEY (booking_id) REFERENCES bookings (id) ON DELETE CASCADE,
+    CONSTRAINT chk_jobs_status CHECK (status IN ('pending', 'done', 'failed')),
+    CONSTRAINT chk_jobs_attempts CHECK (attempts >= 0)
+);
+
+-- у брони не больше одной невыполненной задачи каждого вида
+CREATE UNIQUE INDEX IF NOT EXISTS uq_jobs_pending_kind_booking ON jobs (kind, booking_id) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_jobs_pending_run_at ON jobs (run_at) WHERE status = 'pending';
@@ -0,0 +1,62 @@
+/*
+ Таблица webhooks содержит адреса, на которые отправляются события с бронями ресторанов (например, в кассовую систему
+ или CRM ресторана). Пустой список events означает подписку на все события.
+ */
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id            SERIAL PRIMARY KEY,
+    restaurant_id INTEGER       NOT NULL,
+    url           TEXT          NOT NULL,
+    secret        VARCHAR(64)   NOT NULL,
+    events        VARCHAR(32)[] NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    CONSTRAINT fk_webhooks_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_restaurant_id ON webhooks (restaurant_id);
+
+/*
+ Таблица outbox содержит исходящие события с бронями (transactional outbox): событие добавляется в той же транзакции,
+ что и изменение брони, поэтому не теряется, даже если сервис остановится сразу после изменения. Пока событие
+ не разобрано (relayed_at IS NULL), по нему не запланированы доставки вебхукам.
+ */
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id            SERIAL PRIMARY KEY,
+    restaurant_id INTEGER     NOT NULL,
+    event         VARCHAR(32) NOT NULL,
+    payload       JSONB       NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    relayed_at    TIMESTAMPTZ,
+    CONSTRAINT fk_outbox_restaurants FOREIGN KEY (restaurant_id) REFERENCES restaurants (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_not_relayed ON outbox (id) WHERE relayed_at IS NULL;
+
+/*
+ Таблица webhook_deliveries содержит доставки событий вебхукам и служит журналом доставки. Доставка выполняется, когда
+ наступает next_attempt_at; после неудачной попытки next_attempt_at переносится на более поздний момент. Пока доставку
+ выполняет один из экземпляров сервиса, она закреплена за ним до locked_until и не выбирается другими.
+ */
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id               SERIAL PRIMARY KEY,
+    webhook_id       INTEGER     NOT NULL,
+    event_id         INTEGER     NOT NULL,
+    status           VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts         INTEGER     NOT NULL DEFAULT 0,
+    last_status_code INTEGER,
+    last_error       TEXT,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at     TIMESTAMPTZ,
+    CONSTRAINT fk_webhook_deliveries_webhooks FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE,
+    CONSTRAINT fk_webhook_deliveries_outbox FOREIGN KEY (event_id) REFERENCES outbox (id) ON DELETE CASCADE,
+    CONSTRAINT uq_webhook_deliveries_webhook_event UNIQUE (webhook_id, event_id),
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'delivered', 'failed')),
+    CONSTRAINT chk_webhook_deliveries_attempts CHECK (attempts >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending_next_attempt_at
+    ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,108 @@
+-- возвращаем функции, которые проверяют время броней по bookings.booked_time_from и bookings.booked_time_to
+/*
+    Функция is_table_available проверяет, можно ли забронировать столик на booking_duration в желаемые дату и время.
+    Алгоритм описан в первоначальной миграции: временные промежутки броней столика в желаемую дату сливаются
+    с желаемым, и если количество промежутков изменилось, значит, желаемая бронь накладывается на существующие.
+ */
+CREATE OR REPLACE FUNCTION is_table_available(
+    checked_table_id int, -- ID столика
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS BOOLEAN -- возвращает TRUE, если забронировать можно, иначе - FALSE
+AS
+$$
+DECLARE
+    rows_before_merge INTEGER;
+    rows_after_merge  INTEGER;
+BEGIN
+    -- ищем временные промежутки броней столиков, которые хотя бы раз бронировались в выбранный день
+    CREATE TEMP TABLE booking_intervals
+    AS
+    SELECT booked_time_from, booked_time_to
+    FROM tables
+             JOIN bookings_tables bt on tables.id = bt.table_id
+             JOIN bookings b on b.id = bt.booking_id
+    WHERE booked_date = desired_booking_date
+      AND table_id = checked_table_id
+    UNION
+    -- добавляем временной промежуток желаемой брони к полученным
+    VALUES (desired_booking_time, desired_booking_time + booking_duration);
+
+    /*
+    если остался только один временной промежуток (время желаемой брони), значит, столик вообще не бронировался
+    в выбранную дату, и его можно забронировать
+     */
+    rows_before_merge := (SELECT COUNT(*) FROM booking_intervals);
+    IF rows_before_merge = 1 THEN
+        DROP TABLE booking_intervals;
+        RETURN TRUE;
+    END IF;
+
+    rows_after_merge := (
+        SELECT COUNT(*)
+        FROM (
+                 WITH rng(s, e) AS (
+                     SELECT *
+                     FROM booking_intervals
+                 )
+                 SELECT -- min/max по группе
+                        min(s) s,
+                        max(e) e
+                 FROM (
+                          SELECT *,
+                                 sum(ns::integer) OVER (ORDER BY s, e) grp -- определение групп
+                          FROM (
+                                   SELECT *,
+                                          coalesce(s > max(e)
+                                                       OVER (ORDER BY s, e ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING),
+                                                   TRUE) ns -- начало правее самого правого из предыдущих концов == разрыв
+                                   FROM rng
+                               ) t
+                      ) t
+                 GROUP BY grp
+             ) merged_intervals
+    );
+
+    DROP TABLE booking_intervals;
+    RETURN rows_before_merge = rows_after_merge;
+END;
+$$ LANGUAGE plpgsql;
+
+/*
+ Функция get_available_tables возвращает таблицу вида tables с информацией о столиках, свободных для бронирования.
+ Столики, удерживаемые другими гостями, считаются занятыми.
+ */
+CREATE OR REPLACE FUNCTION get_available_tables(
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS TABLE
+            (
+                id            INTEGER,
+                restaurant_id INTEGER,
+                seats_number  INTEGER
+            )
+AS
+$$
+BEGIN
+    RETURN QUERY
+        SELECT a.id, a.restaurant_id, a.seats_number
+        FROM (
+                 -- столики которые ни разу не бронировались
+                 SELECT tables.id, tables.restaurant_id, tables.seats_number
+                 FROM tables
+                 WHERE tables.id NOT IN (SELECT bookings_tables.table_id FROM bookings_tables)
+                 UNION
+                 -- столики которые хотя бы раз бронировались
+                 SELECT tables.id, tables.restaurant_id, tables.seats_number
+                 FROM tables
+                          JOIN bookings_tables bt on tables.id = bt.table_id
+                          JOIN bookings b on b.id = bt.booking_id
+                 WHERE is_table_available(bt.table_id, desired_booking_date, desired_booking_time, booking_duration)
+             ) a
+        WHERE NOT is_table_held(a.id, desired_booking_date, desired_booking_time, booking_duration);
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,51 @@
+/*
+ Функция is_table_available проверяет, можно ли забронировать столик на booking_duration в желаемые дату и время.
+ Занятость столика определяется по bookings_tables.booked_during - тем же промежуткам, которые проверяет
+ ограничение-исключение excl_bookings_tables_overlap, сетка доступности ресторана и перенос брони:
+   - столики отменённой брони отвязываются от неё;
+   - у завершённой брони (completed или no_show) промежуток заканчивается моментом завершения или становится пустым,
+     поэтому столик свободен с этого момента, а не с booked_time_to;
+   - промежуток брони, которая заканчивается после полуночи, заканчивается на следующий день, а не в начале той же даты.
+ Границы включаются в промежуток, поэтому брони, которые соприкасаются по времени, тоже считаются пересекающимися.
+ */
+CREATE OR REPLACE FUNCTION is_table_available(
+    checked_table_id int, -- ID столика
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS BOOLEAN -- возвращает TRUE, если забронировать можно, иначе - FALSE
+AS
+$$
+SELECT NOT EXISTS(SELECT 1
+                  FROM bookings_tables bt
+                  WHERE bt.table_id = checked_table_id
+                    AND bt.booked_during && tsrange(
+                          desired_booking_date + desired_booking_time,
+                          desired_booking_date + desired_booking_time + booking_duration,
+                          '[]'
+                      ));
+$$ LANGUAGE sql STABLE;
+
+/*
+ Функция get_available_tables возвращает таблицу вида tables с информацией о столиках, свободных для бронирования.
+ Столики, удерживаемые другими гостями, считаются занятыми.
+ */
+CREATE OR REPLACE FUNCTION get_available_tables(
+    desired_booking_date date, -- желаемая дата брони
+    desired_booking_time time, -- желаемое время брони
+    booking_duration interval -- длительность брони
+)
+    RETURNS TABLE
+            (
+                id            INTEGER,
+                restaurant_id INTEGER,
+                seats_number  INTEGER
+            )
+AS
+$$
+SELECT t.id, t.restaurant_id, t.seats_number
+FROM tables t
+WHERE is_table_available(t.id, desired_booking_date, desired_booking_time, booking_duration)
+  AND NOT is_table_held(t.id, desired_booking_date, desired_booking_time, booking_duration);
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_holds_client_key;
+DROP INDEX IF EXISTS idx_holds_token_hash;
+
+ALTER TABLE holds
+    DROP COLUMN IF EXISTS client_key,
+    DROP COLUMN IF EXISTS token_hash;
@@ -0,0 +1,16 @@
+/*
+ Удержания столиков адресуются не только по ID, который легко подобрать, но и по секретному токену: без него
+ удержание нельзя получить, подтвердить или снять. Как и ключи API, токен хранится в виде SHA-256 хеша (token_hash).
+ client_key представляет ключ клиента (например, IP-адрес гостя), по которому ограничивается количество
+ его действующих удержаний.
+ */
+
+-- у действующих удержаний нет токенов, поэтому подтвердить их уже нельзя: снимаем их, освобождая столики
+DELETE FROM holds;
+
+ALTER TABLE holds
+    ADD COLUMN token_hash TEXT NOT NULL,
+    ADD COLUMN client_key TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_token_hash ON holds (token_hash);
+CREATE INDEX IF NOT EXISTS idx_holds_client_key ON holds (client_key, expires_at);
@@ -0,0 +1,3 @@
+ALTER TABLE waitlist
+    DROP CONSTRAINT IF EXISTS fk_waitlist_holds,
+    DROP COLUMN IF EXISTS hold_id;
@@ -0,0 +1,8 @@
+/*
+ Места, предложенные гостю из листа ожидания, удерживаются за ним, пока действует предложение: hold_id представляет
+ ID удержания столиков, по которому оформляется бронь, когда гость принимает предложение.
+ */
+
+ALTER TABLE waitlist
+    ADD COLUMN hold_id INTEGER,
+    ADD CONSTRAINT fk_waitlist_holds FOREIGN KEY (hold_id) REFERENCES holds (id) ON DELETE SET NULL;
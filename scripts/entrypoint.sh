@@ -11,5 +11,12 @@ if [[ -z ${DSN} ]]; then
   export DSN=$(sed -n 's/^dsn:[[:space:]]*"\(.*\)"/\1/p' "${CONFIG_FILE}")
 fi
 
+# переменная API_DSN (например, из docker-compose.yml) имеет приоритет над строкой подключения из конфигурационного файла
+MIGRATE_DSN=${API_DSN:-${DSN}}
+if [[ -n ${MIGRATE_DSN} ]]; then
+  echo "[$(date)] Running database migrations..."
+  migrate -path ./migrations -database "${MIGRATE_DSN}" up
+fi
+
 echo "[$(date)] Starting API server..."
 ./apiserver -config "${CONFIG_FILE}"
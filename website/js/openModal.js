@@ -31,4 +31,67 @@ function openModal(id, name) {
 
     arrayP[1].innerHTML = `Желаемое дата и время посещения: ${convertedDate}`
     document.querySelector("#desired_datetime_input").value = convertedDate
-}
\ No newline at end of file
+
+    holdTables(id, peopleNumber, convertedDate, document.querySelector("#zone_input").value)
+}
+
+// ID ресторана, в котором удерживаются столики, пока гость заполняет форму брони
+let heldRestaurantId = null
+
+function formatTime(date) {
+    const hours = date.getHours() > 9 ? date.getHours() : `0${date.getHours()}`;
+    const minutes = date.getMinutes() > 9 ? date.getMinutes() : `0${date.getMinutes()}`;
+    return `${hours}:${minutes}`;
+}
+
+// holdTables удерживает столики в ресторане на время заполнения формы, чтобы их не забронировал другой гость
+function holdTables(id, peopleNumber, desiredDatetime, zone) {
+    const holdInput = document.querySelector("#hold_id_input")
+    const holdTokenInput = document.querySelector("#hold_token_input")
+    const holdStatus = document.querySelector("#hold_status")
+    holdInput.value = ""
+    holdTokenInput.value = ""
+    holdStatus.innerHTML = ""
+
+    fetch(`/api/v1/restaurants/${id}/holds/`, {
+        method: "POST",
+        headers: {"Content-Type": "application/json"},
+        body: JSON.stringify({
+            people_number: Number(peopleNumber),
+            desired_datetime: desiredDatetime,
+            zone: zone,
+        }),
+    })
+        .then(response => response.ok ? response.json() : Promise.reject(response))
+        .then(hold => {
+            heldRestaurantId = id
+            holdInput.value = hold.id
+            holdTokenInput.value = hold.token
+            holdStatus.innerHTML = `Места закреплены за Вами до ${formatTime(new Date(hold.expires_at))}`
+        })
+        .catch(() => {
+            holdStatus.innerHTML = "Не удалось закрепить места: пока Вы заполняете форму, их может забронировать другой гость"
+        })
+}
+
+// releaseHold снимает удержание столиков, если гость закрыл форму, не оформив бронь
+function releaseHold() {
+    const holdInput = document.querySelector("#hold_id_input")
+    const holdTokenInput = document.querySelector("#hold_token_input")
+    if (heldRestaurantId === null || holdInput.value === "") {
+        return
+    }
+
+    fetch(`/api/v1/restaurants/${heldRestaurantId}/holds/${holdInput.value}/`, {
+        method: "DELETE",
+        headers: {"X-Hold-Token": holdTokenInput.value},
+    })
+    heldRestaurantId = null
+    holdInput.value = ""
+    holdTokenInput.value = ""
+}
+
+const bookingModal = document.getElementById("makeBooking")
+if (bookingModal) {
+    bookingModal.addEventListener("hidden.bs.modal", releaseHold)
+}